@@ -0,0 +1,140 @@
+// Package api defines the versioned JSON records the bidder emits and the JSON
+// schemas generated from them.
+//
+// Every record carries a schema_version. Changing a record struct in a way that
+// changes its schema requires bumping its version and regenerating the schemas
+// with `go generate ./api`; TestSchemasUpToDate fails otherwise.
+package api
+
+//go:generate go test -run TestSchemasUpToDate -update .
+
+// Current schema versions of each record.
+const (
+	CommitmentSchemaVersion      = 1
+	BidSchemaVersion             = 3
+	InclusionSchemaVersion       = 1
+	SilentRejectionSchemaVersion = 1
+)
+
+// Record describes a record type and its current schema version.
+type Record struct {
+	Name    string // Record type name, also the directory of its schemas.
+	Version int    // Current schema version.
+	Example any    // Zero value of the record struct the schema is generated from.
+}
+
+// Records lists every record type the bidder emits.
+var Records = []Record{
+	{Name: "commitment", Version: CommitmentSchemaVersion, Example: CommitmentRecord{}},
+	{Name: "bid", Version: BidSchemaVersion, Example: BidRecord{}},
+	{Name: "inclusion", Version: InclusionSchemaVersion, Example: InclusionRecord{}},
+	{Name: "silent_rejection", Version: SilentRejectionSchemaVersion, Example: SilentRejectionRecord{}},
+}
+
+// LookupRecord returns the record type with the given name.
+func LookupRecord(name string) (Record, bool) {
+	for _, r := range Records {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Record{}, false
+}
+
+// CommitmentRecord is a line of the commitments JSONL file.
+type CommitmentRecord struct {
+	SchemaVersion int        `json:"schema_version"`
+	ReceivedAt    int64      `json:"received_at"` // Unix milliseconds.
+	Commitment    Commitment `json:"commitment"`
+}
+
+// Commitment holds every field of a bidder API commitment, keyed by protobuf
+// field name. 64-bit integers are encoded as strings, as in the API's JSON form.
+type Commitment struct {
+	TxHashes             []string `json:"tx_hashes"`
+	BidAmount            string   `json:"bid_amount"`
+	BlockNumber          int64    `json:"block_number,string"`
+	ReceivedBidDigest    string   `json:"received_bid_digest"`
+	ReceivedBidSignature string   `json:"received_bid_signature"`
+	CommitmentDigest     string   `json:"commitment_digest"`
+	CommitmentSignature  string   `json:"commitment_signature"`
+	ProviderAddress      string   `json:"provider_address"`
+	DecayStartTimestamp  int64    `json:"decay_start_timestamp,string"`
+	DecayEndTimestamp    int64    `json:"decay_end_timestamp,string"`
+	DispatchTimestamp    int64    `json:"dispatch_timestamp,string"`
+	RevertingTxHashes    []string `json:"reverting_tx_hashes"`
+}
+
+// Bid outcomes, resolved once the target block is produced.
+const (
+	BidStatusIncluded = "included" // The transaction is in the target block.
+	BidStatusMissed   = "missed"   // The target block was produced without the transaction.
+	BidStatusUnknown  = "unknown"  // The target block could not be checked.
+)
+
+// BidRecord is a line of the bid records JSONL file, written when a bid is resolved.
+type BidRecord struct {
+	SchemaVersion   int     `json:"schema_version"`
+	TxHash          string  `json:"tx_hash"`
+	BlockNumber     uint64  `json:"block_number"` // Target block of the bid.
+	AmountEth       float64 `json:"amount_eth"`
+	Profile         string  `json:"profile"`
+	EscalationLevel int     `json:"escalation_level"` // Times the bid amount was escalated after missed bids.
+	Status          string  `json:"status"`
+	SentAt          int64   `json:"sent_at"`     // Unix milliseconds.
+	ResolvedAt      int64   `json:"resolved_at"` // Unix milliseconds.
+
+	// Where the decay window fell relative to the start of the target block's
+	// slot, in milliseconds, negative before the slot starts.
+	DecayStartSlotOffsetMs int64 `json:"decay_start_slot_offset_ms"`
+	DecayEndSlotOffsetMs   int64 `json:"decay_end_slot_offset_ms"`
+
+	Strategy *BidStrategy `json:"strategy,omitempty"` // Set with BID_RECORD_STRATEGY.
+}
+
+// Guards that can shape a bid, listed in BidStrategy.Guards.
+const (
+	GuardEscalated         = "escalated"           // The amount was escalated after missed bids.
+	GuardClampedMin        = "clamped_min"         // The amount was raised to BID_MIN_ETH.
+	GuardClampedMax        = "clamped_max"         // The amount was lowered to BID_MAX_ETH.
+	GuardAdaptiveBlobCount = "adaptive_blob_count" // The blob count followed the blob base fee.
+	GuardBlobCycle         = "blob_cycle"          // The blob count came from NUM_BLOBS.
+)
+
+// BidStrategy is why a bid looks the way it does, for later analysis.
+type BidStrategy struct {
+	Pricer         string   `json:"pricer"`            // How the amount was drawn: profile, uniform or fixed.
+	Mode           string   `json:"mode"`              // Transaction mode: blob, or the TX_TYPE generator.
+	NumBlobs       int      `json:"num_blobs"`         // Blobs per transaction, 0 for transfers.
+	BaseFeeWei     string   `json:"base_fee_wei"`      // Base fee of the header the bid was built on.
+	BlobBaseFeeWei string   `json:"blob_base_fee_wei"` // Blob base fee of the next block.
+	Guards         []string `json:"guards"`            // Guards that applied, in order.
+}
+
+// InclusionRecord is the body of the inclusion webhook, posted when a bid's
+// transaction is included in its target block.
+type InclusionRecord struct {
+	SchemaVersion int     `json:"schema_version"`
+	TxHash        string  `json:"tx_hash"`
+	BlockNumber   uint64  `json:"block_number"`
+	GasUsed       uint64  `json:"gas_used"`      // From the receipt, 0 if it couldn't be fetched.
+	BlobGasUsed   uint64  `json:"blob_gas_used"` // From the receipt, 0 if it couldn't be fetched.
+	AmountEth     float64 `json:"amount_eth"`    // The bid that landed the transaction.
+	Profile       string  `json:"profile"`
+	SentAt        int64   `json:"sent_at"`     // Unix milliseconds.
+	IncludedAt    int64   `json:"included_at"` // Unix milliseconds, when the inclusion was observed.
+	LatencyMs     int64   `json:"latency_ms"`  // From sending the bid to observing the inclusion.
+}
+
+// SilentRejectionRecord is the body of the silent rejection webhook, posted when
+// the bidder node takes a bid without error but no provider commits to it.
+type SilentRejectionRecord struct {
+	SchemaVersion int      `json:"schema_version"`
+	TxHashes      []string `json:"tx_hashes"`
+	BlockNumber   uint64   `json:"block_number"` // Target block of the bid.
+	AmountWei     string   `json:"amount_wei"`
+	DecayStart    int64    `json:"decay_start"` // Unix milliseconds.
+	DecayEnd      int64    `json:"decay_end"`   // Unix milliseconds.
+	SentAt        int64    `json:"sent_at"`     // Unix milliseconds.
+	AnsweredAt    int64    `json:"answered_at"` // Unix milliseconds, when the bid's stream ended.
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaDialect is the JSON Schema dialect of the generated schemas.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+//go:embed schemas
+var schemaFS embed.FS
+
+// Schema is the subset of JSON Schema used by the generated schemas.
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // false or a *Schema.
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// SchemaPath returns the path of a record version's schema under the schemas directory.
+func SchemaPath(name string, version int) string {
+	return fmt.Sprintf("schemas/%s/v%d.json", name, version)
+}
+
+// GenerateSchema generates the JSON schema of a record version from its struct.
+func GenerateSchema(r Record) *Schema {
+	s := schemaOf(reflect.TypeOf(r.Example), false)
+	s.Dialect = SchemaDialect
+	s.ID = fmt.Sprintf("%s/v%d", r.Name, r.Version)
+	return s
+}
+
+// MarshalSchema renders a schema the way it is stored in the schemas directory.
+func MarshalSchema(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// LoadSchema returns the embedded schema of a record version.
+func LoadSchema(name string, version int) (*Schema, error) {
+	data, err := schemaFS.ReadFile(SchemaPath(name, version))
+	if err != nil {
+		return nil, fmt.Errorf("no schema for %s version %d", name, version)
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema for %s version %d: %w", name, version, err)
+	}
+	return &s, nil
+}
+
+// schemaOf maps a Go type to its JSON schema. asString is set for fields with
+// the ",string" JSON option.
+func schemaOf(t reflect.Type, asString bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if asString {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), false)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), false)}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			s.Properties[name] = schemaOf(f.Type, hasOption(opts, "string"))
+			if !hasOption(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	default:
+		panic(fmt.Sprintf("api: no JSON schema for type %s", t))
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
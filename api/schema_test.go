@@ -0,0 +1,66 @@
+package api
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "write schemas for new record versions")
+
+// TestSchemasUpToDate fails when a record struct no longer matches the stored
+// schema of its current version, which means the change would break consumers
+// and needs a version bump. With -update, schemas for new versions are written;
+// existing versions are never overwritten.
+func TestSchemasUpToDate(t *testing.T) {
+	for _, r := range Records {
+		t.Run(r.Name, func(t *testing.T) {
+			generated, err := MarshalSchema(GenerateSchema(r))
+			require.NoError(t, err)
+
+			path := SchemaPath(r.Name, r.Version)
+			stored, err := os.ReadFile(path)
+			if os.IsNotExist(err) && *update {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, generated, 0o644))
+				return
+			}
+			require.NoError(t, err, "no schema for %s v%d; run go generate ./api", r.Name, r.Version)
+			require.True(t, bytes.Equal(stored, generated),
+				"%s schema v%d changed; bump its schema version and run go generate ./api\nstored:\n%s\ngenerated:\n%s",
+				r.Name, r.Version, stored, generated)
+		})
+	}
+}
+
+func TestValidateRecord(t *testing.T) {
+	valid := `{"schema_version":1,"received_at":1700000000000,"commitment":{
+		"tx_hashes":["aa"],"bid_amount":"1000","block_number":"100",
+		"received_bid_digest":"d","received_bid_signature":"s",
+		"commitment_digest":"cd","commitment_signature":"cs","provider_address":"0x01",
+		"decay_start_timestamp":"1","decay_end_timestamp":"2","dispatch_timestamp":"3",
+		"reverting_tx_hashes":[]}}`
+	version, err := ValidateRecord("commitment", []byte(valid))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+
+	_, err = ValidateRecord("commitment", []byte(`{"received_at":1}`))
+	require.ErrorContains(t, err, "missing schema_version")
+
+	_, err = ValidateRecord("commitment", []byte(`{"schema_version":99}`))
+	require.ErrorContains(t, err, "no schema for commitment version 99")
+
+	_, err = ValidateRecord("commitment", []byte(`{"schema_version":1,"received_at":"soon","commitment":{}}`))
+	require.ErrorContains(t, err, "$.commitment: missing required property")
+
+	_, err = ValidateRecord("commitment", []byte(strings.Replace(valid, "1700000000000", "1.5", 1)))
+	require.ErrorContains(t, err, "$.received_at: expected integer")
+
+	_, err = ValidateRecord("commitment", []byte(strings.Replace(valid, `"received_at"`, `"extra":true,"received_at"`, 1)))
+	require.ErrorContains(t, err, `unexpected property "extra"`)
+}
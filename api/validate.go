@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ValidateRecord checks a JSON record against the schema for the version it declares.
+//
+// Parameters:
+// - name: The record type.
+// - data: A single JSON record.
+//
+// Returns:
+// - The record's schema version, and an error describing the first violation if any.
+func ValidateRecord(name string, data []byte) (int, error) {
+	var header struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if header.SchemaVersion == nil {
+		return 0, fmt.Errorf("missing schema_version")
+	}
+
+	schema, err := LoadSchema(name, *header.SchemaVersion)
+	if err != nil {
+		return *header.SchemaVersion, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return *header.SchemaVersion, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return *header.SchemaVersion, validate(schema, value, "$")
+}
+
+func validate(s *Schema, value any, path string) error {
+	switch s.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string, got %s", path, jsonType(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %s", path, jsonType(value))
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expected integer, got %s", path, jsonType(value))
+		}
+		if _, err := n.Int64(); err != nil {
+			return fmt.Errorf("%s: expected integer, got %s", path, n)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s: expected number, got %s", path, jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %s", path, jsonType(value))
+		}
+		for i, item := range items {
+			if err := validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %s", path, jsonType(value))
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				prop, ok = additionalSchema(s.AdditionalProperties)
+				if !ok {
+					return fmt.Errorf("%s: unexpected property %q", path, k)
+				}
+			}
+			if prop == nil {
+				continue // additionalProperties: true
+			}
+			if err := validate(prop, obj[k], path+"."+k); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+	return nil
+}
+
+// additionalSchema interprets additionalProperties: a missing value or true allows any
+// property (nil schema), false allows none, and an object is the schema of extra properties.
+func additionalSchema(v any) (*Schema, bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, true
+	case bool:
+		return nil, v
+	case map[string]any:
+		data, _ := json.Marshal(v)
+		var s Schema
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, false
+		}
+		return &s, true
+	case *Schema:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	"github.com/primev/preconf_blob_bidder/internal/config"
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/store"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+	"github.com/primev/preconf_blob_bidder/internal/watch"
+	"github.com/urfave/cli/v2"
+)
+
+// loadRunConfig applies the config file, if any, and resolves every option.
+// Config file values act as defaults beneath flags and env vars.
+//
+// Parameters:
+// - c: The CLI context holding the flags.
+//
+// Returns:
+// - The options, to be checked with Validate before use, or an error if the config file is invalid.
+func loadRunConfig(c *cli.Context) (*config.Config, error) {
+	if configFile := config.NewSource(c).String(FlagConfigFile, "CONFIG_FILE", ""); configFile != "" {
+		fileCfg, err := config.LoadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyConfigFile(c, fileCfg); err != nil {
+			return nil, err
+		}
+	}
+	return loadConfig(c), nil
+}
+
+// loadConfig resolves every option from its flag, env var or default. The
+// config file, if any, must already have been applied with applyConfigFile.
+//
+// Parameters:
+// - c: The CLI context holding the flags.
+//
+// Returns:
+// - The options, to be checked with Validate before use.
+func loadConfig(c *cli.Context) *config.Config {
+	src := config.NewSource(c)
+	cfg := &config.Config{
+		AppName:                   src.String(FlagAppName, "APP_NAME", "preconf_bidder"),
+		Version:                   src.String(FlagVersion, "VERSION", "0.8.0"),
+		LogFile:                   src.String(FlagLogFile, "LOG_FILE", ""),
+		LogTimestampFormat:        src.String(FlagLogTimestampFormat, "LOG_TIMESTAMP_FORMAT", ""),
+		PrettyLog:                 src.Bool(FlagPrettyLog, "PRETTY_LOG", true),
+		LogDedupWindow:            c.Duration(FlagLogDedupWindow),
+		ServerAddress:             src.String(FlagServerAddress, "SERVER_ADDRESS", "localhost:13524"),
+		BidderTLS:                 src.Bool(FlagBidderTLS, "BIDDER_TLS", false),
+		BidderTLSCAFile:           src.String(FlagBidderTLSCAFile, "BIDDER_TLS_CA_FILE", ""),
+		UsePayload:                src.Bool(FlagUsePayload, "USE_PAYLOAD", true),
+		RPCEndpoint:               src.String(FlagRpcEndpoint, "RPC_ENDPOINT", "https://ethereum-holesky-rpc.publicnode.com"),
+		WSEndpoint:                src.String(FlagWsEndpoint, "WS_ENDPOINT", "wss://ethereum-holesky-rpc.publicnode.com"),
+		WSEndpointList:            src.String(FlagWsEndpoints, "WS_ENDPOINTS", ""),
+		PrivateKey:                src.String(FlagPrivateKey, "PRIVATE_KEY", ""),
+		Offset:                    src.Uint64(FlagOffset, "OFFSET", 1),
+		BidAmount:                 src.Float64(FlagBidAmount, "BID_AMOUNT", 0.001),
+		PriorityFee:               src.Uint64(FlagPriorityFee, "PRIORITY_FEE", 1),
+		StdDevPercentage:          src.Float64(FlagBidAmountStdDevPercentage, "BID_AMOUNT_STD_DEV_PERCENTAGE", 100.0),
+		NumBlob:                   src.Uint(FlagNumBlob, "NUM_BLOB", 0),
+		DefaultTimeoutSeconds:     src.Uint(FlagDefaultTimeout, "DEFAULT_TIMEOUT", 15),
+		RunDurationMinutes:        src.Uint(FlagRunDurationMinutes, "RUN_DURATION_MINUTES", 0),
+		MaxRuntime:                c.Duration(FlagMaxRuntime),
+		ShutdownDrainTimeoutSec:   src.Uint(FlagShutdownBidDrainTimeout, "SHUTDOWN_BID_DRAIN_TIMEOUT_SEC", 10),
+		ProposerAllowlist:         src.String(FlagProposerAllowlist, "PROPOSER_ALLOWLIST", ""),
+		BeaconEndpoint:            src.String(FlagBeaconEndpoint, "BEACON_ENDPOINT", ""),
+		TxABIFile:                 src.String(FlagTxABIFile, "TX_ABI_FILE", ""),
+		CommitmentsFile:           src.String(FlagCommitmentsFile, "COMMITMENTS_FILE", ""),
+		TxCountWindow:             src.Uint(FlagTxCountWindow, "TX_COUNT_WINDOW", 20),
+		BidProfilesFile:           src.String(FlagBidProfilesFile, "BID_PROFILES_FILE", ""),
+		BidProfileSelector:        src.String(FlagBidProfileSelector, "BID_PROFILE_SELECTOR", strategy.SelectorRoundRobin),
+		BidBlockRange:             src.Uint64(FlagBidBlockRange, "BID_BLOCK_RANGE", 1),
+		FeePercentileWindow:       src.Uint(FlagFeePercentileWindow, "FEE_PERCENTILE_WINDOW", 10),
+		BidCycleRetries:           src.Uint(FlagBidCycleRetries, "BID_CYCLE_RETRIES", 0),
+		DrainSignalName:           src.String(FlagDrainSignal, "DRAIN_SIGNAL", "SIGTERM"),
+		DrainTimeoutSec:           src.Uint(FlagDrainTimeout, "DRAIN_TIMEOUT_SEC", 120),
+		DrainConfirmations:        src.Uint64(FlagDrainConfirmations, "DRAIN_CONFIRMATIONS", 2),
+		HealthAddr:                src.String(FlagHealthAddr, "HEALTH_ADDR", ""),
+		HeartbeatInterval:         c.Duration(FlagHeartbeatInterval),
+		BidRecordsFile:            src.String(FlagBidRecordsFile, "BID_RECORDS_FILE", ""),
+		BidLogFile:                src.String(FlagBidLogFile, "BID_LOG_FILE", ""),
+		BidLogFormat:              src.String(FlagBidLogFormat, "BID_LOG_FORMAT", store.FormatJSONL),
+		BidLogMaxSizeMB:           src.Uint(FlagBidLogMaxSizeMB, "BID_LOG_MAX_SIZE_MB", 100),
+		BidLogMaxBackups:          src.Uint(FlagBidLogMaxBackups, "BID_LOG_MAX_BACKUPS", 10),
+		EscalationMissedBids:      src.Uint(FlagEscalationMissedBids, "ESCALATION_MISSED_BIDS", 3),
+		EscalationStepPct:         src.Float64(FlagEscalationStepPct, "ESCALATION_STEP_PCT", 25),
+		EscalationMaxMultiplier:   src.Float64(FlagEscalationMaxMultiplier, "ESCALATION_MAX_MULTIPLIER", 5),
+		TipAsBaseFeePct:           src.Float64(FlagTipAsBaseFeePct, "TIP_AS_BASE_FEE_PCT", 0),
+		TipFloorWei:               src.Uint64(FlagTipFloorWei, "TIP_FLOOR_WEI", 1),
+		GasFeeCapGwei:             src.Float64(FlagGasFeeCapGwei, "GAS_FEE_CAP_GWEI", 0),
+		GasTipGwei:                src.Float64(FlagGasTipGwei, "GAS_TIP_GWEI", 0),
+		BlobFeeCapGwei:            src.Float64(FlagBlobFeeCapGwei, "BLOB_FEE_CAP_GWEI", 0),
+		BaseFeeMultiplier:         src.Float64(FlagBaseFeeMultiplier, "BASE_FEE_MULTIPLIER", 1),
+		MaxFeeGwei:                src.Float64(FlagMaxFeeGwei, "MAX_FEE_GWEI", 0),
+		BlobFeeAsGasFeeMultiple:   src.Float64(FlagBlobFeeAsGasFeeMultiple, "BLOB_FEE_AS_GAS_FEE_MULTIPLE", 0),
+		TxDedup:                   src.Bool(FlagTxDedup, "TX_DEDUP", true),
+		BidLatencySLO:             c.Duration(FlagBidLatencySLO),
+		NonceCheckInterval:        c.Duration(FlagNonceCheckInterval),
+		NoncePauseChecks:          src.Uint(FlagNoncePauseChecks, "NONCE_PAUSE_CHECKS", 3),
+		NonceResyncFailures:       src.Uint(FlagNonceResyncFailures, "NONCE_RESYNC_FAILURES", 1),
+		BlobChainLength:           src.Uint(FlagBlobChainLength, "BLOB_CHAIN_LENGTH", 1),
+		DecayJitter:               c.Duration(FlagDecayJitter),
+		BidMinEth:                 src.Float64(FlagBidMinEth, "BID_MIN_ETH", 0),
+		BidMaxEth:                 src.Float64(FlagBidMaxEth, "BID_MAX_ETH", 0),
+		BidAmountStrategy:         src.String(FlagBidAmountStrategy, "BID_AMOUNT_STRATEGY", ""),
+		BidAmountMin:              src.String(FlagBidAmountMin, "BID_AMOUNT_MIN", ""),
+		BidAmountMax:              src.String(FlagBidAmountMax, "BID_AMOUNT_MAX", ""),
+		BidMode:                   src.String(FlagBidMode, "BID_MODE", strategy.BidModeRandom),
+		BidAmountEth:              src.Float64(FlagBidAmountEth, "BID_AMOUNT_ETH", 0),
+		TransferScheduleCSV:       src.String(FlagTransferScheduleCSV, "TRANSFER_SCHEDULE_CSV", ""),
+		TransferAmountWei:         src.String(FlagTransferAmountWei, "TRANSFER_AMOUNT_WEI", "1000000000"),
+		BidderOutagePolicy:        src.String(FlagBidderOutagePolicy, "BIDDER_OUTAGE_POLICY", bb.OutagePolicySkip),
+		BidderOutageFailures:      src.Uint(FlagBidderOutageFailures, "BIDDER_OUTAGE_FAILURES", 3),
+		BidderOutageQueueSize:     src.Uint(FlagBidderOutageQueueSize, "BIDDER_OUTAGE_QUEUE_SIZE", 8),
+		DecayStartOffsetMs:        c.Int64(FlagDecayStartOffsetMs),
+		DecayDurationMs:           c.Int64(FlagDecayDurationMs),
+		AdaptiveBlobCount:         src.Bool(FlagAdaptiveBlobCount, "ADAPTIVE_BLOB_COUNT", false),
+		AdaptiveBlobMin:           src.Uint(FlagAdaptiveBlobMin, "ADAPTIVE_BLOB_MIN", 1),
+		AdaptiveBlobMax:           src.Uint(FlagAdaptiveBlobMax, "ADAPTIVE_BLOB_MAX", 6),
+		AdaptiveBlobLowFeeWei:     src.Uint64(FlagAdaptiveBlobLowFeeWei, "ADAPTIVE_BLOB_LOW_FEE_WEI", 1_000_000_000),
+		AdaptiveBlobHighFeeWei:    src.Uint64(FlagAdaptiveBlobHighFeeWei, "ADAPTIVE_BLOB_HIGH_FEE_WEI", 30_000_000_000),
+		KeyAuditMode:              src.String(FlagKeyAudit, "KEY_AUDIT", keyaudit.ModeOff),
+		KeyAuditFile:              src.String(FlagKeyAuditFile, "KEY_AUDIT_FILE", "key_audit.jsonl"),
+		RPCPoolSize:               src.Uint(FlagRpcPoolSize, "RPC_POOL_SIZE", 2),
+		RPCPoolHealthInterval:     c.Duration(FlagRpcPoolHealthInterval),
+		HeaderStaleTimeout:        c.Duration(FlagHeaderStaleTimeout),
+		BidEndpointTimeout:        c.Duration(FlagBidEndpointTimeout),
+		BlockTimeSetting:          src.String(FlagBlockTime, "BLOCK_TIME", "auto"),
+		NumBlobs:                  src.String(FlagNumBlobs, "NUM_BLOBS", ""),
+		BidRecordStrategy:         src.Bool(FlagBidRecordStrategy, "BID_RECORD_STRATEGY", false),
+		BidPricingWorkers:         src.Uint64(FlagBidPricingWorkers, "BID_PRICING_WORKERS", 1),
+		TxType:                    src.String(FlagTxType, "TX_TYPE", ee.GeneratorTransfer),
+		TokenTransfer:             src.Bool(FlagTokenTransfer, "TOKEN_TRANSFER", false),
+		TokenAddress:              src.String(FlagTokenAddress, "TOKEN_ADDRESS", ""),
+		TokenAmount:               src.String(FlagTokenAmount, "TOKEN_AMOUNT", "1"),
+		MetricsPort:               src.Uint(FlagMetricsPort, "METRICS_PORT", 9090),
+		OffsetDecayPolicy:         src.String(FlagOffsetDecayPolicy, "OFFSET_DECAY_POLICY", strategy.OffsetDecayExtend),
+		TxPerBlock:                src.Uint(FlagTxPerBlock, "TX_PER_BLOCK", 1),
+		BidOutcomeLogBlocks:       src.Uint64(FlagBidOutcomeLogBlocks, "BID_OUTCOME_LOG_BLOCKS", 10),
+		BackrunTo:                 src.String(FlagBackrunTo, "BACKRUN_TO", ""),
+		DryRun:                    src.Bool(FlagDryRun, "DRY_RUN", false),
+		MetricsAddr:               src.String(FlagMetricsAddr, "METRICS_ADDR", ""),
+		BlobSidecar:               src.String(FlagBlobSidecar, "BLOB_SIDECAR", ee.BlobSidecarFull),
+		Mode:                      src.String(FlagMode, "MODE", watch.ModeBuild),
+		WatchAddressesList:        src.String(FlagWatchAddresses, "WATCH_ADDRESSES", ""),
+		BlobSeed:                  src.String(FlagBlobSeed, "BLOB_SEED", ""),
+		BlobDataPath:              src.String(FlagBlobDataPath, "BLOB_DATA_PATH", ""),
+		MaxConcurrentBundles:      src.Uint(FlagMaxConcurrentBundles, "MAX_CONCURRENT_BUNDLES", 0),
+		BundleOverflowPolicy:      src.String(FlagBundleOverflowPolicy, "BUNDLE_OVERFLOW_POLICY", ee.BundleOverflowQueue),
+		BroadcastDelay:            c.Duration(FlagBroadcastDelay),
+		MinDeposit:                src.Float64(FlagMinDeposit, "MIN_DEPOSIT", 0),
+		TopUpAmount:               src.Float64(FlagTopUpAmount, "TOP_UP_AMOUNT", 0),
+		DepositBlocksPerWindow:    src.Uint64(FlagDepositBlocksPerWindow, "DEPOSIT_BLOCKS_PER_WINDOW", bb.DefaultBlocksPerWindow),
+		FundingPrivateKey:         src.String(FlagFundingPrivateKey, "FUNDING_PRIVATE_KEY", ""),
+		RebalanceInterval:         c.Duration(FlagRebalanceInterval),
+		InclusionWebhookURL:       src.String(FlagInclusionWebhookURL, "INCLUSION_WEBHOOK_URL", ""),
+		InclusionWebhookTimeout:   c.Duration(FlagInclusionWebhookTimeout),
+		InclusionWebhookAttempts:  src.Uint(FlagInclusionWebhookAttempts, "INCLUSION_WEBHOOK_ATTEMPTS", bids.DefaultWebhookAttempts),
+		SilentRejectionWebhookURL: src.String(FlagSilentRejectionWebhookURL, "SILENT_REJECTION_WEBHOOK_URL", ""),
+		SendIntervalBlocks:        src.Uint64(FlagSendIntervalBlocks, "SEND_INTERVAL_BLOCKS", 0),
+		SendIntervalSeconds:       src.Float64(FlagSendIntervalSeconds, "SEND_INTERVAL_SECONDS", 0),
+		StaleBidPolicy:            src.String(FlagStaleBidPolicy, "STALE_BID_POLICY", bb.StaleBidSkip),
+		KeystorePath:              src.String(FlagKeystorePath, "KEYSTORE_PATH", ""),
+		KZGTrustedSetup:           src.String(FlagKZGTrustedSetup, "KZG_TRUSTED_SETUP", ""),
+		PrivateKeys:               src.String(FlagPrivateKeys, "PRIVATE_KEYS", ""),
+		WalletModes:               src.String(FlagWalletModes, "WALLET_MODES", ""),
+		BlobRampEnabled:           src.Bool(FlagBlobRamp, "BLOB_RAMP", false),
+		BlobRampMax:               src.Uint(FlagBlobRampMax, "BLOB_RAMP_MAX", 6),
+		BlobRampStepBlocks:        src.Uint64(FlagBlobRampStepBlocks, "BLOB_RAMP_STEP_BLOCKS", 10),
+		BlobRampSchedule:          src.String(FlagBlobRampSchedule, "BLOB_RAMP_SCHEDULE", ""),
+		BidAllowReverts:           src.Bool(FlagBidAllowReverts, "BID_ALLOW_REVERTS", false),
+		WSEndpointSet:             c.IsSet(FlagWsEndpoint),
+		DecayDurationSet:          c.IsSet(FlagDecayDurationMs),
+		RunDurationSet:            c.IsSet(FlagRunDurationMinutes),
+		MaxRuntimeSet:             c.IsSet(FlagMaxRuntime),
+		DrainTimeoutSet:           c.IsSet(FlagDrainTimeout),
+		NonceCheckIntervalSet:     c.IsSet(FlagNonceCheckInterval),
+		BackrunData:               src.String(FlagBackrunData, "BACKRUN_DATA", ""),
+		KeystorePassword:          src.String(FlagKeystorePassword, "KEYSTORE_PASSWORD", ""),
+		BidRetryableCodes:         src.String(FlagBidRetryableCodes, "BID_RETRYABLE_CODES", ""),
+		ExitOnCriteria:            src.String(FlagExitOnCriteria, "EXIT_ON_CRITERIA", ""),
+		TxMaxLifetimeSetting:      src.String(FlagTxMaxLifetime, "TX_MAX_LIFETIME", ""),
+		SelfTest:                  c.Bool(FlagSelfTest),
+		SelfTestMaxBidAmount:      c.Float64(FlagSelfTestMaxBidAmount),
+		Rebalance: ee.RebalanceConfig{
+			MinBalance: bb.EthToWei(src.Float64(FlagRebalanceMinBalance, "REBALANCE_MIN_BALANCE", 0)),
+			TopUp:      bb.EthToWei(src.Float64(FlagRebalanceTopUpAmount, "REBALANCE_TOP_UP_AMOUNT", 0)),
+			MaxTotal:   bb.EthToWei(src.Float64(FlagRebalanceMaxTotal, "REBALANCE_MAX_TOTAL", 0)),
+			Cooldown:   c.Duration(FlagRebalanceCooldown),
+		},
+		BidRetry: bb.BidRetryPolicy{
+			Attempts: int(src.Uint(FlagBidRetryAttempts, "BID_RETRY_ATTEMPTS", 3)),
+			Backoff:  c.Duration(FlagBidRetryBackoff),
+		},
+	}
+	cfg.HTTPUserAgent = src.String(FlagHTTPUserAgent, "HTTP_USER_AGENT", fmt.Sprintf("%s/%s", cfg.AppName, cfg.Version))
+	return cfg
+}
+
+// applyConfigFile sets each flag from the config file unless the flag was given on
+// the command line or through its env var, so flags and env vars override the file.
+func applyConfigFile(c *cli.Context, cfg *config.File) error {
+	values := map[string]string{
+		FlagServerAddress:      cfg.ServerAddress,
+		FlagRpcEndpoint:        cfg.RPCEndpoint,
+		FlagWsEndpoint:         cfg.WSEndpoint,
+		FlagPrivateKey:         cfg.PrivateKey,
+		FlagBidProfilesFile:    cfg.BidProfilesFile,
+		FlagBidProfileSelector: cfg.BidProfileSelector,
+		FlagCommitmentsFile:    cfg.CommitmentsFile,
+		FlagLogFile:            cfg.LogFile,
+	}
+	if cfg.UsePayload != nil {
+		values[FlagUsePayload] = strconv.FormatBool(*cfg.UsePayload)
+	}
+	if cfg.Offset != nil {
+		values[FlagOffset] = strconv.FormatUint(*cfg.Offset, 10)
+	}
+	if cfg.BidAmount != nil {
+		values[FlagBidAmount] = strconv.FormatFloat(*cfg.BidAmount, 'f', -1, 64)
+	}
+	if cfg.BidAmountStdDevPercentage != nil {
+		values[FlagBidAmountStdDevPercentage] = strconv.FormatFloat(*cfg.BidAmountStdDevPercentage, 'f', -1, 64)
+	}
+	if cfg.NumBlob != nil {
+		values[FlagNumBlob] = strconv.FormatUint(uint64(*cfg.NumBlob), 10)
+	}
+	if cfg.PriorityFee != nil {
+		values[FlagPriorityFee] = strconv.FormatUint(*cfg.PriorityFee, 10)
+	}
+	if cfg.DefaultTimeout != nil {
+		values[FlagDefaultTimeout] = strconv.FormatUint(uint64(*cfg.DefaultTimeout), 10)
+	}
+	if cfg.RunDurationMinutes != nil {
+		values[FlagRunDurationMinutes] = strconv.FormatUint(uint64(*cfg.RunDurationMinutes), 10)
+	}
+	if cfg.Backrun != nil {
+		values[FlagBackrunTo] = cfg.Backrun.To
+		values[FlagBackrunData] = cfg.Backrun.Data
+	}
+
+	for name, value := range values {
+		if value == "" || c.IsSet(name) {
+			continue
+		}
+		if err := c.Set(name, value); err != nil {
+			return fmt.Errorf("invalid config file value for %s: %w", name, err)
+		}
+	}
+	return nil
+}
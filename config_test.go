@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/primev/preconf_blob_bidder/internal/config"
+	"github.com/primev/preconf_blob_bidder/internal/shutdown"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server_address: file:13524
+ws_endpoint: wss://file
+offset: 5
+`), 0o600))
+	t.Setenv("OFFSET", "7")
+
+	// Flags beat env vars, which beat the config file, which beats the defaults
+	var cfg *config.Config
+	app := &cli.App{
+		Flags: newApp(shutdown.NewRegistry()).Flags,
+		Action: func(c *cli.Context) error {
+			file, err := config.LoadFile(path)
+			require.NoError(t, err)
+			require.NoError(t, applyConfigFile(c, file))
+			cfg = loadConfig(c)
+			return nil
+		},
+	}
+	require.NoError(t, app.Run([]string{"biddercli", "--" + FlagWsEndpoint, "wss://flag"}))
+	require.Equal(t, "file:13524", cfg.ServerAddress)
+	require.Equal(t, "wss://flag", cfg.WSEndpoint)
+	require.True(t, cfg.WSEndpointSet)
+	require.Equal(t, uint64(7), cfg.Offset)
+	require.Equal(t, "preconf_bidder/0.8.0", cfg.HTTPUserAgent)
+}
+
+func TestConfigReportsEveryProblem(t *testing.T) {
+	closers := shutdown.NewRegistry()
+	defer closers.Close(closeTimeout)
+	err := newApp(closers).Run([]string{"biddercli",
+		"--" + FlagBlockTime, "12s",
+		"--" + FlagTxPerBlock, "0",
+		"--" + FlagBidMode, "bogus",
+		"--" + FlagDrainSignal, "nope",
+		"--" + FlagOffsetDecayPolicy, "bogus",
+		"--" + FlagProposerAllowlist, "allowlist.txt",
+		"--" + FlagWsEndpoint, "https://node",
+		"--" + FlagBlobRamp,
+		"--" + FlagBlobRampSchedule, "x",
+	})
+	require.ErrorContains(t, err, "TX_PER_BLOCK must be at least 1")
+	require.ErrorContains(t, err, `unknown bid mode "bogus"`)
+	require.ErrorContains(t, err, "invalid DRAIN_SIGNAL")
+	require.ErrorContains(t, err, "invalid OFFSET_DECAY_POLICY")
+	require.ErrorContains(t, err, "PROPOSER_ALLOWLIST requires BEACON_ENDPOINT")
+	require.ErrorContains(t, err, "invalid WS_ENDPOINT")
+	require.ErrorContains(t, err, "invalid BLOB_RAMP_SCHEDULE")
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/params"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/demo"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FlagDemo                  = "demo"
+	FlagDemoBlockTime         = "block-time"
+	FlagDemoCommitLatency     = "commit-latency"
+	FlagDemoCommitProbability = "commit-probability"
+	FlagDemoWsEndpoint        = "demo-ws-endpoint"
+)
+
+// demoCommand returns the demo subcommand, which runs the bot against an
+// in-process fake bidder node and, unless a WebSocket endpoint is given, a
+// simulated chain, so it works with no external dependencies.
+//
+// Parameters:
+// - run: The root action, run once the demo environment is up.
+func demoCommand(run cli.ActionFunc) *cli.Command {
+	return &cli.Command{
+		Name:  "demo",
+		Usage: "Run the bot against a fake bidder node and a simulated chain, with no external dependencies",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  FlagDemoBlockTime,
+				Usage: "How often the simulated chain produces a block",
+				Value: 3 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  FlagDemoCommitLatency,
+				Usage: "Delay before the fake bidder node answers a bid with commitments",
+				Value: 200 * time.Millisecond,
+			},
+			&cli.Float64Flag{
+				Name:  FlagDemoCommitProbability,
+				Usage: "Probability in [0, 1] that a bid gets a commitment",
+				Value: 0.8,
+			},
+			&cli.StringFlag{
+				Name:    FlagDemoWsEndpoint,
+				Usage:   "Follow a real chain over WebSocket instead of the simulated one (requires PRIVATE_KEY)",
+				EnvVars: []string{"DEMO_WS_ENDPOINT"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			probability := c.Float64(FlagDemoCommitProbability)
+			if probability < 0 || probability > 1 {
+				return fmt.Errorf("--%s must be between 0 and 1", FlagDemoCommitProbability)
+			}
+
+			settings := map[string]string{
+				FlagDemo:       "true",
+				FlagUsePayload: "true",
+			}
+
+			// Bids carry the raw transaction, which the fake bidder node submits
+			// to the simulated chain so that inclusion is tracked as usual
+			var onBid func(*pb.Bid)
+			if wsEndpoint := c.String(FlagDemoWsEndpoint); wsEndpoint != "" {
+				settings[FlagWsEndpoint] = wsEndpoint
+			} else {
+				chain, err := demo.StartChain(c.Duration(FlagDemoBlockTime))
+				if err != nil {
+					return fmt.Errorf("failed to start simulated chain: %w", err)
+				}
+				defer chain.Close()
+
+				settings[FlagWsEndpoint] = chain.URL
+				settings[FlagPrivateKey] = chain.PrivateKeyHex()
+				// The fresh chain is too short to detect its block time from
+				if !c.IsSet(FlagBlockTime) {
+					settings[FlagBlockTime] = c.Duration(FlagDemoBlockTime).String()
+				}
+				// The simulated chain only includes transactions tipping at least 1 gwei.
+				// A floored base fee percentage tips the same for transfers and blobs,
+				// unlike PRIORITY_FEE which blob transactions take in gwei.
+				if !c.IsSet(FlagTipAsBaseFeePct) && os.Getenv("TIP_AS_BASE_FEE_PCT") == "" {
+					settings[FlagTipAsBaseFeePct] = "10"
+					settings[FlagTipFloorWei] = strconv.FormatInt(params.GWei, 10)
+				}
+				onBid = func(bid *pb.Bid) {
+					for _, rawTx := range bid.RawTransactions {
+						raw, err := hex.DecodeString(rawTx)
+						if err != nil {
+							slog.Debug("Demo bid has an undecodable transaction", "error", err)
+							continue
+						}
+						if err := chain.SendRawTransaction(raw); err != nil {
+							slog.Debug("Demo chain rejected transaction", "error", err)
+						}
+					}
+				}
+			}
+
+			server, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{
+				Latency:           c.Duration(FlagDemoCommitLatency),
+				CommitProbability: probability,
+				Seed:              time.Now().UnixNano(),
+				OnBid:             onBid,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to start fake bidder node: %w", err)
+			}
+			defer server.Close()
+			settings[FlagServerAddress] = server.Addr()
+
+			for name, value := range settings {
+				if err := c.Set(name, value); err != nil {
+					return fmt.Errorf("failed to set --%s for demo: %w", name, err)
+				}
+			}
+			return run(c)
+		},
+	}
+}
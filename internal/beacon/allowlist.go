@@ -0,0 +1,147 @@
+package beacon
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EligibleBlock decides whether the bot should bid for the block targeted from a header.
+type EligibleBlock interface {
+	Eligible(ctx context.Context, header *types.Header, offset uint64) bool
+}
+
+// ProposerAllowlist is an EligibleBlock that only allows bidding when the proposer
+// of the target slot is in a configured allowlist of validator pubkeys or indices.
+// When the beacon node is unavailable it defaults to bidding.
+type ProposerAllowlist struct {
+	api     API
+	allowed map[string]struct{}
+
+	mu      sync.Mutex
+	genesis uint64
+	duties  map[uint64][]ProposerDuty // epoch -> duties
+}
+
+// NewProposerAllowlist creates a ProposerAllowlist.
+//
+// Parameters:
+// - api: The beacon API used to look up genesis time and proposer duties.
+// - allowlist: Validator pubkeys (0x-prefixed) or validator indices to bid on.
+//
+// Returns:
+// - A pointer to a ProposerAllowlist.
+func NewProposerAllowlist(api API, allowlist []string) *ProposerAllowlist {
+	allowed := make(map[string]struct{}, len(allowlist))
+	for _, entry := range allowlist {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" {
+			allowed[entry] = struct{}{}
+		}
+	}
+	return &ProposerAllowlist{
+		api:     api,
+		allowed: allowed,
+		duties:  make(map[uint64][]ProposerDuty),
+	}
+}
+
+// Eligible reports whether the proposer of the slot targeted by header+offset is allowlisted.
+func (a *ProposerAllowlist) Eligible(ctx context.Context, header *types.Header, offset uint64) bool {
+	slot, err := a.targetSlot(ctx, header.Time, offset)
+	if err != nil {
+		slog.Warn("Beacon node unavailable, bidding without proposer check",
+			"error", err,
+			"blockNumber", header.Number.Uint64(),
+		)
+		return true
+	}
+
+	duty, err := a.dutyForSlot(ctx, slot)
+	if err != nil {
+		slog.Warn("Failed to fetch proposer duties, bidding without proposer check",
+			"error", err,
+			"slot", slot,
+		)
+		return true
+	}
+	if duty == nil {
+		slog.Warn("No proposer duty found for slot, bidding without proposer check",
+			"slot", slot,
+		)
+		return true
+	}
+
+	_, pubkeyAllowed := a.allowed[strings.ToLower(duty.Pubkey)]
+	_, indexAllowed := a.allowed[duty.ValidatorIndex]
+	if !pubkeyAllowed && !indexAllowed {
+		slog.Info("Proposer not in allowlist, skipping block",
+			"slot", slot,
+			"validatorIndex", duty.ValidatorIndex,
+			"pubkey", duty.Pubkey,
+		)
+		return false
+	}
+	return true
+}
+
+// targetSlot converts a header timestamp and block offset into the target beacon slot.
+func (a *ProposerAllowlist) targetSlot(ctx context.Context, headerTime, offset uint64) (uint64, error) {
+	a.mu.Lock()
+	genesis := a.genesis
+	a.mu.Unlock()
+
+	if genesis == 0 {
+		g, err := a.api.GenesisTime(ctx)
+		if err != nil {
+			return 0, err
+		}
+		a.mu.Lock()
+		a.genesis = g
+		a.mu.Unlock()
+		genesis = g
+	}
+
+	var slot uint64
+	if headerTime > genesis {
+		slot = (headerTime - genesis) / SecondsPerSlot
+	}
+	return slot + offset, nil
+}
+
+// dutyForSlot returns the proposer duty for a slot, fetching and caching the epoch's duties.
+func (a *ProposerAllowlist) dutyForSlot(ctx context.Context, slot uint64) (*ProposerDuty, error) {
+	epoch := slot / SlotsPerEpoch
+
+	a.mu.Lock()
+	duties, ok := a.duties[epoch]
+	a.mu.Unlock()
+
+	if !ok {
+		fetched, err := a.api.ProposerDuties(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+		a.mu.Lock()
+		// Only keep the current and next epochs to bound the cache
+		for e := range a.duties {
+			if e+1 < epoch {
+				delete(a.duties, e)
+			}
+		}
+		a.duties[epoch] = fetched
+		a.mu.Unlock()
+		duties = fetched
+	}
+
+	for i := range duties {
+		if duties[i].Slot == strconv.FormatUint(slot, 10) {
+			return &duties[i], nil
+		}
+	}
+	return nil, nil
+}
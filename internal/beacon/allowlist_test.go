@@ -0,0 +1,103 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBeacon is a fake beacon API serving a fixed proposer lookahead.
+type mockBeacon struct {
+	genesis     uint64
+	duties      map[uint64][]ProposerDuty
+	err         error
+	dutyFetches int
+}
+
+func (m *mockBeacon) GenesisTime(ctx context.Context) (uint64, error) {
+	return m.genesis, m.err
+}
+
+func (m *mockBeacon) ProposerDuties(ctx context.Context, epoch uint64) ([]ProposerDuty, error) {
+	m.dutyFetches++
+	return m.duties[epoch], m.err
+}
+
+func TestProposerAllowlistEligible(t *testing.T) {
+	api := &mockBeacon{
+		genesis: 1000,
+		duties: map[uint64][]ProposerDuty{
+			0: {
+				{Pubkey: "0xAAAA", ValidatorIndex: "10", Slot: "1"},
+				{Pubkey: "0xbbbb", ValidatorIndex: "11", Slot: "2"},
+				{Pubkey: "0xcccc", ValidatorIndex: "12", Slot: "3"},
+			},
+		},
+	}
+	allowlist := NewProposerAllowlist(api, []string{"0xaaaa", "12"})
+
+	// Header at slot 0, targeting slots 1..3 through the offset
+	header := &types.Header{Number: big.NewInt(100), Time: 1000}
+
+	require.True(t, allowlist.Eligible(context.Background(), header, 1), "pubkey match is eligible")
+	require.False(t, allowlist.Eligible(context.Background(), header, 2), "unlisted proposer is not eligible")
+	require.True(t, allowlist.Eligible(context.Background(), header, 3), "validator index match is eligible")
+	require.Equal(t, 1, api.dutyFetches, "duties are cached per epoch")
+}
+
+func TestProposerAllowlistBeaconUnavailable(t *testing.T) {
+	api := &mockBeacon{err: errors.New("connection refused")}
+	allowlist := NewProposerAllowlist(api, []string{"0xaaaa"})
+
+	header := &types.Header{Number: big.NewInt(100), Time: 1000}
+	require.True(t, allowlist.Eligible(context.Background(), header, 1))
+}
+
+func TestClientProposerDuties(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/beacon/genesis":
+			_, _ = w.Write([]byte(`{"data":{"genesis_time":"1695902400"}}`))
+		case "/eth/v1/validator/duties/proposer/5":
+			_, _ = w.Write([]byte(`{"data":[{"pubkey":"0xaaaa","validator_index":"7","slot":"160"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+
+	genesis, err := client.GenesisTime(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1695902400), genesis)
+
+	duties, err := client.ProposerDuties(context.Background(), 5)
+	require.NoError(t, err)
+	require.Equal(t, []ProposerDuty{{Pubkey: "0xaaaa", ValidatorIndex: "7", Slot: "160"}}, duties)
+
+	_, err = client.ProposerDuties(context.Background(), 6)
+	require.Error(t, err)
+}
+
+func TestClientUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		_, _ = w.Write([]byte(`{"data":{"genesis_time":"1695902400"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+	client.SetUserAgent("preconf_bidder/1.2.3")
+	_, err := client.GenesisTime(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "preconf_bidder/1.2.3", userAgent)
+}
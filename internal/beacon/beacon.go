@@ -0,0 +1,120 @@
+// Package beacon provides a minimal beacon node API client for looking up
+// upcoming block proposers, and an allowlist that gates bidding on them.
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SecondsPerSlot is the duration of a beacon chain slot in seconds.
+	SecondsPerSlot = 12
+	// SlotsPerEpoch is the number of slots in a beacon chain epoch.
+	SlotsPerEpoch = 32
+)
+
+// API defines the beacon node endpoints used for proposer lookahead.
+type API interface {
+	GenesisTime(ctx context.Context) (uint64, error)
+	ProposerDuties(ctx context.Context, epoch uint64) ([]ProposerDuty, error)
+}
+
+// ProposerDuty is a single entry of the beacon proposer duties response.
+type ProposerDuty struct {
+	Pubkey         string `json:"pubkey"`
+	ValidatorIndex string `json:"validator_index"`
+	Slot           string `json:"slot"`
+}
+
+// Client is an HTTP client for the standard beacon node REST API.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient creates a beacon API client for the given endpoint.
+//
+// Parameters:
+// - endpoint: The base URL of the beacon node, e.g. http://localhost:5052.
+// - timeout: The timeout applied to each request.
+//
+// Returns:
+// - A pointer to a Client.
+func NewClient(endpoint string, timeout time.Duration) *Client {
+	return &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// SetUserAgent sets the User-Agent header of the client's requests, Go's
+// default if empty. It must be called before the client is used.
+func (c *Client) SetUserAgent(userAgent string) {
+	c.userAgent = userAgent
+}
+
+// GenesisTime returns the beacon chain genesis timestamp in seconds.
+func (c *Client) GenesisTime(ctx context.Context) (uint64, error) {
+	var resp struct {
+		Data struct {
+			GenesisTime string `json:"genesis_time"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/eth/v1/beacon/genesis", &resp); err != nil {
+		return 0, err
+	}
+	genesis, err := strconv.ParseUint(resp.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid genesis time %q: %w", resp.Data.GenesisTime, err)
+	}
+	return genesis, nil
+}
+
+// ProposerDuties returns the proposer duties for every slot in the given epoch.
+func (c *Client) ProposerDuties(ctx context.Context, epoch uint64) ([]ProposerDuty, error) {
+	var resp struct {
+		Data []ProposerDuty `json:"data"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// get performs a GET request against the beacon node and decodes the JSON response into out.
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("beacon request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read beacon response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("beacon request %s returned status %d: %s", path, resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode beacon response: %w", err)
+	}
+	return nil
+}
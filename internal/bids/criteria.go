@@ -0,0 +1,216 @@
+package bids
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// ExitCodeCriteriaUnmet is the process exit code of a session that didn't meet its
+// success criteria. Exit code 1 is left to application errors.
+const ExitCodeCriteriaUnmet = 2
+
+// Success criteria understood by ParseCriteria.
+const (
+	CriterionMinBids           = "min_bids"            // Bids sent.
+	CriterionMinCommitments    = "min_commitments"     // Commitments received.
+	CriterionMinCommitmentRate = "min_commitment_rate" // Commitments received per bid sent.
+	CriterionMinInclusionRate  = "min_inclusion_rate"  // Included bids per bid resolved as included or missed.
+)
+
+// Outcome counts what a session achieved.
+type Outcome struct {
+	Bids        uint64
+	Commitments uint64
+	Resolved    uint64 // Bids resolved as included or missed.
+	Included    uint64
+	Won         uint64 // Bids sent that a provider committed to.
+	Lost        uint64 // Bids sent that no provider committed to.
+	MultiWon    uint64 // Won bids that more than one provider committed to.
+}
+
+// WinRate returns the fraction of bids answered by the bidder node that a
+// provider committed to, or 0 when none was answered.
+func (o Outcome) WinRate() float64 {
+	if o.Won+o.Lost == 0 {
+		return 0
+	}
+	return float64(o.Won) / float64(o.Won+o.Lost)
+}
+
+// CommitmentRate returns the commitments received per bid sent, or 0 without bids.
+func (o Outcome) CommitmentRate() float64 {
+	if o.Bids == 0 {
+		return 0
+	}
+	return float64(o.Commitments) / float64(o.Bids)
+}
+
+// InclusionRate returns the fraction of resolved bids that were included, or 0
+// when no bid was resolved.
+func (o Outcome) InclusionRate() float64 {
+	if o.Resolved == 0 {
+		return 0
+	}
+	return float64(o.Included) / float64(o.Resolved)
+}
+
+// Session accumulates the Outcome of a session. It is safe for concurrent use.
+type Session struct {
+	mu      sync.Mutex
+	outcome Outcome
+}
+
+// NewSession creates a Session with nothing recorded.
+func NewSession() *Session {
+	return &Session{}
+}
+
+// RecordBid counts a sent bid.
+func (s *Session) RecordBid() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcome.Bids++
+}
+
+// RecordCommitment counts a received commitment.
+func (s *Session) RecordCommitment() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcome.Commitments++
+}
+
+// RecordBidResult counts a bid the bidder node answered as won if a provider
+// committed to it, and lost otherwise.
+//
+// Parameters:
+// - providers: The number of distinct providers that committed to the bid.
+func (s *Session) RecordBidResult(providers int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case providers == 0:
+		s.outcome.Lost++
+	case providers == 1:
+		s.outcome.Won++
+	default:
+		s.outcome.Won++
+		s.outcome.MultiWon++
+	}
+}
+
+// RecordResolved counts a resolved bid. Bids whose target block couldn't be
+// checked don't count towards the inclusion rate.
+func (s *Session) RecordResolved(record *api.BidRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch record.Status {
+	case api.BidStatusIncluded:
+		s.outcome.Resolved++
+		s.outcome.Included++
+	case api.BidStatusMissed:
+		s.outcome.Resolved++
+	}
+}
+
+// Outcome returns what the session achieved so far.
+func (s *Session) Outcome() Outcome {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.outcome
+}
+
+// Criteria are the minimums a session must reach to succeed, keyed by criterion
+// name. Empty Criteria are always met.
+type Criteria map[string]float64
+
+// ParseCriteria parses comma-separated name=minimum pairs such as
+// "min_commitments=1,min_inclusion_rate=0.5". An empty spec has no criteria.
+func ParseCriteria(spec string) (Criteria, error) {
+	criteria := make(Criteria)
+	if strings.TrimSpace(spec) == "" {
+		return criteria, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid criterion %q: want name=minimum", pair)
+		}
+		name = strings.TrimSpace(name)
+		switch name {
+		case CriterionMinBids, CriterionMinCommitments, CriterionMinCommitmentRate, CriterionMinInclusionRate:
+		default:
+			return nil, fmt.Errorf("unknown criterion %q", name)
+		}
+		min, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || min < 0 {
+			return nil, fmt.Errorf("invalid minimum %q for criterion %s", value, name)
+		}
+		criteria[name] = min
+	}
+	return criteria, nil
+}
+
+// Unmet returns the criteria the outcome falls short of, sorted by name and
+// described as "name: got X, want at least Y".
+func (c Criteria) Unmet(o Outcome) []string {
+	var unmet []string
+	for name, min := range c {
+		var got float64
+		switch name {
+		case CriterionMinBids:
+			got = float64(o.Bids)
+		case CriterionMinCommitments:
+			got = float64(o.Commitments)
+		case CriterionMinCommitmentRate:
+			got = o.CommitmentRate()
+		case CriterionMinInclusionRate:
+			got = o.InclusionRate()
+		}
+		if got < min {
+			unmet = append(unmet, fmt.Sprintf("%s: got %g, want at least %g", name, got, min))
+		}
+	}
+	sort.Strings(unmet)
+	return unmet
+}
+
+// UnmetCriteriaError is returned by Check when the outcome falls short of the criteria.
+type UnmetCriteriaError struct {
+	Unmet []string // As returned by Criteria.Unmet.
+}
+
+func (e *UnmetCriteriaError) Error() string {
+	return "session did not meet its success criteria: " + strings.Join(e.Unmet, "; ")
+}
+
+// Check returns an *UnmetCriteriaError when the outcome falls short of any criterion.
+func (c Criteria) Check(o Outcome) error {
+	if unmet := c.Unmet(o); len(unmet) > 0 {
+		return &UnmetCriteriaError{Unmet: unmet}
+	}
+	return nil
+}
+
+// ExitCode returns 0 when the outcome meets every criterion, and
+// ExitCodeCriteriaUnmet otherwise.
+func (c Criteria) ExitCode(o Outcome) int {
+	if c.Check(o) != nil {
+		return ExitCodeCriteriaUnmet
+	}
+	return 0
+}
+
+// String returns the criteria in the format taken by ParseCriteria.
+func (c Criteria) String() string {
+	pairs := make([]string, 0, len(c))
+	for name, min := range c {
+		pairs = append(pairs, fmt.Sprintf("%s=%g", name, min))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
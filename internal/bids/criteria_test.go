@@ -0,0 +1,61 @@
+package bids
+
+import (
+	"testing"
+
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCriteriaExitCode(t *testing.T) {
+	session := NewSession()
+	for i := 0; i < 4; i++ {
+		session.RecordBid()
+	}
+	session.RecordCommitment()
+	session.RecordResolved(&api.BidRecord{Status: api.BidStatusIncluded})
+	session.RecordResolved(&api.BidRecord{Status: api.BidStatusMissed})
+	session.RecordResolved(&api.BidRecord{Status: api.BidStatusUnknown})
+	outcome := session.Outcome()
+	require.Equal(t, Outcome{Bids: 4, Commitments: 1, Resolved: 2, Included: 1}, outcome)
+
+	met, err := ParseCriteria("min_commitments=1, min_inclusion_rate=0.5")
+	require.NoError(t, err)
+	require.Empty(t, met.Unmet(outcome))
+	require.Equal(t, 0, met.ExitCode(outcome))
+
+	unmet, err := ParseCriteria("min_bids=1,min_inclusion_rate=0.75")
+	require.NoError(t, err)
+	require.Equal(t, []string{"min_inclusion_rate: got 0.5, want at least 0.75"}, unmet.Unmet(outcome))
+	require.Equal(t, ExitCodeCriteriaUnmet, unmet.ExitCode(outcome))
+	var unmetErr *UnmetCriteriaError
+	require.ErrorAs(t, unmet.Check(outcome), &unmetErr)
+
+	// Without criteria every session succeeds
+	none, err := ParseCriteria("")
+	require.NoError(t, err)
+	require.Equal(t, 0, none.ExitCode(Outcome{}))
+}
+
+func TestParseCriteriaRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"min_commitments", "max_bids=1", "min_bids=-1", "min_inclusion_rate=high"} {
+		_, err := ParseCriteria(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestSessionCountsBidResults(t *testing.T) {
+	session := NewSession()
+	require.Zero(t, session.Outcome().WinRate())
+
+	session.RecordBidResult(1)
+	session.RecordBidResult(0)
+	session.RecordBidResult(2)
+	session.RecordBidResult(1)
+
+	outcome := session.Outcome()
+	require.Equal(t, uint64(3), outcome.Won)
+	require.Equal(t, uint64(1), outcome.Lost)
+	require.Equal(t, uint64(1), outcome.MultiWon)
+	require.InDelta(t, 0.75, outcome.WinRate(), 1e-9)
+}
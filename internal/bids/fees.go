@@ -0,0 +1,197 @@
+package bids
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FeeTotals sums what the included transactions of one type paid on chain.
+type FeeTotals struct {
+	Txs         uint64
+	GasUsed     uint64
+	GasFeeWei   *big.Int // Gas used times effective gas price.
+	BlobGasUsed uint64
+	BlobFeeWei  *big.Int // Blob gas used times blob gas price.
+	BidWei      *big.Int // Amounts of the bids on the transactions for the block they were included in.
+}
+
+func newFeeTotals() *FeeTotals {
+	return &FeeTotals{GasFeeWei: new(big.Int), BlobFeeWei: new(big.Int), BidWei: new(big.Int)}
+}
+
+func (t *FeeTotals) add(other *FeeTotals) {
+	t.Txs += other.Txs
+	t.GasUsed += other.GasUsed
+	t.GasFeeWei.Add(t.GasFeeWei, other.GasFeeWei)
+	t.BlobGasUsed += other.BlobGasUsed
+	t.BlobFeeWei.Add(t.BlobFeeWei, other.BlobFeeWei)
+	t.BidWei.Add(t.BidWei, other.BidWei)
+}
+
+func (t *FeeTotals) copy() FeeTotals {
+	c := newFeeTotals()
+	c.add(t)
+	return *c
+}
+
+// AvgGasPriceWei returns the average effective gas price, or 0 without gas used.
+func (t FeeTotals) AvgGasPriceWei() *big.Int {
+	return quoOrZero(t.GasFeeWei, t.GasUsed)
+}
+
+// AvgBlobGasPriceWei returns the average blob gas price, or 0 without blob gas used.
+func (t FeeTotals) AvgBlobGasPriceWei() *big.Int {
+	return quoOrZero(t.BlobFeeWei, t.BlobGasUsed)
+}
+
+// FeesWei returns the gas and blob fees.
+func (t FeeTotals) FeesWei() *big.Int {
+	return new(big.Int).Add(t.GasFeeWei, t.BlobFeeWei)
+}
+
+// TotalCostWei returns the gas and blob fees plus the bid amounts.
+func (t FeeTotals) TotalCostWei() *big.Int {
+	return new(big.Int).Add(t.FeesWei(), t.BidWei)
+}
+
+// CostPerInclusionWei returns the total cost per included transaction, or 0 without any.
+func (t FeeTotals) CostPerInclusionWei() *big.Int {
+	return quoOrZero(t.TotalCostWei(), t.Txs)
+}
+
+func quoOrZero(x *big.Int, n uint64) *big.Int {
+	if n == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Quo(x, new(big.Int).SetUint64(n))
+}
+
+// FeeTracker accumulates the fees paid by our included transactions from their
+// receipts, by transaction type. It is safe for concurrent use.
+type FeeTracker struct {
+	mu      sync.Mutex
+	byType  map[string]*FeeTotals
+	order   []string
+	counted map[common.Hash]bool
+}
+
+// NewFeeTracker creates an empty FeeTracker.
+func NewFeeTracker() *FeeTracker {
+	return &FeeTracker{
+		byType:  make(map[string]*FeeTotals),
+		counted: make(map[common.Hash]bool),
+	}
+}
+
+// TxTypeLabel names a transaction type for stats and metrics.
+func TxTypeLabel(txType uint8) string {
+	switch txType {
+	case types.LegacyTxType:
+		return "legacy"
+	case types.AccessListTxType:
+		return "access_list"
+	case types.DynamicFeeTxType:
+		return "dynamic_fee"
+	case types.BlobTxType:
+		return "blob"
+	default:
+		return fmt.Sprintf("type_%d", txType)
+	}
+}
+
+// RecordInclusion adds the fees paid by an included transaction. A transaction is
+// only counted once.
+//
+// Parameters:
+// - tx: The included transaction.
+// - receipt: Its receipt.
+// - bidWei: The amount of the bid on the transaction for the block it was included in.
+//
+// Returns:
+// - The fees of the transaction, or nil if it was already counted.
+func (f *FeeTracker) RecordInclusion(tx *types.Transaction, receipt *types.Receipt, bidWei *big.Int) *FeeTotals {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.counted[tx.Hash()] {
+		return nil
+	}
+	f.counted[tx.Hash()] = true
+
+	fees := newFeeTotals()
+	fees.Txs = 1
+	fees.GasUsed = receipt.GasUsed
+	if receipt.EffectiveGasPrice != nil {
+		fees.GasFeeWei.Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	}
+	fees.BlobGasUsed = receipt.BlobGasUsed
+	if receipt.BlobGasPrice != nil {
+		fees.BlobFeeWei.Mul(new(big.Int).SetUint64(receipt.BlobGasUsed), receipt.BlobGasPrice)
+	}
+	if bidWei != nil {
+		fees.BidWei.Set(bidWei)
+	}
+
+	label := TxTypeLabel(tx.Type())
+	totals, ok := f.byType[label]
+	if !ok {
+		totals = newFeeTotals()
+		f.byType[label] = totals
+		f.order = append(f.order, label)
+	}
+	totals.add(fees)
+	return fees
+}
+
+// ByType returns the totals of each transaction type.
+func (f *FeeTracker) ByType() map[string]FeeTotals {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	byType := make(map[string]FeeTotals, len(f.byType))
+	for label, totals := range f.byType {
+		byType[label] = totals.copy()
+	}
+	return byType
+}
+
+// Total returns the totals across transaction types.
+func (f *FeeTracker) Total() FeeTotals {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	total := newFeeTotals()
+	for _, totals := range f.byType {
+		total.add(totals)
+	}
+	return *total
+}
+
+// LogSummary logs the fees paid by included transactions, per type and in total.
+func (f *FeeTracker) LogSummary() {
+	f.mu.Lock()
+	order := append([]string(nil), f.order...)
+	f.mu.Unlock()
+
+	byType := f.ByType()
+	for _, label := range order {
+		logFeeTotals("Inclusion fee summary", byType[label], "txType", label)
+	}
+	logFeeTotals("Inclusion cost summary", f.Total())
+}
+
+func logFeeTotals(msg string, t FeeTotals, attrs ...any) {
+	slog.Info(msg, append(attrs,
+		"includedTxs", t.Txs,
+		"gasUsed", t.GasUsed,
+		"gasFeeWei", t.GasFeeWei.String(),
+		"avgGasPriceWei", t.AvgGasPriceWei().String(),
+		"blobGasUsed", t.BlobGasUsed,
+		"blobFeeWei", t.BlobFeeWei.String(),
+		"avgBlobGasPriceWei", t.AvgBlobGasPriceWei().String(),
+		"bidWei", t.BidWei.String(),
+		"costPerInclusionWei", t.CostPerInclusionWei().String(),
+	)...)
+}
@@ -0,0 +1,57 @@
+package bids
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeTrackerTotalsByTxType(t *testing.T) {
+	fees := NewFeeTracker()
+
+	blobTx := types.NewTx(&types.BlobTx{Nonce: 1, BlobFeeCap: uint256.NewInt(10)})
+	blobReceipt := &types.Receipt{
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(100),
+		BlobGasUsed:       131072,
+		BlobGasPrice:      big.NewInt(3),
+	}
+	paid := fees.RecordInclusion(blobTx, blobReceipt, big.NewInt(5_000_000))
+	require.NotNil(t, paid)
+	require.Equal(t, int64(2_100_000), paid.GasFeeWei.Int64())
+	require.Equal(t, int64(393_216), paid.BlobFeeWei.Int64())
+
+	// A second bid resolving on the same transaction isn't counted again
+	require.Nil(t, fees.RecordInclusion(blobTx, blobReceipt, big.NewInt(5_000_000)))
+
+	for i, price := range []int64{100, 300} {
+		tx := types.NewTx(&types.DynamicFeeTx{Nonce: uint64(2 + i)})
+		receipt := &types.Receipt{GasUsed: 50000, EffectiveGasPrice: big.NewInt(price)}
+		require.NotNil(t, fees.RecordInclusion(tx, receipt, big.NewInt(1_000_000)))
+	}
+
+	byType := fees.ByType()
+	require.Len(t, byType, 2)
+
+	blob := byType["blob"]
+	require.Equal(t, uint64(1), blob.Txs)
+	require.Equal(t, uint64(131072), blob.BlobGasUsed)
+	require.Equal(t, int64(3), blob.AvgBlobGasPriceWei().Int64())
+	require.Equal(t, int64(2_100_000+393_216+5_000_000), blob.CostPerInclusionWei().Int64())
+
+	dynamic := byType["dynamic_fee"]
+	require.Equal(t, uint64(2), dynamic.Txs)
+	require.Equal(t, uint64(100000), dynamic.GasUsed)
+	require.Equal(t, int64(200), dynamic.AvgGasPriceWei().Int64())
+	require.Zero(t, dynamic.BlobGasUsed)
+	require.Zero(t, dynamic.AvgBlobGasPriceWei().Sign())
+
+	total := fees.Total()
+	require.Equal(t, uint64(3), total.Txs)
+	require.Equal(t, int64(2_100_000+393_216+20_000_000), total.FeesWei().Int64())
+	require.Equal(t, int64(2_100_000+393_216+20_000_000+7_000_000), total.TotalCostWei().Int64())
+	require.Equal(t, total.TotalCostWei().Int64()/3, total.CostPerInclusionWei().Int64())
+}
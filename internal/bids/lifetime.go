@@ -0,0 +1,60 @@
+package bids
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Lifetime bounds how long a transaction is tracked without being included, as
+// either a duration or a number of blocks. The zero Lifetime never expires.
+type Lifetime struct {
+	Duration time.Duration
+	Blocks   uint64
+}
+
+// ParseLifetime parses a duration such as "2m", or a plain number of blocks such as
+// "10". An empty string or "0" is the zero Lifetime.
+func ParseLifetime(s string) (Lifetime, error) {
+	if s == "" {
+		return Lifetime{}, nil
+	}
+	if blocks, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return Lifetime{Blocks: blocks}, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return Lifetime{}, fmt.Errorf("invalid transaction lifetime %q: want a duration such as 2m or a number of blocks", s)
+	}
+	return Lifetime{Duration: d}, nil
+}
+
+// IsZero reports whether the lifetime never expires.
+func (l Lifetime) IsZero() bool {
+	return l.Duration == 0 && l.Blocks == 0
+}
+
+// Expired reports whether a transaction first bid on for firstBlock at sentAt has
+// outlived the lifetime by block number at now.
+func (l Lifetime) Expired(firstBlock uint64, sentAt time.Time, number uint64, now time.Time) bool {
+	switch {
+	case l.Blocks > 0:
+		return number >= firstBlock+l.Blocks
+	case l.Duration > 0:
+		return now.Sub(sentAt) >= l.Duration
+	default:
+		return false
+	}
+}
+
+// String returns the lifetime as given to ParseLifetime.
+func (l Lifetime) String() string {
+	switch {
+	case l.Blocks > 0:
+		return strconv.FormatUint(l.Blocks, 10)
+	case l.Duration > 0:
+		return l.Duration.String()
+	default:
+		return "0"
+	}
+}
@@ -0,0 +1,145 @@
+package bids
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/primev/preconf_blob_bidder/api"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+)
+
+// Discrepancy kinds found by Reconcile.
+const (
+	DiscrepancyUnknownBid     = "unknown_bid"     // A commitment to a transaction with no bid record.
+	DiscrepancyBlockMismatch  = "block_mismatch"  // A commitment for a block the transaction has no bid record for.
+	DiscrepancyAmountMismatch = "amount_mismatch" // A commitment to a different amount than the bid record.
+)
+
+// amountTolerance is the relative difference between a recorded amount and a
+// committed one that is put down to float rounding of the recorded ETH amount.
+const amountTolerance = 1e-9
+
+// Discrepancy is a commitment that doesn't agree with the bid records.
+type Discrepancy struct {
+	Kind       string
+	Line       int            // Line of the commitment in the commitments file.
+	Commitment api.Commitment // The node's side.
+	Record     *api.BidRecord // The closest bid record, nil for an unknown bid.
+}
+
+// ReconcileReport is the outcome of Reconcile.
+type ReconcileReport struct {
+	Bids          int // Bid records read.
+	Commitments   int // Commitments in the window.
+	Matched       int // Commitments that agree with a bid record.
+	Discrepancies []Discrepancy
+}
+
+// Reconcile compares the commitments the bidder node returned with the bid
+// records written for the same bids. Each commitment received in the window is
+// matched to a bid record by transaction hash and block number, and its amount
+// compared with the recorded one. Bid records match whenever they were sent, so
+// commitments near the window's edges still find their bids.
+//
+// The bidder API has no query for the node's bid history, so bids the node never
+// received can't be told apart from bids that no provider committed to, and
+// aren't reported.
+//
+// Parameters:
+// - bids: The bid records JSONL file.
+// - commitments: The commitments JSONL file.
+// - from, to: The window of commitment receipt times, zero for unbounded.
+//
+// Returns:
+// - A ReconcileReport, or an error if either file can't be read or parsed.
+func Reconcile(bids, commitments io.Reader, from, to time.Time) (ReconcileReport, error) {
+	var report ReconcileReport
+
+	byHash := make(map[string][]*api.BidRecord)
+	err := scanJSONL(bids, func(line int, data []byte) error {
+		record := &api.BidRecord{}
+		if err := json.Unmarshal(data, record); err != nil {
+			return fmt.Errorf("bid records line %d: %w", line, err)
+		}
+		hash := normalizeHash(record.TxHash)
+		byHash[hash] = append(byHash[hash], record)
+		report.Bids++
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	err = scanJSONL(commitments, func(line int, data []byte) error {
+		var record api.CommitmentRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("commitments line %d: %w", line, err)
+		}
+		receivedAt := time.UnixMilli(record.ReceivedAt)
+		if (!from.IsZero() && receivedAt.Before(from)) || (!to.IsZero() && receivedAt.After(to)) {
+			return nil
+		}
+		report.Commitments++
+
+		commitment := record.Commitment
+		discrepancy := Discrepancy{Kind: DiscrepancyUnknownBid, Line: line, Commitment: commitment}
+		for _, hash := range commitment.TxHashes {
+			for _, bid := range byHash[normalizeHash(hash)] {
+				if bid.BlockNumber != uint64(commitment.BlockNumber) {
+					if discrepancy.Record == nil {
+						discrepancy.Kind, discrepancy.Record = DiscrepancyBlockMismatch, bid
+					}
+					continue
+				}
+				discrepancy.Kind, discrepancy.Record = DiscrepancyAmountMismatch, bid
+				if amountsAgree(bid.AmountEth, commitment.BidAmount) {
+					report.Matched++
+					return nil
+				}
+			}
+		}
+		report.Discrepancies = append(report.Discrepancies, discrepancy)
+		return nil
+	})
+	return report, err
+}
+
+// scanJSONL calls fn with each non-blank line of r and its line number.
+func scanJSONL(r io.Reader, fn func(line int, data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		if err := fn(line, scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// normalizeHash drops the 0x prefix and case, which differ between the bid
+// records and the hashes the node echoes back.
+func normalizeHash(hash string) string {
+	return strings.ToLower(strings.TrimPrefix(hash, "0x"))
+}
+
+// amountsAgree reports whether a recorded ETH amount matches a committed wei amount.
+func amountsAgree(amountEth float64, bidAmountWei string) bool {
+	wei, ok := new(big.Int).SetString(bidAmountWei, 10)
+	if !ok {
+		return false
+	}
+	committed := bb.WeiToEth(wei)
+	if committed == 0 {
+		return amountEth == 0
+	}
+	return math.Abs(amountEth-committed)/committed <= amountTolerance
+}
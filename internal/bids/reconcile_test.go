@@ -0,0 +1,87 @@
+package bids
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonl encodes records as JSONL.
+func jsonl(t *testing.T, records ...any) string {
+	t.Helper()
+	var b strings.Builder
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		require.NoError(t, err)
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func commitmentAt(receivedAt time.Time, txHash string, blockNumber int64, bidAmountWei string) api.CommitmentRecord {
+	return api.CommitmentRecord{
+		SchemaVersion: api.CommitmentSchemaVersion,
+		ReceivedAt:    receivedAt.UnixMilli(),
+		Commitment: api.Commitment{
+			TxHashes:    []string{txHash},
+			BidAmount:   bidAmountWei,
+			BlockNumber: blockNumber,
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bidRecords := jsonl(t,
+		api.BidRecord{TxHash: "0xAA01", BlockNumber: 100, AmountEth: 0.001, Status: api.BidStatusIncluded},
+		api.BidRecord{TxHash: "0xaa02", BlockNumber: 100, AmountEth: 0.002, Status: api.BidStatusMissed},
+		api.BidRecord{TxHash: "0xaa03", BlockNumber: 100, AmountEth: 0.003, Status: api.BidStatusMissed},
+		api.BidRecord{TxHash: "0xaa04", BlockNumber: 101, AmountEth: 0.004, Status: api.BidStatusMissed},
+	)
+	commitments := jsonl(t,
+		commitmentAt(start, "aa01", 100, "1000000000000000"),                    // Matches, hashes echoed without 0x
+		commitmentAt(start.Add(time.Second), "aa02", 100, "2500000000000000"),   // Amount mismatch
+		commitmentAt(start.Add(2*time.Second), "aa03", 102, "3000000000000000"), // Block mismatch
+		commitmentAt(start.Add(3*time.Second), "aa99", 100, "1000000000000000"), // Unknown bid
+		commitmentAt(start.Add(time.Hour), "aa98", 100, "1000000000000000"),     // Outside the window
+		commitmentAt(start.Add(-time.Hour), "0xaa04", 101, "4000000000000000"),  // Outside the window
+	)
+
+	report, err := Reconcile(strings.NewReader(bidRecords), strings.NewReader(commitments), start.Add(-time.Minute), start.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Bids)
+	require.Equal(t, 4, report.Commitments)
+	require.Equal(t, 1, report.Matched)
+	require.Len(t, report.Discrepancies, 3)
+
+	amount := report.Discrepancies[0]
+	require.Equal(t, DiscrepancyAmountMismatch, amount.Kind)
+	require.Equal(t, 2, amount.Line)
+	require.Equal(t, "0xaa02", amount.Record.TxHash)
+	require.Equal(t, "2500000000000000", amount.Commitment.BidAmount)
+
+	block := report.Discrepancies[1]
+	require.Equal(t, DiscrepancyBlockMismatch, block.Kind)
+	require.Equal(t, uint64(100), block.Record.BlockNumber)
+	require.Equal(t, int64(102), block.Commitment.BlockNumber)
+
+	unknown := report.Discrepancies[2]
+	require.Equal(t, DiscrepancyUnknownBid, unknown.Kind)
+	require.Nil(t, unknown.Record)
+	require.Equal(t, []string{"aa99"}, unknown.Commitment.TxHashes)
+
+	// Without a window every commitment is reconciled
+	report, err = Reconcile(strings.NewReader(bidRecords), strings.NewReader(commitments), time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 6, report.Commitments)
+	require.Equal(t, 2, report.Matched)
+	require.Len(t, report.Discrepancies, 4)
+
+	_, err = Reconcile(strings.NewReader("{\n"), strings.NewReader(""), time.Time{}, time.Time{})
+	require.ErrorContains(t, err, "bid records line 1")
+}
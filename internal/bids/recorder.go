@@ -0,0 +1,113 @@
+package bids
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// Recorder logs every resolved bid and, when configured with a file path,
+// appends each one as a line to a JSONL file.
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecorder creates a Recorder.
+//
+// Parameters:
+// - filePath: The JSONL file to append bid records to, or empty to only log them.
+//
+// Returns:
+// - A pointer to a Recorder, or an error if the file cannot be opened.
+func NewRecorder(filePath string) (*Recorder, error) {
+	r := &Recorder{}
+	if filePath == "" {
+		return r, nil
+	}
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bid records file: %w", err)
+	}
+	r.file = file
+	r.encoder = json.NewEncoder(file)
+	return r, nil
+}
+
+// Record logs the resolved bid and appends it to the bid records file if configured.
+func (r *Recorder) Record(record *api.BidRecord) error {
+	record.SchemaVersion = api.BidSchemaVersion
+	slog.Info("Bid resolved",
+		"txHash", record.TxHash,
+		"blockNumber", record.BlockNumber,
+		"status", record.Status,
+		"amountEth", record.AmountEth,
+		"profile", record.Profile,
+		"escalationLevel", record.EscalationLevel,
+		"decayStartSlotOffsetMs", record.DecayStartSlotOffsetMs,
+		"decayEndSlotOffsetMs", record.DecayEndSlotOffsetMs,
+		"strategy", record.Strategy,
+	)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	if err := r.encoder.Encode(record); err != nil {
+		slog.Error("Failed to write bid record", "err", err)
+		return fmt.Errorf("failed to write bid record: %w", err)
+	}
+	return nil
+}
+
+// Close syncs and closes the bid records file if one was opened.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	syncErr := r.file.Sync()
+	err := r.file.Close()
+	r.file = nil
+	if err == nil {
+		err = syncErr
+	}
+	return err
+}
+
+// NewBidStrategy describes how a bid was priced and shaped for its record.
+//
+// Parameters:
+// - pricer: How the amount was drawn.
+// - mode: The transaction mode of the bid's profile.
+// - numBlobs: Blobs per transaction, 0 for transfers.
+// - baseFee: Base fee of the header the bid was built on, nil before London.
+// - blobBaseFee: Blob base fee of the next block, nil before Cancun.
+// - guards: Guards that applied to the bid, possibly none.
+//
+// Returns:
+// - The strategy metadata, with unknown fees left empty.
+func NewBidStrategy(pricer, mode string, numBlobs int, baseFee, blobBaseFee *big.Int, guards []string) *api.BidStrategy {
+	s := &api.BidStrategy{
+		Pricer:   pricer,
+		Mode:     mode,
+		NumBlobs: numBlobs,
+		Guards:   append([]string{}, guards...),
+	}
+	if baseFee != nil {
+		s.BaseFeeWei = baseFee.String()
+	}
+	if blobBaseFee != nil {
+		s.BlobBaseFeeWei = blobBaseFee.String()
+	}
+	return s
+}
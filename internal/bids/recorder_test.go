@@ -0,0 +1,78 @@
+package bids
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderWritesStrategyMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bids.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	// A blob bid whose sampled amount escalated and then hit BID_MAX_ETH
+	strategy := NewBidStrategy("profile", "blob", 3, big.NewInt(7_000_000_000), big.NewInt(1),
+		[]string{api.GuardEscalated, api.GuardClampedMax})
+	require.NoError(t, recorder.Record(&api.BidRecord{
+		TxHash:          "0xabc",
+		BlockNumber:     100,
+		AmountEth:       0.002,
+		Profile:         "default",
+		EscalationLevel: 1,
+		Status:          api.BidStatusIncluded,
+		SentAt:          1_700_000_000_000,
+		ResolvedAt:      1_700_000_012_000,
+		Strategy:        strategy,
+	}))
+	// Without the option, records carry no strategy
+	require.NoError(t, recorder.Record(&api.BidRecord{TxHash: "0xdef", Status: api.BidStatusMissed}))
+	require.NoError(t, recorder.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var lines [][]byte
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	require.Len(t, lines, 2)
+
+	for _, line := range lines {
+		version, err := api.ValidateRecord("bid", line)
+		require.NoError(t, err, string(line))
+		require.Equal(t, api.BidSchemaVersion, version)
+	}
+
+	var with, without api.BidRecord
+	require.NoError(t, json.Unmarshal(lines[0], &with))
+	require.NoError(t, json.Unmarshal(lines[1], &without))
+	require.Equal(t, &api.BidStrategy{
+		Pricer:         "profile",
+		Mode:           "blob",
+		NumBlobs:       3,
+		BaseFeeWei:     "7000000000",
+		BlobBaseFeeWei: "1",
+		Guards:         []string{api.GuardEscalated, api.GuardClampedMax},
+	}, with.Strategy)
+	require.Nil(t, without.Strategy)
+}
+
+func TestNewBidStrategyWithoutFeesOrGuards(t *testing.T) {
+	s := NewBidStrategy("fixed", "transfer", 0, nil, nil, nil)
+	require.Equal(t, "", s.BaseFeeWei)
+	require.Equal(t, "", s.BlobBaseFeeWei)
+	require.NotNil(t, s.Guards)
+
+	data, err := json.Marshal(api.BidRecord{SchemaVersion: api.BidSchemaVersion, Strategy: s})
+	require.NoError(t, err)
+	_, err = api.ValidateRecord("bid", data)
+	require.NoError(t, err)
+}
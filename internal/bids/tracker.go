@@ -0,0 +1,145 @@
+// Package bids tracks sent bids until their target block is produced and
+// records how each one was resolved.
+package bids
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// InclusionTracker holds bids until their target block is observed, then
+// resolves them as included or missed. With a maximum lifetime, it also watches
+// each bid-on transaction until it is included or abandoned.
+type InclusionTracker struct {
+	mu          sync.Mutex
+	pending     map[uint64][]*api.BidRecord // Target block -> bids on it.
+	maxLifetime Lifetime
+	txs         map[common.Hash]*AbandonedTx // Transactions not yet included.
+}
+
+// AbandonedTx is a transaction that outlived the maximum lifetime without being included.
+type AbandonedTx struct {
+	TxHash     common.Hash
+	FirstBlock uint64    // Target block of the first bid on the transaction.
+	SentAt     time.Time // When the first bid was tracked.
+	Bids       int       // Number of bids on the transaction.
+}
+
+// NewInclusionTracker creates an empty InclusionTracker.
+//
+// Parameters:
+// - maxLifetime: How long a transaction goes without inclusion before it is abandoned.
+//
+// The zero Lifetime doesn't watch transactions.
+func NewInclusionTracker(maxLifetime Lifetime) *InclusionTracker {
+	return &InclusionTracker{
+		pending:     make(map[uint64][]*api.BidRecord),
+		maxLifetime: maxLifetime,
+		txs:         make(map[common.Hash]*AbandonedTx),
+	}
+}
+
+// Track adds a sent bid, keyed by its BlockNumber.
+func (t *InclusionTracker) Track(record *api.BidRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[record.BlockNumber] = append(t.pending[record.BlockNumber], record)
+
+	if t.maxLifetime.IsZero() {
+		return
+	}
+	hash := common.HexToHash(record.TxHash)
+	tx, ok := t.txs[hash]
+	if !ok {
+		tx = &AbandonedTx{TxHash: hash, FirstBlock: record.BlockNumber, SentAt: time.Now()}
+		t.txs[hash] = tx
+	}
+	tx.Bids++
+}
+
+// Watching returns the number of transactions watched for inclusion.
+func (t *InclusionTracker) Watching() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.txs)
+}
+
+// AbandonExpired stops watching transactions that outlived the maximum lifetime
+// by the given block without being included.
+//
+// Parameters:
+// - number: The latest observed block number.
+// - now: The current time.
+//
+// Returns:
+// - The abandoned transactions.
+func (t *InclusionTracker) AbandonExpired(number uint64, now time.Time) []AbandonedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var abandoned []AbandonedTx
+	for hash, tx := range t.txs {
+		if t.maxLifetime.Expired(tx.FirstBlock, tx.SentAt, number, now) {
+			abandoned = append(abandoned, *tx)
+			delete(t.txs, hash)
+		}
+	}
+	return abandoned
+}
+
+// Pending returns the number of bids whose target block hasn't been observed.
+func (t *InclusionTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var n int
+	for _, records := range t.pending {
+		n += len(records)
+	}
+	return n
+}
+
+// ObserveBlock resolves the bids targeting the block from its transactions. Bids on
+// earlier blocks that were never observed are resolved as unknown.
+//
+// Parameters:
+// - number: The block number.
+// - txs: The block's transactions.
+//
+// Returns:
+// - The resolved bid records, with Status and ResolvedAt set.
+func (t *InclusionTracker) ObserveBlock(number uint64, txs []*types.Transaction) []*api.BidRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	var resolved []*api.BidRecord
+	included := make(map[common.Hash]bool, len(txs))
+	for _, tx := range txs {
+		included[tx.Hash()] = true
+		// Included transactions no longer need watching, whichever block they landed in
+		delete(t.txs, tx.Hash())
+	}
+	for block, records := range t.pending {
+		if block > number {
+			continue
+		}
+		for _, record := range records {
+			switch {
+			case block < number:
+				record.Status = api.BidStatusUnknown
+			case included[common.HexToHash(record.TxHash)]:
+				record.Status = api.BidStatusIncluded
+			default:
+				record.Status = api.BidStatusMissed
+			}
+			record.ResolvedAt = now
+			resolved = append(resolved, record)
+		}
+		delete(t.pending, block)
+	}
+	return resolved
+}
@@ -0,0 +1,112 @@
+package bids
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInclusionTracker(t *testing.T) {
+	ours := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+	other := types.NewTx(&types.DynamicFeeTx{Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+
+	tracker := NewInclusionTracker(Lifetime{})
+	tracker.Track(&api.BidRecord{TxHash: ours.Hash().Hex(), BlockNumber: 99})
+	tracker.Track(&api.BidRecord{TxHash: ours.Hash().Hex(), BlockNumber: 100})
+	tracker.Track(&api.BidRecord{TxHash: ours.Hash().Hex(), BlockNumber: 101})
+	tracker.Track(&api.BidRecord{TxHash: ours.Hash().Hex(), BlockNumber: 102})
+
+	// Block 99 was never observed; 100 was produced without our tx
+	resolved := tracker.ObserveBlock(100, []*types.Transaction{other})
+	require.Len(t, resolved, 2)
+	statuses := map[uint64]string{}
+	for _, r := range resolved {
+		statuses[r.BlockNumber] = r.Status
+		require.NotZero(t, r.ResolvedAt)
+	}
+	require.Equal(t, map[uint64]string{99: api.BidStatusUnknown, 100: api.BidStatusMissed}, statuses)
+
+	resolved = tracker.ObserveBlock(101, []*types.Transaction{other, ours})
+	require.Len(t, resolved, 1)
+	require.Equal(t, api.BidStatusIncluded, resolved[0].Status)
+	require.Equal(t, 1, tracker.Pending())
+}
+
+func TestRecorderWritesValidRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bids.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.Record(&api.BidRecord{
+		TxHash:          "0xabc",
+		BlockNumber:     100,
+		AmountEth:       0.00125,
+		Profile:         "default",
+		EscalationLevel: 1,
+		Status:          api.BidStatusMissed,
+	}))
+	require.NoError(t, recorder.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	_, err = api.ValidateRecord("bid", data)
+	require.NoError(t, err)
+
+	var record api.BidRecord
+	require.NoError(t, json.Unmarshal(data, &record))
+	require.Equal(t, api.BidSchemaVersion, record.SchemaVersion)
+	require.Equal(t, 1, record.EscalationLevel)
+}
+
+func TestInclusionTrackerAbandonsExpiredTxs(t *testing.T) {
+	stale := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+	landed := types.NewTx(&types.DynamicFeeTx{Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+
+	tracker := NewInclusionTracker(Lifetime{Blocks: 3})
+	tracker.Track(&api.BidRecord{TxHash: stale.Hash().Hex(), BlockNumber: 100})
+	tracker.Track(&api.BidRecord{TxHash: stale.Hash().Hex(), BlockNumber: 101})
+	tracker.Track(&api.BidRecord{TxHash: landed.Hash().Hex(), BlockNumber: 100})
+	require.Equal(t, 2, tracker.Watching())
+
+	// The second tx lands a block after its bid's target; the first never does
+	tracker.ObserveBlock(101, []*types.Transaction{landed})
+	require.Equal(t, 1, tracker.Watching())
+	require.Empty(t, tracker.AbandonExpired(102, time.Now()))
+
+	abandoned := tracker.AbandonExpired(103, time.Now())
+	require.Len(t, abandoned, 1)
+	require.Equal(t, stale.Hash(), abandoned[0].TxHash)
+	require.EqualValues(t, 100, abandoned[0].FirstBlock)
+	require.Equal(t, 2, abandoned[0].Bids)
+	require.Zero(t, tracker.Watching())
+}
+
+func TestLifetime(t *testing.T) {
+	l, err := ParseLifetime("10")
+	require.NoError(t, err)
+	require.Equal(t, Lifetime{Blocks: 10}, l)
+	require.False(t, l.Expired(100, time.Now(), 109, time.Now()))
+	require.True(t, l.Expired(100, time.Now(), 110, time.Now()))
+
+	l, err = ParseLifetime("2m")
+	require.NoError(t, err)
+	require.Equal(t, Lifetime{Duration: 2 * time.Minute}, l)
+	sentAt := time.Now()
+	require.False(t, l.Expired(100, sentAt, 1000, sentAt.Add(time.Minute)))
+	require.True(t, l.Expired(100, sentAt, 101, sentAt.Add(2*time.Minute)))
+
+	l, err = ParseLifetime("")
+	require.NoError(t, err)
+	require.True(t, l.IsZero())
+	require.False(t, l.Expired(0, time.Time{}, 1<<40, time.Now()))
+
+	_, err = ParseLifetime("soon")
+	require.ErrorContains(t, err, "invalid transaction lifetime")
+}
@@ -0,0 +1,159 @@
+package bids
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// Defaults of the inclusion webhook.
+const (
+	DefaultWebhookTimeout  = 5 * time.Second
+	DefaultWebhookAttempts = 3
+	webhookBackoff         = 500 * time.Millisecond
+)
+
+// Webhook posts a record to a URL for each included bid, or each silently
+// rejected one, so that other systems can react to them.
+type Webhook struct {
+	url       string
+	userAgent string
+	attempts  int
+	backoff   time.Duration
+	client    *http.Client
+}
+
+// NewWebhook creates a Webhook.
+//
+// Parameters:
+// - url: The URL to POST records to.
+// - userAgent: The User-Agent of the requests, empty for Go's default.
+// - timeout: The timeout of each attempt.
+// - attempts: Attempts per record, at least 1. Failed attempts are retried
+// after 500ms, doubling each time.
+//
+// Returns:
+// - A pointer to a Webhook.
+func NewWebhook(url, userAgent string, timeout time.Duration, attempts int) *Webhook {
+	return &Webhook{
+		url:       url,
+		userAgent: userAgent,
+		attempts:  max(attempts, 1),
+		backoff:   webhookBackoff,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// NewInclusionRecord builds the webhook record of an included bid.
+//
+// Parameters:
+// - record: The resolved bid, with Status included.
+// - gasUsed, blobGasUsed: From the transaction's receipt, 0 if unknown.
+//
+// Returns:
+// - The inclusion record.
+func NewInclusionRecord(record *api.BidRecord, gasUsed, blobGasUsed uint64) api.InclusionRecord {
+	return api.InclusionRecord{
+		SchemaVersion: api.InclusionSchemaVersion,
+		TxHash:        record.TxHash,
+		BlockNumber:   record.BlockNumber,
+		GasUsed:       gasUsed,
+		BlobGasUsed:   blobGasUsed,
+		AmountEth:     record.AmountEth,
+		Profile:       record.Profile,
+		SentAt:        record.SentAt,
+		IncludedAt:    record.ResolvedAt,
+		LatencyMs:     record.ResolvedAt - record.SentAt,
+	}
+}
+
+// NewSilentRejectionRecord builds the webhook record of a silently rejected bid.
+//
+// Parameters:
+// - txHashes: The transactions of the bid.
+// - blockNumber: The target block.
+// - amountWei: The bid amount.
+// - decayStart, decayEnd: The decay window in Unix milliseconds.
+// - sentAt, answeredAt: When the bid was sent and its stream ended.
+//
+// Returns:
+// - The silent rejection record.
+func NewSilentRejectionRecord(txHashes []string, blockNumber uint64, amountWei *big.Int, decayStart, decayEnd int64, sentAt, answeredAt time.Time) api.SilentRejectionRecord {
+	record := api.SilentRejectionRecord{
+		SchemaVersion: api.SilentRejectionSchemaVersion,
+		TxHashes:      txHashes,
+		BlockNumber:   blockNumber,
+		DecayStart:    decayStart,
+		DecayEnd:      decayEnd,
+		SentAt:        sentAt.UnixMilli(),
+		AnsweredAt:    answeredAt.UnixMilli(),
+	}
+	if amountWei != nil {
+		record.AmountWei = amountWei.String()
+	}
+	return record
+}
+
+// Notify posts the record, retrying network errors and 429 or 5xx responses.
+// Other responses outside 2xx are not retried.
+//
+// Parameters:
+// - ctx: Ends the retries when done.
+// - record: The api.InclusionRecord or api.SilentRejectionRecord to report.
+//
+// Returns:
+// - nil once a 2xx response is received, or the error of the last attempt.
+func (w *Webhook) Notify(ctx context.Context, record any) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook record: %w", err)
+	}
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.attempts {
+			return fmt.Errorf("webhook failed after %d attempts: %w", attempt, err)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("webhook failed after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one attempt, reporting whether a failure is worth retrying.
+func (w *Webhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.userAgent != "" {
+		req.Header.Set("User-Agent", w.userAgent)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
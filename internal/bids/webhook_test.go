@@ -0,0 +1,133 @@
+package bids
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookServer records the bodies posted to it, failing the first failures
+// requests with status.
+func webhookServer(t *testing.T, failures int, status int) (*httptest.Server, func() [][]byte) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "bidder/test", r.Header.Get("User-Agent"))
+		bodies = append(bodies, body)
+		if len(bodies) <= failures {
+			w.WriteHeader(status)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() [][]byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies
+	}
+}
+
+func TestWebhookPostsInclusions(t *testing.T) {
+	server, bodies := webhookServer(t, 0, 0)
+	webhook := NewWebhook(server.URL, "bidder/test", time.Second, 3)
+
+	// A bid included in its target block is posted with its receipt's gas
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 7, To: &common.Address{1}, Value: big.NewInt(1)})
+	tracker := NewInclusionTracker(Lifetime{})
+	sentAt := time.Now().Add(-3 * time.Second).UnixMilli()
+	tracker.Track(&api.BidRecord{TxHash: tx.Hash().Hex(), BlockNumber: 100, AmountEth: 0.002, Profile: "small", SentAt: sentAt})
+	resolved := tracker.ObserveBlock(100, []*types.Transaction{tx})
+	require.Len(t, resolved, 1)
+	require.Equal(t, api.BidStatusIncluded, resolved[0].Status)
+	require.NoError(t, webhook.Notify(context.Background(), NewInclusionRecord(resolved[0], 21_000, 0)))
+
+	require.Len(t, bodies(), 1)
+	var posted api.InclusionRecord
+	require.NoError(t, json.Unmarshal(bodies()[0], &posted))
+	require.Equal(t, api.InclusionRecord{
+		SchemaVersion: api.InclusionSchemaVersion,
+		TxHash:        tx.Hash().Hex(),
+		BlockNumber:   100,
+		GasUsed:       21_000,
+		AmountEth:     0.002,
+		Profile:       "small",
+		SentAt:        sentAt,
+		IncludedAt:    resolved[0].ResolvedAt,
+		LatencyMs:     resolved[0].ResolvedAt - sentAt,
+	}, posted)
+	require.GreaterOrEqual(t, posted.LatencyMs, int64(3000))
+	version, err := api.ValidateRecord("inclusion", bodies()[0])
+	require.NoError(t, err)
+	require.Equal(t, api.InclusionSchemaVersion, version)
+}
+
+func TestWebhookRetriesServerErrors(t *testing.T) {
+	server, bodies := webhookServer(t, 2, http.StatusServiceUnavailable)
+	webhook := NewWebhook(server.URL, "bidder/test", time.Second, 3)
+	webhook.backoff = time.Millisecond
+	require.NoError(t, webhook.Notify(context.Background(), api.InclusionRecord{TxHash: "0x01"}))
+	require.Len(t, bodies(), 3)
+
+	// Giving up after the last attempt
+	server, bodies = webhookServer(t, 5, http.StatusInternalServerError)
+	webhook = NewWebhook(server.URL, "bidder/test", time.Second, 2)
+	webhook.backoff = time.Millisecond
+	require.ErrorContains(t, webhook.Notify(context.Background(), api.InclusionRecord{}), "after 2 attempts")
+	require.Len(t, bodies(), 2)
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	server, bodies := webhookServer(t, 5, http.StatusBadRequest)
+	webhook := NewWebhook(server.URL, "bidder/test", time.Second, 3)
+	webhook.backoff = time.Millisecond
+	require.ErrorContains(t, webhook.Notify(context.Background(), api.InclusionRecord{}), "400")
+	require.Len(t, bodies(), 1)
+}
+
+func TestWebhookTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	webhook := NewWebhook(server.URL, "", 20*time.Millisecond, 2)
+	webhook.backoff = time.Millisecond
+	start := time.Now()
+	require.Error(t, webhook.Notify(context.Background(), api.InclusionRecord{}))
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestWebhookPostsSilentRejection(t *testing.T) {
+	server, bodies := webhookServer(t, 0, http.StatusOK)
+	webhook := NewWebhook(server.URL, "bidder/test", time.Second, 1)
+	sentAt := time.UnixMilli(1_700_000_000_000)
+	record := NewSilentRejectionRecord([]string{"0x01"}, 100, big.NewInt(1e15), 1, 2, sentAt, sentAt.Add(time.Second))
+	require.NoError(t, webhook.Notify(context.Background(), record))
+
+	var posted api.SilentRejectionRecord
+	require.NoError(t, json.Unmarshal(bodies()[0], &posted))
+	require.Equal(t, record, posted)
+	require.Equal(t, "1000000000000000", posted.AmountWei)
+	require.Equal(t, int64(1000), posted.AnsweredAt-posted.SentAt)
+	version, err := api.ValidateRecord("silent_rejection", bodies()[0])
+	require.NoError(t, err)
+	require.Equal(t, api.SilentRejectionSchemaVersion, version)
+}
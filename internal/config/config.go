@@ -0,0 +1,82 @@
+// Package config resolves the bidder's settings from flags, env vars and an
+// optional YAML config file, reads secrets from mounted files, and validates
+// the result, reporting every problem at once.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File holds the options that can be set in a config file. Unset fields are
+// nil or empty and leave the corresponding flag, env var, or default in effect.
+type File struct {
+	ServerAddress             string   `yaml:"server_address"`
+	RPCEndpoint               string   `yaml:"rpc_endpoint"`
+	WSEndpoint                string   `yaml:"ws_endpoint"`
+	PrivateKey                string   `yaml:"private_key"`
+	UsePayload                *bool    `yaml:"use_payload"`
+	Offset                    *uint64  `yaml:"offset"`
+	BidAmount                 *float64 `yaml:"bid_amount"`
+	BidAmountStdDevPercentage *float64 `yaml:"bid_amount_std_dev_percentage"`
+	NumBlob                   *uint    `yaml:"num_blob"`
+	PriorityFee               *uint64  `yaml:"priority_fee"`
+	DefaultTimeout            *uint    `yaml:"default_timeout"`
+	RunDurationMinutes        *uint    `yaml:"run_duration_minutes"`
+	BidProfilesFile           string   `yaml:"bid_profiles_file"`
+	BidProfileSelector        string   `yaml:"bid_profile_selector"`
+	CommitmentsFile           string   `yaml:"commitments_file"`
+	LogFile                   string   `yaml:"log_file"`
+	Backrun                   *Backrun `yaml:"backrun"`
+}
+
+// Backrun is the backrun block of a config file, a follow-up call bid on
+// together with each transaction.
+type Backrun struct {
+	To   string `yaml:"to"`   // Target address.
+	Data string `yaml:"data"` // Hex calldata.
+}
+
+// LoadFile reads a YAML config file, expanding ${VAR} and ${VAR:-default}
+// references to environment variables in its values.
+//
+// Parameters:
+// - filePath: The path to the YAML config file.
+//
+// Returns:
+//   - A pointer to the File, or an error if the file is missing, invalid, has unknown
+//     keys, or references an undefined environment variable without a default.
+func LoadFile(filePath string) (*File, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	cfg := &File{}
+	if root.Kind == 0 {
+		return cfg, nil // Empty file
+	}
+	if err := interpolateNode(&root, os.LookupEnv); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", filePath, err)
+	}
+
+	// Re-encode the expanded tree so unknown keys are rejected on decode
+	expanded, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(expanded))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return cfg, nil
+}
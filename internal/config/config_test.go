@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestLoadFileInterpolation(t *testing.T) {
+	t.Setenv("TEST_PRIVATE_KEY", "abc123")
+	t.Setenv("TEST_HOST", "bidder")
+	t.Setenv("TEST_EMPTY", "")
+
+	cfg, err := LoadFile(writeConfig(t, `
+private_key: ${TEST_PRIVATE_KEY}
+server_address: "${TEST_HOST}:${TEST_PORT:-13524}"
+ws_endpoint: ${TEST_EMPTY:-wss://fallback}
+rpc_endpoint: https://node/$${literal}
+offset: ${TEST_OFFSET:-2}
+use_payload: ${TEST_USE_PAYLOAD:-false}
+`))
+	require.NoError(t, err)
+	require.Equal(t, "abc123", cfg.PrivateKey)
+	require.Equal(t, "bidder:13524", cfg.ServerAddress)
+	require.Equal(t, "wss://fallback", cfg.WSEndpoint)
+	require.Equal(t, "https://node/${literal}", cfg.RPCEndpoint)
+	require.Equal(t, uint64(2), *cfg.Offset)
+	require.False(t, *cfg.UsePayload)
+	require.Nil(t, cfg.BidAmount)
+}
+
+func TestLoadFileUndefinedVariable(t *testing.T) {
+	_, err := LoadFile(writeConfig(t, "private_key: ${TEST_UNDEFINED_KEY}\n"))
+	require.ErrorContains(t, err, "private_key: environment variable TEST_UNDEFINED_KEY is not set")
+
+	_, err = LoadFile(writeConfig(t, "private_key: ${TEST_UNDEFINED_KEY\n"))
+	require.ErrorContains(t, err, "unterminated")
+}
+
+func TestLoadFileBackrun(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `
+backrun:
+  to: "0x00000000000000000000000000000000000000bb"
+  data: ${TEST_BACKRUN_DATA:-0xdeadbeef}
+`))
+	require.NoError(t, err)
+	require.Equal(t, &Backrun{To: "0x00000000000000000000000000000000000000bb", Data: "0xdeadbeef"}, cfg.Backrun)
+
+	_, err = LoadFile(writeConfig(t, "backrun:\n  target: 0x1\n"))
+	require.ErrorContains(t, err, "target")
+}
+
+func TestLoadFileRejectsUnknownKeys(t *testing.T) {
+	_, err := LoadFile(writeConfig(t, "bid_amout: 0.1\n"))
+	require.ErrorContains(t, err, "bid_amout")
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lookupFunc reports the value of an environment variable and whether it is set.
+type lookupFunc func(key string) (string, bool)
+
+// interpolateNode expands environment variable references in every scalar value
+// of the tree. Keys are left untouched.
+func interpolateNode(n *yaml.Node, lookup lookupFunc) error {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range n.Content {
+			if err := interpolateNode(child, lookup); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			if err := interpolateNode(n.Content[i], lookup); err != nil {
+				return fmt.Errorf("%s: %w", n.Content[i-1].Value, err)
+			}
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(n.Value, "${") {
+			return nil
+		}
+		expanded, err := interpolate(n.Value, lookup)
+		if err != nil {
+			return err
+		}
+		n.Value = expanded
+		// Let plain scalars resolve again so ${OFFSET} can fill a number field
+		if n.Style == 0 {
+			n.Tag = ""
+		}
+	}
+	return nil
+}
+
+// interpolate expands ${VAR} and ${VAR:-default} references in s. A reference to
+// an unset variable is an error unless it has a default, which is also used when
+// the variable is set but empty. Use $$ for a literal $.
+func interpolate(s string, lookup lookupFunc) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case '{':
+		default:
+			b.WriteByte(s[i])
+			continue
+		}
+
+		end := strings.IndexByte(s[i+2:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		ref := s[i+2 : i+2+end]
+		name, def, hasDefault := strings.Cut(ref, ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty variable reference in %q", s)
+		}
+
+		val, ok := lookup(name)
+		switch {
+		case ok && (val != "" || !hasDefault):
+			b.WriteString(val)
+		case hasDefault:
+			b.WriteString(def)
+		default:
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		i += 2 + end
+	}
+	return b.String(), nil
+}
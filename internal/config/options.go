@@ -0,0 +1,209 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+)
+
+// Config holds the bidder's settings. The options are resolved from flags, env
+// vars and the config file by the caller; Validate checks them and sets the
+// values derived from them, which are only meaningful once it returns nil.
+type Config struct {
+	// Logging
+	AppName            string
+	Version            string
+	LogFile            string
+	LogTimestampFormat string
+	PrettyLog          bool
+	LogDedupWindow     time.Duration
+
+	// Bidder nodes and the execution node
+	ServerAddress   string // One or more bidder nodes, comma-separated.
+	BidderTLS       bool
+	BidderTLSCAFile string
+	CommitmentsFile string
+	BidAllowReverts bool
+	RPCEndpoint     string
+	WSEndpoint      string
+	WSEndpointSet   bool   // Whether WS_ENDPOINT was given rather than defaulted.
+	WSEndpointList  string // WS_ENDPOINTS, comma-separated.
+	HTTPUserAgent   string
+	DryRun          bool
+
+	// Keys and wallets
+	PrivateKey        string
+	PrivateKeys       string // Further pool wallets, comma-separated.
+	WalletModes       string
+	KeystorePath      string
+	KeystorePassword  string
+	FundingPrivateKey string
+	Rebalance         ee.RebalanceConfig
+	RebalanceInterval time.Duration
+	KeyAuditMode      string
+	KeyAuditFile      string
+
+	// Bids
+	UsePayload              bool
+	Offset                  uint64
+	BidAmount               float64
+	StdDevPercentage        float64
+	NumBlob                 uint
+	NumBlobs                string // NUM_BLOBS, the blob counts swept by the default profile.
+	BidProfilesFile         string
+	BidProfileSelector      string
+	BidBlockRange           uint64
+	BidCycleRetries         uint
+	BidPricingWorkers       uint64
+	BidRecordStrategy       bool
+	BidMinEth               float64
+	BidMaxEth               float64
+	BidAmountStrategy       string
+	BidAmountMin            string
+	BidAmountMax            string
+	BidMode                 string
+	BidAmountEth            float64
+	EscalationMissedBids    uint
+	EscalationStepPct       float64
+	EscalationMaxMultiplier float64
+	DecayStartOffsetMs      int64
+	DecayDurationMs         int64
+	DecayDurationSet        bool // Whether DECAY_DURATION_MS was given rather than derived from the block time.
+	DecayJitter             time.Duration
+	OffsetDecayPolicy       string
+	BidLatencySLO           time.Duration
+	BidEndpointTimeout      time.Duration
+	BidRetry                bb.BidRetryPolicy
+	BidRetryableCodes       string
+	StaleBidPolicy          string
+	BidderOutagePolicy      string
+	BidderOutageFailures    uint
+	BidderOutageQueueSize   uint
+	ProposerAllowlist       string
+	BeaconEndpoint          string
+	BroadcastDelay          time.Duration
+	SelfTest                bool
+	SelfTestMaxBidAmount    float64
+
+	// Transactions
+	TxType                  string
+	TxPerBlock              uint
+	TxDedup                 bool
+	TxABIFile               string
+	TxMaxLifetimeSetting    string
+	TokenTransfer           bool
+	TokenAddress            string
+	TokenAmount             string
+	TransferAmountWei       string
+	TransferScheduleCSV     string
+	BackrunTo               string
+	BackrunData             string
+	PriorityFee             uint64
+	TipAsBaseFeePct         float64
+	TipFloorWei             uint64
+	GasFeeCapGwei           float64
+	GasTipGwei              float64
+	BlobFeeCapGwei          float64
+	BaseFeeMultiplier       float64
+	MaxFeeGwei              float64
+	BlobFeeAsGasFeeMultiple float64
+	BlobChainLength         uint
+	BlobSidecar             string
+	BlobSeed                string
+	BlobDataPath            string
+	KZGTrustedSetup         string
+	AdaptiveBlobCount       bool
+	AdaptiveBlobMin         uint
+	AdaptiveBlobMax         uint
+	AdaptiveBlobLowFeeWei   uint64
+	AdaptiveBlobHighFeeWei  uint64
+	BlobRampEnabled         bool
+	BlobRampMax             uint
+	BlobRampStepBlocks      uint64
+	BlobRampSchedule        string
+	MaxConcurrentBundles    uint
+	BundleOverflowPolicy    string
+	SendIntervalBlocks      uint64
+	SendIntervalSeconds     float64
+	Mode                    string
+	WatchAddressesList      string
+
+	// Deposits
+	MinDeposit             float64
+	TopUpAmount            float64
+	DepositBlocksPerWindow uint64
+
+	// Timing and lifetime
+	BlockTimeSetting        string                                // A duration, or auto to detect it with DetectBlockTime.
+	DetectBlockTime         func(wsEndpoint string) time.Duration // nil keeps the default block time.
+	DefaultTimeoutSeconds   uint
+	RunDurationMinutes      uint
+	RunDurationSet          bool // Whether RUN_DURATION_MINUTES was given.
+	MaxRuntime              time.Duration
+	MaxRuntimeSet           bool // Whether MAX_RUNTIME was given.
+	ShutdownDrainTimeoutSec uint
+	DrainSignalName         string
+	DrainTimeoutSec         uint
+	DrainTimeoutSet         bool // Whether DRAIN_TIMEOUT_SEC was given rather than derived from the block time.
+	DrainConfirmations      uint64
+	NonceCheckInterval      time.Duration
+	NonceCheckIntervalSet   bool // Whether NONCE_CHECK_INTERVAL was given rather than derived from the block time.
+	NoncePauseChecks        uint
+	NonceResyncFailures     uint
+	HeaderStaleTimeout      time.Duration
+	RPCPoolSize             uint
+	RPCPoolHealthInterval   time.Duration
+	ExitOnCriteria          string
+
+	// Records, webhooks and monitoring
+	BidRecordsFile            string
+	BidLogFile                string
+	BidLogFormat              string
+	BidLogMaxSizeMB           uint
+	BidLogMaxBackups          uint
+	BidOutcomeLogBlocks       uint64
+	TxCountWindow             uint
+	FeePercentileWindow       uint
+	InclusionWebhookURL       string
+	InclusionWebhookTimeout   time.Duration
+	InclusionWebhookAttempts  uint
+	SilentRejectionWebhookURL string
+	HealthAddr                string
+	HeartbeatInterval         time.Duration
+	MetricsPort               uint
+	MetricsAddr               string // Takes precedence over MetricsPort; set from it by Validate otherwise.
+
+	// Set by Validate
+	ServerAddresses  []string
+	WSEndpoints      []string // WS_ENDPOINTS, empty unless given.
+	WatchAddresses   []common.Address
+	KeystoreKey      *ecdsa.PrivateKey
+	Backrun          *ee.Backrun
+	FeeCaps          ee.FeeCaps
+	Token            *ee.TokenTransfer
+	TransferAmount   *big.Int
+	TransferSchedule *ee.TransferSchedule
+	BlobSource       *ee.BlobSource
+	SendInterval     *strategy.SendInterval
+	ExitCriteria     bids.Criteria
+	TxMaxLifetime    bids.Lifetime
+	BidRange         strategy.BidAmountRange
+	WeiAmount        *strategy.WeiAmount // Wei amounts replacing those of the profiles, nil to use the profiles.
+	BlockTime        time.Duration
+	BlockTiming      strategy.BlockTiming
+	DecayTiming      bb.DecayTiming
+	AdaptiveBlobs    *strategy.AdaptiveBlobCount
+	BlobCycle        *strategy.BlobCycle
+	Outage           *bb.BidderOutage
+	DrainSignal      os.Signal // nil when draining on a signal is disabled.
+	Profiles         []*strategy.Profile
+	ProfileSelector  *strategy.ProfileSelector
+	BlobRamp         *strategy.BlobRamp // nil unless BlobRampEnabled.
+}
@@ -0,0 +1,35 @@
+package config
+
+import "errors"
+
+// Problems collects every invalid setting found while resolving the
+// configuration, so that they can be reported together instead of one per run.
+type Problems struct {
+	errs []error
+}
+
+// Add records a problem. A nil error is ignored.
+func (p *Problems) Add(err error) {
+	if err != nil {
+		p.errs = append(p.errs, err)
+	}
+}
+
+// Errors returns the problems in the order they were found.
+func (p *Problems) Errors() []error {
+	return p.errs
+}
+
+// Err joins the problems into one error, or returns nil if there are none.
+func (p *Problems) Err() error {
+	return errors.Join(p.errs...)
+}
+
+// Split returns the problems joined into err by Err, or err alone if it
+// wasn't joined.
+func Split(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
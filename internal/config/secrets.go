@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretVars are the environment variables that can instead be read from a
+// file named by the same variable with a _FILE suffix, such as a mounted
+// Docker or Kubernetes secret.
+var SecretVars = []string{"PRIVATE_KEY", "PRIVATE_KEYS", "RPC_ENDPOINT", "WS_ENDPOINT", "WS_ENDPOINTS", "KEYSTORE_PASSWORD", "FUNDING_PRIVATE_KEY"}
+
+// LoadSecretFiles sets each of names from the file named by its _FILE variant,
+// with surrounding whitespace trimmed. It must run before the flags are parsed,
+// so that the secrets take the place of the variables themselves.
+//
+// Parameters:
+// - names: The environment variables to resolve, usually SecretVars.
+//
+// Returns:
+// - An error if both a variable and its _FILE variant are set, or a file can't be read.
+func LoadSecretFiles(names ...string) error {
+	return loadSecretFiles(os.LookupEnv, os.Setenv, names)
+}
+
+func loadSecretFiles(lookup lookupFunc, setenv func(string, string) error, names []string) error {
+	var problems Problems
+	for _, name := range names {
+		path, ok := lookup(name + "_FILE")
+		if !ok || path == "" {
+			continue
+		}
+		if value, ok := lookup(name); ok && value != "" {
+			problems.Add(fmt.Errorf("%s and %s_FILE are both set", name, name))
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems.Add(fmt.Errorf("%s_FILE: %w", name, err))
+			continue
+		}
+		if err := setenv(name, strings.TrimSpace(string(data))); err != nil {
+			problems.Add(fmt.Errorf("%s_FILE: %w", name, err))
+		}
+	}
+	return problems.Err()
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "private_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("abc123\n"), 0o600))
+
+	env := map[string]string{"PRIVATE_KEY_FILE": keyFile, "WS_ENDPOINT": "wss://node"}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	setenv := func(name, value string) error {
+		env[name] = value
+		return nil
+	}
+
+	require.NoError(t, loadSecretFiles(lookup, setenv, SecretVars))
+	require.Equal(t, "abc123", env["PRIVATE_KEY"])
+	require.Equal(t, "wss://node", env["WS_ENDPOINT"])
+
+	// Every problem is reported, not just the first
+	env = map[string]string{
+		"PRIVATE_KEY":       "abc123",
+		"PRIVATE_KEY_FILE":  keyFile,
+		"RPC_ENDPOINT_FILE": filepath.Join(dir, "missing"),
+	}
+	err := loadSecretFiles(lookup, setenv, SecretVars)
+	require.ErrorContains(t, err, "PRIVATE_KEY and PRIVATE_KEY_FILE are both set")
+	require.ErrorContains(t, err, "RPC_ENDPOINT_FILE")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestProblems(t *testing.T) {
+	var problems Problems
+	require.NoError(t, problems.Err())
+
+	first, second := errors.New("first"), errors.New("second")
+	problems.Add(first)
+	problems.Add(nil)
+	problems.Add(second)
+	require.Equal(t, []error{first, second}, problems.Errors())
+	require.ErrorIs(t, problems.Err(), first)
+	require.ErrorIs(t, problems.Err(), second)
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Source resolves options in order of precedence: a flag given on the command
+// line or set from the config file, then its env var, then a default.
+type Source struct {
+	c *cli.Context
+}
+
+// NewSource creates a Source reading the flags of c.
+func NewSource(c *cli.Context) Source {
+	return Source{c: c}
+}
+
+// String returns a string option, falling back to defaultValue when the flag
+// and env var are empty.
+func (s Source) String(flagName, envVar, defaultValue string) string {
+	val := s.c.String(flagName)
+	if val == "" {
+		val = os.Getenv(envVar)
+		if val == "" {
+			val = defaultValue
+		}
+	}
+	return val
+}
+
+// Bool returns a bool option. An env var that doesn't parse as a bool is
+// logged and ignored.
+func (s Source) Bool(flagName, envVar string, defaultValue bool) bool {
+	if s.c.IsSet(flagName) {
+		return s.c.Bool(flagName)
+	}
+	envVal, envVarExists := os.LookupEnv(envVar)
+	if envVarExists && envVal != "" {
+		parsedVal, err := strconv.ParseBool(envVal)
+		if err == nil {
+			return parsedVal
+		}
+		slog.Warn("Environment variable set but could not be parsed as bool. Using default value.", "envVar", envVar, "value", envVal, "error", err, "default", defaultValue)
+	}
+	return defaultValue
+}
+
+// Uint64 returns an unsigned integer option. An env var that doesn't parse
+// leaves the default in effect.
+func (s Source) Uint64(flagName, envVar string, defaultValue uint64) uint64 {
+	if s.c.IsSet(flagName) {
+		return s.c.Uint64(flagName)
+	}
+	parsedVal, err := strconv.ParseUint(os.Getenv(envVar), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return parsedVal
+}
+
+// Uint returns an unsigned integer option, as Uint64 does.
+func (s Source) Uint(flagName, envVar string, defaultValue uint) uint {
+	if s.c.IsSet(flagName) {
+		return s.c.Uint(flagName)
+	}
+	parsedVal, err := strconv.ParseUint(os.Getenv(envVar), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return uint(parsedVal)
+}
+
+// Float64 returns a float option. An env var that doesn't parse leaves the
+// default in effect.
+func (s Source) Float64(flagName, envVar string, defaultValue float64) float64 {
+	if s.c.IsSet(flagName) {
+		return s.c.Float64(flagName)
+	}
+	parsedVal, err := strconv.ParseFloat(os.Getenv(envVar), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return parsedVal
+}
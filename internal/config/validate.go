@@ -0,0 +1,614 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/store"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+	"github.com/primev/preconf_blob_bidder/internal/watch"
+)
+
+// Validate checks every option and sets the values derived from them. Every
+// invalid setting is reported, joined into the returned error; Split recovers
+// them one by one.
+//
+// Returns:
+// - An error joining every problem found, or nil if the configuration is valid.
+func (cfg *Config) Validate() error {
+	var err error
+	// Settings are checked as they are read, and every problem is reported at once
+	var problems Problems
+	cfg.Backrun, err = ee.ParseBackrun(cfg.BackrunTo, cfg.BackrunData)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid BACKRUN_TO or BACKRUN_DATA: %w", err))
+	}
+	// SERVER_ADDRESS may list several bidder nodes, which each bid is sent to
+	cfg.ServerAddresses = bb.ParseServerAddresses(cfg.ServerAddress)
+	if len(cfg.ServerAddresses) == 0 {
+		problems.Add(fmt.Errorf("SERVER_ADDRESS must name at least one bidder node"))
+	}
+	for _, address := range cfg.ServerAddresses {
+		if err := bb.ValidateServerAddress(address); err != nil {
+			problems.Add(fmt.Errorf("invalid SERVER_ADDRESS: %w", err))
+		}
+	}
+	// WS_ENDPOINTS lists several nodes to fail over between; WS_ENDPOINT is a list of one
+	if cfg.WSEndpointList != "" {
+		if cfg.WSEndpointSet {
+			problems.Add(fmt.Errorf("WS_ENDPOINTS cannot be combined with WS_ENDPOINT"))
+		}
+		for _, endpoint := range strings.Split(cfg.WSEndpointList, ",") {
+			if endpoint = strings.TrimSpace(endpoint); endpoint == "" {
+				continue
+			}
+			endpoint, err := ValidateWebSocketURL(endpoint)
+			if err != nil {
+				problems.Add(fmt.Errorf("invalid WS_ENDPOINTS: %w", err))
+				continue
+			}
+			cfg.WSEndpoints = append(cfg.WSEndpoints, endpoint)
+		}
+		if len(cfg.WSEndpoints) == 0 {
+			problems.Add(fmt.Errorf("WS_ENDPOINTS must name at least one endpoint"))
+		} else {
+			cfg.WSEndpoint = cfg.WSEndpoints[0]
+		}
+	}
+	switch cfg.Mode {
+	case watch.ModeBuild:
+		if cfg.WatchAddressesList != "" {
+			problems.Add(fmt.Errorf("WATCH_ADDRESSES requires MODE=%s", watch.ModeWatch))
+		}
+	case watch.ModeWatch:
+		cfg.WatchAddresses, err = watch.ParseAddresses(cfg.WatchAddressesList)
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid WATCH_ADDRESSES: %w", err))
+		} else if len(cfg.WatchAddresses) == 0 {
+			problems.Add(fmt.Errorf("MODE=%s requires WATCH_ADDRESSES", watch.ModeWatch))
+		}
+	default:
+		problems.Add(fmt.Errorf("unknown mode %q (must be %s or %s)", cfg.Mode, watch.ModeBuild, watch.ModeWatch))
+	}
+	if cfg.BlobSeed != "" {
+		seed, err := strconv.ParseUint(cfg.BlobSeed, 10, 64)
+		if err != nil {
+			problems.Add(fmt.Errorf("BLOB_SEED must be an unsigned integer: %w", err))
+		}
+		cfg.BlobSource = ee.NewBlobSource(seed)
+	}
+	if cfg.BlobDataPath != "" {
+		source, err := ee.NewFileBlobSource(cfg.BlobDataPath)
+		switch {
+		case cfg.BlobSeed != "":
+			problems.Add(fmt.Errorf("BLOB_DATA_PATH cannot be combined with BLOB_SEED"))
+		case err != nil:
+			problems.Add(fmt.Errorf("invalid BLOB_DATA_PATH: %w", err))
+		default:
+			cfg.BlobSource = source
+		}
+	}
+	if cfg.BundleOverflowPolicy != ee.BundleOverflowQueue && cfg.BundleOverflowPolicy != ee.BundleOverflowDrop {
+		problems.Add(fmt.Errorf("BUNDLE_OVERFLOW_POLICY must be %s or %s", ee.BundleOverflowQueue, ee.BundleOverflowDrop))
+	}
+	// Hash-only bids can give providers a head start before their transactions go out
+	if cfg.BroadcastDelay < 0 {
+		problems.Add(fmt.Errorf("BROADCAST_DELAY cannot be negative"))
+	}
+	if cfg.BroadcastDelay > 0 && cfg.UsePayload {
+		problems.Add(fmt.Errorf("BROADCAST_DELAY requires USE_PAYLOAD=false, as payload bids carry their transactions"))
+	}
+	if cfg.MinDeposit != 0 || cfg.TopUpAmount != 0 {
+		if _, err := bb.NewDepositManager(nil, bb.EthToWei(cfg.MinDeposit), bb.EthToWei(cfg.TopUpAmount), cfg.DepositBlocksPerWindow); err != nil {
+			problems.Add(fmt.Errorf("invalid MIN_DEPOSIT or TOP_UP_AMOUNT: %w", err))
+		}
+	}
+	// A funding wallet outside the pool tops up pool wallets that run low
+	if cfg.FundingPrivateKey != "" {
+		if err := ValidatePrivateKey(cfg.FundingPrivateKey); err != nil {
+			problems.Add(fmt.Errorf("invalid FUNDING_PRIVATE_KEY: %w", err))
+		}
+		if _, err := ee.NewRebalancer(nil, bb.AuthAcct{}, cfg.Rebalance, nil); err != nil {
+			problems.Add(fmt.Errorf("invalid wallet rebalancing settings: %w", err))
+		}
+		if cfg.RebalanceInterval <= 0 {
+			problems.Add(fmt.Errorf("REBALANCE_INTERVAL must be positive"))
+		}
+	} else if (cfg.Rebalance.MinBalance != nil && cfg.Rebalance.MinBalance.Sign() != 0) || (cfg.Rebalance.TopUp != nil && cfg.Rebalance.TopUp.Sign() != 0) {
+		problems.Add(fmt.Errorf("REBALANCE_MIN_BALANCE and REBALANCE_TOP_UP_AMOUNT require FUNDING_PRIVATE_KEY"))
+	}
+	if cfg.InclusionWebhookURL != "" {
+		if u, err := url.Parse(cfg.InclusionWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems.Add(fmt.Errorf("INCLUSION_WEBHOOK_URL must be an http or https URL"))
+		}
+	}
+	if cfg.SilentRejectionWebhookURL != "" {
+		if u, err := url.Parse(cfg.SilentRejectionWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems.Add(fmt.Errorf("SILENT_REJECTION_WEBHOOK_URL must be an http or https URL"))
+		}
+	}
+	if (cfg.InclusionWebhookURL != "" || cfg.SilentRejectionWebhookURL != "") && (cfg.InclusionWebhookTimeout <= 0 || cfg.InclusionWebhookAttempts < 1) {
+		problems.Add(fmt.Errorf("INCLUSION_WEBHOOK_TIMEOUT must be positive and INCLUSION_WEBHOOK_ATTEMPTS at least 1"))
+	}
+	if cfg.SendIntervalSeconds < 0 {
+		problems.Add(fmt.Errorf("SEND_INTERVAL_SECONDS cannot be negative"))
+	}
+	if cfg.SendIntervalBlocks > 1 && cfg.SendIntervalSeconds > 0 {
+		slog.Warn("SEND_INTERVAL_BLOCKS takes precedence, ignoring SEND_INTERVAL_SECONDS",
+			"sendIntervalBlocks", cfg.SendIntervalBlocks,
+			"sendIntervalSeconds", cfg.SendIntervalSeconds,
+		)
+	}
+	cfg.SendInterval = strategy.NewSendInterval(cfg.SendIntervalBlocks, time.Duration(cfg.SendIntervalSeconds*float64(time.Second)))
+	if _, err := bb.NewStaleBidGuard(cfg.StaleBidPolicy, cfg.Offset, nil); err != nil {
+		problems.Add(fmt.Errorf("invalid STALE_BID_POLICY: %w", err))
+	}
+	if cfg.KeystorePath != "" {
+		if cfg.PrivateKey != "" {
+			problems.Add(fmt.Errorf("PRIVATE_KEY and KEYSTORE_PATH are both set; use one or the other"))
+		} else if cfg.KeystoreKey, err = bb.LoadKeystore(cfg.KeystorePath, cfg.KeystorePassword); err != nil {
+			problems.Add(fmt.Errorf("invalid KEYSTORE_PATH or KEYSTORE_PASSWORD: %w", err))
+		}
+	}
+	if cfg.BidRetry.RetryableCodes, err = bb.ParseBidStatusCodes(cfg.BidRetryableCodes); err != nil {
+		problems.Add(fmt.Errorf("invalid BID_RETRYABLE_CODES: %w", err))
+	}
+	if cfg.BidRetry.RetryableCodes == nil {
+		cfg.BidRetry.RetryableCodes = bb.DefaultRetryableBidCodes
+	}
+	if cfg.BidRetry.Attempts < 1 || cfg.BidRetry.Backoff < 0 {
+		problems.Add(fmt.Errorf("BID_RETRY_ATTEMPTS must be at least 1 and BID_RETRY_BACKOFF not negative"))
+	}
+	if cfg.RPCPoolSize < 1 || cfg.RPCPoolHealthInterval <= 0 {
+		problems.Add(fmt.Errorf("RPC_POOL_SIZE and RPC_POOL_HEALTH_INTERVAL must be positive"))
+	}
+	if cfg.HeaderStaleTimeout < 0 {
+		problems.Add(fmt.Errorf("HEADER_STALE_TIMEOUT cannot be negative"))
+	}
+	if cfg.BidLogFormat != store.FormatJSONL && cfg.BidLogFormat != store.FormatCSV {
+		problems.Add(fmt.Errorf("BID_LOG_FORMAT must be %s or %s, got %q", store.FormatJSONL, store.FormatCSV, cfg.BidLogFormat))
+	}
+	if cfg.BidLogMaxBackups < 1 {
+		problems.Add(fmt.Errorf("BID_LOG_MAX_BACKUPS must be at least 1"))
+	}
+	if cfg.BidEndpointTimeout < 0 {
+		problems.Add(fmt.Errorf("BID_ENDPOINT_TIMEOUT cannot be negative"))
+	}
+	// RUN_DURATION_MINUTES predates MAX_RUNTIME and still overrides its default
+	if cfg.RunDurationSet {
+		if cfg.MaxRuntimeSet {
+			problems.Add(fmt.Errorf("RUN_DURATION_MINUTES cannot be combined with MAX_RUNTIME"))
+		}
+		cfg.MaxRuntime = time.Duration(cfg.RunDurationMinutes) * time.Minute
+	}
+	if cfg.MaxRuntime < 0 {
+		problems.Add(fmt.Errorf("MAX_RUNTIME cannot be negative"))
+	}
+	if cfg.BidPricingWorkers < 1 {
+		problems.Add(fmt.Errorf("BID_PRICING_WORKERS must be at least 1"))
+	}
+	if cfg.BlobChainLength < 1 {
+		problems.Add(fmt.Errorf("BLOB_CHAIN_LENGTH must be at least 1"))
+	}
+	if cfg.TxPerBlock < 1 {
+		problems.Add(fmt.Errorf("TX_PER_BLOCK must be at least 1"))
+	}
+	if err := ee.ValidateBlobSidecarMode(cfg.BlobSidecar, cfg.UsePayload); err != nil {
+		problems.Add(fmt.Errorf("invalid BLOB_SIDECAR: %w", err))
+	}
+	cfg.ExitCriteria, err = bids.ParseCriteria(cfg.ExitOnCriteria)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid EXIT_ON_CRITERIA: %w", err))
+	}
+	cfg.TxMaxLifetime, err = bids.ParseLifetime(cfg.TxMaxLifetimeSetting)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid TX_MAX_LIFETIME: %w", err))
+	}
+	if cfg.TipAsBaseFeePct < 0 {
+		problems.Add(fmt.Errorf("TIP_AS_BASE_FEE_PCT cannot be negative"))
+	}
+	if cfg.GasFeeCapGwei < 0 || cfg.GasTipGwei < 0 || cfg.BlobFeeCapGwei < 0 || cfg.MaxFeeGwei < 0 {
+		problems.Add(fmt.Errorf("GAS_FEE_CAP_GWEI, GAS_TIP_GWEI, BLOB_FEE_CAP_GWEI and MAX_FEE_GWEI cannot be negative"))
+	}
+	if cfg.BaseFeeMultiplier <= 0 {
+		problems.Add(fmt.Errorf("BASE_FEE_MULTIPLIER must be positive, got %v", cfg.BaseFeeMultiplier))
+	}
+	if cfg.GasFeeCapGwei > 0 && (cfg.MaxFeeGwei > 0 || cfg.BaseFeeMultiplier != 1) {
+		problems.Add(fmt.Errorf("GAS_FEE_CAP_GWEI fixes the max fee per gas; it can't be combined with MAX_FEE_GWEI or BASE_FEE_MULTIPLIER"))
+	}
+	if cfg.GasTipGwei > 0 && cfg.TipAsBaseFeePct > 0 {
+		problems.Add(fmt.Errorf("GAS_TIP_GWEI and TIP_AS_BASE_FEE_PCT are both set; use one or the other"))
+	}
+	if cfg.BlobFeeAsGasFeeMultiple < 0 {
+		problems.Add(fmt.Errorf("BLOB_FEE_AS_GAS_FEE_MULTIPLE cannot be negative"))
+	}
+	if cfg.BlobFeeAsGasFeeMultiple > 0 && cfg.BlobFeeCapGwei > 0 {
+		problems.Add(fmt.Errorf("BLOB_FEE_CAP_GWEI and BLOB_FEE_AS_GAS_FEE_MULTIPLE are both set; use one or the other"))
+	}
+	cfg.FeeCaps = ee.FeeCaps{
+		GasFeeCap:          ee.GweiToWei(cfg.GasFeeCapGwei),
+		BlobFeeCap:         ee.GweiToWei(cfg.BlobFeeCapGwei),
+		BaseFeeMultiplier:  cfg.BaseFeeMultiplier,
+		MaxFee:             ee.GweiToWei(cfg.MaxFeeGwei),
+		BlobFeeGasMultiple: cfg.BlobFeeAsGasFeeMultiple,
+	}
+	if err := cfg.FeeCaps.Validate(ee.GweiToWei(cfg.GasTipGwei)); err != nil {
+		problems.Add(fmt.Errorf("GAS_FEE_CAP_GWEI and MAX_FEE_GWEI must be at least GAS_TIP_GWEI: %w", err))
+	}
+	if cfg.EscalationStepPct < 0 || cfg.EscalationMaxMultiplier < 1 {
+		problems.Add(fmt.Errorf("ESCALATION_STEP_PCT cannot be negative and ESCALATION_MAX_MULTIPLIER must be at least 1"))
+	}
+	cfg.BidRange, err = strategy.NewBidAmountRange(cfg.BidMinEth, cfg.BidMaxEth)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid BID_MIN_ETH or BID_MAX_ETH: %w", err))
+	}
+	// Amounts drawn in wei replace those of the profiles, keeping their exact value
+	if cfg.BidAmountStrategy != "" {
+		if cfg.BidMinEth > 0 || cfg.BidMaxEth > 0 {
+			problems.Add(fmt.Errorf("BID_MIN_ETH and BID_MAX_ETH cannot be combined with BID_AMOUNT_STRATEGY"))
+		}
+		cfg.WeiAmount, err = strategy.NewWeiAmount(cfg.BidAmountStrategy, cfg.BidAmountMin, cfg.BidAmountMax)
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid BID_AMOUNT_STRATEGY, BID_AMOUNT_MIN or BID_AMOUNT_MAX: %w", err))
+		}
+	}
+	// TOKEN_TRANSFER is shorthand for TX_TYPE=erc20
+	if cfg.TokenTransfer {
+		if cfg.TxType != ee.GeneratorTransfer && cfg.TxType != ee.GeneratorERC20 {
+			problems.Add(fmt.Errorf("TOKEN_TRANSFER and TX_TYPE=%s are mutually exclusive", cfg.TxType))
+		}
+		cfg.TxType = ee.GeneratorERC20
+	}
+	if cfg.TxType == ee.GeneratorERC20 {
+		cfg.Token, err = ee.ParseTokenTransfer(cfg.TokenAddress, cfg.TokenAmount)
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid TOKEN_ADDRESS or TOKEN_AMOUNT: %w", err))
+		}
+	}
+	// Blob transactions are chosen per profile by NUM_BLOB, so TX_TYPE only
+	// replaces the generator of the other profiles
+	if _, ok := ee.LookupGenerator(cfg.TxType); !ok || cfg.TxType == ee.GeneratorBlob {
+		problems.Add(fmt.Errorf("TX_TYPE must name a registered generator other than %s (use NUM_BLOB for blobs): %s", ee.GeneratorBlob, strings.Join(ee.Generators(), ", ")))
+	}
+	// Parsed as an integer so that large amounts keep their exact value
+	var ok bool
+	cfg.TransferAmount, ok = new(big.Int).SetString(cfg.TransferAmountWei, 10)
+	if !ok || cfg.TransferAmount.Sign() < 0 {
+		problems.Add(fmt.Errorf("TRANSFER_AMOUNT_WEI must be a non-negative integer amount of wei, got %q", cfg.TransferAmountWei))
+	}
+	if cfg.TransferScheduleCSV != "" {
+		cfg.TransferSchedule, err = ee.LoadTransferSchedule(cfg.TransferScheduleCSV)
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid TRANSFER_SCHEDULE_CSV: %w", err))
+		}
+	}
+	// Timing defaults follow the chain's block time, detected from recent
+	// headers unless configured, so devnets with fast blocks work unchanged
+	// An invalid one is reported, and the other timing settings are checked against the default
+	cfg.BlockTime = strategy.DefaultBlockTime
+	if cfg.BlockTimeSetting == "auto" {
+		if cfg.DetectBlockTime != nil {
+			cfg.BlockTime = cfg.DetectBlockTime(cfg.WSEndpoint)
+		}
+	} else if parsed, err := time.ParseDuration(cfg.BlockTimeSetting); err != nil {
+		problems.Add(fmt.Errorf("invalid BLOCK_TIME %q: %w", cfg.BlockTimeSetting, err))
+	} else {
+		cfg.BlockTime = parsed
+	}
+	cfg.BlockTiming, err = strategy.NewBlockTiming(cfg.BlockTime)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid BLOCK_TIME: %w", err))
+		cfg.BlockTime = strategy.DefaultBlockTime
+		cfg.BlockTiming, _ = strategy.NewBlockTiming(cfg.BlockTime)
+	}
+	if !cfg.DecayDurationSet {
+		cfg.DecayDurationMs = cfg.BlockTiming.DecayDuration.Milliseconds()
+	}
+	if !cfg.DrainTimeoutSet {
+		cfg.DrainTimeoutSec = uint(max(cfg.BlockTiming.DrainTimeout/time.Second, 1))
+	}
+	if !cfg.NonceCheckIntervalSet {
+		cfg.NonceCheckInterval = cfg.BlockTiming.NonceCheckInterval
+	}
+	cfg.DecayTiming, err = bb.NewDecayTiming(cfg.DecayStartOffsetMs, cfg.DecayDurationMs)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid DECAY_START_OFFSET_MS or DECAY_DURATION_MS: %w", err))
+	} else if err := cfg.BlockTiming.Validate(cfg.DecayTiming.StartOffset, cfg.DecayTiming.Duration, time.Duration(cfg.DrainTimeoutSec)*time.Second); err != nil {
+		problems.Add(fmt.Errorf("timing settings are too long for a block time of %s: %w", cfg.BlockTime, err))
+	} else if cfg.DecayTiming.Duration < cfg.BlockTime {
+		slog.Warn("DECAY_DURATION_MS is shorter than a block interval, bids may fully decay before their block",
+			"decayDuration", cfg.DecayTiming.Duration.String(),
+			"blockTime", cfg.BlockTime.String(),
+		)
+	}
+	// Blob profiles scale their blob count by the blob base fee of the target block
+	if cfg.AdaptiveBlobCount {
+		if cfg.BlobRampEnabled {
+			problems.Add(fmt.Errorf("ADAPTIVE_BLOB_COUNT cannot be combined with BLOB_RAMP"))
+		}
+		cfg.AdaptiveBlobs, err = strategy.NewAdaptiveBlobCount(cfg.AdaptiveBlobMin, cfg.AdaptiveBlobMax,
+			new(big.Int).SetUint64(cfg.AdaptiveBlobLowFeeWei), new(big.Int).SetUint64(cfg.AdaptiveBlobHighFeeWei))
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid adaptive blob count settings: %w", err))
+		}
+	}
+	if cfg.NumBlob > strategy.MaxBlobsPerTx {
+		problems.Add(fmt.Errorf("NUM_BLOB must be at most %d, got %d", strategy.MaxBlobsPerTx, cfg.NumBlob))
+	}
+	// The default profile sweeps the listed blob counts, one per block
+	if cfg.NumBlobs != "" {
+		counts, err := strategy.ParseBlobCounts(cfg.NumBlobs)
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid NUM_BLOBS %q: %w", cfg.NumBlobs, err))
+		} else {
+			if cfg.NumBlob > 0 || cfg.BidProfilesFile != "" || cfg.BlobRampEnabled || cfg.AdaptiveBlobCount {
+				problems.Add(fmt.Errorf("NUM_BLOBS cannot be combined with NUM_BLOB, BID_PROFILES_FILE, BLOB_RAMP or ADAPTIVE_BLOB_COUNT"))
+			}
+			cfg.BlobCycle = strategy.NewBlobCycle(counts)
+			cfg.NumBlob = counts[0]
+			for _, count := range counts {
+				if int(count) < cfg.BlobSource.DataBlobs() {
+					problems.Add(fmt.Errorf("BLOB_DATA_PATH needs %d blobs, but NUM_BLOBS includes %d", cfg.BlobSource.DataBlobs(), count))
+					break
+				}
+			}
+		}
+	}
+	// Blob counts chosen at runtime could fall short of the data, which is never cut
+	if cfg.BlobSource.DataBlobs() > 0 && (cfg.BlobRampEnabled || cfg.AdaptiveBlobCount) {
+		problems.Add(fmt.Errorf("BLOB_DATA_PATH cannot be combined with BLOB_RAMP or ADAPTIVE_BLOB_COUNT"))
+	}
+	cfg.Outage, err = bb.NewBidderOutage(cfg.BidderOutagePolicy, int(cfg.BidderOutageFailures), int(cfg.BidderOutageQueueSize))
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid BIDDER_OUTAGE_POLICY: %w", err))
+	}
+	// A required audit must never silently fall back to no audit, so an
+	// unknown mode stops the bot before any key signs
+	switch cfg.KeyAuditMode {
+	case keyaudit.ModeOff, keyaudit.ModeOn, keyaudit.ModeRequired:
+	default:
+		problems.Add(fmt.Errorf("unknown key audit mode %q (must be %s, %s or %s)", cfg.KeyAuditMode, keyaudit.ModeOff, keyaudit.ModeOn, keyaudit.ModeRequired))
+	}
+	// A typo in BID_MODE could cost real money, so it never falls back to a default
+	switch cfg.BidMode {
+	case strategy.BidModeRandom:
+	case strategy.BidModeFixed:
+		if cfg.BidAmountEth <= 0 {
+			problems.Add(fmt.Errorf("BID_AMOUNT_ETH must be positive in fixed bid mode"))
+		}
+		if cfg.BidAmountStrategy != "" || cfg.BidMinEth > 0 || cfg.BidMaxEth > 0 {
+			problems.Add(fmt.Errorf("fixed bid mode cannot be combined with BID_AMOUNT_STRATEGY, BID_MIN_ETH or BID_MAX_ETH"))
+		}
+		// Every bid is exactly BID_AMOUNT_ETH, so misses don't escalate it
+		cfg.WeiAmount = strategy.NewFixedWeiAmount(bb.EthToWei(cfg.BidAmountEth))
+		cfg.EscalationMissedBids = 0
+	default:
+		problems.Add(fmt.Errorf("unknown bid mode %q (must be %s or %s)", cfg.BidMode, strategy.BidModeRandom, strategy.BidModeFixed))
+	}
+	cfg.DrainSignal, err = ParseDrainSignal(cfg.DrainSignalName)
+	if err != nil {
+		problems.Add(fmt.Errorf("invalid DRAIN_SIGNAL: %w", err))
+	}
+	if cfg.BidBlockRange == 0 {
+		problems.Add(fmt.Errorf("BID_BLOCK_RANGE must be at least 1"))
+	}
+
+	// Without a profiles file, bid with a single profile built from the flags
+	cfg.Profiles = []*strategy.Profile{{
+		Name:             "default",
+		BidAmount:        cfg.BidAmount,
+		StdDevPercentage: cfg.StdDevPercentage,
+		NumBlob:          cfg.NumBlob,
+	}}
+	if cfg.BidProfilesFile != "" {
+		var err error
+		cfg.Profiles, err = strategy.LoadProfiles(cfg.BidProfilesFile)
+		if err != nil {
+			problems.Add(fmt.Errorf("failed to load BID_PROFILES_FILE: %w", err))
+		}
+	}
+	if cfg.BlobSource.DataBlobs() > 0 && cfg.BlobCycle == nil {
+		sendsBlobs := false
+		for _, profile := range cfg.Profiles {
+			if profile.NumBlob == 0 {
+				continue
+			}
+			sendsBlobs = true
+			if int(profile.NumBlob) < cfg.BlobSource.DataBlobs() {
+				problems.Add(fmt.Errorf("BLOB_DATA_PATH needs %d blobs, but profile %s sends %d (NUM_BLOB allows at most %d)", cfg.BlobSource.DataBlobs(), profile.Name, profile.NumBlob, strategy.MaxBlobsPerTx))
+			}
+		}
+		if !sendsBlobs {
+			problems.Add(fmt.Errorf("BLOB_DATA_PATH needs blob transactions; set NUM_BLOB"))
+		}
+	}
+
+	// A self-test bids once on one block with the smallest configured amount, capped
+	if cfg.SelfTest && len(cfg.Profiles) > 0 {
+		amount := cfg.SelfTestMaxBidAmount
+		for _, profile := range cfg.Profiles {
+			amount = math.Min(amount, profile.BidAmount)
+		}
+		cfg.Profiles = []*strategy.Profile{{Name: "selftest", BidAmount: amount, NumBlob: cfg.Profiles[0].NumBlob}}
+		cfg.BidBlockRange = 1
+		cfg.BlobChainLength = 1
+		cfg.TxPerBlock = 1
+		cfg.BlobRampEnabled = false
+		cfg.BidRange = strategy.BidAmountRange{MaxEth: amount}
+		cfg.WeiAmount = nil
+	}
+	// Profiles without a decay window of their own use DECAY_DURATION_MS
+	for _, profile := range cfg.Profiles {
+		if profile.DecayMs == 0 {
+			continue
+		}
+		if err := cfg.BlockTiming.Validate(0, profile.DecayDuration(), 0); err != nil {
+			problems.Add(fmt.Errorf("decay_ms of profile %s is too long for the block time: %w", profile.Name, err))
+		}
+	}
+	// A bid whose decay window ends before its target block is due is worthless, so
+	// windows are checked against the farthest block bid on and fitted per OFFSET_DECAY_POLICY
+	if err := strategy.ValidateOffsetDecayPolicy(cfg.OffsetDecayPolicy); err != nil {
+		problems.Add(fmt.Errorf("invalid OFFSET_DECAY_POLICY: %w", err))
+	} else if cfg.DecayTiming.Duration > 0 && cfg.BidBlockRange > 0 {
+		if err := cfg.checkOffsetDecay("default", cfg.DecayTiming.Duration); err != nil {
+			problems.Add(fmt.Errorf("OFFSET is too far out for the decay window: %w", err))
+		}
+		for _, profile := range cfg.Profiles {
+			if profile.DecayMs == 0 {
+				continue
+			}
+			if err := cfg.checkOffsetDecay(profile.Name, profile.DecayDuration()); err != nil {
+				problems.Add(fmt.Errorf("OFFSET is too far out for the decay window of profile %s: %w", profile.Name, err))
+			}
+		}
+	}
+	if len(cfg.Profiles) > 0 {
+		cfg.ProfileSelector, err = strategy.NewProfileSelector(cfg.BidProfileSelector, cfg.Profiles, time.Now().UnixNano())
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid bid profile configuration: %w", err))
+		}
+	}
+	// The blob ramp replaces profile selection, bidding like the first profile
+	// with the ramp step's blob count
+	if cfg.BlobRampEnabled && len(cfg.Profiles) > 0 {
+		schedule := strategy.BlobRampSchedule(cfg.BlobRampMax)
+		var err error
+		if cfg.BlobRampSchedule != "" {
+			schedule, err = strategy.ParseBlobRampSchedule(cfg.BlobRampSchedule)
+		}
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid BLOB_RAMP_SCHEDULE: %w", err))
+		} else if cfg.BlobRamp, err = strategy.NewBlobRamp(cfg.Profiles[0], schedule, cfg.BlobRampStepBlocks, time.Now().UnixNano()); err != nil {
+			problems.Add(fmt.Errorf("invalid blob ramp configuration: %w", err))
+		} else {
+			cfg.Profiles = cfg.BlobRamp.Steps()
+		}
+	}
+	if cfg.ProposerAllowlist != "" && cfg.BeaconEndpoint == "" {
+		problems.Add(fmt.Errorf("PROPOSER_ALLOWLIST requires BEACON_ENDPOINT to be set"))
+	}
+	// An empty WS_ENDPOINT is prompted for once the configuration is valid
+	if cfg.WSEndpoint != "" {
+		endpoint, err := ValidateWebSocketURL(cfg.WSEndpoint)
+		if err != nil {
+			problems.Add(fmt.Errorf("invalid WS_ENDPOINT: %w", err))
+		} else {
+			cfg.WSEndpoint = endpoint
+		}
+	}
+	if cfg.PrivateKeys != "" {
+		for i, key := range strings.Split(cfg.PrivateKeys, ",") {
+			if err := ValidatePrivateKey(strings.TrimSpace(key)); err != nil {
+				problems.Add(fmt.Errorf("invalid key %d in PRIVATE_KEYS: %w", i, err))
+			}
+		}
+	}
+	// METRICS_ADDR takes precedence over METRICS_PORT, which listens on every interface
+	if cfg.MetricsAddr == "" && cfg.MetricsPort != 0 {
+		if cfg.MetricsPort > 65535 {
+			problems.Add(fmt.Errorf("METRICS_PORT must be at most 65535, got %d", cfg.MetricsPort))
+		} else {
+			cfg.MetricsAddr = fmt.Sprintf(":%d", cfg.MetricsPort)
+		}
+	}
+	return problems.Err()
+}
+
+// checkOffsetDecay checks a decay window against the farthest block bid on,
+// warning when it ends before that block is due and fitting it per
+// OFFSET_DECAY_POLICY.
+//
+// Parameters:
+// - profile: The name of the profile the window belongs to, for the warning.
+// - decay: The duration of the decay window.
+//
+// Returns:
+// - An error if the policy refuses the window or the fitted window is too long.
+func (cfg *Config) checkOffsetDecay(profile string, decay time.Duration) error {
+	farthest := cfg.Offset + cfg.BidBlockRange - 1
+	start, fitted, mismatch, err := cfg.BlockTiming.FitDecay(cfg.OffsetDecayPolicy, farthest, cfg.DecayTiming.StartOffset, decay)
+	if !mismatch {
+		return nil
+	}
+	slog.Warn("Decay window ends before the target block is due, so bids would be worthless by then",
+		"profile", profile,
+		"offset", cfg.Offset,
+		"bidBlockRange", cfg.BidBlockRange,
+		"targetDueAfter", cfg.BlockTiming.TargetHorizon(farthest).String(),
+		"decayEndsAfter", (cfg.DecayTiming.StartOffset + decay).String(),
+		"policy", cfg.OffsetDecayPolicy,
+		"fittedStartOffset", start.String(),
+		"fittedDuration", fitted.String(),
+	)
+	if err != nil {
+		return err
+	}
+	return cfg.BlockTiming.Validate(start, fitted, 0)
+}
+
+// ValidateWebSocketURL validates and formats the WebSocket URL
+func ValidateWebSocketURL(input string) (string, error) {
+	if input == "" {
+		return "", fmt.Errorf("endpoint cannot be empty")
+	}
+
+	if !strings.Contains(input, "://") {
+		input = "ws://" + input
+	}
+
+	parsedURL, err := url.Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL format: %v", err)
+	}
+
+	if parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
+		return "", fmt.Errorf("invalid scheme: %s (only ws:// or wss:// are supported)", parsedURL.Scheme)
+	}
+
+	if parsedURL.Host == "" {
+		return "", fmt.Errorf("URL must include a host")
+	}
+
+	return parsedURL.String(), nil
+}
+
+// ValidatePrivateKey ensures the private key is a 64-character hexadecimal string
+func ValidatePrivateKey(input string) error {
+	if len(input) != 64 {
+		return fmt.Errorf("private key must be 64 hex characters")
+	}
+	return nil
+}
+
+// drainSignals are the signals that can be configured to start a drain.
+var drainSignals = map[string]os.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// ParseDrainSignal returns the signal that starts a drain, or nil for "none".
+func ParseDrainSignal(name string) (os.Signal, error) {
+	name = strings.ToUpper(name)
+	if name == "NONE" {
+		return nil, nil
+	}
+	if sig, ok := drainSignals[name]; ok {
+		return sig, nil
+	}
+	return nil, fmt.Errorf("unknown drain signal %q (must be SIGTERM, SIGHUP, SIGUSR1, SIGUSR2, or none)", name)
+}
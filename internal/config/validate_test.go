@@ -0,0 +1,131 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/store"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+	"github.com/primev/preconf_blob_bidder/internal/watch"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig returns the default options, which Validate accepts.
+func validConfig() *Config {
+	return &Config{
+		ServerAddress:           "localhost:13524",
+		UsePayload:              true,
+		WSEndpoint:              "wss://node",
+		Offset:                  1,
+		BidAmount:               0.001,
+		StdDevPercentage:        100,
+		BidProfileSelector:      strategy.SelectorRoundRobin,
+		BidBlockRange:           1,
+		BidPricingWorkers:       1,
+		BidMode:                 strategy.BidModeRandom,
+		EscalationStepPct:       25,
+		EscalationMaxMultiplier: 5,
+		OffsetDecayPolicy:       strategy.OffsetDecayExtend,
+		BidRetry:                bb.BidRetryPolicy{Attempts: 3, Backoff: 100 * time.Millisecond},
+		StaleBidPolicy:          bb.StaleBidSkip,
+		BidderOutagePolicy:      bb.OutagePolicySkip,
+		BidderOutageFailures:    3,
+		BidderOutageQueueSize:   8,
+		TxType:                  ee.GeneratorTransfer,
+		TxPerBlock:              1,
+		TokenAmount:             "1",
+		TransferAmountWei:       "1000000000",
+		BaseFeeMultiplier:       1,
+		BlobChainLength:         1,
+		BlobSidecar:             ee.BlobSidecarFull,
+		BundleOverflowPolicy:    ee.BundleOverflowQueue,
+		Mode:                    watch.ModeBuild,
+		KeyAuditMode:            "off",
+		BlockTimeSetting:        "12s",
+		DrainSignalName:         "SIGTERM",
+		DrainConfirmations:      2,
+		RPCPoolSize:             2,
+		RPCPoolHealthInterval:   30 * time.Second,
+		BidLogFormat:            store.FormatJSONL,
+		BidLogMaxBackups:        10,
+		MetricsPort:             9090,
+	}
+}
+
+func TestValidateDerivesSettings(t *testing.T) {
+	cfg := validConfig()
+	cfg.WSEndpoint = "node:8546"
+	require.NoError(t, cfg.Validate())
+
+	require.Equal(t, "ws://node:8546", cfg.WSEndpoint)
+	require.Equal(t, []string{"localhost:13524"}, cfg.ServerAddresses)
+	require.Equal(t, 12*time.Second, cfg.BlockTime)
+	require.Equal(t, cfg.BlockTiming.DecayDuration.Milliseconds(), cfg.DecayDurationMs)
+	require.Equal(t, ":9090", cfg.MetricsAddr)
+	require.Len(t, cfg.Profiles, 1)
+	require.NotNil(t, cfg.ProfileSelector)
+	require.NotNil(t, cfg.DrainSignal)
+}
+
+func TestValidateDetectsBlockTime(t *testing.T) {
+	cfg := validConfig()
+	cfg.BlockTimeSetting = "auto"
+	var detectedFrom string
+	cfg.DetectBlockTime = func(wsEndpoint string) time.Duration {
+		detectedFrom = wsEndpoint
+		return 2 * time.Second
+	}
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, "wss://node", detectedFrom)
+	require.Equal(t, 2*time.Second, cfg.BlockTime)
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.TxPerBlock = 0
+	cfg.OffsetDecayPolicy = "bogus"
+	cfg.ProposerAllowlist = "allowlist.txt"
+	cfg.WSEndpoint = "https://node"
+	cfg.BlobRampEnabled = true
+	cfg.BlobRampSchedule = "x"
+	cfg.PrivateKeys = "short"
+	cfg.MetricsPort = 70000
+
+	err := cfg.Validate()
+	require.Len(t, Split(err), 7)
+	require.ErrorContains(t, err, "TX_PER_BLOCK must be at least 1")
+	require.ErrorContains(t, err, "invalid OFFSET_DECAY_POLICY")
+	require.ErrorContains(t, err, "PROPOSER_ALLOWLIST requires BEACON_ENDPOINT")
+	require.ErrorContains(t, err, "invalid WS_ENDPOINT")
+	require.ErrorContains(t, err, "invalid BLOB_RAMP_SCHEDULE")
+	require.ErrorContains(t, err, "invalid key 0 in PRIVATE_KEYS")
+	require.ErrorContains(t, err, "METRICS_PORT must be at most 65535")
+}
+
+func TestValidateSelfTest(t *testing.T) {
+	cfg := validConfig()
+	cfg.SelfTest = true
+	cfg.SelfTestMaxBidAmount = 0.0005
+	cfg.BidBlockRange = 3
+	cfg.BlobRampEnabled = true
+	require.NoError(t, cfg.Validate())
+
+	require.Len(t, cfg.Profiles, 1)
+	require.Equal(t, "selftest", cfg.Profiles[0].Name)
+	require.Equal(t, 0.0005, cfg.Profiles[0].BidAmount)
+	require.Equal(t, uint64(1), cfg.BidBlockRange)
+	require.Nil(t, cfg.BlobRamp)
+}
+
+func TestValidateBlobRamp(t *testing.T) {
+	cfg := validConfig()
+	cfg.BlobRampEnabled = true
+	cfg.BlobRampMax = 3
+	cfg.BlobRampStepBlocks = 10
+	require.NoError(t, cfg.Validate())
+
+	require.NotNil(t, cfg.BlobRamp)
+	require.Equal(t, cfg.BlobRamp.Steps(), cfg.Profiles)
+}
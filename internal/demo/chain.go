@@ -0,0 +1,122 @@
+// Package demo runs a simulated chain that the bidder can connect to over
+// WebSocket, so it can be tried without a node or a funded account.
+package demo
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// Chain is an in-process simulated chain with a funded account, serving
+// JSON-RPC over WebSocket and producing a block every block time.
+type Chain struct {
+	URL string            // WebSocket endpoint of the chain.
+	Key *ecdsa.PrivateKey // Key of the funded account.
+
+	backend *simulated.Backend
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// StartChain starts a simulated chain.
+//
+// Parameters:
+// - blockTime: How often a block is produced.
+//
+// Returns:
+// - A pointer to the running Chain, or an error if it cannot be started.
+func StartChain(blockTime time.Duration) (*Chain, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate demo key: %w", err)
+	}
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e18))
+	alloc := types.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: balance}}
+	backend := simulated.NewBackend(alloc, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.WSHost = "127.0.0.1"
+		nodeConf.WSPort = port
+		nodeConf.WSModules = []string{"eth", "net", "web3"}
+	})
+
+	c := &Chain{
+		URL:     fmt.Sprintf("ws://127.0.0.1:%d", port),
+		Key:     key,
+		backend: backend,
+		stop:    make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.produceBlocks(blockTime)
+	return c, nil
+}
+
+// PrivateKeyHex returns the funded account's key as 64 hex characters.
+func (c *Chain) PrivateKeyHex() string {
+	return hex.EncodeToString(crypto.FromECDSA(c.Key))
+}
+
+// SendRawTransaction adds an RLP-encoded transaction to the pool, to be included
+// in the next block.
+func (c *Chain) SendRawTransaction(raw []byte) error {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("invalid transaction: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return c.backend.Client().SendTransaction(ctx, &tx)
+}
+
+// Close stops block production and shuts the chain down.
+func (c *Chain) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.stop)
+		c.wg.Wait()
+		err = c.backend.Close()
+	})
+	return err
+}
+
+func (c *Chain) produceBlocks(blockTime time.Duration) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(blockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			hash := c.backend.Commit()
+			slog.Debug("Demo chain produced block", "hash", hash.Hex())
+		}
+	}
+}
+
+// freePort returns a TCP port that was free at the time of the call.
+func freePort() (int, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer lis.Close()
+	return lis.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,65 @@
+package demo
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainIncludesSubmittedTransactions(t *testing.T) {
+	chain, err := StartChain(50 * time.Millisecond)
+	require.NoError(t, err)
+	defer chain.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := ethclient.DialContext(ctx, chain.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	headers := make(chan *types.Header)
+	sub, err := client.SubscribeNewHead(ctx, headers)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	// A self transfer from the funded account, submitted the way the fake bidder does
+	addr := crypto.PubkeyToAddress(chain.Key.PublicKey)
+	chainID, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	header, err := client.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	tx, err := types.SignNewTx(chain.Key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		To:        &addr,
+		Value:     big.NewInt(1),
+		Gas:       21_000,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), big.NewInt(params.GWei)),
+	})
+	require.NoError(t, err)
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, chain.SendRawTransaction(raw))
+
+	for {
+		select {
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case h := <-headers:
+			block, err := client.BlockByHash(ctx, h.Hash())
+			require.NoError(t, err)
+			if block.Transaction(tx.Hash()) != nil {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("transaction was not included")
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// BlobBaseFee returns the blob base fee per blob gas (in wei) paid by the blobs in
+// the header's block, or nil before Cancun.
+func BlobBaseFee(header *types.Header) *big.Int {
+	if header.ExcessBlobGas == nil {
+		return nil
+	}
+	return eip4844.CalcBlobFee(*header.ExcessBlobGas)
+}
+
+// NextBlobBaseFee estimates the blob base fee per blob gas (in wei) of the block
+// after the header's, or nil before Cancun.
+func NextBlobBaseFee(header *types.Header) *big.Int {
+	if header.ExcessBlobGas == nil || header.BlobGasUsed == nil {
+		return nil
+	}
+	return eip4844.CalcBlobFee(eip4844.CalcExcessBlobGas(*header.ExcessBlobGas, *header.BlobGasUsed))
+}
+
+// BlobFee returns the blob fee (in wei) a transaction pays at the given blob base fee.
+func BlobFee(tx *types.Transaction, blobBaseFee *big.Int) *big.Int {
+	if blobBaseFee == nil {
+		return new(big.Int)
+	}
+	blobGas := uint64(len(tx.BlobHashes())) * params.BlobTxBlobGasPerBlob
+	return new(big.Int).Mul(new(big.Int).SetUint64(blobGas), blobBaseFee)
+}
+
+// FeeCapsCover reports whether a transaction's fee caps still cover the base fee
+// of the header's block and, for a blob transaction, the estimated blob base fee
+// of the next block. A header without a base fee covers any cap.
+func FeeCapsCover(tx *types.Transaction, header *types.Header) bool {
+	if header.BaseFee != nil && tx.GasFeeCap().Cmp(header.BaseFee) < 0 {
+		return false
+	}
+	if len(tx.BlobHashes()) == 0 {
+		return true
+	}
+	blobBaseFee := NextBlobBaseFee(header)
+	return blobBaseFee == nil || tx.BlobGasFeeCap().Cmp(blobBaseFee) >= 0
+}
@@ -0,0 +1,51 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobFees(t *testing.T) {
+	// Below the target, the excess is zero and the blob base fee is the minimum of 1 wei
+	excess, used := uint64(0), uint64(params.BlobTxBlobGasPerBlob)
+	header := &types.Header{ExcessBlobGas: &excess, BlobGasUsed: &used}
+	require.Equal(t, big.NewInt(1), BlobBaseFee(header))
+	require.Equal(t, big.NewInt(1), NextBlobBaseFee(header))
+
+	// A full block raises the next block's excess and fee
+	excess, used = 100*params.BlobTxTargetBlobGasPerBlock, params.MaxBlobGasPerBlock
+	require.Equal(t, 1, NextBlobBaseFee(header).Cmp(BlobBaseFee(header)))
+
+	tx := types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{{1}, {2}, {3}}})
+	require.Equal(t, big.NewInt(3*params.BlobTxBlobGasPerBlob*7), BlobFee(tx, big.NewInt(7)))
+
+	require.Nil(t, BlobBaseFee(&types.Header{}))
+	require.Zero(t, BlobFee(tx, nil).Sign())
+}
+
+func TestFeeCapsCover(t *testing.T) {
+	excess, used := uint64(100*params.BlobTxTargetBlobGasPerBlock), uint64(params.MaxBlobGasPerBlock)
+	header := &types.Header{BaseFee: big.NewInt(100), ExcessBlobGas: &excess, BlobGasUsed: &used}
+	nextBlobFee := NextBlobBaseFee(header)
+
+	transfer := types.NewTx(&types.DynamicFeeTx{GasFeeCap: big.NewInt(100)})
+	require.True(t, FeeCapsCover(transfer, header))
+	require.False(t, FeeCapsCover(transfer, &types.Header{BaseFee: big.NewInt(101)}))
+	require.True(t, FeeCapsCover(transfer, &types.Header{}))
+
+	blob := func(blobFeeCap *big.Int) *types.Transaction {
+		return types.NewTx(&types.BlobTx{
+			GasFeeCap:  uint256.NewInt(100),
+			BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+			BlobHashes: []common.Hash{{1}},
+		})
+	}
+	require.True(t, FeeCapsCover(blob(nextBlobFee), header))
+	require.False(t, FeeCapsCover(blob(new(big.Int).Sub(nextBlobFee, big.NewInt(1))), header))
+}
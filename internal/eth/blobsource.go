@@ -0,0 +1,122 @@
+package eth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"golang.org/x/exp/rand"
+)
+
+// Blob data packing. Each 32-byte field element of a blob holds 31 bytes of
+// data after a zero byte, which keeps every element below the BLS modulus.
+const (
+	blobElementDataSize = gokzg4844.SerializedScalarSize - 1
+	BlobDataCapacity    = gokzg4844.ScalarsPerBlob * blobElementDataSize // Bytes of data per blob.
+)
+
+// BlobSource generates the data of blob transactions. A source made with a
+// seed produces the same sequence of blobs, and so the same commitments and
+// versioned hashes, on every run and machine, for reproducible tests. A source
+// made from a file sends the file's contents in every transaction.
+type BlobSource struct {
+	mu   sync.Mutex
+	rng  *rand.Rand
+	data []kzg4844.Blob // The blobs of a file source.
+}
+
+// NewBlobSource returns a BlobSource seeded with seed.
+func NewBlobSource(seed uint64) *BlobSource {
+	return &BlobSource{rng: rand.New(rand.NewSource(seed))}
+}
+
+// NewFileBlobSource returns a BlobSource sending the contents of a file, or of
+// the regular files in a directory concatenated in name order.
+//
+// Parameters:
+// - path: The file or directory.
+//
+// Returns:
+// - A pointer to a BlobSource, or an error if the data can't be read or is empty.
+func NewFileBlobSource(path string) (*BlobSource, error) {
+	data, err := readBlobData(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("blob data %s is empty", path)
+	}
+	return &BlobSource{data: PackBlobs(data)}, nil
+}
+
+// DataBlobs returns the number of blobs a file source's data takes, 0 for other sources.
+func (s *BlobSource) DataBlobs() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.data)
+}
+
+// Blobs returns the next n blobs of the source. A nil source returns random
+// blobs that differ between runs. A file source returns its data, followed by
+// empty blobs up to n; the data is never cut short.
+func (s *BlobSource) Blobs(n int) []kzg4844.Blob {
+	if s == nil {
+		return randBlobs(n)
+	}
+	if s.data != nil {
+		blobs := append([]kzg4844.Blob{}, s.data...)
+		for len(blobs) < n {
+			blobs = append(blobs, kzg4844.Blob{})
+		}
+		return blobs
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return blobsFrom(s.rng.Read, n)
+}
+
+// PackBlobs chunks data into blobs of BlobDataCapacity bytes, 31 bytes per
+// field element after a zero byte. The last blob is padded with zeros.
+func PackBlobs(data []byte) []kzg4844.Blob {
+	blobs := make([]kzg4844.Blob, (len(data)+BlobDataCapacity-1)/BlobDataCapacity)
+	for i := range blobs {
+		chunk := data[i*BlobDataCapacity : min((i+1)*BlobDataCapacity, len(data))]
+		for j := 0; j*blobElementDataSize < len(chunk); j++ {
+			element := chunk[j*blobElementDataSize : min((j+1)*blobElementDataSize, len(chunk))]
+			copy(blobs[i][j*gokzg4844.SerializedScalarSize+1:], element)
+		}
+	}
+	return blobs
+}
+
+// readBlobData reads a file, or the regular files of a directory in name order,
+// which os.ReadDir returns them in.
+func readBlobData(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob data: %w", err)
+	}
+	if !info.IsDir() {
+		return os.ReadFile(path)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob data: %w", err)
+	}
+	var data []byte
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blob data: %w", err)
+		}
+		data = append(data, contents...)
+	}
+	return data, nil
+}
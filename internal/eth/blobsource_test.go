@@ -0,0 +1,93 @@
+package eth
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobSourceIsDeterministic(t *testing.T) {
+	// The versioned hashes of seed 42 are fixed, so any run on any machine reproduces them
+	sidecar := makeSidecar(NewBlobSource(42).Blobs(2))
+	require.NoError(t, validateSidecar(sidecar))
+	require.Equal(t, []common.Hash{
+		common.HexToHash("0x0129971309c223206cb308f4351050cc8429e2b4dc57b6d12326c04ab63be700"),
+		common.HexToHash("0x01425b530a935b245722771b3e564529a495bdb8f4cfd8091418c6b479c803e8"),
+	}, sidecar.BlobHashes())
+
+	// Each call continues the sequence, so a run's transactions don't repeat blobs
+	source := NewBlobSource(42)
+	first, second := source.Blobs(1), source.Blobs(1)
+	require.Equal(t, sidecar.Blobs, append(first, second...))
+	require.NotEqual(t, NewBlobSource(42).Blobs(1), NewBlobSource(43).Blobs(1))
+}
+
+func TestPackBlobs(t *testing.T) {
+	// Each field element holds 31 bytes after a zero byte; the rest is zero padding
+	blobs := PackBlobs([]byte("hello blobs"))
+	require.Len(t, blobs, 1)
+	require.Equal(t, byte(0), blobs[0][0])
+	require.Equal(t, []byte("hello blobs"), blobs[0][1:12])
+	require.Equal(t, make([]byte, len(blobs[0])-12), blobs[0][12:])
+
+	// One byte over a blob's capacity starts a second blob, and all-ones data
+	// stays below the field modulus
+	full := bytes.Repeat([]byte{0xff}, BlobDataCapacity+1)
+	blobs = PackBlobs(full)
+	require.Len(t, blobs, 2)
+	require.Equal(t, []byte{0, 0xff, 0xff}, blobs[0][:3])
+	require.Equal(t, []byte{0, 0xff}, blobs[0][32:34])
+	require.Equal(t, []byte{0, 0xff, 0}, blobs[1][:3])
+
+	// The commitments of known data are fixed
+	for _, tc := range []struct {
+		data        []byte
+		commitments []string
+	}{
+		{[]byte("hello blobs"), []string{
+			"0xa22c54118b3700625af11f91786f3705c14338b2dac14b6d6b0968373caef3307a8613005ccf35487c12a2e2c70c248f",
+		}},
+		{full, []string{
+			"0x923a7266c9ef4ffeec8b733509d7ff55658a6bbbc449a2f282003bccc5e85ae52e133609f7b71511f88cc726207f8c41",
+			"0xb0158099b8307483a778b4ff5fe99d7b27af54f82830d8b329a9b1f86d72fc04b4cef94e49c490539e842d0395f732ec",
+		}},
+	} {
+		sidecar := makeSidecar(PackBlobs(tc.data))
+		require.NoError(t, validateSidecar(sidecar))
+		commitments := make([]string, len(sidecar.Commitments))
+		for i, commitment := range sidecar.Commitments {
+			commitments[i] = hexutil.Encode(commitment[:])
+		}
+		require.Equal(t, tc.commitments, commitments)
+	}
+}
+
+func TestFileBlobSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte(" blobs"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o600))
+
+	// A directory's files are concatenated in name order
+	source, err := NewFileBlobSource(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, source.DataBlobs())
+	require.Equal(t, PackBlobs([]byte("hello blobs")), source.Blobs(1))
+
+	// The data is padded with empty blobs up to the requested count, never cut short
+	blobs := source.Blobs(3)
+	require.Len(t, blobs, 3)
+	require.Equal(t, kzg4844.Blob{}, blobs[2])
+	require.Len(t, source.Blobs(0), 1)
+
+	_, err = NewFileBlobSource(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty"), nil, 0o600))
+	_, err = NewFileBlobSource(filepath.Join(dir, "empty"))
+	require.Error(t, err)
+}
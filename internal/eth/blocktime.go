@@ -0,0 +1,126 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HeaderReader returns block headers. *ethclient.Client implements it.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// EstimateBlockTime estimates a chain's block time from the timestamps of the
+// latest header and the one span blocks before it, or the genesis if the chain is
+// shorter. Timestamps have second resolution, so a longer span resolves sub-second
+// block times more finely.
+//
+// Parameters:
+// - ctx: The context bounding the header requests.
+// - reader: The source of headers.
+// - span: The number of blocks to average over.
+//
+// Returns:
+// - The average time between blocks, or an error if it can't be measured.
+func EstimateBlockTime(ctx context.Context, reader HeaderReader, span uint64) (time.Duration, error) {
+	latest, err := reader.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	head := latest.Number.Uint64()
+	if head == 0 {
+		return 0, fmt.Errorf("chain has no blocks after genesis")
+	}
+	if span > head {
+		span = head
+	}
+	earlier, err := reader.HeaderByNumber(ctx, new(big.Int).SetUint64(head-span))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get header %d: %w", head-span, err)
+	}
+	return averageBlockTime(earlier.Time, latest.Time, span)
+}
+
+// averageBlockTime returns the average time between blocks over blocks blocks
+// that took from fromTime to toTime, in Unix seconds.
+func averageBlockTime(fromTime, toTime, blocks uint64) (time.Duration, error) {
+	if toTime <= fromTime {
+		return 0, fmt.Errorf("header timestamps don't advance over %d blocks", blocks)
+	}
+	return time.Duration(toTime-fromTime) * time.Second / time.Duration(blocks), nil
+}
+
+// BlockTimeEstimator re-estimates the block time slowly from the headers the bot
+// receives, averaging over a span of blocks at a time so that a few late blocks
+// don't move it.
+type BlockTimeEstimator struct {
+	span uint64
+
+	mu        sync.Mutex
+	estimate  time.Duration
+	fromBlock uint64
+	fromTime  uint64
+	started   bool
+}
+
+// NewBlockTimeEstimator creates a BlockTimeEstimator.
+//
+// Parameters:
+// - initial: The block time until the first span of headers is observed.
+// - span: The number of blocks each estimate averages over.
+//
+// Returns:
+// - A pointer to a BlockTimeEstimator.
+func NewBlockTimeEstimator(initial time.Duration, span uint64) *BlockTimeEstimator {
+	if span < 1 {
+		span = 1
+	}
+	return &BlockTimeEstimator{span: span, estimate: initial}
+}
+
+// Observe adds a header's number and timestamp. Once span blocks have passed since
+// the last estimate the block time is estimated again, and adopted if it differs
+// from the current estimate by at least a tenth.
+//
+// Returns:
+// - The current estimate, and whether this header changed it.
+func (e *BlockTimeEstimator) Observe(number, timestamp uint64) (time.Duration, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Start over after a reorg or the first header
+	if !e.started || number <= e.fromBlock {
+		e.fromBlock, e.fromTime, e.started = number, timestamp, true
+		return e.estimate, false
+	}
+	blocks := number - e.fromBlock
+	if blocks < e.span {
+		return e.estimate, false
+	}
+	estimate, err := averageBlockTime(e.fromTime, timestamp, blocks)
+	e.fromBlock, e.fromTime = number, timestamp
+	if err != nil {
+		return e.estimate, false
+	}
+	diff := estimate - e.estimate
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff*10 < e.estimate {
+		return e.estimate, false
+	}
+	e.estimate = estimate
+	return estimate, true
+}
+
+// Estimate returns the current block time estimate.
+func (e *BlockTimeEstimator) Estimate() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.estimate
+}
@@ -0,0 +1,81 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeaders is a chain of head+1 headers produced every interval from genesis.
+type fakeHeaders struct {
+	head     uint64
+	interval time.Duration
+}
+
+func (f fakeHeaders) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	n := f.head
+	if number != nil {
+		n = number.Uint64()
+	}
+	if n > f.head {
+		return nil, fmt.Errorf("block %d not found", n)
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n), Time: f.timestamp(n)}, nil
+}
+
+// timestamp returns the whole-second timestamp of block n.
+func (f fakeHeaders) timestamp(n uint64) uint64 {
+	return uint64(1_700_000_000 + time.Duration(n)*f.interval/time.Second)
+}
+
+func TestEstimateBlockTime(t *testing.T) {
+	for _, interval := range []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 12 * time.Second} {
+		estimate, err := EstimateBlockTime(context.Background(), fakeHeaders{head: 1000, interval: interval}, 64)
+		require.NoError(t, err)
+		require.Equal(t, interval, estimate)
+	}
+
+	// A short chain averages over all its blocks
+	estimate, err := EstimateBlockTime(context.Background(), fakeHeaders{head: 5, interval: 2 * time.Second}, 64)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, estimate)
+
+	_, err = EstimateBlockTime(context.Background(), fakeHeaders{head: 0, interval: time.Second}, 64)
+	require.Error(t, err)
+}
+
+func TestBlockTimeEstimatorReestimatesSlowly(t *testing.T) {
+	chain := fakeHeaders{interval: 2 * time.Second}
+	e := NewBlockTimeEstimator(12*time.Second, 10)
+
+	// Nothing changes until a whole span of headers has been observed
+	for n := uint64(100); n < 110; n++ {
+		estimate, changed := e.Observe(n, chain.timestamp(n))
+		require.False(t, changed)
+		require.Equal(t, 12*time.Second, estimate)
+	}
+	estimate, changed := e.Observe(110, chain.timestamp(110))
+	require.True(t, changed)
+	require.Equal(t, 2*time.Second, estimate)
+
+	// A single slow block within a span doesn't move the estimate by a tenth
+	slow := fakeHeaders{interval: 2 * time.Second}
+	for n := uint64(111); n <= 120; n++ {
+		ts := slow.timestamp(n)
+		if n >= 115 {
+			ts++
+		}
+		_, changed = e.Observe(n, ts)
+		require.False(t, changed)
+	}
+	require.Equal(t, 2*time.Second, e.Estimate())
+
+	// A reorg starts the span over
+	_, changed = e.Observe(118, chain.timestamp(118))
+	require.False(t, changed)
+}
@@ -0,0 +1,95 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BroadcastAt returns when the transactions of a bid sent at bidAt are broadcast:
+// delay after the bid, but no later than deadline, past which the target block
+// is due and a later broadcast can't make it.
+//
+// Parameters:
+// - bidAt: When the bid was sent.
+// - delay: The head start given to providers, 0 to broadcast right after the bid.
+// - deadline: When the target block is due, or the zero time for none.
+func BroadcastAt(bidAt time.Time, delay time.Duration, deadline time.Time) time.Time {
+	at := bidAt.Add(delay)
+	if !deadline.IsZero() && at.After(deadline) {
+		return deadline
+	}
+	return at
+}
+
+// BroadcastAfterDelay waits until BroadcastAt and then calls broadcast. A done
+// ctx cuts the wait short rather than skipping the broadcast, so that shutting
+// down doesn't leave the transactions of bids already sent unbroadcast.
+//
+// Parameters:
+// - ctx: Ends the wait early.
+// - bidAt: When the bid was sent.
+// - delay: The head start given to providers.
+// - deadline: When the target block is due, or the zero time for none.
+// - broadcast: Sends the bid's transactions.
+//
+// Returns:
+// - The error of broadcast.
+func BroadcastAfterDelay(ctx context.Context, bidAt time.Time, delay time.Duration, deadline time.Time, broadcast func() error) error {
+	if wait := time.Until(BroadcastAt(bidAt, delay, deadline)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+	return broadcast()
+}
+
+// BroadcastBid broadcasts the transactions of a bid after the delay, as
+// BroadcastAfterDelay does. Their nonces were counted as used when the bid was
+// built, so a sender whose transaction fails to go out has the failure counted
+// by nonces, as a failed send right after building would be, and resyncs rather
+// than stall on the gap.
+//
+// Parameters:
+// - ctx: Ends the wait early.
+// - bidAt: When the bid was sent.
+// - delay: The head start given to providers.
+// - deadline: When the target block is due, or the zero time for none.
+// - txs: The bid's transactions.
+// - send: Broadcasts one transaction.
+// - nonces: Allocated the transactions' nonces, or nil.
+//
+// Returns:
+// - Whether a sender was resynced, and the errors of send.
+func BroadcastBid(ctx context.Context, bidAt time.Time, delay time.Duration, deadline time.Time, txs []*types.Transaction, send func(tx *types.Transaction) error, nonces *NonceAllocator) (bool, error) {
+	failed := make(map[common.Address]bool)
+	err := BroadcastAfterDelay(ctx, bidAt, delay, deadline, func() error {
+		var errs []error
+		for _, tx := range txs {
+			err := send(tx)
+			if err == nil {
+				continue
+			}
+			errs = append(errs, err)
+			if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+				failed[from] = true
+			}
+		}
+		return errors.Join(errs...)
+	})
+	resynced := false
+	if nonces != nil {
+		for from := range failed {
+			if nonces.Fail(from) {
+				resynced = true
+			}
+		}
+	}
+	return resynced, err
+}
@@ -0,0 +1,82 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastAt(t *testing.T) {
+	bidAt := time.UnixMilli(1_700_000_000_000)
+	require.Equal(t, bidAt.Add(2*time.Second), BroadcastAt(bidAt, 2*time.Second, time.Time{}))
+	require.Equal(t, bidAt.Add(2*time.Second), BroadcastAt(bidAt, 2*time.Second, bidAt.Add(12*time.Second)))
+	// The broadcast never waits past the target block's deadline
+	require.Equal(t, bidAt.Add(time.Second), BroadcastAt(bidAt, 2*time.Second, bidAt.Add(time.Second)))
+	require.Equal(t, bidAt, BroadcastAt(bidAt, 0, bidAt.Add(time.Second)))
+}
+
+// broadcastElapsed returns how long after bidAt BroadcastAfterDelay broadcast.
+func broadcastElapsed(t *testing.T, ctx context.Context, delay time.Duration, deadline time.Time) time.Duration {
+	t.Helper()
+	bidAt := time.Now()
+	var broadcastAt time.Time
+	err := BroadcastAfterDelay(ctx, bidAt, delay, deadline, func() error {
+		broadcastAt = time.Now()
+		return nil
+	})
+	require.NoError(t, err)
+	return broadcastAt.Sub(bidAt)
+}
+
+func TestBroadcastAfterDelay(t *testing.T) {
+	elapsed := broadcastElapsed(t, context.Background(), 200*time.Millisecond, time.Now().Add(time.Minute))
+	require.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	require.Less(t, elapsed, 400*time.Millisecond)
+
+	// A deadline before the delay ends brings the broadcast forward
+	elapsed = broadcastElapsed(t, context.Background(), time.Minute, time.Now().Add(100*time.Millisecond))
+	require.GreaterOrEqual(t, elapsed, 90*time.Millisecond)
+	require.Less(t, elapsed, 300*time.Millisecond)
+
+	// Shutting down broadcasts right away instead of dropping the transactions
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Less(t, broadcastElapsed(t, ctx, time.Minute, time.Time{}), 100*time.Millisecond)
+}
+
+func TestBroadcastBidFailureResyncsNonces(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 5})
+	require.NoError(t, err)
+
+	nonces := NewNonceAllocator(1)
+	_, err = nonces.Allocate(context.Background(), &scriptedNonceReader{counts: [][2]uint64{{5, 5}}}, from, 1)
+	require.NoError(t, err)
+	nonces.Succeed(from)
+
+	// A broadcast that goes out leaves the counter alone
+	resynced, err := BroadcastBid(context.Background(), time.Now(), 0, time.Time{}, []*types.Transaction{tx}, func(*types.Transaction) error { return nil }, nonces)
+	require.NoError(t, err)
+	require.False(t, resynced)
+	next, ok := nonces.Next(from)
+	require.True(t, ok)
+	require.Equal(t, uint64(6), next)
+
+	// One that fails leaves nonce 5 unused, so the next allocation starts from the pending count
+	resynced, err = BroadcastBid(context.Background(), time.Now(), 0, time.Time{}, []*types.Transaction{tx}, func(*types.Transaction) error {
+		return errors.New("connection refused")
+	}, nonces)
+	require.EqualError(t, err, "connection refused")
+	require.True(t, resynced)
+	_, ok = nonces.Next(from)
+	require.False(t, ok)
+}
@@ -17,10 +17,10 @@ import (
 )
 
 type JSONRPCResponse struct {
-	Result    json.RawMessage `json:"result"`
-	RPCError  RPCError         `json:"error"`
-	ID        int              `json:"id,omitempty"`
-	Jsonrpc   string           `json:"jsonrpc,omitempty"`
+	Result   json.RawMessage `json:"result"`
+	RPCError RPCError        `json:"error"`
+	ID       int             `json:"id,omitempty"`
+	Jsonrpc  string          `json:"jsonrpc,omitempty"`
 }
 
 type RPCError struct {
@@ -35,10 +35,14 @@ type FlashbotsPayload struct {
 	ID      int                      `json:"id"`
 }
 
-
 // SendBundle sends a signed transaction bundle to the specified RPC URL.
 // It returns the result as a string or an error if the operation fails.
 func SendBundle(rpcurl string, signedTx *types.Transaction, blkNum uint64) (string, error) {
+	return sendBundle(rpcurl, "", signedTx, blkNum)
+}
+
+// sendBundle is SendBundle with a User-Agent header, Go's default if userAgent is empty.
+func sendBundle(rpcurl, userAgent string, signedTx *types.Transaction, blkNum uint64) (string, error) {
 	// Marshal the signed transaction into binary format.
 	binary, err := signedTx.MarshalBinary()
 	if err != nil {
@@ -88,6 +92,9 @@ func SendBundle(rpcurl string, signedTx *types.Transaction, blkNum uint64) (stri
 		return "", err
 	}
 	req.Header.Add("Content-Type", "application/json")
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
 
 	// Execute the HTTP request.
 	resp, err := http.DefaultClient.Do(req)
@@ -0,0 +1,117 @@
+package eth
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+)
+
+// DecodedCall is a human-readable representation of transaction calldata or revert data.
+type DecodedCall struct {
+	Method string                 `json:"method"`
+	Args   map[string]interface{} `json:"args,omitempty"`
+	Raw    string                 `json:"raw,omitempty"` // Set when the selector is unknown, with the selector bracketed.
+}
+
+// CalldataDecoder decodes calldata and revert data against a user-supplied ABI.
+type CalldataDecoder struct {
+	abi abi.ABI
+}
+
+// NewCalldataDecoder loads the ABI at filePath and returns a decoder for it.
+//
+// Parameters:
+// - filePath: The path to the JSON ABI file.
+//
+// Returns:
+// - A pointer to a CalldataDecoder, or an error if the ABI cannot be loaded.
+func NewCalldataDecoder(filePath string) (*CalldataDecoder, error) {
+	parsedABI, err := bb.LoadABI(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ABI %s: %w", filePath, err)
+	}
+	return &CalldataDecoder{abi: parsedABI}, nil
+}
+
+// DecodeCalldata decodes transaction input into a method name and named arguments.
+// Unknown selectors fall back to hex with the 4-byte selector highlighted.
+func (d *CalldataDecoder) DecodeCalldata(data []byte) DecodedCall {
+	if len(data) < 4 {
+		return DecodedCall{Raw: hexutil.Encode(data)}
+	}
+
+	method, err := d.abi.MethodById(data[:4])
+	if err != nil {
+		return DecodedCall{Raw: highlightSelector(data)}
+	}
+
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return DecodedCall{Method: method.Name, Raw: highlightSelector(data)}
+	}
+
+	return DecodedCall{Method: method.Name, Args: formatArgs(args)}
+}
+
+// DecodeRevert decodes revert data as a standard Error(string), Panic(uint256),
+// or a custom error declared in the ABI.
+func (d *CalldataDecoder) DecodeRevert(data []byte) DecodedCall {
+	if len(data) < 4 {
+		return DecodedCall{Raw: hexutil.Encode(data)}
+	}
+
+	// UnpackRevert handles both Error(string) and Panic(uint256)
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return DecodedCall{Method: "Error", Args: map[string]interface{}{"reason": reason}}
+	}
+
+	abiErr, err := d.abi.ErrorByID([4]byte(data[:4]))
+	if err != nil {
+		return DecodedCall{Raw: highlightSelector(data)}
+	}
+
+	args := make(map[string]interface{})
+	if err := abiErr.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+		return DecodedCall{Method: abiErr.Name, Raw: highlightSelector(data)}
+	}
+
+	return DecodedCall{Method: abiErr.Name, Args: formatArgs(args)}
+}
+
+// highlightSelector renders calldata as hex with the 4-byte selector in brackets.
+func highlightSelector(data []byte) string {
+	return "0x[" + hex.EncodeToString(data[:4]) + "]" + hex.EncodeToString(data[4:])
+}
+
+// formatArgs converts decoded ABI values into log-friendly representations.
+func formatArgs(args map[string]interface{}) map[string]interface{} {
+	formatted := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		formatted[name] = formatValue(value)
+	}
+	return formatted
+}
+
+// formatValue renders big integers as decimal strings and byte arrays as hex.
+func formatValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case []*big.Int:
+		out := make([]string, len(v))
+		for i, n := range v {
+			out[i] = n.String()
+		}
+		return out
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	default:
+		return v
+	}
+}
@@ -0,0 +1,67 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCalldata(t *testing.T) {
+	decoder, err := NewCalldataDecoder("testdata/fixture.abi")
+	require.NoError(t, err)
+
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	recipients := []common.Address{
+		common.HexToAddress("0x00000000000000000000000000000000000000b1"),
+		common.HexToAddress("0x00000000000000000000000000000000000000b2"),
+	}
+	amounts := []*big.Int{big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)}
+
+	data, err := decoder.abi.Pack("batchTransfer", token, recipients, amounts)
+	require.NoError(t, err)
+
+	decoded := decoder.DecodeCalldata(data)
+	require.Equal(t, "batchTransfer", decoded.Method)
+	require.Empty(t, decoded.Raw)
+	require.Equal(t, token, decoded.Args["token"])
+	require.Equal(t, recipients, decoded.Args["recipients"])
+	require.Equal(t, []string{"1", "100000000000000000000"}, decoded.Args["amounts"])
+}
+
+func TestDecodeCalldataUnknownSelector(t *testing.T) {
+	decoder, err := NewCalldataDecoder("testdata/fixture.abi")
+	require.NoError(t, err)
+
+	decoded := decoder.DecodeCalldata(hexutil.MustDecode("0xdeadbeef0102"))
+	require.Empty(t, decoded.Method)
+	require.Equal(t, "0x[deadbeef]0102", decoded.Raw)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	decoder, err := NewCalldataDecoder("testdata/fixture.abi")
+	require.NoError(t, err)
+
+	// Custom error declared in the ABI
+	account := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	abiErr := decoder.abi.Errors["InsufficientBalance"]
+	args, err := abiErr.Inputs.Pack(account, big.NewInt(42))
+	require.NoError(t, err)
+	data := append(abiErr.ID.Bytes()[:4], args...)
+
+	decoded := decoder.DecodeRevert(data)
+	require.Equal(t, "InsufficientBalance", decoded.Method)
+	require.Equal(t, account, decoded.Args["account"])
+	require.Equal(t, "42", decoded.Args["needed"])
+
+	// Standard Error(string) revert
+	reason := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6f6f707300000000000000000000000000000000000000000000000000000000")
+	decoded = decoder.DecodeRevert(reason)
+	require.Equal(t, "Error", decoded.Method)
+	require.Equal(t, "oops", decoded.Args["reason"])
+}
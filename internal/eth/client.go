@@ -0,0 +1,45 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ClientFunc returns the client to use for each call, so that long-lived users
+// follow reconnections instead of keeping a connection that has been closed. It
+// implements NonceReader and RebalanceClient.
+type ClientFunc func() *ethclient.Client
+
+// NonceAt returns the account's transaction count at the given block.
+func (f ClientFunc) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return f().NonceAt(ctx, account, blockNumber)
+}
+
+// PendingNonceAt returns the account's transaction count in the pending state.
+func (f ClientFunc) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return f().PendingNonceAt(ctx, account)
+}
+
+// HeaderByNumber returns the header of the given block, the latest if nil.
+func (f ClientFunc) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return f().HeaderByNumber(ctx, number)
+}
+
+// NetworkID returns the network ID of the node.
+func (f ClientFunc) NetworkID(ctx context.Context) (*big.Int, error) {
+	return f().NetworkID(ctx)
+}
+
+// BalanceAt returns the account's balance in wei at the given block.
+func (f ClientFunc) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return f().BalanceAt(ctx, account, blockNumber)
+}
+
+// SendTransaction sends a signed transaction to the node's pool.
+func (f ClientFunc) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return f().SendTransaction(ctx, tx)
+}
@@ -0,0 +1,122 @@
+package eth
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxDeduplicator remembers the last transaction built for each sender and nonce,
+// so that a rebuild of the same transfer while the previous one is still pending
+// is bid on under its existing hash instead of being broadcast as a new transaction.
+type TxDeduplicator struct {
+	mu      sync.Mutex
+	byNonce map[senderNonce]*types.Transaction
+}
+
+// senderNonce identifies a transaction slot of a wallet.
+type senderNonce struct {
+	from  common.Address
+	nonce uint64
+}
+
+// NewTxDeduplicator creates an empty TxDeduplicator.
+func NewTxDeduplicator() *TxDeduplicator {
+	return &TxDeduplicator{byNonce: make(map[senderNonce]*types.Transaction)}
+}
+
+// Dedup compares tx with the last transaction built for its sender and nonce.
+// Transactions are identical if their type, recipient, value and data match; fees
+// and blob contents are not compared.
+//
+// Parameters:
+// - from: The sender of tx.
+// - tx: The newly built transaction.
+//
+// Returns:
+// - The previously built transaction and true if it is identical to tx.
+// - Otherwise tx and false, after recording tx as the last built for its nonce.
+func (d *TxDeduplicator) Dedup(from common.Address, tx *types.Transaction) (*types.Transaction, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.prune(from, tx.Nonce())
+	return d.dedup(from, tx)
+}
+
+// DedupChain is Dedup for a chain of transactions with sequential nonces built
+// from the same pending nonce. Only transactions below the first nonce of the
+// chain are considered mined or replaced.
+//
+// Parameters:
+// - from: The sender of the chain.
+// - txs: The newly built transactions, in nonce order.
+//
+// Returns:
+// - For each transaction, the previously built one if identical, otherwise itself.
+// - For each transaction, whether it was a duplicate.
+func (d *TxDeduplicator) DedupChain(from common.Address, txs []*types.Transaction) ([]*types.Transaction, []bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deduped := make([]*types.Transaction, len(txs))
+	duplicates := make([]bool, len(txs))
+	if len(txs) == 0 {
+		return deduped, duplicates
+	}
+	d.prune(from, txs[0].Nonce())
+	for i, tx := range txs {
+		deduped[i], duplicates[i] = d.dedup(from, tx)
+	}
+	return deduped, duplicates
+}
+
+// dedup compares tx with the last transaction built for its nonce, recording it
+// if they differ; the caller must hold the lock.
+func (d *TxDeduplicator) dedup(from common.Address, tx *types.Transaction) (*types.Transaction, bool) {
+	key := senderNonce{from: from, nonce: tx.Nonce()}
+	if prev, ok := d.byNonce[key]; ok && identicalTx(prev, tx) {
+		return prev, true
+	}
+	d.byNonce[key] = tx
+	return tx, false
+}
+
+// prune forgets the sender's transactions below its pending nonce, which were
+// mined or replaced; the caller must hold the lock.
+func (d *TxDeduplicator) prune(from common.Address, pendingNonce uint64) {
+	for k := range d.byNonce {
+		if k.from == from && k.nonce < pendingNonce {
+			delete(d.byNonce, k)
+		}
+	}
+}
+
+// Forget drops the transaction with the given hash, so that the next transaction
+// built for its nonce is broadcast instead of being bid on under the old hash.
+//
+// Returns:
+// - Whether the transaction was known.
+func (d *TxDeduplicator) Forget(hash common.Hash) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, tx := range d.byNonce {
+		if tx.Hash() == hash {
+			delete(d.byNonce, key)
+			return true
+		}
+	}
+	return false
+}
+
+func identicalTx(a, b *types.Transaction) bool {
+	if a.Type() != b.Type() || a.Nonce() != b.Nonce() {
+		return false
+	}
+	if (a.To() == nil) != (b.To() == nil) || (a.To() != nil && *a.To() != *b.To()) {
+		return false
+	}
+	return a.Value().Cmp(b.Value()) == 0 && bytes.Equal(a.Data(), b.Data())
+}
@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var sender = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+func transferTx(nonce uint64, value int64, tip int64) *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.DynamicFeeTx{
+		Nonce:     nonce,
+		To:        &to,
+		Value:     big.NewInt(value),
+		Gas:       21_000,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(100 + tip),
+	})
+}
+
+func TestTxDeduplicatorRebidsIdenticalRebuild(t *testing.T) {
+	d := NewTxDeduplicator()
+
+	first := transferTx(7, 1e9, 1)
+	got, dup := d.Dedup(sender, first)
+	require.False(t, dup)
+	require.Equal(t, first.Hash(), got.Hash())
+
+	// The next block rebuilds the same transfer with a new tip while nonce 7 is pending
+	rebuilt := transferTx(7, 1e9, 2)
+	require.NotEqual(t, first.Hash(), rebuilt.Hash())
+	got, dup = d.Dedup(sender, rebuilt)
+	require.True(t, dup)
+	require.Equal(t, first.Hash(), got.Hash())
+}
+
+func TestTxDeduplicatorKeepsDifferentTransactions(t *testing.T) {
+	d := NewTxDeduplicator()
+	d.Dedup(sender, transferTx(7, 1e9, 1))
+
+	changed := transferTx(7, 2e9, 1)
+	got, dup := d.Dedup(sender, changed)
+	require.False(t, dup)
+	require.Equal(t, changed.Hash(), got.Hash())
+
+	// Once nonce 8 is built, nonce 7 is forgotten
+	d.Dedup(sender, transferTx(8, 1e9, 1))
+	again := transferTx(7, 2e9, 3)
+	got, dup = d.Dedup(sender, again)
+	require.False(t, dup)
+	require.Equal(t, again.Hash(), got.Hash())
+}
+
+func TestTxDeduplicatorForget(t *testing.T) {
+	d := NewTxDeduplicator()
+	first := transferTx(7, 1e9, 1)
+	d.Dedup(sender, first)
+
+	// An abandoned tx is forgotten, so its rebuild is sent as a new tx
+	require.True(t, d.Forget(first.Hash()))
+	require.False(t, d.Forget(first.Hash()))
+	rebuilt := transferTx(7, 1e9, 2)
+	got, dup := d.Dedup(sender, rebuilt)
+	require.False(t, dup)
+	require.Equal(t, rebuilt.Hash(), got.Hash())
+}
+
+func TestTxDeduplicatorSeparatesSenders(t *testing.T) {
+	d := NewTxDeduplicator()
+	other := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	d.Dedup(sender, transferTx(7, 1e9, 1))
+
+	// Another wallet's nonce neither matches nor evicts the first wallet's tx
+	_, dup := d.Dedup(other, transferTx(9, 1e9, 1))
+	require.False(t, dup)
+	_, dup = d.Dedup(other, transferTx(7, 1e9, 2))
+	require.False(t, dup)
+	_, dup = d.Dedup(sender, transferTx(7, 1e9, 3))
+	require.True(t, dup)
+}
@@ -0,0 +1,58 @@
+package eth
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestERC20TransferData(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	data := ERC20TransferData(to, big.NewInt(1000))
+	require.Equal(t,
+		"a9059cbb"+
+			"00000000000000000000000000000000000000000000000000000000000000aa"+
+			"00000000000000000000000000000000000000000000000000000000000003e8",
+		hex.EncodeToString(data))
+}
+
+func TestParseTokenTransfer(t *testing.T) {
+	token, err := ParseTokenTransfer("0x00000000000000000000000000000000000000bb", "1000000000000000000000")
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0xbb"), token.Address)
+	require.Equal(t, "1000000000000000000000", token.Amount.String())
+
+	_, err = ParseTokenTransfer("", "1")
+	require.Error(t, err)
+	for _, amount := range []string{"", "0", "-1", "1.5", "1e18"} {
+		_, err = ParseTokenTransfer("0x00000000000000000000000000000000000000bb", amount)
+		require.Error(t, err, amount)
+	}
+}
+
+func TestSendERC20Transfer(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	client := &partialHeaderClient{latest: &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(params.GWei)}}
+	tokenAddr := common.HexToAddress("0xbb")
+	to := common.HexToAddress("0xaa")
+
+	tx, blockNumber, err := SendERC20Transfer(client, wallet, tokenAddr, to, big.NewInt(1000), 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), blockNumber)
+	require.Equal(t, tokenAddr, *tx.To())
+	require.Zero(t, tx.Value().Sign(), "tokens move in calldata, not value")
+	require.Equal(t, ERC20TransferData(to, big.NewInt(1000)), tx.Data())
+
+	// Without a token the generator fails instead of sending an empty call
+	_, _, err = BuildChain(client, wallet, GeneratorERC20, TxOptions{}, 1, 1, nil)
+	require.Error(t, err)
+}
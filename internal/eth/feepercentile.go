@@ -0,0 +1,140 @@
+package eth
+
+import (
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FeePercentileTracker keeps the effective priority fees of transactions in the
+// most recent blocks, to estimate what tip achieves next-block inclusion.
+type FeePercentileTracker struct {
+	window int
+
+	mu     sync.Mutex
+	blocks [][]*big.Int // Effective tips of each block in the window, oldest first.
+}
+
+// NewFeePercentileTracker creates a FeePercentileTracker over the given number of blocks.
+func NewFeePercentileTracker(window int) *FeePercentileTracker {
+	if window < 1 {
+		window = 1
+	}
+	return &FeePercentileTracker{window: window}
+}
+
+// ObserveBlock records the effective priority fees paid by the transactions of a block.
+func (t *FeePercentileTracker) ObserveBlock(baseFee *big.Int, txs []*types.Transaction) {
+	tips := make([]*big.Int, 0, len(txs))
+	for _, tx := range txs {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil || tip.Sign() < 0 {
+			continue
+		}
+		tips = append(tips, tip)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.blocks = append(t.blocks, tips)
+	if len(t.blocks) > t.window {
+		t.blocks = t.blocks[1:]
+	}
+}
+
+// Percentile returns the p-th percentile (0-100) of effective tips across the window
+// using the nearest-rank method, or nil if no transactions have been observed.
+func (t *FeePercentileTracker) Percentile(p float64) *big.Int {
+	t.mu.Lock()
+	var tips []*big.Int
+	for _, block := range t.blocks {
+		tips = append(tips, block...)
+	}
+	t.mu.Unlock()
+
+	if len(tips) == 0 {
+		return nil
+	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+
+	rank := int(math.Ceil(p / 100 * float64(len(tips))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(tips) {
+		rank = len(tips)
+	}
+	return new(big.Int).Set(tips[rank-1])
+}
+
+// PreconfValueEstimate compares the cost of a preconf bid plus our tip against paying
+// the p90 priority fee without a preconf.
+type PreconfValueEstimate struct {
+	BidWei         *big.Int `json:"bid_wei"`
+	TipWei         *big.Int `json:"tip_wei"`
+	P90TipWei      *big.Int `json:"p90_tip_wei"`
+	Gas            uint64   `json:"gas"`
+	PreconfCostWei *big.Int `json:"preconf_cost_wei"`  // bid + tip * gas
+	TipOnlyCostWei *big.Int `json:"tip_only_cost_wei"` // p90 tip * gas
+	SavingsWei     *big.Int `json:"savings_wei"`       // Positive when the preconf was cheaper.
+}
+
+// EstimatePreconfValue compares a bid against the p90 tip-only alternative.
+//
+// Parameters:
+// - bidWei: The preconf bid amount in wei.
+// - tipWei: The priority fee per gas paid by our transaction.
+// - p90TipWei: The p90 priority fee per gas of recent blocks.
+// - gas: The gas used by our transaction.
+//
+// Returns:
+// - The PreconfValueEstimate.
+func EstimatePreconfValue(bidWei, tipWei, p90TipWei *big.Int, gas uint64) PreconfValueEstimate {
+	gasBig := new(big.Int).SetUint64(gas)
+	preconfCost := new(big.Int).Add(bidWei, new(big.Int).Mul(tipWei, gasBig))
+	tipOnlyCost := new(big.Int).Mul(p90TipWei, gasBig)
+	return PreconfValueEstimate{
+		BidWei:         bidWei,
+		TipWei:         tipWei,
+		P90TipWei:      p90TipWei,
+		Gas:            gas,
+		PreconfCostWei: preconfCost,
+		TipOnlyCostWei: tipOnlyCost,
+		SavingsWei:     new(big.Int).Sub(tipOnlyCost, preconfCost),
+	}
+}
+
+// PreconfValueReport aggregates preconf value estimates across a session.
+type PreconfValueReport struct {
+	mu         sync.Mutex
+	bids       uint64
+	cheaper    uint64
+	savingsWei *big.Int
+}
+
+// NewPreconfValueReport creates an empty PreconfValueReport.
+func NewPreconfValueReport() *PreconfValueReport {
+	return &PreconfValueReport{savingsWei: new(big.Int)}
+}
+
+// Add includes an estimate in the aggregate.
+func (r *PreconfValueReport) Add(e PreconfValueEstimate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bids++
+	if e.SavingsWei.Sign() > 0 {
+		r.cheaper++
+	}
+	r.savingsWei.Add(r.savingsWei, e.SavingsWei)
+}
+
+// Summary returns the number of estimated bids, how many were cheaper than the
+// tip-only alternative, and the total savings in wei (negative when preconf cost more).
+func (r *PreconfValueReport) Summary() (bids, cheaper uint64, savingsWei *big.Int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bids, r.cheaper, new(big.Int).Set(r.savingsWei)
+}
@@ -0,0 +1,65 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// tipTxs builds dynamic fee transactions paying the given tips with a generous fee cap.
+func tipTxs(tips ...int64) []*types.Transaction {
+	txs := make([]*types.Transaction, len(tips))
+	for i, tip := range tips {
+		txs[i] = types.NewTx(&types.DynamicFeeTx{
+			GasTipCap: big.NewInt(tip),
+			GasFeeCap: big.NewInt(1_000_000),
+		})
+	}
+	return txs
+}
+
+func TestFeePercentileTracker(t *testing.T) {
+	tracker := NewFeePercentileTracker(2)
+	baseFee := big.NewInt(100)
+
+	require.Nil(t, tracker.Percentile(90))
+
+	// Tips 1..10 spread across two blocks
+	tracker.ObserveBlock(baseFee, tipTxs(1, 2, 3, 4, 5))
+	tracker.ObserveBlock(baseFee, tipTxs(6, 7, 8, 9, 10))
+	require.Equal(t, big.NewInt(9), tracker.Percentile(90))
+	require.Equal(t, big.NewInt(5), tracker.Percentile(50))
+	require.Equal(t, big.NewInt(1), tracker.Percentile(0))
+	require.Equal(t, big.NewInt(10), tracker.Percentile(100))
+
+	// The oldest block falls out of the window
+	tracker.ObserveBlock(baseFee, tipTxs(100, 200, 300, 400, 500))
+	require.Equal(t, big.NewInt(400), tracker.Percentile(90))
+}
+
+func TestFeePercentileTrackerCapsTipAtFeeCap(t *testing.T) {
+	tracker := NewFeePercentileTracker(1)
+
+	// Fee cap 150 with base fee 100 leaves an effective tip of 50
+	tx := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(80), GasFeeCap: big.NewInt(150)})
+	tracker.ObserveBlock(big.NewInt(100), []*types.Transaction{tx})
+	require.Equal(t, big.NewInt(50), tracker.Percentile(90))
+}
+
+func TestEstimatePreconfValue(t *testing.T) {
+	// Preconf: 1000 wei bid + 1 wei tip * 21000 gas = 22000; tip-only: 2 * 21000 = 42000
+	estimate := EstimatePreconfValue(big.NewInt(1000), big.NewInt(1), big.NewInt(2), 21000)
+	require.Equal(t, big.NewInt(22000), estimate.PreconfCostWei)
+	require.Equal(t, big.NewInt(42000), estimate.TipOnlyCostWei)
+	require.Equal(t, big.NewInt(20000), estimate.SavingsWei)
+
+	report := NewPreconfValueReport()
+	report.Add(estimate)
+	report.Add(EstimatePreconfValue(big.NewInt(50000), big.NewInt(1), big.NewInt(2), 21000))
+	bids, cheaper, savings := report.Summary()
+	require.Equal(t, uint64(2), bids)
+	require.Equal(t, uint64(1), cheaper)
+	require.Equal(t, big.NewInt(20000-29000), savings)
+}
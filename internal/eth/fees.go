@@ -0,0 +1,109 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrFeeAboveCap is returned by BuildChain when the fee cap derived from the
+// base fee exceeds FeeCaps.MaxFee, so that the block is skipped instead of sent
+// at an unbounded price.
+var ErrFeeAboveCap = errors.New("fee cap above the maximum fee")
+
+// FeeCaps fixes the fee caps of built transactions instead of deriving them
+// from the latest header.
+type FeeCaps struct {
+	GasFeeCap         *big.Int // Max fee per gas in wei, nil for the base fee plus the tip.
+	BlobFeeCap        *big.Int // Max fee per blob gas in wei, nil for 10% over the next blob base fee.
+	BaseFeeMultiplier float64  // Multiplies the base fee in a derived fee cap, 0 for 1.
+	MaxFee            *big.Int // Highest derived fee cap per gas in wei, nil for no limit.
+	// BlobFeeGasMultiple derives the blob fee cap as the gas fee cap times this
+	// factor instead of from the blob base fee, 0 to not. BlobFeeCap takes precedence.
+	BlobFeeGasMultiple float64
+}
+
+// Validate checks that the gas fee cap leaves room for a fixed tip, since a
+// transaction whose tip exceeds its fee cap is invalid.
+//
+// Parameters:
+// - tip: The fixed tip per gas in wei, nil if the tip isn't fixed.
+//
+// Returns:
+// - An error if the gas fee cap or the max fee is below the tip, or the base fee
+// multiplier or blob fee multiple is negative.
+func (f FeeCaps) Validate(tip *big.Int) error {
+	if f.BaseFeeMultiplier < 0 {
+		return fmt.Errorf("base fee multiplier of %v is negative", f.BaseFeeMultiplier)
+	}
+	if f.BlobFeeGasMultiple < 0 {
+		return fmt.Errorf("blob fee multiple of %v is negative", f.BlobFeeGasMultiple)
+	}
+	if tip == nil {
+		return nil
+	}
+	if f.GasFeeCap != nil && f.GasFeeCap.Cmp(tip) < 0 {
+		return fmt.Errorf("gas fee cap of %s wei is below the tip of %s wei", f.GasFeeCap, tip)
+	}
+	if f.MaxFee != nil && f.MaxFee.Cmp(tip) < 0 {
+		return fmt.Errorf("max fee of %s wei is below the tip of %s wei", f.MaxFee, tip)
+	}
+	return nil
+}
+
+// GweiToWei converts an amount in gwei, such as GAS_FEE_CAP_GWEI, to wei,
+// dropping fractions of a wei. It returns nil for 0, which leaves a fee unset.
+func GweiToWei(gwei float64) *big.Int {
+	if gwei == 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+// gasFeeCap returns the fee cap and tip per gas of a transaction built on a base
+// fee. A fixed cap below the chosen tip lowers the tip to the cap. Otherwise the
+// cap is the base fee times BaseFeeMultiplier plus the tip, with a nil base fee
+// (a pre-London chain) counting as zero, and a cap above MaxFee fails with
+// ErrFeeAboveCap.
+func (f FeeCaps) gasFeeCap(baseFee, tip *big.Int) (*big.Int, *big.Int, error) {
+	if f.GasFeeCap != nil {
+		feeCap := new(big.Int).Set(f.GasFeeCap)
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+		return feeCap, tip, nil
+	}
+	feeCap := new(big.Int).Set(tip)
+	if baseFee != nil {
+		scaled := new(big.Int).Set(baseFee)
+		if f.BaseFeeMultiplier != 0 {
+			scaled, _ = new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(f.BaseFeeMultiplier)).Int(nil)
+		}
+		feeCap.Add(feeCap, scaled)
+	}
+	if f.MaxFee != nil && feeCap.Cmp(f.MaxFee) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s wei per gas exceeds %s wei", ErrFeeAboveCap, feeCap, f.MaxFee)
+	}
+	return feeCap, tip, nil
+}
+
+// blobFeeCap returns the max fee per blob gas of a blob transaction: the fixed
+// BlobFeeCap, the gas fee cap times BlobFeeGasMultiple, or 10% over the blob
+// base fee plus one wei so that it can replace a pending transaction.
+func (f FeeCaps) blobFeeCap(gasFeeCap, blobBaseFee *big.Int) (*big.Int, error) {
+	if f.BlobFeeCap != nil {
+		return new(big.Int).Set(f.BlobFeeCap), nil
+	}
+	if f.BlobFeeGasMultiple > 0 {
+		feeCap, _ := new(big.Float).Mul(new(big.Float).SetInt(gasFeeCap), big.NewFloat(f.BlobFeeGasMultiple)).Int(nil)
+		return feeCap, nil
+	}
+	if blobBaseFee == nil {
+		return nil, fmt.Errorf("%w: no blob gas fields, so blob transactions can't be priced (chain before Cancun or fields omitted by the provider)", ErrIncompleteHeader)
+	}
+	feeCap := new(big.Int).Add(blobBaseFee, big.NewInt(1))
+	return feeCap.Mul(feeCap, big.NewInt(110)).Div(feeCap, big.NewInt(100)), nil
+}
@@ -0,0 +1,151 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChainUsesFixedFeeCaps(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	excess, used := uint64(0), uint64(0)
+	client := &partialHeaderClient{latest: &types.Header{
+		Number:        big.NewInt(100),
+		BaseFee:       big.NewInt(20 * params.GWei),
+		ExcessBlobGas: &excess,
+		BlobGasUsed:   &used,
+	}}
+	tip := FixedTip(big.NewInt(2 * params.GWei))
+
+	// Without fixed caps the fee cap follows the base fee
+	chain, _, err := BuildChain(client, wallet, GeneratorTransfer, TxOptions{}, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(22*params.GWei), chain[0].GasFeeCap())
+
+	fees := FeeCaps{GasFeeCap: GweiToWei(50), BlobFeeCap: GweiToWei(3)}
+	chain, _, err = BuildChain(client, wallet, GeneratorTransfer, TxOptions{Fees: fees}, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50*params.GWei), chain[0].GasFeeCap())
+	require.Equal(t, big.NewInt(2*params.GWei), chain[0].GasTipCap())
+
+	chain, _, err = BuildChain(client, wallet, GeneratorBlob, TxOptions{NumBlobs: 1, Fees: fees}, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50*params.GWei), chain[0].GasFeeCap())
+	require.Equal(t, big.NewInt(3*params.GWei), chain[0].BlobGasFeeCap())
+
+	// A tip above a fixed cap is lowered to it
+	chain, _, err = BuildChain(client, wallet, GeneratorTransfer, TxOptions{Fees: FeeCaps{GasFeeCap: GweiToWei(1)}}, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(params.GWei), chain[0].GasFeeCap())
+	require.Equal(t, big.NewInt(params.GWei), chain[0].GasTipCap())
+}
+
+func TestFeeCapsValidate(t *testing.T) {
+	fees := FeeCaps{GasFeeCap: GweiToWei(1.5)}
+	require.Equal(t, big.NewInt(1_500_000_000), fees.GasFeeCap)
+	require.NoError(t, fees.Validate(GweiToWei(1.5)))
+	require.Error(t, fees.Validate(GweiToWei(2)))
+	require.NoError(t, fees.Validate(nil), "a tip that isn't fixed is lowered when building")
+	require.NoError(t, FeeCaps{}.Validate(GweiToWei(2)))
+	require.Nil(t, GweiToWei(0))
+}
+
+func TestGasFeeCapMath(t *testing.T) {
+	tip := big.NewInt(2 * params.GWei)
+	baseFee := big.NewInt(10 * params.GWei)
+
+	feeCap, gotTip, err := FeeCaps{}.gasFeeCap(baseFee, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(12*params.GWei), feeCap)
+	require.Equal(t, tip, gotTip)
+
+	feeCap, _, err = FeeCaps{BaseFeeMultiplier: 2}.gasFeeCap(baseFee, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(22*params.GWei), feeCap)
+
+	feeCap, _, err = FeeCaps{BaseFeeMultiplier: 1.25}.gasFeeCap(baseFee, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(14_500_000_000), feeCap)
+
+	// A pre-London header has no base fee, leaving only the tip
+	feeCap, _, err = FeeCaps{BaseFeeMultiplier: 2, MaxFee: GweiToWei(5)}.gasFeeCap(nil, tip)
+	require.NoError(t, err)
+	require.Equal(t, tip, feeCap)
+
+	// A derived cap above the maximum is refused, one at the maximum is sent
+	_, _, err = FeeCaps{BaseFeeMultiplier: 2, MaxFee: GweiToWei(20)}.gasFeeCap(baseFee, tip)
+	require.ErrorIs(t, err, ErrFeeAboveCap)
+	feeCap, _, err = FeeCaps{BaseFeeMultiplier: 2, MaxFee: GweiToWei(22)}.gasFeeCap(baseFee, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(22*params.GWei), feeCap)
+
+	// The maximum doesn't apply to a fixed cap
+	feeCap, _, err = FeeCaps{GasFeeCap: GweiToWei(30), MaxFee: GweiToWei(20)}.gasFeeCap(baseFee, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(30*params.GWei), feeCap)
+}
+
+func TestBuildChainUsesGivenHeader(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	client := &partialHeaderClient{latest: &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(params.GWei)}}
+	header := &types.Header{Number: big.NewInt(101), BaseFee: big.NewInt(30 * params.GWei)}
+	tip := FixedTip(big.NewInt(2 * params.GWei))
+
+	chain, blockNumber, err := BuildChain(client, wallet, GeneratorTransfer, TxOptions{Header: header}, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, uint64(102), blockNumber, "the header plus the offset")
+	require.Equal(t, big.NewInt(32*params.GWei), chain[0].GasFeeCap())
+
+	// Over MAX_FEE_GWEI the block is skipped
+	opts := TxOptions{Header: header, Fees: FeeCaps{MaxFee: GweiToWei(25)}}
+	_, _, err = BuildChain(client, wallet, GeneratorTransfer, opts, 1, 1, tip)
+	require.ErrorIs(t, err, ErrFeeAboveCap)
+}
+
+func TestFeeCapsValidateMaxFee(t *testing.T) {
+	require.NoError(t, FeeCaps{MaxFee: GweiToWei(2)}.Validate(GweiToWei(2)))
+	require.Error(t, FeeCaps{MaxFee: GweiToWei(1)}.Validate(GweiToWei(2)))
+	require.Error(t, FeeCaps{BaseFeeMultiplier: -1}.Validate(nil))
+}
+
+func TestBlobFeeCapAsGasFeeMultiple(t *testing.T) {
+	gasFeeCap := big.NewInt(20 * params.GWei)
+	blobBaseFee := big.NewInt(params.GWei)
+
+	feeCap, err := FeeCaps{}.blobFeeCap(gasFeeCap, blobBaseFee)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1_100_000_001), feeCap, "10% over the blob base fee plus one wei")
+
+	feeCap, err = FeeCaps{BlobFeeGasMultiple: 0.5}.blobFeeCap(gasFeeCap, nil)
+	require.NoError(t, err, "a derived cap doesn't need the blob base fee")
+	require.Equal(t, big.NewInt(10*params.GWei), feeCap)
+
+	feeCap, err = FeeCaps{BlobFeeGasMultiple: 0.5, BlobFeeCap: GweiToWei(3)}.blobFeeCap(gasFeeCap, blobBaseFee)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(3*params.GWei), feeCap, "a fixed cap takes precedence")
+
+	_, err = FeeCaps{}.blobFeeCap(gasFeeCap, nil)
+	require.ErrorIs(t, err, ErrIncompleteHeader)
+	require.Error(t, FeeCaps{BlobFeeGasMultiple: -1}.Validate(nil))
+
+	// Built blob transactions follow the derived gas fee cap
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	excess, used := uint64(0), uint64(0)
+	header := &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(20 * params.GWei), ExcessBlobGas: &excess, BlobGasUsed: &used}
+	opts := TxOptions{NumBlobs: 1, Header: header, Fees: FeeCaps{BlobFeeGasMultiple: 2}}
+	chain, _, err := BuildChain(&partialHeaderClient{latest: header}, wallet, GeneratorBlob, opts, 1, 1, FixedTip(big.NewInt(2*params.GWei)))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(22*params.GWei), chain[0].GasFeeCap())
+	require.Equal(t, big.NewInt(44*params.GWei), chain[0].BlobGasFeeCap())
+}
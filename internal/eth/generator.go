@@ -0,0 +1,359 @@
+package eth
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+)
+
+// Names of the built-in generators.
+const (
+	GeneratorTransfer = keyaudit.FeatureTransfer
+	GeneratorBlob     = keyaudit.FeatureBlob
+	GeneratorERC20    = keyaudit.FeatureERC20
+)
+
+// BuildContext is what a TxGenerator builds one transaction from. BuildChain
+// fills it once per chain and advances Nonce for each transaction.
+type BuildContext struct {
+	Ctx         context.Context
+	Client      BlobTxClient   // For generators that read more chain state.
+	From        common.Address // The wallet sending the transaction.
+	ChainID     *big.Int
+	Nonce       uint64
+	Header      *types.Header // The latest header.
+	BaseFee     *big.Int      // Base fee of the latest header.
+	Tip         *big.Int      // Priority fee per gas chosen by the tip policy, at most GasFeeCap.
+	GasFeeCap   *big.Int      // Max fee per gas: Options.Fees.GasFeeCap, or BaseFee plus Tip.
+	BlobBaseFee *big.Int      // Blob base fee of the next block, nil before Cancun.
+	TargetBlock uint64
+	Options     TxOptions
+}
+
+// TxOptions are the per-bid settings of BuildChain. Generators ignore the ones
+// they don't use.
+type TxOptions struct {
+	To       *common.Address // Recipient, nil for the sending wallet.
+	Value    *big.Int        // Value sent, nil for none.
+	NumBlobs int             // Blobs per transaction.
+	Nonces   *NonceAllocator // Allocates the chain's nonces, nil to start from the pending count.
+	Backrun  *Backrun        // Appended to the chain, nil for none.
+	Blobs    *BlobSource     // Blob data, nil for random blobs.
+	Fees     FeeCaps         // Fixed fee caps, zero to derive them from the latest header.
+	Header   *types.Header   // Latest header, nil to fetch it.
+	Token    *TokenTransfer  // Token of the erc20 generator, nil for none.
+}
+
+// Backrun is a follow-up transaction BuildChain appends after a chain, at the
+// next nonce, so that the chain and its backrun are bid on as one bundle.
+type Backrun struct {
+	To   common.Address
+	Data []byte // Calldata, empty for a plain call.
+}
+
+// ParseBackrun parses the BACKRUN_TO address and BACKRUN_DATA hex calldata,
+// with or without a 0x prefix. It returns nil if both are empty.
+func ParseBackrun(to, data string) (*Backrun, error) {
+	if to == "" && data == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(to) {
+		return nil, fmt.Errorf("backrun target %q is not an address", to)
+	}
+	calldata, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("backrun data is not hex: %w", err)
+	}
+	return &Backrun{To: common.HexToAddress(to), Data: calldata}, nil
+}
+
+// TxMetadata describes a generated transaction for the logs.
+type TxMetadata struct {
+	Kind  string      // Logged as "<Kind> transaction created and signed".
+	Attrs []slog.Attr // Logged after the hash, block number and nonce.
+}
+
+// TxGenerator builds the unsigned transactions of one transaction mode.
+type TxGenerator interface {
+	Generate(bc *BuildContext) (types.TxData, TxMetadata, error)
+}
+
+// TxGeneratorFunc adapts a function to a TxGenerator.
+type TxGeneratorFunc func(bc *BuildContext) (types.TxData, TxMetadata, error)
+
+// Generate calls f.
+func (f TxGeneratorFunc) Generate(bc *BuildContext) (types.TxData, TxMetadata, error) {
+	return f(bc)
+}
+
+var (
+	generatorsMu sync.RWMutex
+	generators   = make(map[string]TxGenerator)
+)
+
+func init() {
+	RegisterGenerator(GeneratorTransfer, TxGeneratorFunc(generateTransfer))
+	RegisterGenerator(GeneratorBlob, TxGeneratorFunc(generateBlob))
+	RegisterGenerator(GeneratorERC20, TxGeneratorFunc(generateERC20))
+}
+
+// RegisterGenerator makes a generator available under a name, which TX_TYPE
+// then accepts. Like database/sql.Register it is meant to be called from init,
+// and panics if the name is taken or the generator is nil.
+func RegisterGenerator(name string, g TxGenerator) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	if g == nil {
+		panic("eth: RegisterGenerator generator is nil")
+	}
+	if _, dup := generators[name]; dup {
+		panic("eth: RegisterGenerator called twice for " + name)
+	}
+	generators[name] = g
+}
+
+// LookupGenerator returns the generator registered under name.
+func LookupGenerator(name string) (TxGenerator, bool) {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	g, ok := generators[name]
+	return g, ok
+}
+
+// Generators returns the registered generator names, sorted.
+func Generators() []string {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildChain builds and signs a chain of transactions from one wallet with a
+// registered generator. The nonces are allocated as one contiguous range from
+// the wallet's pending nonce, or by opts.Nonces, and each signature is recorded
+// in the key audit under the generator's name. With opts.Backrun the backrun
+// follows the chain at the next nonce.
+//
+// Parameters:
+// - client: The client used to read the nonce, latest header and chain ID.
+// - authAcct: The wallet sending the chain.
+// - generator: The name of a registered generator.
+// - opts: Settings passed through to the generator.
+// - length: The number of transactions in the chain, not counting a backrun.
+// - offset: The number of blocks after the latest one to target.
+// - tipPolicy: Chooses the tip from the latest base fee, or the default priority fee if nil.
+//
+// Returns:
+// - The signed transactions in nonce order, which is the order they must be broadcast in.
+// - The target block number.
+func BuildChain(client BlobTxClient, authAcct bb.AuthAcct, generator string, opts TxOptions, length int, offset uint64, tipPolicy TipPolicy) ([]*types.Transaction, uint64, error) {
+	if length < 1 {
+		return nil, 0, fmt.Errorf("%s chain length must be at least 1, got %d", generator, length)
+	}
+	g, ok := LookupGenerator(generator)
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown transaction generator %q (registered: %s)", generator, strings.Join(Generators(), ", "))
+	}
+
+	total := length
+	if opts.Backrun != nil {
+		total++
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	var err error
+	header := opts.Header
+	if header == nil {
+		header, err = client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			slog.Default().Error("Failed to get latest block header",
+				slog.String("function", "HeaderByNumber"),
+				slog.Any("error", err))
+			return nil, 0, err
+		}
+	}
+	header, err = completeHeader(ctx, client, header)
+	if err != nil {
+		slog.Default().Warn("Skipping block with an incomplete header",
+			slog.Any("error", err))
+		return nil, 0, err
+	}
+
+	chainID, err := client.NetworkID(ctx)
+	if err != nil {
+		slog.Default().Error("Failed to get network ID",
+			slog.String("function", "NetworkID"),
+			slog.Any("error", err))
+		return nil, 0, err
+	}
+
+	// Use the tip policy or the default priority fee
+	priorityFee := defaultPriorityFeeGwei
+	if tipPolicy != nil {
+		priorityFee = tipPolicy(header.BaseFee)
+	}
+
+	gasFeeCap, priorityFee, err := opts.Fees.gasFeeCap(header.BaseFee, priorityFee)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Allocate nonces last, so that a skipped block leaves no gap
+	var nonce uint64
+	if opts.Nonces != nil {
+		nonce, err = opts.Nonces.Allocate(ctx, client, authAcct.Address, total)
+	} else {
+		nonce, err = client.PendingNonceAt(ctx, authAcct.Address)
+	}
+	if err != nil {
+		slog.Default().Error("Failed to get pending nonce",
+			slog.String("function", "PendingNonceAt"),
+			slog.Any("error", err))
+		return nil, 0, err
+	}
+
+	blockNumber := header.Number.Uint64()
+	bc := &BuildContext{
+		Ctx:         ctx,
+		Client:      client,
+		From:        authAcct.Address,
+		ChainID:     chainID,
+		Header:      header,
+		BaseFee:     header.BaseFee,
+		Tip:         priorityFee,
+		GasFeeCap:   gasFeeCap,
+		BlobBaseFee: NextBlobBaseFee(header),
+		TargetBlock: blockNumber + offset,
+		Options:     opts,
+	}
+
+	txs := make([]*types.Transaction, 0, total)
+	for i := 0; i < total; i++ {
+		bc.Nonce = nonce + uint64(i)
+		feature, gen := generator, g
+		if i == length {
+			feature, gen = keyaudit.FeatureBackrun, TxGeneratorFunc(generateBackrun)
+		}
+		data, meta, err := gen.Generate(bc)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		signedTx, err := signTx(types.NewTx(data), chainID, authAcct.PrivateKey, feature)
+		if err != nil {
+			slog.Default().Error("Failed to sign transaction",
+				slog.String("function", "SignTx"),
+				slog.String("generator", feature),
+				slog.Any("error", err))
+			return nil, 0, err
+		}
+
+		attrs := []any{
+			slog.String("tx_hash", signedTx.Hash().Hex()),
+			slog.Uint64("block_number", blockNumber),
+			slog.Uint64("nonce", signedTx.Nonce()),
+		}
+		for _, attr := range meta.Attrs {
+			attrs = append(attrs, attr)
+		}
+		slog.Default().Info(meta.Kind+" transaction created and signed", attrs...)
+		txs = append(txs, signedTx)
+	}
+
+	return txs, bc.TargetBlock, nil
+}
+
+// generateTransfer builds an ETH transfer to Options.To, or to the sending wallet.
+func generateTransfer(bc *BuildContext) (types.TxData, TxMetadata, error) {
+	to := bc.From
+	if bc.Options.To != nil {
+		to = *bc.Options.To
+	}
+	value := bc.Options.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	return &types.DynamicFeeTx{
+		Nonce:     bc.Nonce,
+		To:        &to,
+		Value:     value,
+		Gas:       1_000_000,
+		GasFeeCap: bc.GasFeeCap,
+		GasTipCap: bc.Tip,
+	}, TxMetadata{
+		Kind:  "ETH transfer",
+		Attrs: []slog.Attr{slog.String("to", to.Hex())},
+	}, nil
+}
+
+// generateBackrun builds the Options.Backrun call.
+func generateBackrun(bc *BuildContext) (types.TxData, TxMetadata, error) {
+	backrun := bc.Options.Backrun
+	return &types.DynamicFeeTx{
+		Nonce:     bc.Nonce,
+		To:        &backrun.To,
+		Value:     new(big.Int),
+		Gas:       1_000_000,
+		GasFeeCap: bc.GasFeeCap,
+		GasTipCap: bc.Tip,
+		Data:      backrun.Data,
+	}, TxMetadata{
+		Kind: "Backrun",
+		Attrs: []slog.Attr{
+			slog.String("to", backrun.To.Hex()),
+			slog.Int("data_bytes", len(backrun.Data)),
+		},
+	}, nil
+}
+
+// generateBlob builds a blob transaction to the sending wallet carrying
+// Options.NumBlobs blobs from Options.Blobs.
+func generateBlob(bc *BuildContext) (types.TxData, TxMetadata, error) {
+	blobFeeCap, err := bc.Options.Fees.blobFeeCap(bc.GasFeeCap, bc.BlobBaseFee)
+	if err != nil {
+		slog.Default().Error("Latest block header has no blob gas fields")
+		return nil, TxMetadata{}, err
+	}
+
+	// Generate the blobs and their corresponding sidecar
+	blobs := bc.Options.Blobs.Blobs(bc.Options.NumBlobs)
+	sideCar := makeSidecar(blobs)
+	if err := validateSidecar(sideCar); err != nil {
+		slog.Default().Error("Blob sidecar is incomplete",
+			slog.Any("error", err))
+		return nil, TxMetadata{}, err
+	}
+
+	return &types.BlobTx{
+		ChainID:    uint256.MustFromBig(bc.ChainID),
+		Nonce:      bc.Nonce,
+		GasTipCap:  uint256.MustFromBig(bc.Tip),
+		GasFeeCap:  uint256.MustFromBig(bc.GasFeeCap),
+		Gas:        1_000_000,
+		To:         bc.From,
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: sideCar.BlobHashes(),
+		Sidecar:    sideCar,
+	}, TxMetadata{
+		Kind:  "Blob",
+		Attrs: []slog.Attr{slog.Int("num_blobs", bc.Options.NumBlobs)},
+	}, nil
+}
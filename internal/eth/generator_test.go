@@ -0,0 +1,130 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+// memoGenerator sends a zero-value transaction to the wallet carrying a memo.
+var memoGenerator = TxGeneratorFunc(func(bc *BuildContext) (types.TxData, TxMetadata, error) {
+	memo := []byte("block " + new(big.Int).SetUint64(bc.TargetBlock).String())
+	return &types.DynamicFeeTx{
+		ChainID:   bc.ChainID,
+		Nonce:     bc.Nonce,
+		To:        &bc.From,
+		Value:     new(big.Int),
+		Gas:       100_000,
+		GasFeeCap: new(big.Int).Add(bc.BaseFee, bc.Tip),
+		GasTipCap: bc.Tip,
+		Data:      memo,
+	}, TxMetadata{Kind: "Memo", Attrs: []slog.Attr{slog.String("memo", string(memo))}}, nil
+})
+
+func TestRegisteredGeneratorBuildsChain(t *testing.T) {
+	RegisterGenerator("memo", memoGenerator)
+	require.Contains(t, Generators(), "memo")
+	require.Subset(t, Generators(), []string{GeneratorTransfer, GeneratorBlob})
+	require.Panics(t, func() { RegisterGenerator("memo", memoGenerator) })
+	require.Panics(t, func() { RegisterGenerator("nil", nil) })
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	backend := simulated.NewBackend(types.GenesisAlloc{wallet.Address: {Balance: big.NewInt(params.Ether)}})
+	defer backend.Close()
+	client := backend.Client().(BlobTxClient)
+
+	chain, targetBlock, err := BuildChain(client, wallet, "memo", TxOptions{}, 2, 1, FixedTip(big.NewInt(params.GWei)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), targetBlock)
+	require.Len(t, chain, 2)
+	for i, tx := range chain {
+		require.Equal(t, uint64(i), tx.Nonce())
+		require.Equal(t, []byte("block 1"), tx.Data())
+		require.NoError(t, backend.Client().SendTransaction(context.Background(), tx))
+	}
+	backend.Commit()
+
+	block, err := backend.Client().BlockByNumber(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+	require.Len(t, block.Transactions(), 2)
+
+	_, _, err = BuildChain(client, wallet, "unknown", TxOptions{}, 1, 1, nil)
+	require.ErrorContains(t, err, `unknown transaction generator "unknown"`)
+}
+
+func TestTransferGeneratorDefaultsToSelf(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	backend := simulated.NewBackend(types.GenesisAlloc{wallet.Address: {Balance: big.NewInt(params.Ether)}})
+	defer backend.Close()
+
+	chain, _, err := BuildChain(backend.Client().(BlobTxClient), wallet, GeneratorTransfer, TxOptions{Value: big.NewInt(1e9)}, 1, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, wallet.Address, *chain[0].To())
+	require.Equal(t, big.NewInt(1e9), chain[0].Value())
+}
+
+// capturingBidder records the input of the bids sent to it and rejects them.
+type capturingBidder struct {
+	inputs []interface{}
+}
+
+func (b *capturingBidder) SendBid(input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
+	b.inputs = append(b.inputs, input)
+	return nil, errors.New("bid captured")
+}
+
+func TestBuildChainWithBackrun(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	backend := simulated.NewBackend(types.GenesisAlloc{wallet.Address: {Balance: big.NewInt(params.Ether)}})
+	defer backend.Close()
+	client := backend.Client().(BlobTxClient)
+
+	backrun := &Backrun{To: common.HexToAddress("0x00000000000000000000000000000000000000bb"), Data: []byte{0xde, 0xad, 0xbe, 0xef}}
+	opts := TxOptions{Value: big.NewInt(1e9), Nonces: NewNonceAllocator(1), Backrun: backrun}
+	chain, targetBlock, err := BuildChain(client, wallet, GeneratorTransfer, opts, 1, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	require.Equal(t, uint64(0), chain[0].Nonce())
+	require.Equal(t, wallet.Address, *chain[0].To())
+	require.Equal(t, uint64(1), chain[1].Nonce())
+	require.Equal(t, backrun.To, *chain[1].To())
+	require.Equal(t, backrun.Data, chain[1].Data())
+
+	parsed, err := ParseBackrun(backrun.To.Hex(), "0xdeadbeef")
+	require.NoError(t, err)
+	require.Equal(t, backrun, parsed)
+	parsed, err = ParseBackrun("", "")
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	_, err = ParseBackrun("", "0xdeadbeef")
+	require.ErrorContains(t, err, "not an address")
+
+	// Both hashes go out in one bid
+	bidder := &capturingBidder{}
+	hashes := []string{chain[0].Hash().Hex(), chain[1].Hash().Hex()}
+	require.Error(t, bb.SendPreconfBid(bidder, hashes, int64(targetBlock), 0.001, bb.NewDecayWindow(time.Now(), 12*time.Second)))
+	require.Len(t, bidder.inputs, 1)
+	require.Equal(t, []string{
+		strings.TrimPrefix(hashes[0], "0x"),
+		strings.TrimPrefix(hashes[1], "0x"),
+	}, bidder.inputs[0])
+}
@@ -0,0 +1,65 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrIncompleteHeader is returned when a header lacks a field needed to build
+// a transaction, even after asking the provider again.
+var ErrIncompleteHeader = errors.New("incomplete block header")
+
+// gasPriceSuggester is implemented by clients that can suggest a gas price,
+// such as *ethclient.Client.
+type gasPriceSuggester interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// completeHeader returns a header with a number and base fee. Some providers
+// leave the base fee out of headers, so a header without one is fetched again,
+// and if that has none either the client's suggested gas price stands in for
+// it, which overestimates the base fee by the suggested tip.
+//
+// Parameters:
+// - ctx: The context of the calls.
+// - client: The client the header came from.
+// - header: The header to check.
+//
+// Returns:
+// - The header, a copy with the base fee filled in, or an error wrapping
+// ErrIncompleteHeader.
+func completeHeader(ctx context.Context, client BlobTxClient, header *types.Header) (*types.Header, error) {
+	if header == nil || header.Number == nil {
+		return nil, fmt.Errorf("%w: no block number", ErrIncompleteHeader)
+	}
+	if header.BaseFee != nil {
+		return header, nil
+	}
+	slog.Default().Warn("Block header has no base fee, fetching it again",
+		slog.Uint64("block_number", header.Number.Uint64()))
+
+	refetched, err := client.HeaderByNumber(ctx, header.Number)
+	if err == nil && refetched != nil && refetched.BaseFee != nil {
+		return refetched, nil
+	}
+
+	suggester, ok := client.(gasPriceSuggester)
+	if !ok {
+		return nil, fmt.Errorf("%w: no base fee in block %d", ErrIncompleteHeader, header.Number.Uint64())
+	}
+	gasPrice, err := suggester.SuggestGasPrice(ctx)
+	if err != nil || gasPrice == nil {
+		return nil, fmt.Errorf("%w: no base fee in block %d and no gas price suggested: %v", ErrIncompleteHeader, header.Number.Uint64(), err)
+	}
+	slog.Default().Warn("Using the suggested gas price as the base fee",
+		slog.Uint64("block_number", header.Number.Uint64()),
+		slog.String("gas_price", gasPrice.String()))
+	completed := types.CopyHeader(header)
+	completed.BaseFee = gasPrice
+	return completed, nil
+}
@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+// partialHeaderClient serves headers the way providers that omit fields do.
+type partialHeaderClient struct {
+	latest    *types.Header // Returned for the latest header.
+	refetched *types.Header // Returned when a header is fetched by number.
+}
+
+func (c *partialHeaderClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (c *partialHeaderClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return c.latest, nil
+	}
+	if c.refetched == nil {
+		return nil, errors.New("not found")
+	}
+	return c.refetched, nil
+}
+
+func (c *partialHeaderClient) NetworkID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+// suggestingClient also suggests a gas price.
+type suggestingClient struct {
+	partialHeaderClient
+	gasPrice *big.Int
+}
+
+func (c *suggestingClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.gasPrice, nil
+}
+
+func TestBuildChainHandlesHeaderWithoutBaseFee(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	// A percentage tip would panic on a nil base fee
+	tip := BaseFeePercentTip(10, big.NewInt(params.GWei))
+	noBaseFee := &types.Header{Number: big.NewInt(100)}
+	opts := TxOptions{Value: big.NewInt(1)}
+
+	// Without a base fee anywhere the block is skipped with an error
+	client := &partialHeaderClient{latest: noBaseFee}
+	_, _, err = BuildChain(client, wallet, GeneratorTransfer, opts, 1, 1, tip)
+	require.ErrorIs(t, err, ErrIncompleteHeader)
+
+	// Fetching the header again recovers the base fee
+	client.refetched = &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(20 * params.GWei)}
+	chain, target, err := BuildChain(client, wallet, GeneratorTransfer, opts, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), target)
+	require.Equal(t, big.NewInt(2*params.GWei), chain[0].GasTipCap())
+	require.Equal(t, big.NewInt(22*params.GWei), chain[0].GasFeeCap())
+
+	// Otherwise the suggested gas price stands in for it
+	suggesting := &suggestingClient{partialHeaderClient{latest: noBaseFee}, big.NewInt(30 * params.GWei)}
+	chain, _, err = BuildChain(suggesting, wallet, GeneratorTransfer, opts, 1, 1, tip)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(33*params.GWei), chain[0].GasFeeCap())
+	require.Nil(t, noBaseFee.BaseFee, "the provider's header is left unchanged")
+
+	// Headers without a number or blob fields are rejected too
+	_, _, err = BuildChain(&partialHeaderClient{latest: &types.Header{BaseFee: big.NewInt(1)}}, wallet, GeneratorTransfer, opts, 1, 1, tip)
+	require.ErrorIs(t, err, ErrIncompleteHeader)
+	_, _, err = BuildChain(client, wallet, GeneratorBlob, TxOptions{NumBlobs: 1}, 1, 1, tip)
+	require.ErrorIs(t, err, ErrIncompleteHeader)
+}
@@ -0,0 +1,105 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+var (
+	kzgMu sync.RWMutex
+	// kzgContext computes blob commitments and proofs from a loaded trusted setup.
+	// When nil, the setup embedded in go-ethereum is used.
+	kzgContext *gokzg4844.Context
+)
+
+// trustedSetupFile is the JSON trusted setup format, with slices so that the
+// number of points can be checked before it is converted for go-kzg-4844.
+type trustedSetupFile struct {
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// LoadTrustedSetup replaces the embedded KZG trusted setup used for blob
+// commitments and proofs with the one in the given file.
+//
+// Parameters:
+// - path: A JSON trusted setup file with g1_lagrange and g2_monomial points.
+//
+// Returns:
+// - An error if the file cannot be read or is not a valid trusted setup.
+func LoadTrustedSetup(path string) error {
+	ctx, err := newKZGContext(path)
+	if err != nil {
+		return err
+	}
+	kzgMu.Lock()
+	defer kzgMu.Unlock()
+	kzgContext = ctx
+	return nil
+}
+
+func newKZGContext(path string) (*gokzg4844.Context, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KZG trusted setup: %w", err)
+	}
+	var file trustedSetupFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid KZG trusted setup %s: %w", path, err)
+	}
+	if len(file.G1Lagrange) != gokzg4844.ScalarsPerBlob {
+		return nil, fmt.Errorf("invalid KZG trusted setup %s: %d g1_lagrange points, want %d",
+			path, len(file.G1Lagrange), gokzg4844.ScalarsPerBlob)
+	}
+	if len(file.G2Monomial) < 2 {
+		return nil, fmt.Errorf("invalid KZG trusted setup %s: %d g2_monomial points, want at least 2",
+			path, len(file.G2Monomial))
+	}
+
+	setup := &gokzg4844.JSONTrustedSetup{SetupG2: file.G2Monomial}
+	copy(setup.SetupG1Lagrange[:], file.G1Lagrange)
+	// Checked up front, since go-kzg-4844 panics on malformed points
+	if err := gokzg4844.CheckTrustedSetupIsWellFormed(setup); err != nil {
+		return nil, fmt.Errorf("invalid KZG trusted setup %s: %w", path, err)
+	}
+	ctx, err := gokzg4844.NewContext4096(setup)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KZG trusted setup %s: %w", path, err)
+	}
+	return ctx, nil
+}
+
+// blobToCommitment computes the KZG commitment of a blob with the active trusted setup.
+func blobToCommitment(blob *kzg4844.Blob) (kzg4844.Commitment, error) {
+	kzgMu.RLock()
+	ctx := kzgContext
+	kzgMu.RUnlock()
+	if ctx == nil {
+		return kzg4844.BlobToCommitment(blob)
+	}
+	commitment, err := ctx.BlobToKZGCommitment((*gokzg4844.Blob)(blob), 0)
+	if err != nil {
+		return kzg4844.Commitment{}, err
+	}
+	return kzg4844.Commitment(commitment), nil
+}
+
+// computeBlobProof computes the KZG proof of a blob with the active trusted setup.
+func computeBlobProof(blob *kzg4844.Blob, commitment kzg4844.Commitment) (kzg4844.Proof, error) {
+	kzgMu.RLock()
+	ctx := kzgContext
+	kzgMu.RUnlock()
+	if ctx == nil {
+		return kzg4844.ComputeBlobProof(blob, commitment)
+	}
+	proof, err := ctx.ComputeBlobKZGProof((*gokzg4844.Blob)(blob), (gokzg4844.KZGCommitment)(commitment), 0)
+	if err != nil {
+		return kzg4844.Proof{}, err
+	}
+	return kzg4844.Proof(proof), nil
+}
@@ -0,0 +1,55 @@
+package eth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTrustedSetup(t *testing.T) {
+	t.Cleanup(func() { kzgContext = nil })
+
+	blob := randBlob()
+	embedded, err := kzg4844.BlobToCommitment(&blob)
+	require.NoError(t, err)
+
+	// The mainnet setup loaded from a file gives the same commitment as the embedded one
+	require.NoError(t, LoadTrustedSetup(filepath.Join("testdata", "trusted_setup.json")))
+	require.NotNil(t, kzgContext)
+	commitment, err := blobToCommitment(&blob)
+	require.NoError(t, err)
+	require.Equal(t, embedded, commitment)
+
+	sidecar := makeSidecar([]kzg4844.Blob{blob})
+	require.NoError(t, validateSidecar(sidecar))
+	require.NoError(t, kzg4844.VerifyBlobProof(&sidecar.Blobs[0], sidecar.Commitments[0], sidecar.Proofs[0]))
+}
+
+func TestLoadTrustedSetupInvalid(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	err := LoadTrustedSetup(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "failed to read KZG trusted setup")
+
+	err = LoadTrustedSetup(write("garbage.json", "not json"))
+	require.ErrorContains(t, err, "invalid KZG trusted setup")
+
+	err = LoadTrustedSetup(write("short.json", `{"g1_lagrange": ["0x00"], "g2_monomial": ["0x00", "0x00"]}`))
+	require.ErrorContains(t, err, "1 g1_lagrange points, want 4096")
+
+	// Right number of points, but not valid curve points
+	points := `"0x` + strings.Repeat("00", 48) + `"` + strings.Repeat(`, "0x`+strings.Repeat("00", 48)+`"`, 4095)
+	err = LoadTrustedSetup(write("malformed.json", `{"g1_lagrange": [`+points+`], "g2_monomial": ["0xzz", "0xzz"]}`))
+	require.ErrorContains(t, err, "invalid KZG trusted setup")
+
+	require.Nil(t, kzgContext)
+}
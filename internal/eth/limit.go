@@ -0,0 +1,87 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Policies for bundles submitted while MAX_CONCURRENT_BUNDLES are in flight.
+const (
+	BundleOverflowQueue = "queue" // Wait for a submission to finish.
+	BundleOverflowDrop  = "drop"  // Fail the submission with ErrBundleDropped.
+)
+
+// ErrBundleDropped is returned for a bundle dropped by the drop policy.
+var ErrBundleDropped = errors.New("bundle dropped: too many concurrent bundle submissions")
+
+// LimitedSender caps the bundle submissions of another BundleSender that are
+// in flight at once, so broadcasts across many blocks can't pile up HTTP
+// requests. It is safe for concurrent use.
+type LimitedSender struct {
+	next    BundleSender
+	policy  string
+	slots   chan struct{}
+	onQueue func(depth int)
+
+	mu      sync.Mutex
+	waiting int
+}
+
+// NewLimitedSender creates a LimitedSender.
+//
+// Parameters:
+// - next: The sender submissions are passed on to.
+// - max: Most submissions in flight at once, at least 1.
+// - policy: BundleOverflowQueue or BundleOverflowDrop.
+// - onQueue: Called with the number of queued submissions whenever it changes, or nil.
+//
+// Returns:
+// - A pointer to a LimitedSender, or an error if max or the policy is invalid.
+func NewLimitedSender(next BundleSender, max int, policy string, onQueue func(depth int)) (*LimitedSender, error) {
+	switch policy {
+	case BundleOverflowQueue, BundleOverflowDrop:
+	default:
+		return nil, fmt.Errorf("unknown bundle overflow policy %q (must be %s or %s)", policy, BundleOverflowQueue, BundleOverflowDrop)
+	}
+	if max < 1 {
+		return nil, fmt.Errorf("concurrent bundle limit must be at least 1, got %d", max)
+	}
+	if onQueue == nil {
+		onQueue = func(int) {}
+	}
+	return &LimitedSender{
+		next:    next,
+		policy:  policy,
+		slots:   make(chan struct{}, max),
+		onQueue: onQueue,
+	}, nil
+}
+
+// SendBundle sends the bundle once fewer than the limit are in flight. When the
+// limit is reached it waits under the queue policy and returns ErrBundleDropped
+// under the drop policy.
+func (s *LimitedSender) SendBundle(tx *types.Transaction, blockNumber uint64) error {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		if s.policy == BundleOverflowDrop {
+			return ErrBundleDropped
+		}
+		s.queued(1)
+		s.slots <- struct{}{}
+		s.queued(-1)
+	}
+	defer func() { <-s.slots }()
+	return s.next.SendBundle(tx, blockNumber)
+}
+
+// queued adjusts the number of waiting submissions and reports it.
+func (s *LimitedSender) queued(delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.waiting += delta
+	s.onQueue(s.waiting)
+}
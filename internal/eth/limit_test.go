@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// slowSender records the most submissions in flight at once.
+type slowSender struct {
+	inFlight, maxInFlight, sent atomic.Int64
+	release                     chan struct{}
+}
+
+func (s *slowSender) SendBundle(tx *types.Transaction, blockNumber uint64) error {
+	n := s.inFlight.Add(1)
+	for {
+		max := s.maxInFlight.Load()
+		if n <= max || s.maxInFlight.CompareAndSwap(max, n) {
+			break
+		}
+	}
+	if s.release != nil {
+		<-s.release
+	} else {
+		time.Sleep(time.Millisecond)
+	}
+	s.inFlight.Add(-1)
+	s.sent.Add(1)
+	return nil
+}
+
+func TestLimitedSenderNeverExceedsTheCap(t *testing.T) {
+	next := &slowSender{}
+	var maxDepth atomic.Int64
+	sender, err := NewLimitedSender(next, 3, BundleOverflowQueue, func(depth int) {
+		if int64(depth) > maxDepth.Load() {
+			maxDepth.Store(int64(depth))
+		}
+	})
+	require.NoError(t, err)
+
+	tx := types.NewTx(&types.LegacyTx{})
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, sender.SendBundle(tx, 1))
+		}()
+	}
+	wg.Wait()
+
+	// Every submission waited its turn rather than being dropped
+	require.LessOrEqual(t, next.maxInFlight.Load(), int64(3))
+	require.Equal(t, int64(100), next.sent.Load())
+	require.Positive(t, maxDepth.Load())
+}
+
+func TestLimitedSenderDropsWhenSaturated(t *testing.T) {
+	next := &slowSender{release: make(chan struct{})}
+	sender, err := NewLimitedSender(next, 1, BundleOverflowDrop, nil)
+	require.NoError(t, err)
+
+	tx := types.NewTx(&types.LegacyTx{})
+	done := make(chan error)
+	go func() { done <- sender.SendBundle(tx, 1) }()
+	require.Eventually(t, func() bool { return next.inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	require.ErrorIs(t, sender.SendBundle(tx, 1), ErrBundleDropped)
+	close(next.release)
+	require.NoError(t, <-done)
+	require.NoError(t, sender.SendBundle(tx, 1))
+}
+
+func TestNewLimitedSenderRejectsInvalidSettings(t *testing.T) {
+	_, err := NewLimitedSender(&slowSender{}, 0, BundleOverflowQueue, nil)
+	require.Error(t, err)
+	_, err = NewLimitedSender(&slowSender{}, 1, "block", nil)
+	require.Error(t, err)
+}
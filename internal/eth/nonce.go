@@ -0,0 +1,361 @@
+package eth
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceReader returns an account's transaction counts. *ethclient.Client implements it.
+type NonceReader interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// Likely causes of a divergence between the chain's transaction counts and the
+// nonces the monitor tracks.
+const (
+	NonceCauseExternal = "external" // The pool holds transactions from the account that we didn't send.
+	NonceCauseDropped  = "dropped"  // Our transaction at the latest count is gone, stranding ours above it.
+	NonceCauseReorg    = "reorg"    // The latest count went backwards.
+)
+
+// Decisions of a nonce check.
+const (
+	NonceActionOK     = "ok"     // The chain agrees with the tracked nonces.
+	NonceActionResync = "resync" // The monitor adopted the chain's counts.
+	NonceActionWait   = "wait"   // Diverged with transactions of ours in flight; checked again next time.
+	NonceActionPause  = "pause"  // Diverged for too many checks in a row; bidding from the account pauses.
+)
+
+// NonceCheck is the outcome of comparing an account's transaction counts with
+// the nonces tracked for it.
+type NonceCheck struct {
+	Address  common.Address
+	Latest   uint64        // eth_getTransactionCount at latest.
+	Pending  uint64        // eth_getTransactionCount at pending.
+	Tracked  uint64        // The next nonce according to the tracked transactions.
+	InFlight int           // Tracked transactions not yet mined.
+	Cause    string        // Likely cause of the divergence, empty when consistent.
+	Action   string        // What the monitor did about it.
+	Dropped  []common.Hash // Stranded transactions forgotten by a resync.
+}
+
+// NonceMonitor tracks the nonces of our transactions in flight and periodically
+// compares them with the chain's transaction counts.
+//
+// Our transactions reach builders through bids rather than the public pool, so a
+// consistent account has every nonce in [latest, pending) in flight, and its lowest
+// nonce in flight equal to latest.
+type NonceMonitor struct {
+	reader      NonceReader
+	pauseChecks int
+
+	mu       sync.Mutex
+	accounts map[common.Address]*nonceState
+}
+
+// nonceState is the monitor's view of one account.
+type nonceState struct {
+	inFlight  map[uint64]common.Hash // Nonce -> hash of our transactions not yet mined.
+	latest    uint64                 // Latest count at the previous check.
+	checked   bool
+	divergent int // Consecutive divergent checks that couldn't resync.
+	paused    bool
+}
+
+// NewNonceMonitor creates a NonceMonitor.
+//
+// Parameters:
+// - reader: The source of transaction counts.
+// - pauseChecks: Consecutive divergent checks that can't resync before bidding pauses.
+//
+// Returns:
+// - A pointer to a NonceMonitor.
+func NewNonceMonitor(reader NonceReader, pauseChecks int) *NonceMonitor {
+	if pauseChecks < 1 {
+		pauseChecks = 1
+	}
+	return &NonceMonitor{
+		reader:      reader,
+		pauseChecks: pauseChecks,
+		accounts:    make(map[common.Address]*nonceState),
+	}
+}
+
+// Record tracks a transaction we sent from the account until it is mined or forgotten.
+func (m *NonceMonitor) Record(from common.Address, nonce uint64, hash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.account(from).inFlight[nonce] = hash
+}
+
+// Forget stops tracking the transaction with the given hash.
+//
+// Returns:
+// - Whether the transaction was tracked.
+func (m *NonceMonitor) Forget(hash common.Hash) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, state := range m.accounts {
+		for nonce, h := range state.inFlight {
+			if h == hash {
+				delete(state.inFlight, nonce)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Paused reports whether bidding from the account is paused until its nonces are
+// consistent again.
+func (m *NonceMonitor) Paused(from common.Address) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if state, ok := m.accounts[from]; ok {
+		return state.paused
+	}
+	return false
+}
+
+// Check fetches the account's latest and pending transaction counts and compares
+// them with its tracked nonces. A divergence is resynced when no transaction of
+// ours in flight has a nonce between the two counts, since resyncing could then
+// only strand or replace transactions that can't land anyway. Otherwise the
+// check waits, and pauses the account after pauseChecks such checks in a row.
+func (m *NonceMonitor) Check(ctx context.Context, from common.Address) (NonceCheck, error) {
+	latest, err := m.reader.NonceAt(ctx, from, nil)
+	if err != nil {
+		return NonceCheck{}, err
+	}
+	pending, err := m.reader.PendingNonceAt(ctx, from)
+	if err != nil {
+		return NonceCheck{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.account(from)
+	reorg := state.checked && latest < state.latest
+	state.latest = latest
+	state.checked = true
+
+	// Transactions below the latest count were mined, or replaced by someone else's
+	tracked := latest
+	for nonce := range state.inFlight {
+		if nonce < latest {
+			delete(state.inFlight, nonce)
+			continue
+		}
+		if nonce+1 > tracked {
+			tracked = nonce + 1
+		}
+	}
+
+	check := NonceCheck{
+		Address:  from,
+		Latest:   latest,
+		Pending:  pending,
+		Tracked:  tracked,
+		InFlight: len(state.inFlight),
+	}
+	_, ownsLatest := state.inFlight[latest]
+	switch {
+	case reorg:
+		check.Cause = NonceCauseReorg
+	case m.externalBetween(state, latest, pending):
+		check.Cause = NonceCauseExternal
+	case len(state.inFlight) > 0 && !ownsLatest && pending <= latest:
+		check.Cause = NonceCauseDropped
+	default:
+		state.divergent = 0
+		state.paused = false
+		check.Action = NonceActionOK
+		return check, nil
+	}
+
+	for nonce := latest; nonce < pending; nonce++ {
+		if _, ok := state.inFlight[nonce]; ok {
+			state.divergent++
+			check.Action = NonceActionWait
+			if state.divergent >= m.pauseChecks {
+				state.paused = true
+				check.Action = NonceActionPause
+			}
+			return check, nil
+		}
+	}
+
+	// Transactions above a gap can't land until it fills, so they are rebuilt from the latest count
+	if check.Cause == NonceCauseDropped {
+		for nonce, hash := range state.inFlight {
+			check.Dropped = append(check.Dropped, hash)
+			delete(state.inFlight, nonce)
+		}
+		check.Tracked = latest
+		check.InFlight = 0
+	}
+	state.divergent = 0
+	state.paused = false
+	check.Action = NonceActionResync
+	return check, nil
+}
+
+// externalBetween reports whether the pool holds a transaction at a nonce in
+// [latest, pending) that isn't ours; the caller must hold the lock.
+func (m *NonceMonitor) externalBetween(state *nonceState, latest, pending uint64) bool {
+	for nonce := latest; nonce < pending; nonce++ {
+		if _, ok := state.inFlight[nonce]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// account returns the state of the account, creating it if needed; the caller
+// must hold the lock.
+func (m *NonceMonitor) account(from common.Address) *nonceState {
+	state, ok := m.accounts[from]
+	if !ok {
+		state = &nonceState{inFlight: make(map[uint64]common.Hash)}
+		m.accounts[from] = state
+	}
+	return state
+}
+
+// PendingNonceReader returns an account's pending transaction count.
+type PendingNonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceAllocator hands out nonces from a local counter per account, for chains
+// built faster than the chain's pending count advances. Our transactions reach
+// builders through bids rather than the public pool, so the pending count only
+// moves once they are mined.
+//
+// The counter resyncs to the pending count when that is ahead, after
+// transactions sent elsewhere, and when it hasn't moved since the previous
+// allocation, which means the previous chain was dropped and left a gap. It
+// also resyncs after a number of failed sends in a row.
+type NonceAllocator struct {
+	resyncFailures int
+
+	mu       sync.Mutex
+	accounts map[common.Address]*allocatorState
+}
+
+// allocatorState is the counter of one account.
+type allocatorState struct {
+	next     uint64 // The next nonce to hand out.
+	pending  uint64 // The pending count at the previous allocation.
+	failures int    // Failed sends in a row.
+}
+
+// NewNonceAllocator creates an empty NonceAllocator.
+//
+// Parameters:
+// - resyncFailures: Failed sends in a row after which an account resyncs, at least 1.
+//
+// Returns:
+// - A pointer to a NonceAllocator.
+func NewNonceAllocator(resyncFailures int) *NonceAllocator {
+	if resyncFailures < 1 {
+		resyncFailures = 1
+	}
+	return &NonceAllocator{
+		resyncFailures: resyncFailures,
+		accounts:       make(map[common.Address]*allocatorState),
+	}
+}
+
+// Allocate reserves n consecutive nonces for an account.
+//
+// Parameters:
+// - ctx: The context of reading the pending count.
+// - reader: Reads the account's pending count.
+// - from: The account.
+// - n: The number of nonces.
+//
+// Returns:
+// - The first of the nonces, or an error if the pending count couldn't be read.
+func (a *NonceAllocator) Allocate(ctx context.Context, reader PendingNonceReader, from common.Address, n int) (uint64, error) {
+	pending, err := reader.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	state, ok := a.accounts[from]
+	switch {
+	case !ok:
+		state = &allocatorState{next: pending}
+		a.accounts[from] = state
+	case pending > state.next:
+		state.next = pending
+	case pending < state.next && pending == state.pending:
+		slog.Warn("Resyncing nonces after a dropped chain",
+			"address", from.Hex(),
+			"pendingNonce", pending,
+			"gap", state.next-pending,
+		)
+		state.next = pending
+	}
+	first := state.next
+	state.next += uint64(n)
+	state.pending = pending
+	return first, nil
+}
+
+// Resync makes the next allocation for the account start from its pending count,
+// after its transactions were dropped or never bid on.
+func (a *NonceAllocator) Resync(from common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.accounts, from)
+}
+
+// Fail counts a chain from the account that failed to build or send, and
+// resyncs the account once resyncFailures have failed in a row.
+//
+// Returns:
+// - Whether the account was resynced.
+func (a *NonceAllocator) Fail(from common.Address) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	state, ok := a.accounts[from]
+	if !ok {
+		return false
+	}
+	state.failures++
+	if state.failures < a.resyncFailures {
+		return false
+	}
+	delete(a.accounts, from)
+	return true
+}
+
+// Succeed resets the account's count of failed sends.
+func (a *NonceAllocator) Succeed(from common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if state, ok := a.accounts[from]; ok {
+		state.failures = 0
+	}
+}
+
+// Next returns the next nonce the account's counter hands out, if it has one.
+func (a *NonceAllocator) Next(from common.Address) (uint64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	state, ok := a.accounts[from]
+	if !ok {
+		return 0, false
+	}
+	return state.next, true
+}
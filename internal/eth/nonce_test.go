@@ -0,0 +1,197 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedNonceReader returns the next scripted latest and pending counts on each check.
+type scriptedNonceReader struct {
+	counts [][2]uint64
+}
+
+func (r *scriptedNonceReader) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return r.counts[0][0], nil
+}
+
+func (r *scriptedNonceReader) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	pending := r.counts[0][1]
+	r.counts = r.counts[1:]
+	return pending, nil
+}
+
+func TestNonceMonitor(t *testing.T) {
+	from := common.HexToAddress("0x1")
+	ours := common.HexToHash("0xa")
+
+	for _, tc := range []struct {
+		name     string
+		inFlight map[uint64]common.Hash
+		counts   [][2]uint64 // Latest and pending counts of successive checks.
+		cause    string
+		actions  []string
+		dropped  []common.Hash
+	}{
+		{
+			name:     "consistent with ours in the pool",
+			inFlight: map[uint64]common.Hash{5: ours},
+			counts:   [][2]uint64{{5, 6}, {6, 6}},
+			actions:  []string{NonceActionOK, NonceActionOK},
+		},
+		{
+			name:    "external send resyncs",
+			counts:  [][2]uint64{{5, 7}},
+			cause:   NonceCauseExternal,
+			actions: []string{NonceActionResync},
+		},
+		{
+			name:     "dropped transaction resyncs and forgets the stranded ones",
+			inFlight: map[uint64]common.Hash{6: ours},
+			counts:   [][2]uint64{{5, 5}},
+			cause:    NonceCauseDropped,
+			actions:  []string{NonceActionResync},
+			dropped:  []common.Hash{ours},
+		},
+		{
+			name:    "reorg resyncs",
+			counts:  [][2]uint64{{7, 7}, {5, 7}},
+			cause:   NonceCauseReorg,
+			actions: []string{NonceActionOK, NonceActionResync},
+		},
+		{
+			name:     "external send behind ours in flight pauses when it persists",
+			inFlight: map[uint64]common.Hash{6: ours},
+			counts:   [][2]uint64{{5, 7}, {5, 7}},
+			cause:    NonceCauseExternal,
+			actions:  []string{NonceActionWait, NonceActionPause},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			monitor := NewNonceMonitor(&scriptedNonceReader{counts: tc.counts}, 2)
+			for nonce, hash := range tc.inFlight {
+				monitor.Record(from, nonce, hash)
+			}
+
+			var check NonceCheck
+			for i, action := range tc.actions {
+				var err error
+				check, err = monitor.Check(context.Background(), from)
+				require.NoError(t, err)
+				require.Equal(t, action, check.Action, "check %d", i)
+			}
+			require.Equal(t, tc.cause, check.Cause)
+			require.Equal(t, tc.dropped, check.Dropped)
+			require.Equal(t, check.Action == NonceActionPause, monitor.Paused(from))
+		})
+	}
+}
+
+func TestNonceMonitorResumesAfterForget(t *testing.T) {
+	from := common.HexToAddress("0x1")
+	ours := common.HexToHash("0xa")
+	monitor := NewNonceMonitor(&scriptedNonceReader{counts: [][2]uint64{{5, 7}, {5, 7}}}, 1)
+	monitor.Record(from, 6, ours)
+
+	check, err := monitor.Check(context.Background(), from)
+	require.NoError(t, err)
+	require.Equal(t, NonceActionPause, check.Action)
+	require.True(t, monitor.Paused(from))
+
+	// Once the doomed transaction is abandoned, the counts can be adopted
+	require.True(t, monitor.Forget(ours))
+	require.False(t, monitor.Forget(ours))
+	check, err = monitor.Check(context.Background(), from)
+	require.NoError(t, err)
+	require.Equal(t, NonceActionResync, check.Action)
+	require.False(t, monitor.Paused(from))
+}
+
+// fixedPendingReader returns a settable pending count.
+type fixedPendingReader struct {
+	pending uint64
+}
+
+func (r *fixedPendingReader) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return r.pending, nil
+}
+
+func TestNonceAllocator(t *testing.T) {
+	ctx := context.Background()
+	from := common.HexToAddress("0x1")
+	reader := &fixedPendingReader{pending: 5}
+	allocator := NewNonceAllocator(1)
+
+	// The first chain starts at the pending count
+	first, err := allocator.Allocate(ctx, reader, from, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), first)
+
+	// It was mined, so the next one follows it
+	reader.pending = 8
+	first, err = allocator.Allocate(ctx, reader, from, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), first)
+
+	// Only part of it was mined: the rest is still in flight, so the counter moves on
+	reader.pending = 9
+	first, err = allocator.Allocate(ctx, reader, from, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), first)
+
+	// Nothing was mined since: the chain was dropped, leaving a gap at 9
+	first, err = allocator.Allocate(ctx, reader, from, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), first, "resyncs to the pending count after a gap")
+
+	// Transactions sent elsewhere move the pending count past the counter
+	reader.pending = 20
+	first, err = allocator.Allocate(ctx, reader, from, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), first)
+
+	// An explicit resync starts over from the pending count
+	allocator.Resync(from)
+	first, err = allocator.Allocate(ctx, reader, from, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), first)
+
+	// Accounts have separate counters
+	first, err = allocator.Allocate(ctx, reader, common.HexToAddress("0x2"), 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), first)
+}
+
+func TestNonceAllocatorResyncsAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	from := common.HexToAddress("0x1")
+	reader := &fixedPendingReader{pending: 5}
+	allocator := NewNonceAllocator(2)
+
+	_, ok := allocator.Next(from)
+	require.False(t, ok)
+	require.False(t, allocator.Fail(from), "nothing to resync before the first allocation")
+
+	_, err := allocator.Allocate(ctx, reader, from, 3)
+	require.NoError(t, err)
+	next, ok := allocator.Next(from)
+	require.True(t, ok)
+	require.Equal(t, uint64(8), next)
+
+	// A success in between restarts the count of failures
+	require.False(t, allocator.Fail(from))
+	allocator.Succeed(from)
+	require.False(t, allocator.Fail(from))
+	next, _ = allocator.Next(from)
+	require.Equal(t, uint64(8), next, "the counter survives failures below the limit")
+
+	require.True(t, allocator.Fail(from))
+	_, ok = allocator.Next(from)
+	require.False(t, ok)
+	first, err := allocator.Allocate(ctx, reader, from, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), first)
+}
@@ -0,0 +1,160 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+)
+
+// RebalanceClient is the part of an Ethereum client used to top up wallets.
+// *ethclient.Client implements it.
+type RebalanceClient interface {
+	BlobTxClient
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// RebalanceConfig bounds the top-ups of a Rebalancer.
+type RebalanceConfig struct {
+	MinBalance *big.Int      // Balance in wei below which a wallet is topped up.
+	TopUp      *big.Int      // Wei sent by each top-up.
+	Cooldown   time.Duration // Least time between two top-ups of a wallet, so one still pending isn't repeated.
+	MaxTotal   *big.Int      // Most wei sent over the session, nil or 0 for no limit.
+}
+
+// TopUp is a transfer sent from the funding wallet to a wallet of the pool.
+type TopUp struct {
+	Wallet  common.Address
+	Balance *big.Int // The wallet's balance before the top-up.
+	Tx      *types.Transaction
+}
+
+// Rebalancer keeps the wallets of the pool funded from a funding wallet. Each
+// check tops up at most one wallet, the lowest below the minimum balance, so the
+// funding wallet never has two transfers racing for a nonce.
+type Rebalancer struct {
+	client    RebalanceClient
+	funder    bb.AuthAcct
+	cfg       RebalanceConfig
+	tipPolicy TipPolicy
+	now       func() time.Time
+
+	mu        sync.Mutex
+	lastTopUp map[common.Address]time.Time
+	sent      *big.Int
+}
+
+// NewRebalancer creates a Rebalancer.
+//
+// Parameters:
+// - client: Reads balances, and builds and sends the transfers; a ClientFunc follows reconnections.
+// - funder: The wallet the top-ups are sent from.
+// - cfg: The minimum balance, top-up amount and limits.
+// - tipPolicy: Chooses the tip of the transfers, or the default priority fee if nil.
+//
+// Returns:
+// - A pointer to a Rebalancer, or an error if the amounts aren't positive.
+func NewRebalancer(client RebalanceClient, funder bb.AuthAcct, cfg RebalanceConfig, tipPolicy TipPolicy) (*Rebalancer, error) {
+	if cfg.MinBalance == nil || cfg.MinBalance.Sign() <= 0 {
+		return nil, fmt.Errorf("rebalance minimum balance must be positive")
+	}
+	if cfg.TopUp == nil || cfg.TopUp.Sign() <= 0 {
+		return nil, fmt.Errorf("rebalance top-up amount must be positive")
+	}
+	if cfg.Cooldown < 0 {
+		return nil, fmt.Errorf("rebalance cooldown must not be negative, got %s", cfg.Cooldown)
+	}
+	if cfg.MaxTotal != nil && cfg.MaxTotal.Sign() < 0 {
+		return nil, fmt.Errorf("rebalance total limit must not be negative")
+	}
+	return &Rebalancer{
+		client:    client,
+		funder:    funder,
+		cfg:       cfg,
+		tipPolicy: tipPolicy,
+		now:       time.Now,
+		lastTopUp: make(map[common.Address]time.Time),
+		sent:      new(big.Int),
+	}, nil
+}
+
+// Check reads the balance of each wallet and tops up the lowest one below the
+// minimum balance, skipping wallets topped up within the cooldown. Once the
+// session's limit would be passed, no more top-ups are sent.
+//
+// Parameters:
+// - ctx: Bounds the balance reads and the transfer.
+// - wallets: The wallets of the pool.
+//
+// Returns:
+// - The top-up sent, nil if no wallet needed one or the limit was reached.
+// - The errors reading balances, building or sending the transfer.
+func (r *Rebalancer) Check(ctx context.Context, wallets []common.Address) (*TopUp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	var lowest *TopUp
+	for _, wallet := range wallets {
+		if wallet == r.funder.Address {
+			continue
+		}
+		if last, ok := r.lastTopUp[wallet]; ok && r.now().Sub(last) < r.cfg.Cooldown {
+			continue
+		}
+		balance, err := r.client.BalanceAt(ctx, wallet, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read balance of %s: %w", wallet.Hex(), err))
+			continue
+		}
+		if balance.Cmp(r.cfg.MinBalance) >= 0 {
+			continue
+		}
+		if lowest == nil || balance.Cmp(lowest.Balance) < 0 {
+			lowest = &TopUp{Wallet: wallet, Balance: balance}
+		}
+	}
+	if lowest == nil {
+		return nil, errors.Join(errs...)
+	}
+
+	if r.cfg.MaxTotal != nil && r.cfg.MaxTotal.Sign() > 0 {
+		if total := new(big.Int).Add(r.sent, r.cfg.TopUp); total.Cmp(r.cfg.MaxTotal) > 0 {
+			slog.Warn("Wallet top-up limit reached, not topping up",
+				"wallet", lowest.Wallet.Hex(),
+				"balanceWei", lowest.Balance.String(),
+				"sentWei", r.sent.String(),
+				"maxTotalWei", r.cfg.MaxTotal.String(),
+			)
+			return nil, errors.Join(errs...)
+		}
+	}
+
+	tx, _, err := ETHTransfer(r.client, r.funder, lowest.Wallet, r.cfg.TopUp, 0, r.tipPolicy)
+	if err == nil {
+		err = r.client.SendTransaction(ctx, tx)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to top up %s: %w", lowest.Wallet.Hex(), err))
+		return nil, errors.Join(errs...)
+	}
+	lowest.Tx = tx
+	r.lastTopUp[lowest.Wallet] = r.now()
+	r.sent.Add(r.sent, r.cfg.TopUp)
+	return lowest, errors.Join(errs...)
+}
+
+// Sent returns the wei sent by top-ups so far.
+func (r *Rebalancer) Sent() *big.Int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return new(big.Int).Set(r.sent)
+}
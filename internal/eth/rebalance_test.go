@@ -0,0 +1,111 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+// balanceClient serves wallet balances and records the transactions sent.
+type balanceClient struct {
+	partialHeaderClient
+	balances map[common.Address]*big.Int
+	sent     []*types.Transaction
+}
+
+func (c *balanceClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if balance, ok := c.balances[account]; ok {
+		return balance, nil
+	}
+	return new(big.Int), nil
+}
+
+func (c *balanceClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func newTestRebalancer(t *testing.T, cfg RebalanceConfig) (*Rebalancer, *balanceClient, bb.AuthAcct) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	funder := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	client := &balanceClient{
+		partialHeaderClient: partialHeaderClient{latest: &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(params.GWei)}},
+		balances:            make(map[common.Address]*big.Int),
+	}
+	r, err := NewRebalancer(client, funder, cfg, nil)
+	require.NoError(t, err)
+	return r, client, funder
+}
+
+func TestRebalancerTopsUpLowWallet(t *testing.T) {
+	cfg := RebalanceConfig{MinBalance: big.NewInt(params.Ether / 10), TopUp: big.NewInt(params.Ether / 2), Cooldown: time.Minute}
+	r, client, funder := newTestRebalancer(t, cfg)
+	healthy, low, lower := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+	client.balances[healthy] = big.NewInt(params.Ether)
+	client.balances[low] = big.NewInt(params.Ether / 20)
+	client.balances[lower] = big.NewInt(params.Ether / 100)
+	wallets := []common.Address{healthy, low, lower}
+
+	// The lowest wallet is topped up first, by a transfer from the funding wallet
+	topUp, err := r.Check(context.Background(), wallets)
+	require.NoError(t, err)
+	require.NotNil(t, topUp)
+	require.Equal(t, lower, topUp.Wallet)
+	require.Len(t, client.sent, 1)
+	tx := client.sent[0]
+	require.Equal(t, lower, *tx.To())
+	require.Equal(t, cfg.TopUp, tx.Value())
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	require.NoError(t, err)
+	require.Equal(t, funder.Address, from)
+
+	// The next check moves on while the first top-up is in cooldown
+	topUp, err = r.Check(context.Background(), wallets)
+	require.NoError(t, err)
+	require.Equal(t, low, topUp.Wallet)
+	topUp, err = r.Check(context.Background(), wallets)
+	require.NoError(t, err)
+	require.Nil(t, topUp)
+	require.Len(t, client.sent, 2)
+	require.Equal(t, big.NewInt(params.Ether), r.Sent())
+
+	// Once the cooldown is over a wallet that is still low is topped up again
+	r.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	topUp, err = r.Check(context.Background(), wallets)
+	require.NoError(t, err)
+	require.Equal(t, lower, topUp.Wallet)
+}
+
+func TestRebalancerLimit(t *testing.T) {
+	cfg := RebalanceConfig{MinBalance: big.NewInt(100), TopUp: big.NewInt(60), MaxTotal: big.NewInt(100)}
+	r, client, _ := newTestRebalancer(t, cfg)
+	wallets := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+
+	topUp, err := r.Check(context.Background(), wallets)
+	require.NoError(t, err)
+	require.NotNil(t, topUp)
+	// A second top-up would take the total past the limit
+	topUp, err = r.Check(context.Background(), wallets)
+	require.NoError(t, err)
+	require.Nil(t, topUp)
+	require.Len(t, client.sent, 1)
+}
+
+func TestNewRebalancerValidates(t *testing.T) {
+	_, err := NewRebalancer(nil, bb.AuthAcct{}, RebalanceConfig{TopUp: big.NewInt(1)}, nil)
+	require.Error(t, err)
+	_, err = NewRebalancer(nil, bb.AuthAcct{}, RebalanceConfig{MinBalance: big.NewInt(1)}, nil)
+	require.Error(t, err)
+	_, err = NewRebalancer(nil, bb.AuthAcct{}, RebalanceConfig{MinBalance: big.NewInt(1), TopUp: big.NewInt(1), Cooldown: -time.Second}, nil)
+	require.Error(t, err)
+}
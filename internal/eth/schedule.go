@@ -0,0 +1,88 @@
+package eth
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Transfer is one row of a TransferSchedule.
+type Transfer struct {
+	To    common.Address
+	Value *big.Int // Value in wei.
+}
+
+// TransferSchedule cycles through a fixed sequence of ETH transfers, starting
+// over once all have been used.
+type TransferSchedule struct {
+	mu        sync.Mutex
+	transfers []Transfer
+	next      int
+}
+
+// LoadTransferSchedule reads a CSV file of recipient,value rows, with values in
+// wei. A first row of recipient,value is taken as a header.
+//
+// Parameters:
+// - filePath: The path of the CSV file.
+//
+// Returns:
+// - A pointer to a TransferSchedule, or an error if a row has an invalid address or value.
+func LoadTransferSchedule(filePath string) (*TransferSchedule, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transfer schedule: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var transfers []Transfer
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transfer schedule: %w", err)
+		}
+		if line == 1 && strings.EqualFold(row[0], "recipient") {
+			continue
+		}
+
+		if !common.IsHexAddress(row[0]) {
+			return nil, fmt.Errorf("transfer schedule line %d: invalid recipient %q", line, row[0])
+		}
+		value, ok := new(big.Int).SetString(row[1], 10)
+		if !ok || value.Sign() < 0 {
+			return nil, fmt.Errorf("transfer schedule line %d: invalid value %q, must be a non-negative amount in wei", line, row[1])
+		}
+		transfers = append(transfers, Transfer{To: common.HexToAddress(row[0]), Value: value})
+	}
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("transfer schedule %s defines no transfers", filePath)
+	}
+	return &TransferSchedule{transfers: transfers}, nil
+}
+
+// Next returns the next transfer, wrapping around after the last one.
+func (s *TransferSchedule) Next() Transfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	transfer := s.transfers[s.next]
+	s.next = (s.next + 1) % len(s.transfers)
+	return transfer
+}
+
+// Len returns the number of transfers in the schedule.
+func (s *TransferSchedule) Len() int {
+	return len(s.transfers)
+}
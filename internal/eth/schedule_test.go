@@ -0,0 +1,56 @@
+package eth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSchedule(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "schedule.csv")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
+
+func TestTransferScheduleWrapsAround(t *testing.T) {
+	path := writeSchedule(t, `recipient,value
+0x00000000000000000000000000000000000000a1,1000
+0x00000000000000000000000000000000000000a2, 0
+0x00000000000000000000000000000000000000a3,20000000000000000000
+`)
+	schedule, err := LoadTransferSchedule(path)
+	require.NoError(t, err)
+	require.Equal(t, 3, schedule.Len())
+
+	want := []struct {
+		to    string
+		value string
+	}{
+		{"0x00000000000000000000000000000000000000a1", "1000"},
+		{"0x00000000000000000000000000000000000000a2", "0"},
+		{"0x00000000000000000000000000000000000000a3", "20000000000000000000"},
+		{"0x00000000000000000000000000000000000000a1", "1000"},
+		{"0x00000000000000000000000000000000000000a2", "0"},
+	}
+	for i, w := range want {
+		transfer := schedule.Next()
+		require.Equal(t, common.HexToAddress(w.to), transfer.To, "row %d", i)
+		require.Equal(t, w.value, transfer.Value.String(), "row %d", i)
+	}
+}
+
+func TestTransferScheduleRejectsInvalidRows(t *testing.T) {
+	for name, contents := range map[string]string{
+		"bad address":    "0x1234,1000\n",
+		"bad value":      "0x00000000000000000000000000000000000000a1,0.5\n",
+		"negative value": "0x00000000000000000000000000000000000000a1,-1\n",
+		"missing value":  "0x00000000000000000000000000000000000000a1\n",
+		"empty":          "recipient,value\n",
+	} {
+		_, err := LoadTransferSchedule(writeSchedule(t, contents))
+		require.Error(t, err, name)
+	}
+}
@@ -6,8 +6,8 @@ package eth
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"log/slog"
 	"math/big"
 	"os"
@@ -16,19 +16,16 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/holiman/uint256"
 	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
 	"golang.org/x/exp/rand"
 )
 
 var (
-	defaultTimeout time.Duration
+	defaultTimeout         time.Duration
 	defaultPriorityFeeGwei = big.NewInt(1) // in wei
 )
 
@@ -65,191 +62,69 @@ func init() {
 	}
 }
 
-// SelfETHTransfer sends an ETH transfer transaction from the authenticated account.
-func SelfETHTransfer(client *ethclient.Client, authAcct bb.AuthAcct, value *big.Int, offset uint64, priorityFeeGwei *big.Int) (*types.Transaction, uint64, error) {
-	// Set a timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-
-	// Get the account's nonce
-	nonce, err := client.PendingNonceAt(ctx, authAcct.Address)
-	if err != nil {
-		slog.Default().Error("Failed to get pending nonce",
-			slog.String("function", "PendingNonceAt"),
-			slog.Any("error", err))
-		return nil, 0, err
-	}
-
-	// Get the current base fee per gas from the latest block header
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		slog.Default().Error("Failed to get latest block header",
-			slog.String("function", "HeaderByNumber"),
-			slog.Any("error", err))
-		return nil, 0, err
-	}
+// SelfETHTransfer sends an ETH transfer transaction from the authenticated account to itself.
+// The tip is chosen by the TipPolicy from the latest base fee, or the default priority fee if nil.
+func SelfETHTransfer(client *ethclient.Client, authAcct bb.AuthAcct, value *big.Int, offset uint64, tipPolicy TipPolicy) (*types.Transaction, uint64, error) {
+	return ETHTransfer(client, authAcct, authAcct.Address, value, offset, tipPolicy)
+}
 
-	// Get the chain ID
-	chainID, err := client.NetworkID(ctx)
+// ETHTransfer sends an ETH transfer transaction from the authenticated account to a recipient.
+// The tip is chosen by the TipPolicy from the latest base fee, or the default priority fee if nil.
+func ETHTransfer(client BlobTxClient, authAcct bb.AuthAcct, to common.Address, value *big.Int, offset uint64, tipPolicy TipPolicy) (*types.Transaction, uint64, error) {
+	txs, blockNumber, err := BuildChain(client, authAcct, GeneratorTransfer, TxOptions{To: &to, Value: value}, 1, offset, tipPolicy)
 	if err != nil {
-		slog.Default().Error("Failed to get network ID",
-			slog.String("function", "NetworkID"),
-			slog.Any("error", err))
 		return nil, 0, err
 	}
+	return txs[0], blockNumber, nil
+}
 
-	baseFee := header.BaseFee
-	blockNumber := header.Number.Uint64()
-
-	// Use provided priority fee or default
-	priorityFee := defaultPriorityFeeGwei
-	if priorityFeeGwei != nil {
-		priorityFee = new(big.Int).Mul(priorityFeeGwei, big.NewInt(1))
-	}
-
-	// Create a transaction with the specified priority fee
-	maxFee := new(big.Int).Add(baseFee, priorityFee)
-	tx := types.NewTx(&types.DynamicFeeTx{
-		Nonce:     nonce,
-		To:        &authAcct.Address,
-		Value:     value,
-		Gas:       1_000_000,
-		GasFeeCap: maxFee,
-		GasTipCap: priorityFee,
-	})
-
-	// Sign the transaction with the authenticated account's private key
-	signer := types.LatestSignerForChainID(chainID)
-	signedTx, err := types.SignTx(tx, signer, authAcct.PrivateKey)
+// SendERC20Transfer builds an ERC-20 transfer of amount base units of a token from
+// the authenticated account to a recipient.
+// The tip is chosen by the TipPolicy from the latest base fee, or the default priority fee if nil.
+func SendERC20Transfer(client BlobTxClient, authAcct bb.AuthAcct, tokenAddr, to common.Address, amount *big.Int, offset uint64, tipPolicy TipPolicy) (*types.Transaction, uint64, error) {
+	opts := TxOptions{To: &to, Token: &TokenTransfer{Address: tokenAddr, Amount: amount}}
+	txs, blockNumber, err := BuildChain(client, authAcct, GeneratorERC20, opts, 1, offset, tipPolicy)
 	if err != nil {
-		slog.Default().Error("Failed to sign transaction",
-			slog.String("function", "SignTx"),
-			slog.Any("error", err))
 		return nil, 0, err
 	}
+	return txs[0], blockNumber, nil
+}
 
-	slog.Default().Info("Self ETH transfer transaction created and signed",
-		slog.String("tx_hash", signedTx.Hash().Hex()),
-		slog.Uint64("block_number", blockNumber))
-
-	return signedTx, blockNumber + offset, nil
+// BlobTxClient is the part of an Ethereum client used to build blob transactions
+// and transfers. *ethclient.Client implements it.
+type BlobTxClient interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	NetworkID(ctx context.Context) (*big.Int, error)
 }
 
 // ExecuteBlobTransaction executes a blob transaction with preconfirmation bids.
-func ExecuteBlobTransaction(client *ethclient.Client, authAcct bb.AuthAcct, numBlobs int, offset uint64, priorityFeeGwei *big.Int) (*types.Transaction, uint64, error) {
-
-	pubKey, ok := authAcct.PrivateKey.Public().(*ecdsa.PublicKey)
-	if !ok || pubKey == nil {
-		slog.Default().Error("Failed to cast public key to ECDSA")
-		return nil, 0, errors.New("failed to cast public key to ECDSA")
-	}
-
-	var (
-		gasLimit    = uint64(1_000_000)
-		blockNumber uint64
-		nonce       uint64
-	)
-
-	// Set a timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-
-	privateKey := authAcct.PrivateKey
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		slog.Default().Error("Failed to cast public key to ECDSA")
-		return nil, 0, errors.New("failed to cast public key to ECDSA")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-	nonce, err := client.PendingNonceAt(ctx, authAcct.Address)
-	if err != nil {
-		slog.Default().Error("Failed to get pending nonce",
-			slog.String("function", "PendingNonceAt"),
-			slog.Any("error", err))
-		return nil, 0, err
-	}
-
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		slog.Default().Error("Failed to get latest block header",
-			slog.String("function", "HeaderByNumber"),
-			slog.Any("error", err))
-		return nil, 0, err
-	}
-
-	blockNumber = header.Number.Uint64()
-
-	chainID, err := client.NetworkID(ctx)
-	if err != nil {
-		slog.Default().Error("Failed to get network ID",
-			slog.String("function", "NetworkID"),
-			slog.Any("error", err))
-		return nil, 0, err
-	}
-
-	// Calculate the blob fee cap and ensure it is sufficient for transaction replacement
-	parentExcessBlobGas := eip4844.CalcExcessBlobGas(*header.ExcessBlobGas, *header.BlobGasUsed)
-	blobFeeCap := eip4844.CalcBlobFee(parentExcessBlobGas)
-	blobFeeCap.Add(blobFeeCap, big.NewInt(1)) // Ensure it's at least 1 unit higher to replace a transaction
-
-	// Generate random blobs and their corresponding sidecar
-	blobs := randBlobs(numBlobs)
-	sideCar := makeSidecar(blobs)
-	blobHashes := sideCar.BlobHashes()
-
-	// Incrementally increase blob fee cap for replacement
-	incrementFactor := big.NewInt(110) // 10% increase
-	blobFeeCap.Mul(blobFeeCap, incrementFactor).Div(blobFeeCap, big.NewInt(100))
-
-	// Use provided priority fee or default
-	priorityFee := defaultPriorityFeeGwei
-	if priorityFeeGwei != nil {
-		priorityFee = new(big.Int).Mul(priorityFeeGwei, big.NewInt(1_000_000_000)) // Convert gwei to wei
-	}
-
-	baseFee := header.BaseFee
-	maxFeePerGas := baseFee
-	maxFeePriority := new(big.Int).Add(maxFeePerGas, priorityFee)
-
-	// Create a new BlobTx transaction
-	tx := types.NewTx(&types.BlobTx{
-		ChainID:    uint256.MustFromBig(chainID),
-		Nonce:      nonce,
-		GasTipCap:  uint256.MustFromBig(priorityFee),
-		GasFeeCap:  uint256.MustFromBig(maxFeePriority),
-		Gas:        gasLimit,
-		To:         fromAddress,
-		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
-		BlobHashes: blobHashes,
-		Sidecar:    sideCar,
-	})
-
-	// Create the transaction options with the private key and chain ID
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
-	if err != nil {
-		slog.Default().Error("Failed to create keyed transactor",
-			slog.String("function", "NewKeyedTransactorWithChainID"),
-			slog.Any("error", err))
-		return nil, 0, err
-	}
-
-	// Sign the transaction
-	signedTx, err := auth.Signer(auth.From, tx)
+// The tip is chosen by the TipPolicy from the latest base fee, or the default priority fee if nil.
+func ExecuteBlobTransaction(client *ethclient.Client, authAcct bb.AuthAcct, numBlobs int, offset uint64, tipPolicy TipPolicy) (*types.Transaction, uint64, error) {
+	txs, blockNumber, err := ExecuteBlobChain(client, authAcct, 1, numBlobs, offset, tipPolicy)
 	if err != nil {
-		slog.Default().Error("Failed to sign blob transaction",
-			slog.String("function", "Signer"),
-			slog.Any("error", err))
 		return nil, 0, err
 	}
+	return txs[0], blockNumber, nil
+}
 
-	slog.Default().Info("Blob transaction created and signed",
-		slog.String("tx_hash", signedTx.Hash().Hex()),
-		slog.Uint64("block_number", blockNumber),
-		slog.Int("num_blobs", numBlobs))
-
-	return signedTx, blockNumber + offset, nil
+// ExecuteBlobChain builds a chain of blob transactions from one wallet with
+// sequential nonces, to be included in the same block. The nonces are allocated
+// as one contiguous range from the wallet's pending nonce.
+//
+// Parameters:
+// - client: The client used to read the nonce, latest header and chain ID.
+// - authAcct: The wallet sending the chain.
+// - length: The number of transactions in the chain.
+// - numBlobs: The number of blobs in each transaction.
+// - offset: The number of blocks after the latest one to target.
+// - tipPolicy: Chooses the tip from the latest base fee, or the default priority fee if nil.
+//
+// Returns:
+// - The signed transactions in nonce order, which is the order they must be broadcast in.
+// - The target block number.
+func ExecuteBlobChain(client BlobTxClient, authAcct bb.AuthAcct, length int, numBlobs int, offset uint64, tipPolicy TipPolicy) ([]*types.Transaction, uint64, error) {
+	return BuildChain(client, authAcct, GeneratorBlob, TxOptions{NumBlobs: numBlobs}, length, offset, tipPolicy)
 }
 
 // makeSidecar creates a sidecar for the given blobs by generating commitments and proofs.
@@ -259,12 +134,25 @@ func makeSidecar(blobs []kzg4844.Blob) *types.BlobTxSidecar {
 		proofs      []kzg4844.Proof
 	)
 
-	// Generate commitments and proofs for each blob
-	for _, blob := range blobs {
-		c, _ := kzg4844.BlobToCommitment(&blob)
-		p, _ := kzg4844.ComputeBlobProof(&blob, c)
-
+	// Generate commitments and proofs for each blob. Failures are left out so that
+	// validateSidecar reports the partial sidecar instead of sending invalid data.
+	for i, blob := range blobs {
+		c, err := blobToCommitment(&blob)
+		if err != nil {
+			slog.Default().Warn("Failed to compute blob commitment",
+				slog.Int("blob_index", i),
+				slog.Any("error", err))
+			continue
+		}
 		commitments = append(commitments, c)
+
+		p, err := computeBlobProof(&blob, c)
+		if err != nil {
+			slog.Default().Warn("Failed to compute blob proof",
+				slog.Int("blob_index", i),
+				slog.Any("error", err))
+			continue
+		}
 		proofs = append(proofs, p)
 	}
 
@@ -275,29 +163,51 @@ func makeSidecar(blobs []kzg4844.Blob) *types.BlobTxSidecar {
 	}
 }
 
+// validateSidecar checks that every blob in the sidecar has a matching commitment and proof.
+func validateSidecar(sidecar *types.BlobTxSidecar) error {
+	if sidecar == nil || len(sidecar.Blobs) == 0 {
+		return errors.New("blob sidecar contains no blobs")
+	}
+	if len(sidecar.Commitments) != len(sidecar.Blobs) || len(sidecar.Proofs) != len(sidecar.Blobs) {
+		return fmt.Errorf("partial blob sidecar: %d blobs, %d commitments, %d proofs",
+			len(sidecar.Blobs), len(sidecar.Commitments), len(sidecar.Proofs))
+	}
+	return nil
+}
+
 // randBlobs generates a slice of random blobs.
 func randBlobs(n int) []kzg4844.Blob {
+	return blobsFrom(rand.Read, n)
+}
+
+// randBlob generates a single random blob.
+func randBlob() kzg4844.Blob {
+	return blobFrom(rand.Read)
+}
+
+// blobsFrom generates a slice of blobs from the bytes of read.
+func blobsFrom(read func([]byte) (int, error), n int) []kzg4844.Blob {
 	blobs := make([]kzg4844.Blob, n)
 	for i := 0; i < n; i++ {
-		blobs[i] = randBlob()
+		blobs[i] = blobFrom(read)
 	}
 	return blobs
 }
 
-// randBlob generates a single random blob.
-func randBlob() kzg4844.Blob {
+// blobFrom generates a single blob from the bytes of read.
+func blobFrom(read func([]byte) (int, error)) kzg4844.Blob {
 	var blob kzg4844.Blob
 	for i := 0; i < len(blob); i += gokzg4844.SerializedScalarSize {
-		fieldElementBytes := randFieldElement()
+		fieldElementBytes := fieldElementFrom(read)
 		copy(blob[i:i+gokzg4844.SerializedScalarSize], fieldElementBytes[:])
 	}
 	return blob
 }
 
-// randFieldElement generates a random field element.
-func randFieldElement() [32]byte {
+// fieldElementFrom generates a field element from the bytes of read.
+func fieldElementFrom(read func([]byte) (int, error)) [32]byte {
 	bytes := make([]byte, 32)
-	_, err := rand.Read(bytes)
+	_, err := read(bytes)
 	if err != nil {
 		slog.Default().Error("Failed to generate random field element",
 			slog.Any("error", err))
@@ -0,0 +1,62 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSidecar(t *testing.T) {
+	sidecar := makeSidecar(randBlobs(2))
+	require.NoError(t, validateSidecar(sidecar))
+
+	// Drop the proof of the second blob to simulate partial availability
+	sidecar.Proofs = sidecar.Proofs[:1]
+	err := validateSidecar(sidecar)
+	require.EqualError(t, err, "partial blob sidecar: 2 blobs, 2 commitments, 1 proofs")
+
+	require.Error(t, validateSidecar(nil))
+}
+
+func TestExecuteBlobChainBroadcastsContiguousNoncesInOrder(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	backend := simulated.NewBackend(types.GenesisAlloc{wallet.Address: {Balance: big.NewInt(params.Ether)}})
+	defer backend.Close()
+	client := backend.Client().(BlobTxClient)
+
+	// One transaction from the wallet is already mined
+	first, _, err := ExecuteBlobChain(client, wallet, 1, 1, 1, FixedTip(big.NewInt(params.GWei)))
+	require.NoError(t, err)
+	require.NoError(t, backend.Client().SendTransaction(context.Background(), first[0]))
+	backend.Commit()
+
+	const length = 3
+	chain, targetBlock, err := ExecuteBlobChain(client, wallet, length, 1, 1, FixedTip(big.NewInt(params.GWei)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), targetBlock)
+	require.Len(t, chain, length)
+	for i, tx := range chain {
+		require.Equal(t, uint64(1+i), tx.Nonce(), "nonces are contiguous from the pending nonce")
+		require.NoError(t, backend.Client().SendTransaction(context.Background(), tx))
+	}
+	backend.Commit()
+
+	block, err := backend.Client().BlockByNumber(context.Background(), big.NewInt(2))
+	require.NoError(t, err)
+	require.Len(t, block.Transactions(), length)
+	for i, tx := range block.Transactions() {
+		require.Equal(t, chain[i].Hash(), tx.Hash(), "the chain is included in nonce order")
+	}
+
+	_, _, err = ExecuteBlobChain(client, wallet, 0, 1, 1, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// How blob transactions are delivered, set with BLOB_SIDECAR.
+const (
+	BlobSidecarFull = "full" // With the blobs, commitments and proofs.
+	BlobSidecarOmit = "omit" // Execution part only, with the blob hashes but no sidecar.
+)
+
+// ErrSidecarRequired is returned for a BLOB_SIDECAR=omit setup where the
+// protocol needs the sidecar.
+var ErrSidecarRequired = errors.New("blob transactions must carry their sidecar here")
+
+// ValidateBlobSidecarMode checks a BLOB_SIDECAR mode against the way
+// transactions are delivered. Payload bids carry transactions in their network
+// form, which EIP-4844 defines to include the sidecar, so only hash-only bids,
+// whose transactions go out as bundles, can omit it. A blob transaction's hash
+// doesn't cover the sidecar, so a hash-only bid is the same either way.
+//
+// Parameters:
+// - mode: One of the BlobSidecar modes.
+// - usePayload: Whether bids carry the transactions themselves.
+//
+// Returns:
+// - An error for an unknown mode, or one wrapping ErrSidecarRequired.
+func ValidateBlobSidecarMode(mode string, usePayload bool) error {
+	switch mode {
+	case BlobSidecarFull:
+		return nil
+	case BlobSidecarOmit:
+		if usePayload {
+			return fmt.Errorf("%w: payload bids send the network form, which includes the sidecar; set USE_PAYLOAD=false to omit it", ErrSidecarRequired)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown blob sidecar mode %q (must be %s or %s)", mode, BlobSidecarFull, BlobSidecarOmit)
+}
+
+// DeliveredTx returns tx as it is delivered under a BLOB_SIDECAR mode: without
+// its sidecar under BlobSidecarOmit, and unchanged otherwise.
+func DeliveredTx(mode string, tx *types.Transaction) *types.Transaction {
+	if mode == BlobSidecarOmit && tx.BlobTxSidecar() != nil {
+		return tx.WithoutBlobTxSidecar()
+	}
+	return tx
+}
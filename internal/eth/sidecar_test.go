@@ -0,0 +1,52 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBlobSidecarMode(t *testing.T) {
+	require.NoError(t, ValidateBlobSidecarMode(BlobSidecarFull, true))
+	require.NoError(t, ValidateBlobSidecarMode(BlobSidecarFull, false))
+	require.NoError(t, ValidateBlobSidecarMode(BlobSidecarOmit, false))
+	require.ErrorIs(t, ValidateBlobSidecarMode(BlobSidecarOmit, true), ErrSidecarRequired)
+	require.ErrorContains(t, ValidateBlobSidecarMode("none", false), `unknown blob sidecar mode "none"`)
+}
+
+func TestDeliveredTxOmitsSidecar(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sidecar := makeSidecar(randBlobs(1))
+	tx, err := types.SignNewTx(key, types.NewCancunSigner(big.NewInt(1)), &types.BlobTx{
+		ChainID:    uint256.NewInt(1),
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(1),
+		Gas:        21_000,
+		BlobFeeCap: uint256.NewInt(1),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	require.NoError(t, err)
+
+	require.Same(t, tx, DeliveredTx(BlobSidecarFull, tx))
+
+	// The execution part keeps the blob hashes and the transaction hash
+	stripped := DeliveredTx(BlobSidecarOmit, tx)
+	require.Nil(t, stripped.BlobTxSidecar())
+	require.Equal(t, tx.BlobHashes(), stripped.BlobHashes())
+	require.Equal(t, tx.Hash(), stripped.Hash())
+	full, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	bare, err := stripped.MarshalBinary()
+	require.NoError(t, err)
+	require.Less(t, len(bare), len(full))
+
+	// Transactions without blobs are delivered as they are
+	transfer := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	require.Same(t, transfer, DeliveredTx(BlobSidecarOmit, transfer))
+}
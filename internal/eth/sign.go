@@ -0,0 +1,33 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+)
+
+// signTx signs a transaction for the chain and records the signature in the key
+// audit. Every transaction this package signs goes through it.
+//
+// Parameters:
+// - tx: The unsigned transaction.
+// - chainID: The chain the transaction is for.
+// - key: The wallet key.
+// - feature: The feature signing, recorded in the audit.
+//
+// Returns:
+// - The signed transaction, or an error if signing failed or a required audit couldn't record it.
+func signTx(tx *types.Transaction, chainID *big.Int, key *ecdsa.PrivateKey, feature string) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	signed, err := types.SignTx(tx, signer, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := keyaudit.RecordSignature(crypto.PubkeyToAddress(key.PublicKey), signer.Hash(tx), feature); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
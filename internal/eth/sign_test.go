@@ -0,0 +1,60 @@
+package eth
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+// auditRecorder keeps the audit records in memory.
+type auditRecorder struct {
+	mu      sync.Mutex
+	records []keyaudit.Record
+}
+
+func (r *auditRecorder) Append(record keyaudit.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	return nil
+}
+
+func TestEverySigningPathRecordsOneAuditRecord(t *testing.T) {
+	recorder := &auditRecorder{}
+	keyaudit.Install(recorder, false)
+	defer keyaudit.Install(nil, false)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := bb.AuthAcct{PrivateKey: key, PublicKey: &key.PublicKey, Address: crypto.PubkeyToAddress(key.PublicKey)}
+	backend := simulated.NewBackend(types.GenesisAlloc{wallet.Address: {Balance: big.NewInt(params.Ether)}})
+	defer backend.Close()
+	client := backend.Client().(BlobTxClient)
+	signer := types.LatestSignerForChainID(params.AllDevChainProtocolChanges.ChainID)
+
+	transfer, _, err := ETHTransfer(client, wallet, common.HexToAddress("0xa1"), big.NewInt(1), 1, nil)
+	require.NoError(t, err)
+	chain, _, err := ExecuteBlobChain(client, wallet, 3, 1, 1, nil)
+	require.NoError(t, err)
+
+	signed := append([]*types.Transaction{transfer}, chain...)
+	features := []string{keyaudit.FeatureTransfer, keyaudit.FeatureBlob, keyaudit.FeatureBlob, keyaudit.FeatureBlob}
+	require.Len(t, recorder.records, len(signed), "one record per signature")
+	for i, tx := range signed {
+		record := recorder.records[i]
+		require.Equal(t, keyaudit.EventSign, record.Event)
+		require.Equal(t, wallet.Address, record.Address)
+		require.Equal(t, keyaudit.PayloadTx, record.PayloadType)
+		require.Equal(t, signer.Hash(tx), record.Digest)
+		require.Equal(t, features[i], record.Feature)
+	}
+}
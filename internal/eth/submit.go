@@ -0,0 +1,134 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+)
+
+// bundleConnectAttempts is the number of connections a bundle is tried on before
+// a connection error is returned.
+const bundleConnectAttempts = 3
+
+// BundleSender delivers a signed transaction to builders for a target block.
+// Transactions of hash-only bids reach builders only this way.
+type BundleSender interface {
+	SendBundle(tx *types.Transaction, blockNumber uint64) error
+}
+
+// RelaySender sends each transaction to an RPC endpoint with eth_sendBundle.
+type RelaySender struct {
+	RPCEndpoint string
+	UserAgent   string // Sent as the User-Agent header, Go's default if empty.
+
+	// Pool, if set, holds the connections to send over instead of a request per
+	// bundle to RPCEndpoint. A connection that fails is reconnected and the bundle
+	// sent again.
+	Pool *bb.ClientPool
+	// Logger writes the reconnections of pooled sends, slog.Default() if nil.
+	Logger *slog.Logger
+}
+
+// SendBundle sends the transaction over a pooled connection, or calls SendBundle
+// with the endpoint without a pool.
+func (s RelaySender) SendBundle(tx *types.Transaction, blockNumber uint64) error {
+	if s.Pool != nil {
+		logger := s.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		return sendPooledBundle(s.Pool, logger, tx, blockNumber)
+	}
+	_, err := sendBundle(s.RPCEndpoint, s.UserAgent, tx, blockNumber)
+	return err
+}
+
+// sendPooledBundle calls eth_sendBundle on a connection of the pool. On a
+// connection error the connection is redialed and the call made again, up to
+// bundleConnectAttempts times; any other error is returned as is.
+func sendPooledBundle(pool *bb.ClientPool, logger *slog.Logger, tx *types.Transaction, blockNumber uint64) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	bundle := map[string]interface{}{
+		"txs":         []string{hexutil.Encode(raw)},
+		"blockNumber": hexutil.EncodeUint64(blockNumber),
+	}
+
+	client, err := pool.Get()
+	for attempt := 1; ; attempt++ {
+		if err == nil {
+			err = callSendBundle(client, bundle)
+			if err == nil || !isConnectionError(err) {
+				return err
+			}
+		}
+		if attempt == bundleConnectAttempts {
+			return fmt.Errorf("bundle not sent after %d connection attempts: %w", attempt, err)
+		}
+		logger.Warn("Bundle submission failed to reach the RPC, reconnecting",
+			"error", err,
+			"txHash", tx.Hash().String(),
+			"attempt", attempt,
+		)
+		if client != nil {
+			client, err = pool.Reconnect(client)
+		} else {
+			client, err = pool.Get()
+		}
+	}
+}
+
+// callSendBundle makes one eth_sendBundle call.
+func callSendBundle(client *ethclient.Client, bundle map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	var result interface{}
+	return client.Client().CallContext(ctx, &result, "eth_sendBundle", bundle)
+}
+
+// isConnectionError reports whether err means the connection failed, rather than
+// the RPC answering with an error or the call timing out.
+func isConnectionError(err error) bool {
+	var rpcErr rpc.Error
+	var httpErr rpc.HTTPError
+	switch {
+	case errors.As(err, &rpcErr), errors.As(err, &httpErr):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, rpc.ErrClientQuit), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// DryRunSender logs each transaction with its raw encoding instead of sending it.
+type DryRunSender struct{}
+
+// SendBundle logs the transaction that would have been sent.
+func (DryRunSender) SendBundle(tx *types.Transaction, blockNumber uint64) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	slog.Info("Dry run, transaction not sent",
+		"txHash", tx.Hash().String(),
+		"rawTx", hexutil.Encode(raw),
+		"blockNumber", blockNumber,
+	)
+	return nil
+}
@@ -0,0 +1,156 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleSenders(t *testing.T) {
+	var requests []FlashbotsPayload
+	var userAgents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var payload FlashbotsPayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		requests = append(requests, payload)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x01"}}`))
+	}))
+	defer srv.Close()
+
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 7})
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	require.NoError(t, RelaySender{RPCEndpoint: srv.URL}.SendBundle(tx, 100))
+	require.Len(t, requests, 1)
+	require.Equal(t, "eth_sendBundle", requests[0].Method)
+	require.Equal(t, []interface{}{hexutil.Encode(raw)}, requests[0].Params[0]["txs"])
+	require.Equal(t, hexutil.EncodeUint64(100), requests[0].Params[0]["blockNumber"])
+
+	// The user agent identifies the bot to relays
+	require.NoError(t, RelaySender{RPCEndpoint: srv.URL, UserAgent: "preconf_bidder/1.2.3"}.SendBundle(tx, 100))
+	require.Len(t, requests, 2)
+	require.Equal(t, "preconf_bidder/1.2.3", userAgents[1])
+
+	// A dry run never reaches the endpoint
+	var sender BundleSender = DryRunSender{}
+	require.NoError(t, sender.SendBundle(tx, 100))
+	require.Len(t, requests, 2)
+}
+
+// bundleRelay is an RPC endpoint that answers health checks and records bundles.
+type bundleRelay struct {
+	*httptest.Server
+	mu      sync.Mutex
+	bundles []map[string]interface{}
+}
+
+// startBundleRelay starts a relay that answers eth_sendBundle with bundleErr, or
+// a bundle hash if it is empty.
+func startBundleRelay(t *testing.T, bundleErr string) *bundleRelay {
+	t.Helper()
+	relay := &bundleRelay{}
+	relay.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                   `json:"method"`
+			Params []map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method != "eth_sendBundle":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		case bundleErr != "":
+			relay.mu.Lock()
+			relay.bundles = append(relay.bundles, req.Params[0])
+			relay.mu.Unlock()
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"` + bundleErr + `"}}`))
+		default:
+			relay.mu.Lock()
+			relay.bundles = append(relay.bundles, req.Params[0])
+			relay.mu.Unlock()
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x01"}}`))
+		}
+	}))
+	t.Cleanup(relay.Close)
+	return relay
+}
+
+func (r *bundleRelay) received() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.bundles...)
+}
+
+func TestRelaySenderReconnects(t *testing.T) {
+	first := startBundleRelay(t, "")
+	second := startBundleRelay(t, "")
+
+	// The endpoint moves to the second relay once the first dies
+	var mu sync.Mutex
+	url := first.URL
+	dial := func(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return ethclient.DialContext(ctx, url)
+	}
+	// Health checks never run, so only the failed send can reconnect
+	pool := bb.NewClientPool([]string{"rpc"}, 1, time.Hour, time.Second, dial, nil)
+	defer pool.Close()
+	sender := RelaySender{Pool: pool}
+
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 7})
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, sender.SendBundle(tx, 100))
+	require.Len(t, first.received(), 1)
+
+	first.Close()
+	mu.Lock()
+	url = second.URL
+	mu.Unlock()
+
+	require.NoError(t, sender.SendBundle(tx, 101))
+	bundles := second.received()
+	require.Len(t, bundles, 1)
+	require.Equal(t, []interface{}{hexutil.Encode(raw)}, bundles[0]["txs"])
+	require.Equal(t, hexutil.EncodeUint64(101), bundles[0]["blockNumber"])
+	healthy, _ := pool.Healthy()
+	require.Equal(t, 1, healthy)
+}
+
+func TestRelaySenderRPCErrorNotRetried(t *testing.T) {
+	relay := startBundleRelay(t, "bundle rejected")
+	pool := bb.NewClientPool([]string{relay.URL}, 1, time.Hour, time.Second, nil, nil)
+	defer pool.Close()
+
+	err := RelaySender{Pool: pool}.SendBundle(types.NewTx(&types.DynamicFeeTx{}), 100)
+	require.ErrorContains(t, err, "bundle rejected")
+	require.Len(t, relay.received(), 1)
+}
+
+func TestRelaySenderGivesUp(t *testing.T) {
+	relay := startBundleRelay(t, "")
+	pool := bb.NewClientPool([]string{relay.URL}, 1, time.Hour, time.Second, nil, nil)
+	defer pool.Close()
+	relay.Close()
+
+	err := RelaySender{Pool: pool}.SendBundle(types.NewTx(&types.DynamicFeeTx{}), 100)
+	require.ErrorContains(t, err, "bundle not sent after 3 connection attempts")
+	_, err = pool.Get()
+	require.ErrorIs(t, err, bb.ErrNoHealthyClient)
+}
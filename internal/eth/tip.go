@@ -0,0 +1,38 @@
+package eth
+
+import (
+	"math"
+	"math/big"
+)
+
+// TipPolicy chooses a transaction's priority fee per gas (in wei) from the
+// current base fee.
+type TipPolicy func(baseFee *big.Int) *big.Int
+
+// FixedTip returns a TipPolicy that always tips tipWei.
+func FixedTip(tipWei *big.Int) TipPolicy {
+	return func(*big.Int) *big.Int {
+		return new(big.Int).Set(tipWei)
+	}
+}
+
+// BaseFeePercentTip returns a TipPolicy that tips a percentage of the base fee,
+// never less than floorWei. The percentage is applied with a resolution of 0.01%.
+//
+// Parameters:
+// - pct: The tip as a percentage of the base fee, e.g. 10 for 10%.
+// - floorWei: The minimum tip in wei.
+//
+// Returns:
+// - The TipPolicy.
+func BaseFeePercentTip(pct float64, floorWei *big.Int) TipPolicy {
+	basisPoints := big.NewInt(int64(math.Round(pct * 100)))
+	return func(baseFee *big.Int) *big.Int {
+		tip := new(big.Int).Mul(baseFee, basisPoints)
+		tip.Div(tip, big.NewInt(10_000))
+		if tip.Cmp(floorWei) < 0 {
+			tip.Set(floorWei)
+		}
+		return tip
+	}
+}
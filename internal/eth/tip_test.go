@@ -0,0 +1,27 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseFeePercentTip(t *testing.T) {
+	floor := big.NewInt(100_000_000) // 0.1 gwei
+	tip := BaseFeePercentTip(12.5, floor)
+
+	// 12.5% of a 20 gwei base fee
+	require.Equal(t, big.NewInt(2_500_000_000), tip(big.NewInt(20_000_000_000)))
+
+	// 12.5% of 0.4 gwei is below the floor
+	require.Equal(t, floor, tip(big.NewInt(400_000_000)))
+
+	// The floor isn't aliased by the returned tip
+	tip(big.NewInt(1)).SetInt64(0)
+	require.Equal(t, big.NewInt(100_000_000), floor)
+}
+
+func TestFixedTip(t *testing.T) {
+	require.Equal(t, big.NewInt(7), FixedTip(big.NewInt(7))(big.NewInt(1_000_000)))
+}
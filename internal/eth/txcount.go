@@ -0,0 +1,121 @@
+package eth
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// saturationPercentile is the percentile of recent rolling averages above which
+// the market is considered saturated.
+const saturationPercentile = 0.9
+
+// TxCountFetcher returns the number of transactions in a block.
+type TxCountFetcher interface {
+	TxCountByNumber(ctx context.Context, blockNumber uint64) (uint64, error)
+}
+
+// ClientTxCountFetcher fetches block transaction counts from an Ethereum node.
+type ClientTxCountFetcher struct {
+	Client ClientFunc // Resolved on each fetch, so reconnections are followed.
+}
+
+// TxCountByNumber calls eth_getBlockTransactionCountByNumber for the given block.
+func (f ClientTxCountFetcher) TxCountByNumber(ctx context.Context, blockNumber uint64) (uint64, error) {
+	var count hexutil.Uint64
+	err := f.Client().Client().CallContext(ctx, &count, "eth_getBlockTransactionCountByNumber", hexutil.EncodeUint64(blockNumber))
+	return uint64(count), err
+}
+
+// TxCountTracker tracks a rolling average of transactions per block to detect
+// block saturation trends.
+type TxCountTracker struct {
+	fetcher TxCountFetcher
+	window  int
+
+	mu       sync.Mutex
+	counts   []uint64  // Transaction counts of the last `window` blocks.
+	averages []float64 // History of rolling averages used for the percentile.
+}
+
+// NewTxCountTracker creates a TxCountTracker.
+//
+// Parameters:
+// - fetcher: The source of per-block transaction counts.
+// - window: The number of blocks in the rolling average.
+//
+// Returns:
+// - A pointer to a TxCountTracker.
+func NewTxCountTracker(fetcher TxCountFetcher, window int) *TxCountTracker {
+	if window < 1 {
+		window = 1
+	}
+	return &TxCountTracker{
+		fetcher: fetcher,
+		window:  window,
+	}
+}
+
+// Observe fetches the transaction count of a confirmed block and adds it to the rolling window.
+func (t *TxCountTracker) Observe(ctx context.Context, blockNumber uint64) error {
+	count, err := t.fetcher.TxCountByNumber(ctx, blockNumber)
+	if err != nil {
+		return err
+	}
+	t.Add(count)
+	return nil
+}
+
+// Add records the transaction count of a block.
+func (t *TxCountTracker) Add(count uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts = append(t.counts, count)
+	if len(t.counts) > t.window {
+		t.counts = t.counts[1:]
+	}
+
+	// Keep a history of averages spanning several windows
+	t.averages = append(t.averages, t.average())
+	if len(t.averages) > t.window*10 {
+		t.averages = t.averages[1:]
+	}
+}
+
+// Average returns the rolling average of transactions per block.
+func (t *TxCountTracker) Average() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.average()
+}
+
+// Saturated reports whether the current rolling average is in the top 90th
+// percentile of recent history. It returns false until a full window is observed.
+func (t *TxCountTracker) Saturated() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.averages) < t.window {
+		return false
+	}
+
+	sorted := append([]float64(nil), t.averages...)
+	sort.Float64s(sorted)
+	threshold := sorted[int(float64(len(sorted)-1)*saturationPercentile)]
+	return t.average() >= threshold
+}
+
+// average computes the rolling average; the caller must hold the lock.
+func (t *TxCountTracker) average() float64 {
+	if len(t.counts) == 0 {
+		return 0
+	}
+	var sum uint64
+	for _, c := range t.counts {
+		sum += c
+	}
+	return float64(sum) / float64(len(t.counts))
+}
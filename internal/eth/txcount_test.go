@@ -0,0 +1,47 @@
+package eth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTxCountFetcher returns the block number as the transaction count.
+type fakeTxCountFetcher struct{}
+
+func (fakeTxCountFetcher) TxCountByNumber(ctx context.Context, blockNumber uint64) (uint64, error) {
+	return blockNumber, nil
+}
+
+func TestTxCountTrackerRollingAverage(t *testing.T) {
+	tracker := NewTxCountTracker(fakeTxCountFetcher{}, 3)
+
+	for _, n := range []uint64{10, 20, 30, 40} {
+		require.NoError(t, tracker.Observe(context.Background(), n))
+	}
+
+	// Only the last three blocks are in the window
+	require.InDelta(t, 30.0, tracker.Average(), 1e-9)
+}
+
+func TestTxCountTrackerSaturated(t *testing.T) {
+	tracker := NewTxCountTracker(fakeTxCountFetcher{}, 2)
+
+	// Not enough history yet
+	tracker.Add(100)
+	require.False(t, tracker.Saturated())
+
+	for i := 0; i < 10; i++ {
+		tracker.Add(100)
+	}
+	require.True(t, tracker.Saturated(), "a flat history sits at its own 90th percentile")
+
+	tracker.Add(10)
+	tracker.Add(10)
+	require.False(t, tracker.Saturated(), "quiet blocks are below the 90th percentile")
+
+	tracker.Add(500)
+	tracker.Add(500)
+	require.True(t, tracker.Saturated(), "busy blocks are above the 90th percentile")
+}
@@ -0,0 +1,221 @@
+// Package fakebidder is an in-process stand-in for the mev-commit bidder node's
+// gRPC API. It answers bids with synthetic commitments, for demos without a real
+// node and for tests of the gRPC client.
+package fakebidder
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Config controls how the fake bidder answers bids.
+type Config struct {
+	Latency           time.Duration // Delay before each commitment is sent.
+	CommitProbability float64       // Probability in [0, 1] that a bid gets a commitment.
+	Providers         int           // Number of providers that may commit to each bid, 1 if 0.
+	Seed              int64         // Seed for the commitment draws.
+	RejectCode        codes.Code    // If not OK, every bid is refused with this gRPC status.
+
+	// OnBid, if set, is called with each received bid before it is answered.
+	OnBid func(*pb.Bid)
+}
+
+// Server is a fake bidder node gRPC server.
+type Server struct {
+	pb.UnimplementedBidderServer
+
+	cfg      Config
+	grpc     *grpc.Server
+	listener net.Listener
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	deposits map[uint64]*big.Int // Window -> deposited wei.
+
+	bids        atomic.Uint64
+	commitments atomic.Uint64
+}
+
+// Start serves a fake bidder on addr, e.g. "127.0.0.1:0" for any free port.
+//
+// Parameters:
+// - addr: The TCP address to listen on.
+// - cfg: How bids are answered.
+//
+// Returns:
+// - A pointer to the running Server, or an error if addr cannot be listened on.
+func Start(addr string, cfg Config) (*Server, error) {
+	if cfg.Providers <= 0 {
+		cfg.Providers = 1
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		grpc:     grpc.NewServer(),
+		listener: lis,
+		rng:      rand.New(rand.NewSource(cfg.Seed)),
+		deposits: make(map[uint64]*big.Int),
+	}
+	pb.RegisterBidderServer(s.grpc, s)
+	go s.grpc.Serve(lis)
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Bids returns the number of bids received.
+func (s *Server) Bids() uint64 {
+	return s.bids.Load()
+}
+
+// Commitments returns the number of commitments sent.
+func (s *Server) Commitments() uint64 {
+	return s.commitments.Load()
+}
+
+// Close stops the server, cutting off open bid streams.
+func (s *Server) Close() error {
+	s.grpc.Stop()
+	return nil
+}
+
+// SendBid answers a bid with a synthetic commitment from each provider that commits.
+func (s *Server) SendBid(bid *pb.Bid, stream pb.Bidder_SendBidServer) error {
+	s.bids.Add(1)
+	if s.cfg.RejectCode != codes.OK {
+		return status.Error(s.cfg.RejectCode, "bid rejected by fake bidder node")
+	}
+	if s.cfg.OnBid != nil {
+		s.cfg.OnBid(bid)
+	}
+
+	for provider := 1; provider <= s.cfg.Providers; provider++ {
+		if !s.commits() {
+			continue
+		}
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-time.After(s.cfg.Latency):
+		}
+		if err := stream.Send(commitmentFor(bid, provider)); err != nil {
+			return err
+		}
+		s.commitments.Add(1)
+	}
+	return nil
+}
+
+// AutoDepositStatus reports auto deposit as enabled with a single current window.
+func (s *Server) AutoDepositStatus(context.Context, *pb.EmptyMessage) (*pb.AutoDepositStatusResponse, error) {
+	return &pb.AutoDepositStatusResponse{
+		WindowBalances: []*pb.AutoDeposit{{
+			DepositedAmount: "1000000000000000000",
+			WindowNumber:    wrapperspb.UInt64(1),
+			IsCurrent:       true,
+		}},
+		IsAutodepositEnabled: true,
+	}, nil
+}
+
+// GetDeposit reports the amount deposited in a window through Deposit.
+func (s *Server) GetDeposit(_ context.Context, req *pb.GetDepositRequest) (*pb.DepositResponse, error) {
+	window := req.GetWindowNumber().GetValue()
+	return &pb.DepositResponse{Amount: s.DepositOf(window).String(), WindowNumber: wrapperspb.UInt64(window)}, nil
+}
+
+// Deposit adds to the deposit of the requested window, or of the window of the
+// requested block with 10 blocks per window.
+func (s *Server) Deposit(_ context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	amount, ok := new(big.Int).SetString(req.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid deposit amount %q", req.Amount)
+	}
+	window := req.GetWindowNumber().GetValue()
+	if req.WindowNumber == nil && req.BlockNumber != nil {
+		window = (req.BlockNumber.Value-1)/10 + 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deposits[window] == nil {
+		s.deposits[window] = new(big.Int)
+	}
+	s.deposits[window].Add(s.deposits[window], amount)
+	return &pb.DepositResponse{Amount: amount.String(), WindowNumber: wrapperspb.UInt64(window)}, nil
+}
+
+// DepositOf returns the amount deposited in a window.
+func (s *Server) DepositOf(window uint64) *big.Int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if deposit, ok := s.deposits[window]; ok {
+		return new(big.Int).Set(deposit)
+	}
+	return new(big.Int)
+}
+
+// commits draws whether a provider commits to a bid.
+func (s *Server) commits() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < s.cfg.CommitProbability
+}
+
+// commitmentFor builds a plausible commitment to a bid from the numbered provider.
+func commitmentFor(bid *pb.Bid, provider int) *pb.Commitment {
+	txHashes := bid.TxHashes
+	if len(txHashes) == 0 {
+		for _, raw := range bid.RawTransactions {
+			data, err := hex.DecodeString(raw)
+			if err != nil {
+				continue
+			}
+			// Blob transactions are sent with their sidecar, which the hash excludes
+			var tx types.Transaction
+			if err := tx.UnmarshalBinary(data); err != nil {
+				continue
+			}
+			txHashes = append(txHashes, strings.TrimPrefix(tx.Hash().Hex(), "0x"))
+		}
+	}
+
+	bidDigest := crypto.Keccak256([]byte(fmt.Sprintf("%v|%s|%d|%d|%d", txHashes, bid.Amount, bid.BlockNumber, bid.DecayStartTimestamp, bid.DecayEndTimestamp)))
+	commitmentDigest := crypto.Keccak256(bidDigest, []byte{byte(provider)})
+	return &pb.Commitment{
+		TxHashes:             txHashes,
+		BidAmount:            bid.Amount,
+		BlockNumber:          bid.BlockNumber,
+		ReceivedBidDigest:    hex.EncodeToString(bidDigest),
+		ReceivedBidSignature: hex.EncodeToString(crypto.Keccak256(bidDigest, []byte("bidder"))),
+		CommitmentDigest:     hex.EncodeToString(commitmentDigest),
+		CommitmentSignature:  hex.EncodeToString(crypto.Keccak256(commitmentDigest, []byte("provider"))),
+		ProviderAddress:      fmt.Sprintf("0x%040x", provider),
+		DecayStartTimestamp:  bid.DecayStartTimestamp,
+		DecayEndTimestamp:    bid.DecayEndTimestamp,
+		DispatchTimestamp:    time.Now().UnixMilli(),
+		RevertingTxHashes:    bid.RevertingTxHashes,
+	}
+}
@@ -0,0 +1,107 @@
+package fakebidder
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func dial(t *testing.T, s *Server) pb.BidderClient {
+	t.Helper()
+	conn, err := grpc.NewClient(s.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewBidderClient(conn)
+}
+
+func receiveAll(t *testing.T, stream pb.Bidder_SendBidClient) []*pb.Commitment {
+	t.Helper()
+	var commitments []*pb.Commitment
+	for {
+		c, err := stream.Recv()
+		if err == io.EOF {
+			return commitments
+		}
+		require.NoError(t, err)
+		commitments = append(commitments, c)
+	}
+}
+
+func TestServerCommitsToBids(t *testing.T) {
+	var received []*pb.Bid
+	s, err := Start("127.0.0.1:0", Config{
+		Latency:           5 * time.Millisecond,
+		CommitProbability: 1,
+		Providers:         2,
+		OnBid:             func(b *pb.Bid) { received = append(received, b) },
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := dial(t, s).SendBid(ctx, &pb.Bid{
+		TxHashes:            []string{"ab"},
+		Amount:              "1000",
+		BlockNumber:         42,
+		DecayStartTimestamp: 1,
+		DecayEndTimestamp:   2,
+	})
+	require.NoError(t, err)
+
+	commitments := receiveAll(t, stream)
+	require.Len(t, commitments, 2)
+	require.NotEqual(t, commitments[0].ProviderAddress, commitments[1].ProviderAddress)
+	require.Equal(t, int64(42), commitments[0].BlockNumber)
+	require.Equal(t, []string{"ab"}, commitments[0].TxHashes)
+	require.Len(t, received, 1)
+	require.Equal(t, uint64(1), s.Bids())
+	require.Equal(t, uint64(2), s.Commitments())
+}
+
+func TestServerDeclinesBids(t *testing.T) {
+	s, err := Start("127.0.0.1:0", Config{CommitProbability: 0})
+	require.NoError(t, err)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := dial(t, s).SendBid(ctx, &pb.Bid{RawTransactions: []string{"02"}, Amount: "1"})
+	require.NoError(t, err)
+	require.Empty(t, receiveAll(t, stream))
+	require.Equal(t, uint64(0), s.Commitments())
+}
+
+func TestServerCommitsToRawTransactionsByHash(t *testing.T) {
+	s, err := Start("127.0.0.1:0", Config{CommitProbability: 1})
+	require.NoError(t, err)
+	defer s.Close()
+
+	// A blob transaction is sent with its sidecar, which its hash doesn't cover
+	tx := types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{{1}}}).WithBlobTxSidecar(&types.BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{{}},
+		Commitments: []kzg4844.Commitment{{}},
+		Proofs:      []kzg4844.Proof{{}},
+	})
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := dial(t, s).SendBid(ctx, &pb.Bid{RawTransactions: []string{hex.EncodeToString(raw)}, Amount: "1"})
+	require.NoError(t, err)
+	commitments := receiveAll(t, stream)
+	require.Len(t, commitments, 1)
+	require.Equal(t, []string{strings.TrimPrefix(tx.Hash().Hex(), "0x")}, commitments[0].TxHashes)
+}
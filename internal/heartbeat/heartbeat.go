@@ -0,0 +1,54 @@
+// Package heartbeat periodically logs that the bidder is alive, so quiet
+// periods without bids can be told apart from a stalled process.
+package heartbeat
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Status is the bidder state reported by each heartbeat.
+type Status struct {
+	Block     uint64  // Latest block header received.
+	Connected bool    // Whether the header subscription is connected.
+	Bids      uint64  // Cumulative bids sent.
+	SpendEth  float64 // Cumulative bid amount in ETH.
+	FeesEth   float64 // Cumulative gas and blob fees paid by included transactions in ETH.
+}
+
+// Run logs an "alive" line every interval until ctx is done. An interval of 0
+// disables the heartbeat and Run returns immediately.
+//
+// Parameters:
+// - ctx: Stops the heartbeat when done.
+// - logger: The logger to write heartbeats to.
+// - interval: The time between heartbeats.
+// - status: Returns the current bidder status.
+func Run(ctx context.Context, logger *slog.Logger, interval time.Duration, status func() Status) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastBids uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := status()
+			logger.Info("alive",
+				"blockNumber", s.Block,
+				"connected", s.Connected,
+				"bidsThisInterval", s.Bids-lastBids,
+				"totalBids", s.Bids,
+				"totalSpendEth", s.SpendEth,
+				"totalFeesEth", s.FeesEth,
+			)
+			lastBids = s.Bids
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package heartbeat
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler collects the attributes of each logged record.
+type recordingHandler struct {
+	mu      sync.Mutex
+	times   []time.Time
+	records []map[string]any
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler            { return h }
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := map[string]any{"msg": r.Message}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.times = append(h.times, time.Now())
+	h.records = append(h.records, attrs)
+	return nil
+}
+
+func (h *recordingHandler) snapshot() ([]time.Time, []map[string]any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]time.Time(nil), h.times...), append([]map[string]any(nil), h.records...)
+}
+
+func TestHeartbeatInterval(t *testing.T) {
+	handler := &recordingHandler{}
+	var bids atomic.Uint64
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	const interval = 20 * time.Millisecond
+	start := time.Now()
+	go func() {
+		defer close(done)
+		Run(ctx, slog.New(handler), interval, func() Status {
+			return Status{Block: 100, Connected: true, Bids: bids.Add(2), SpendEth: 0.5}
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		times, _ := handler.snapshot()
+		return len(times) >= 3
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	times, records := handler.snapshot()
+	for i, at := range times {
+		// The n-th heartbeat is emitted no earlier than n intervals after start
+		require.GreaterOrEqual(t, at.Sub(start), time.Duration(i+1)*interval)
+	}
+	require.Equal(t, "alive", records[0]["msg"])
+	require.Equal(t, uint64(100), records[0]["blockNumber"])
+	require.Equal(t, true, records[0]["connected"])
+	require.Equal(t, uint64(2), records[1]["bidsThisInterval"])
+	require.Equal(t, uint64(4), records[1]["totalBids"])
+}
+
+func TestHeartbeatDisabled(t *testing.T) {
+	handler := &recordingHandler{}
+	returned := make(chan struct{})
+	go func() {
+		Run(context.Background(), slog.New(handler), 0, func() Status { return Status{} })
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("Run with a zero interval did not return")
+	}
+	_, records := handler.snapshot()
+	require.Empty(t, records)
+}
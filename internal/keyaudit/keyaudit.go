@@ -0,0 +1,258 @@
+// Package keyaudit keeps an append-only trail of every signature made with the
+// process's keys, without any payload contents, and summarizes it.
+package keyaudit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Audit modes.
+const (
+	ModeOff      = "off"      // No audit trail.
+	ModeOn       = "on"       // Signatures are recorded; a failed write is logged.
+	ModeRequired = "required" // A signature that can't be recorded is refused.
+)
+
+// Events of the audit trail.
+const (
+	EventSign   = "sign"   // A key signed a payload.
+	EventPause  = "pause"  // Signing with a key, or all keys, is expected to stop.
+	EventResume = "resume" // The matching pause ended.
+)
+
+// PayloadTx is the payload type of a signed transaction, the only kind of payload
+// this process signs.
+const PayloadTx = "tx"
+
+// Features that sign.
+const (
+	FeatureTransfer = "transfer"
+	FeatureBlob     = "blob"
+	FeatureDeposit  = "deposit"
+	FeatureWithdraw = "withdraw"
+	FeatureBackrun  = "backrun"
+	FeatureERC20    = "erc20"
+)
+
+// Record is one line of the audit trail. Pauses without an address apply to all keys.
+type Record struct {
+	Time        int64          `json:"time"` // Unix milliseconds.
+	Event       string         `json:"event"`
+	Address     common.Address `json:"address,omitempty"`
+	PayloadType string         `json:"payload_type,omitempty"`
+	Digest      common.Hash    `json:"digest,omitempty"` // The hash that was signed.
+	Feature     string         `json:"feature,omitempty"`
+	Reason      string         `json:"reason,omitempty"` // Why signing paused or resumed.
+}
+
+// Sink stores audit records. *Log implements it.
+type Sink interface {
+	Append(record Record) error
+}
+
+// Log appends audit records as JSON lines to a file opened in append-only mode.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Open opens, or creates, the audit log at path for appending.
+func Open(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key audit log: %w", err)
+	}
+	return &Log{file: file}, nil
+}
+
+// Append writes a record as one line, in a single write.
+func (l *Log) Append(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// hook is the process-wide sink signatures are recorded to.
+var hook struct {
+	mu       sync.RWMutex
+	sink     Sink
+	required bool
+}
+
+// ErrNotRecorded is returned, wrapped, for a signature that must not be used
+// because the audit is required and it couldn't be recorded.
+var ErrNotRecorded = errors.New("signature not recorded in the required key audit")
+
+// Install sets the sink signatures are recorded to. Once an audit is required it
+// stays required for the life of the process, so a later Install can't turn it off.
+func Install(sink Sink, required bool) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.sink = sink
+	hook.required = hook.required || required
+}
+
+// RecordSignature records that a key signed a transaction digest for a feature.
+//
+// Returns:
+// - An error wrapping ErrNotRecorded if the audit is required and the record
+// couldn't be written; the signature must then be discarded.
+func RecordSignature(address common.Address, digest common.Hash, feature string) error {
+	hook.mu.RLock()
+	sink, required := hook.sink, hook.required
+	hook.mu.RUnlock()
+
+	if sink == nil {
+		if required {
+			return fmt.Errorf("%w: no audit log installed", ErrNotRecorded)
+		}
+		return nil
+	}
+	err := sink.Append(Record{
+		Time:        time.Now().UnixMilli(),
+		Event:       EventSign,
+		Address:     address,
+		PayloadType: PayloadTx,
+		Digest:      digest,
+		Feature:     feature,
+	})
+	if err != nil {
+		if required {
+			return fmt.Errorf("%w: %v", ErrNotRecorded, err)
+		}
+		slog.Warn("Failed to record signature in key audit", "address", address.Hex(), "feature", feature, "error", err)
+	}
+	return nil
+}
+
+// RecordPause records that signing with the key, or with all keys for the zero
+// address, is paused or resumed. Pauses are informational, so errors are returned
+// but never refuse anything.
+func RecordPause(address common.Address, paused bool, reason string) error {
+	hook.mu.RLock()
+	sink := hook.sink
+	hook.mu.RUnlock()
+	if sink == nil {
+		return nil
+	}
+	event := EventResume
+	if paused {
+		event = EventPause
+	}
+	return sink.Append(Record{Time: time.Now().UnixMilli(), Event: event, Address: address, Reason: reason})
+}
+
+// AuditedSigner wraps the signer of bound-contract transaction options so each
+// transaction it signs is recorded for the feature.
+func AuditedSigner(signer bind.SignerFn, feature string) bind.SignerFn {
+	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		signed, err := signer(address, tx)
+		if err != nil {
+			return nil, err
+		}
+		digest := types.LatestSignerForChainID(signed.ChainId()).Hash(signed)
+		if err := RecordSignature(address, digest, feature); err != nil {
+			return nil, err
+		}
+		return signed, nil
+	}
+}
+
+// DaySummary counts the signatures of one UTC day.
+type DaySummary struct {
+	Day        string         // YYYY-MM-DD.
+	Signatures int            // All signatures of the day.
+	ByFeature  map[string]int // Signatures per feature.
+}
+
+// Anomaly is a signature made while its key was paused.
+type Anomaly struct {
+	Line   int
+	Record Record
+	Reason string // The reason of the pause it fell in.
+}
+
+// Summary is the result of reading an audit trail.
+type Summary struct {
+	Days      []DaySummary // In date order.
+	Anomalies []Anomaly
+}
+
+// Summarize reads an audit trail in order, counting signatures per day and
+// flagging those made by a key during one of its pauses, or a pause of all keys.
+func Summarize(r io.Reader) (Summary, error) {
+	days := make(map[string]*DaySummary)
+	paused := make(map[common.Address]string) // Address, or the zero address for all keys -> pause reason.
+
+	scanner := bufio.NewScanner(r)
+	var summary Summary
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return Summary{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		switch record.Event {
+		case EventPause:
+			paused[record.Address] = record.Reason
+		case EventResume:
+			delete(paused, record.Address)
+		case EventSign:
+			day := time.UnixMilli(record.Time).UTC().Format(time.DateOnly)
+			d, ok := days[day]
+			if !ok {
+				d = &DaySummary{Day: day, ByFeature: make(map[string]int)}
+				days[day] = d
+			}
+			d.Signatures++
+			d.ByFeature[record.Feature]++
+
+			reason, ok := paused[record.Address]
+			if !ok {
+				reason, ok = paused[common.Address{}]
+			}
+			if ok {
+				summary.Anomalies = append(summary.Anomalies, Anomaly{Line: line, Record: record, Reason: reason})
+			}
+		default:
+			return Summary{}, fmt.Errorf("line %d: unknown event %q", line, record.Event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	for _, d := range days {
+		summary.Days = append(summary.Days, *d)
+	}
+	sort.Slice(summary.Days, func(i, j int) bool { return summary.Days[i].Day < summary.Days[j].Day })
+	return summary, nil
+}
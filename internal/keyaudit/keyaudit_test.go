@@ -0,0 +1,121 @@
+package keyaudit
+
+import (
+	"errors"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type failingSink struct{}
+
+func (failingSink) Append(Record) error { return errors.New("disk full") }
+
+func TestLogAppendsAndSummarizes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path)
+	require.NoError(t, err)
+
+	alice := common.HexToAddress("0xa1")
+	bob := common.HexToAddress("0xb2")
+	day1 := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC).UnixMilli()
+	day2 := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC).UnixMilli()
+	for _, r := range []Record{
+		{Time: day1, Event: EventSign, Address: alice, PayloadType: PayloadTx, Feature: FeatureBlob},
+		{Time: day1, Event: EventPause, Address: alice, Reason: "nonce divergence"},
+		{Time: day1, Event: EventSign, Address: alice, PayloadType: PayloadTx, Feature: FeatureBlob},
+		{Time: day1, Event: EventSign, Address: bob, PayloadType: PayloadTx, Feature: FeatureTransfer},
+		{Time: day2, Event: EventResume, Address: alice},
+		{Time: day2, Event: EventPause, Reason: "drain"},
+		{Time: day2, Event: EventSign, Address: bob, PayloadType: PayloadTx, Feature: FeatureTransfer},
+	} {
+		require.NoError(t, log.Append(r))
+	}
+	require.NoError(t, log.Close())
+
+	// Reopening appends rather than truncating
+	log, err = Open(path)
+	require.NoError(t, err)
+	require.NoError(t, log.Append(Record{Time: day2, Event: EventResume, Reason: "drain"}))
+	require.NoError(t, log.Append(Record{Time: day2, Event: EventSign, Address: alice, PayloadType: PayloadTx, Feature: FeatureBlob}))
+	require.NoError(t, log.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	summary, err := Summarize(file)
+	require.NoError(t, err)
+
+	require.Equal(t, []DaySummary{
+		{Day: "2026-03-01", Signatures: 3, ByFeature: map[string]int{FeatureBlob: 2, FeatureTransfer: 1}},
+		{Day: "2026-03-02", Signatures: 2, ByFeature: map[string]int{FeatureBlob: 1, FeatureTransfer: 1}},
+	}, summary.Days)
+
+	require.Len(t, summary.Anomalies, 2)
+	require.Equal(t, 3, summary.Anomalies[0].Line)
+	require.Equal(t, "nonce divergence", summary.Anomalies[0].Reason)
+	require.Equal(t, 7, summary.Anomalies[1].Line)
+	require.Equal(t, "drain", summary.Anomalies[1].Reason)
+}
+
+func TestRequiredAuditRefusesUnrecordedSignatures(t *testing.T) {
+	defer func() {
+		hook.mu.Lock()
+		hook.sink, hook.required = nil, false
+		hook.mu.Unlock()
+	}()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1))
+	require.NoError(t, err)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1})
+
+	// Without a required audit, failed writes don't stop signing
+	Install(failingSink{}, false)
+	_, err = AuditedSigner(opts.Signer, FeatureDeposit)(opts.From, tx)
+	require.NoError(t, err)
+
+	Install(failingSink{}, true)
+	_, err = AuditedSigner(opts.Signer, FeatureDeposit)(opts.From, tx)
+	require.ErrorIs(t, err, ErrNotRecorded)
+
+	// Once required, the audit can't be turned off
+	Install(nil, false)
+	require.ErrorIs(t, RecordSignature(opts.From, common.Hash{}, FeatureDeposit), ErrNotRecorded)
+}
+
+// rawSigning matches signing calls that bypass the key audit.
+var rawSigning = regexp.MustCompile(`types\.SignTx\(|types\.SignNewTx\(|crypto\.Sign\(|\.Signer\(|Transact\(authAcct\.Auth`)
+
+func TestEverySigningPathGoesThroughTheAudit(t *testing.T) {
+	// Only signTx may sign directly, recording the signature itself
+	allowed := map[string]bool{"internal/eth/sign.go": true}
+	root := filepath.Join("..", "..")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") || allowed[filepath.ToSlash(rel)] {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		require.Falsef(t, rawSigning.Match(src), "%s signs without recording the signature in the key audit", rel)
+		return nil
+	})
+	require.NoError(t, err)
+}
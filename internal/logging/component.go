@@ -0,0 +1,24 @@
+package logging
+
+import "log/slog"
+
+// Subsystems named by the component attribute, so log queries can filter by them.
+const (
+	ComponentBidder = "bidder" // Bids sent to the mev-commit bidder nodes.
+	ComponentEth    = "eth"    // Calls to the execution RPC, including bundle submission.
+	ComponentWS     = "ws"     // The WebSocket header subscription.
+)
+
+// WithComponent returns a logger that marks every entry with the subsystem that
+// wrote it. The attribute stays at the top level of the entry, unlike a group,
+// so the subsystem's other attributes keep their names.
+//
+// Parameters:
+// - logger: The logger to derive from, slog.Default() if nil.
+// - component: One of the Component constants.
+func WithComponent(logger *slog.Logger, component string) *slog.Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger.With(slog.String("component", component))
+}
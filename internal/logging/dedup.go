@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DedupHandler collapses floods of identical failures, such as the same error
+// logged every block while the node or bidder is down. A warning or error
+// carrying an "error" or "err" attribute is passed on the first time its level,
+// message and error text are seen. Repeats within the window are counted
+// instead, and when the window ends one summary record with the latest repeat's
+// attributes reports "seen N times in the last M seconds". Records without an
+// error attribute always pass.
+type DedupHandler struct {
+	next  slog.Handler
+	state *dedupState // Shared with the handlers derived by WithAttrs and WithGroup.
+}
+
+type dedupState struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	recent map[string]*dedupEntry // By dedupKey.
+}
+
+// dedupEntry tracks one failure during its window.
+type dedupEntry struct {
+	start   time.Time
+	timer   *time.Timer
+	repeats int          // Records suppressed since the first one.
+	last    slog.Record  // The latest suppressed record.
+	next    slog.Handler // The handler the latest record was logged through.
+}
+
+// NewDedupHandler creates a DedupHandler.
+//
+// Parameters:
+// - next: The handler that writes the records.
+// - window: How long repeats of a failure are collapsed after it is first logged.
+//
+// Returns:
+// - A pointer to a DedupHandler. It must be closed to write the pending summaries.
+func NewDedupHandler(next slog.Handler, window time.Duration) *DedupHandler {
+	return &DedupHandler{next: next, state: &dedupState{window: window, recent: make(map[string]*dedupEntry)}}
+}
+
+// Enabled reports whether the wrapped handler handles the level.
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record on unless it repeats a failure logged within the window.
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	key, ok := dedupKey(r)
+	if !ok {
+		return h.next.Handle(ctx, r)
+	}
+
+	s := h.state
+	s.mu.Lock()
+	if entry, seen := s.recent[key]; seen {
+		entry.repeats++
+		entry.last = r.Clone()
+		entry.next = h.next
+		s.mu.Unlock()
+		return nil
+	}
+	entry := &dedupEntry{start: time.Now()}
+	entry.timer = time.AfterFunc(s.window, func() { s.expire(key, entry) })
+	s.recent[key] = entry
+	s.mu.Unlock()
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a DedupHandler wrapping next.WithAttrs(attrs) that shares
+// the counts of h.
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DedupHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup returns a DedupHandler wrapping next.WithGroup(name) that shares
+// the counts of h.
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	return &DedupHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Close writes the summaries of the windows still open, so that no repeats are
+// lost on shutdown.
+func (h *DedupHandler) Close() error {
+	s := h.state
+	s.mu.Lock()
+	entries := s.recent
+	s.recent = make(map[string]*dedupEntry)
+	s.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.timer.Stop()
+		writeSummary(entry)
+	}
+	return nil
+}
+
+// expire ends the window of entry and writes its summary.
+func (s *dedupState) expire(key string, entry *dedupEntry) {
+	s.mu.Lock()
+	if s.recent[key] != entry {
+		// Already written by Close
+		s.mu.Unlock()
+		return
+	}
+	delete(s.recent, key)
+	s.mu.Unlock()
+	writeSummary(entry)
+}
+
+// writeSummary writes the summary of an entry that collapsed any repeats.
+func writeSummary(entry *dedupEntry) {
+	if entry.repeats == 0 {
+		return
+	}
+	seen := entry.repeats + 1
+	elapsed := time.Since(entry.start)
+	summary := slog.NewRecord(time.Now(), entry.last.Level, entry.last.Message, entry.last.PC)
+	entry.last.Attrs(func(attr slog.Attr) bool {
+		summary.AddAttrs(attr)
+		return true
+	})
+	summary.AddAttrs(
+		slog.Int("seen", seen),
+		slog.String("summary", fmt.Sprintf("seen %d times in the last %.0f seconds", seen, elapsed.Seconds())),
+	)
+	entry.next.Handle(context.Background(), summary)
+}
+
+// dedupKey returns what identifies a failure: the level, message and error
+// text of a warning or error with an error attribute.
+func dedupKey(r slog.Record) (string, bool) {
+	if r.Level < slog.LevelWarn {
+		return "", false
+	}
+	var errText string
+	found := false
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "error" || attr.Key == "err" {
+			errText = attr.Value.String()
+			found = true
+			return false
+		}
+		return true
+	})
+	if !found {
+		return "", false
+	}
+	return fmt.Sprintf("%s\x00%s\x00%s", r.Level, r.Message, errText), true
+}
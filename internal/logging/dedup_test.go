@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer that summaries can be written to from a timer.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// decodeLines parses JSON log lines.
+func decodeLines(t *testing.T, out string) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDedupHandlerCollapsesRepeats(t *testing.T) {
+	var out bytes.Buffer
+	dedup := NewDedupHandler(slog.NewJSONHandler(&out, nil), time.Hour)
+	logger := slog.New(dedup).With("app", "test")
+
+	down := errors.New("connection refused")
+	for block := 1; block <= 5; block++ {
+		logger.Error("Failed to send bid", "blockNumber", block, "error", down)
+	}
+	logger.Error("Failed to send bid", "blockNumber", 6, "error", errors.New("invalid argument"))
+	logger.Warn("Failed to send bid", "blockNumber", 7, "error", down)
+	logger.Error("Bid cycle failed", "blockNumber", 8, "error", down)
+	// Records without an error, and info records, always pass
+	logger.Warn("Header has no base fee", "blockNumber", 9)
+	logger.Warn("Header has no base fee", "blockNumber", 10)
+	logger.Info("Bid sent", "err", down)
+	logger.Info("Bid sent", "err", down)
+
+	records := decodeLines(t, out.String())
+	require.Len(t, records, 8, "only the first of the identical errors passes")
+	require.EqualValues(t, 1, records[0]["blockNumber"])
+	require.Equal(t, "invalid argument", records[1]["error"])
+
+	require.NoError(t, dedup.Close())
+	records = decodeLines(t, out.String())
+	require.Len(t, records, 9)
+	summary := records[8]
+	require.Equal(t, "Failed to send bid", summary["msg"])
+	require.Equal(t, "ERROR", summary["level"])
+	require.Equal(t, "test", summary["app"])
+	require.EqualValues(t, 5, summary["blockNumber"], "the summary has the latest repeat's attributes")
+	require.EqualValues(t, 5, summary["seen"])
+	require.Contains(t, summary["summary"], "seen 5 times in the last")
+
+	// A failure is logged again once its window has ended
+	logger.Error("Failed to send bid", "blockNumber", 11, "error", down)
+	require.Len(t, decodeLines(t, out.String()), 10)
+}
+
+func TestDedupHandlerSummarizesWhenWindowEnds(t *testing.T) {
+	var out syncBuffer
+	logger := slog.New(NewDedupHandler(slog.NewJSONHandler(&out, nil), 50*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		logger.Error("Subscription error", "error", "EOF")
+	}
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "seen 3 times")
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, decodeLines(t, out.String()), 2)
+
+	// A failure seen once in its window writes no summary
+	logger.Error("Failed to fetch block body", "error", "not found")
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, decodeLines(t, out.String()), 3)
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// CustomJSONHandler is a custom slog.Handler that formats logs as pretty-printed JSON with customized timestamp
+type CustomJSONHandler struct {
+	mu      *sync.Mutex
+	encoder *json.Encoder
+	level   slog.Level
+	attrs   []slog.Attr
+}
+
+// NewCustomJSONHandler creates a new instance of CustomJSONHandler
+func NewCustomJSONHandler(w io.Writer, level slog.Level) *CustomJSONHandler {
+	h := NewCompactJSONHandler(w, level)
+	h.encoder.SetIndent("", "  ") // Set indentation for pretty-printing
+	return h
+}
+
+// NewCompactJSONHandler creates a CustomJSONHandler that writes each entry on a single line
+func NewCompactJSONHandler(w io.Writer, level slog.Level) *CustomJSONHandler {
+	encoder := json.NewEncoder(w)
+	return &CustomJSONHandler{
+		mu:      &sync.Mutex{},
+		encoder: encoder,
+		level:   level,
+	}
+}
+
+// Handle processes each log record
+func (h *CustomJSONHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level {
+		return nil // Skip logs below the set level
+	}
+
+	// Create a map to hold the log entry
+	logEntry := make(map[string]interface{})
+
+	// Customize the timestamp to include only milliseconds
+	logEntry["time"] = r.Time.Format("2006-01-02T15:04:05.000Z07:00") // RFC3339 with milliseconds
+
+	// Set the log level
+	logEntry["level"] = r.Level.String()
+
+	// Set the message
+	logEntry["msg"] = r.Message
+
+	// Add the logger's attributes, then the record's
+	for _, attr := range h.attrs {
+		logEntry[attr.Key] = attr.Value.Any()
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		logEntry[attr.Key] = attr.Value.Any()
+		return true
+	})
+
+	// Encode the log entry as pretty JSON; bids log from several goroutines
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.encoder.Encode(logEntry)
+}
+
+// Enabled checks if the handler is enabled for the given level
+func (h *CustomJSONHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// WithAttrs returns a new handler with the given attributes
+func (h *CustomJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := *h
+	out.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &out
+}
+
+// WithGroup returns a new handler with the given group name
+func (h *CustomJSONHandler) WithGroup(name string) slog.Handler {
+	// Groups can be handled if needed, but for simplicity, we ignore them here
+	return h
+}
+
+// fanoutHandler forwards each record to every wrapped handler.
+type fanoutHandler []slog.Handler
+
+func (f fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanoutHandler) WithGroup(name string) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
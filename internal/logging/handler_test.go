@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeRecorder keeps each Write call it receives separately.
+type writeRecorder struct {
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func TestCustomJSONHandlerWritesEachRecordOnce(t *testing.T) {
+	out := &writeRecorder{}
+	logger := slog.New(NewCustomJSONHandler(out, slog.LevelInfo))
+
+	// Pretty-printed entries span several lines, so each must reach the
+	// configured writer in a single Write to stay whole
+	var wg sync.WaitGroup
+	for g := 0; g < 50; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			derived := logger.With("goroutine", g)
+			for i := 0; i < 10; i++ {
+				derived.Info("event", "i", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	require.Len(t, out.writes, 500)
+	seen := make(map[[2]float64]bool)
+	for _, write := range out.writes {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(write, &entry), string(write))
+		require.Equal(t, "event", entry["msg"])
+		seen[[2]float64{entry["goroutine"].(float64), entry["i"].(float64)}] = true
+	}
+	require.Len(t, seen, 500)
+}
@@ -0,0 +1,105 @@
+// Package logging sets up the bidder's structured logger.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Config controls where and how logs are written.
+type Config struct {
+	AppName string
+	Version string
+	Level   slog.Level
+
+	// TimestampFormat, when set, replaces the default millisecond RFC3339 timestamps
+	// with one of the TimestampFormattingHandler formats.
+	TimestampFormat string
+
+	// FilePath, when set, additionally writes JSON logs to a rotated file.
+	FilePath   string
+	MaxSizeMB  int // Size at which the log file is rotated, lumberjack's default (100) if 0.
+	MaxBackups int // Number of rotated files to keep, all if 0.
+
+	Stderr  io.Writer // Console output, os.Stderr if nil.
+	Compact bool      // Write console entries on a single line instead of pretty-printed.
+
+	// DedupWindow, when set, collapses repeats of a failure within the window
+	// into a summary with a DedupHandler.
+	DedupWindow time.Duration
+}
+
+// InitializeLogger builds the logger described by cfg.
+//
+// Parameters:
+// - cfg: The logger configuration.
+//
+// Returns:
+// - The logger, with app and version attributes on every entry.
+// - A closer that flushes and closes the log file. It must be closed after the last log line.
+// - An error if the timestamp format is unknown.
+func InitializeLogger(cfg Config) (*slog.Logger, io.Closer, error) {
+	stderr := cfg.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	console := NewCustomJSONHandler(stderr, cfg.Level)
+	if cfg.Compact {
+		console = NewCompactJSONHandler(stderr, cfg.Level)
+	}
+	handlers := fanoutHandler{console}
+	var closer io.Closer = nopCloser{}
+	if cfg.FilePath != "" {
+		file := &lumberjack.Logger{
+			Filename:   cfg.FilePath,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+		}
+		handlers = append(handlers, slog.NewJSONHandler(file, &slog.HandlerOptions{Level: cfg.Level}))
+		closer = file
+	}
+
+	var handler slog.Handler = handlers
+	if cfg.TimestampFormat != "" {
+		formatted, err := NewTimestampFormattingHandler(handlers, cfg.TimestampFormat)
+		if err != nil {
+			closer.Close()
+			return nil, nil, err
+		}
+		handler = formatted
+	}
+	if cfg.DedupWindow > 0 {
+		dedup := NewDedupHandler(handler, cfg.DedupWindow)
+		// Summaries are written before the log file closes
+		closer = closers{dedup, closer}
+		handler = dedup
+	}
+
+	logger := slog.New(handler).With(
+		slog.String("app", cfg.AppName),
+		slog.String("version", cfg.Version),
+	)
+	return logger, closer, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// closers closes each closer in order, returning the first error.
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var first error
+	for _, closer := range c {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primev/preconf_blob_bidder/internal/shutdown"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastEventOnDiskAfterShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bidder.log")
+	var console bytes.Buffer
+	logger, closer, err := InitializeLogger(Config{AppName: "test", Version: "v0", FilePath: path, Stderr: &console})
+	require.NoError(t, err)
+
+	registry := shutdown.NewRegistry()
+	registry.Register("logger", shutdown.OrderLogger, closer)
+
+	for i := 0; i < 100; i++ {
+		logger.Info("Bid sent", "n", i)
+	}
+	logger.Info("Shutting down")
+	require.NoError(t, registry.Close(time.Second))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 101)
+	require.Contains(t, lines[99], `"n":99`)
+	require.Contains(t, lines[100], `"msg":"Shutting down"`)
+	require.Contains(t, lines[100], `"app":"test"`)
+
+	require.Contains(t, console.String(), "Shutting down")
+}
+
+func TestInitializeLoggerWithoutFile(t *testing.T) {
+	var console bytes.Buffer
+	logger, closer, err := InitializeLogger(Config{AppName: "test", Version: "v0", Stderr: &console})
+	require.NoError(t, err)
+	logger.With("demo", true).Info(fmt.Sprintf("event %d", 1))
+	require.NoError(t, closer.Close())
+	require.Contains(t, console.String(), "event 1")
+	require.Contains(t, console.String(), `"app": "test"`)
+	require.Contains(t, console.String(), `"demo": true`)
+}
+
+func TestInitializeLoggerCompact(t *testing.T) {
+	var console bytes.Buffer
+	logger, _, err := InitializeLogger(Config{AppName: "test", Version: "v0", Stderr: &console, Compact: true})
+	require.NoError(t, err)
+	logger.Info("event 1")
+	logger.Info("event 2")
+	lines := strings.Split(strings.TrimSpace(console.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"app":"test"`)
+	require.Contains(t, lines[1], `"msg":"event 2"`)
+}
+
+func TestEntriesStayWholeUnderConcurrentLogging(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bidder.log")
+	var console bytes.Buffer
+	logger, closer, err := InitializeLogger(Config{Stderr: &console, Compact: true, FilePath: path, TimestampFormat: TimestampUnixMilli})
+	require.NoError(t, err)
+
+	// Loggers derived with With share each handler's lock, so entries from several
+	// goroutines reach the console and the file one whole entry at a time
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			derived := logger.With("goroutine", g)
+			for i := 0; i < 50; i++ {
+				derived.Info("event", "i", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	require.NoError(t, closer.Close())
+
+	file, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, output := range []string{console.String(), string(file)} {
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 400)
+		for _, line := range lines {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+			require.Equal(t, "event", entry["msg"])
+			require.Contains(t, entry, "time")
+		}
+	}
+}
+
+func TestInitializeLoggerDedupSummaryOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bidder.log")
+	logger, closer, err := InitializeLogger(Config{AppName: "test", Version: "v0", FilePath: path, Stderr: &bytes.Buffer{}, DedupWindow: time.Hour})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		logger.Error("Failed to send bid", "error", "connection refused")
+	}
+	require.NoError(t, closer.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[1], `"seen":3`)
+}
+
+func TestWithComponent(t *testing.T) {
+	var console bytes.Buffer
+	logger, _, err := InitializeLogger(Config{AppName: "test", Version: "v0", Stderr: &console, Compact: true})
+	require.NoError(t, err)
+	WithComponent(logger, ComponentBidder).Info("Bid accepted", "blockNumber", 7)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(console.Bytes(), &entry))
+	require.Equal(t, "bidder", entry["component"])
+	require.Equal(t, float64(7), entry["blockNumber"], "attributes aren't nested under the component")
+	require.Equal(t, "test", entry["app"])
+}
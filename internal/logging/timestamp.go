@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Supported LOG_TIMESTAMP_FORMAT values.
+const (
+	TimestampRFC3339     = "rfc3339"
+	TimestampRFC3339Nano = "rfc3339nano"
+	TimestampUnix        = "unix"
+	TimestampUnixMilli   = "unixmilli"
+)
+
+// TimestampFormattingHandler wraps another handler and replaces each record's
+// time with a "time" attribute in the configured format. The wrapped handler
+// sees a zero record time, which the slog handlers treat as "no time", so the
+// formatted attribute is the only timestamp written.
+type TimestampFormattingHandler struct {
+	next   slog.Handler
+	format func(time.Time) any
+}
+
+// NewTimestampFormattingHandler creates a TimestampFormattingHandler.
+//
+// Parameters:
+// - next: The handler that writes the records.
+// - format: One of rfc3339, rfc3339nano, unix, or unixmilli.
+//
+// Returns:
+// - A pointer to a TimestampFormattingHandler, or an error if the format is unknown.
+func NewTimestampFormattingHandler(next slog.Handler, format string) (*TimestampFormattingHandler, error) {
+	var f func(time.Time) any
+	switch format {
+	case TimestampRFC3339:
+		f = func(t time.Time) any { return t.Format(time.RFC3339) }
+	case TimestampRFC3339Nano:
+		f = func(t time.Time) any { return t.Format(time.RFC3339Nano) }
+	case TimestampUnix:
+		f = func(t time.Time) any { return t.Unix() }
+	case TimestampUnixMilli:
+		f = func(t time.Time) any { return t.UnixMilli() }
+	default:
+		return nil, fmt.Errorf("unknown log timestamp format %q (must be %s, %s, %s, or %s)",
+			format, TimestampRFC3339, TimestampRFC3339Nano, TimestampUnix, TimestampUnixMilli)
+	}
+	return &TimestampFormattingHandler{next: next, format: f}, nil
+}
+
+// Enabled reports whether the wrapped handler handles the level.
+func (h *TimestampFormattingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle moves the record time into a formatted "time" attribute and passes the record on.
+func (h *TimestampFormattingHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(time.Time{}, r.Level, r.Message, r.PC)
+	if !r.Time.IsZero() {
+		out.AddAttrs(slog.Any(slog.TimeKey, h.format(r.Time)))
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		out.AddAttrs(attr)
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs returns a TimestampFormattingHandler wrapping next.WithAttrs(attrs).
+func (h *TimestampFormattingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TimestampFormattingHandler{next: h.next.WithAttrs(attrs), format: h.format}
+}
+
+// WithGroup returns a TimestampFormattingHandler wrapping next.WithGroup(name).
+func (h *TimestampFormattingHandler) WithGroup(name string) slog.Handler {
+	return &TimestampFormattingHandler{next: h.next.WithGroup(name), format: h.format}
+}
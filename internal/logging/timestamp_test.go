@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampFormattingHandler(t *testing.T) {
+	ts := time.Date(2024, 11, 5, 13, 4, 5, 123456789, time.UTC)
+	tests := []struct {
+		format string
+		want   any
+	}{
+		{TimestampRFC3339, "2024-11-05T13:04:05Z"},
+		{TimestampRFC3339Nano, "2024-11-05T13:04:05.123456789Z"},
+		{TimestampUnix, float64(ts.Unix())},
+		{TimestampUnixMilli, float64(ts.UnixMilli())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			h, err := NewTimestampFormattingHandler(slog.NewJSONHandler(&buf, nil), tt.format)
+			require.NoError(t, err)
+
+			r := slog.NewRecord(ts, slog.LevelInfo, "Bid sent", 0)
+			r.AddAttrs(slog.Int("block", 7))
+			require.NoError(t, h.WithAttrs([]slog.Attr{slog.String("app", "test")}).Handle(context.Background(), r))
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			require.Equal(t, tt.want, entry["time"])
+			require.Equal(t, "test", entry["app"])
+			require.Equal(t, float64(7), entry["block"])
+		})
+	}
+
+	_, err := NewTimestampFormattingHandler(slog.NewJSONHandler(&bytes.Buffer{}, nil), "iso")
+	require.ErrorContains(t, err, "unknown log timestamp format")
+}
+
+func TestTimestampFormattingWithCustomJSONHandler(t *testing.T) {
+	var console bytes.Buffer
+	logger, _, err := InitializeLogger(Config{Stderr: &console, TimestampFormat: TimestampUnix})
+	require.NoError(t, err)
+	logger.Info("Bid sent")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(console.Bytes(), &entry))
+	require.IsType(t, float64(0), entry["time"])
+}
@@ -0,0 +1,219 @@
+// Package metrics defines the Prometheus metrics exported by the bidder.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// AvgTxsPerBlock is the rolling average of transactions per confirmed block.
+	AvgTxsPerBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "preconf_avg_txs_per_block",
+		Help: "Rolling average of transactions per confirmed block.",
+	})
+
+	// PayloadBidDowngrades counts payload bids sent as hash-only bids, by reason.
+	PayloadBidDowngrades = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_payload_bid_downgrades_total",
+		Help: "Payload bids downgraded to hash-only bids, by reason.",
+	}, []string{"reason"})
+
+	// IncludedGasUsed counts the gas used by our included transactions, by transaction type.
+	IncludedGasUsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_included_gas_used_total",
+		Help: "Gas used by included transactions, by transaction type.",
+	}, []string{"tx_type"})
+
+	// IncludedBlobGasUsed counts the blob gas used by our included transactions, by transaction type.
+	IncludedBlobGasUsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_included_blob_gas_used_total",
+		Help: "Blob gas used by included transactions, by transaction type.",
+	}, []string{"tx_type"})
+
+	// IncludedCostGwei counts what our included transactions cost in gwei, by
+	// transaction type and kind (gas fee, blob fee or bid).
+	IncludedCostGwei = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_included_cost_gwei_total",
+		Help: "Cost of included transactions in gwei, by transaction type and kind (gas, blob, bid).",
+	}, []string{"tx_type", "kind"})
+
+	// OutageBidsDiscarded counts bids queued during a bidder node outage that were
+	// never sent, by reason (overflow or expired).
+	OutageBidsDiscarded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_outage_bids_discarded_total",
+		Help: "Bids queued during a bidder node outage and discarded, by reason.",
+	}, []string{"reason"})
+
+	// BidSendFailures counts bids that couldn't be sent to the bidder node, by kind
+	// (gave_up after retryable errors, or rejected) and the gRPC status of the
+	// last attempt.
+	BidSendFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_bid_send_failures_total",
+		Help: "Bids that couldn't be sent to the bidder node, by kind (gave_up, rejected) and gRPC status.",
+	}, []string{"kind", "code"})
+
+	// BidsSent counts bids the bidder node accepted.
+	BidsSent = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_bids_sent_total",
+		Help: "Bids sent to the bidder node.",
+	})
+
+	// BidsFailed counts bids that failed for any reason, including the ones in
+	// BidSendFailures.
+	BidsFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_bids_failed_total",
+		Help: "Bids that failed to be sent or to stream their commitments.",
+	})
+
+	// TransactionsSent counts the transactions built and bid on, by the generator
+	// that built them (transfer, blob, or a registered TX_TYPE).
+	TransactionsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_transactions_sent_total",
+		Help: "Transactions built and bid on, by generator.",
+	}, []string{"type"})
+
+	// BidOutcomes counts bids the bidder node answered, by outcome: won if a
+	// provider committed to the bid, lost otherwise.
+	BidOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_bid_outcomes_total",
+		Help: "Bids answered by the bidder node, by outcome (won, lost).",
+	}, []string{"outcome"})
+
+	// BidSilentRejections counts bids the bidder node took without error but
+	// answered without a commitment, to alert on apart from failed bids.
+	BidSilentRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_bid_silent_rejections_total",
+		Help: "Bids the bidder node took without error that got no commitment.",
+	})
+
+	// WalletTopUps counts transfers from the funding wallet to pool wallets
+	// that ran low.
+	WalletTopUps = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_wallet_top_ups_total",
+		Help: "Transfers from the funding wallet to pool wallets below the minimum balance.",
+	})
+
+	// BidProviders counts bids the bidder node answered by how many distinct
+	// providers committed to them: 0, 1, or 2+.
+	BidProviders = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_bid_providers_total",
+		Help: "Bids answered by the bidder node, by the number of providers that committed (0, 1, 2+).",
+	}, []string{"providers"})
+
+	// BundlesSent counts transactions delivered to the RPC endpoint as bundles.
+	BundlesSent = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_bundles_sent_total",
+		Help: "Bundles sent to the RPC endpoint.",
+	})
+
+	// BundlesDropped counts bundles dropped because MAX_CONCURRENT_BUNDLES
+	// submissions were in flight under the drop policy.
+	BundlesDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_bundles_dropped_total",
+		Help: "Bundles dropped because too many bundle submissions were in flight.",
+	})
+
+	// BundleQueueDepth is the number of bundle submissions waiting for one in
+	// flight to finish under the queue policy.
+	BundleQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "preconf_bundle_queue_depth",
+		Help: "Bundle submissions waiting for a free submission slot.",
+	})
+
+	// WSReconnects counts successful reconnections of the WebSocket client.
+	WSReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_ws_reconnects_total",
+		Help: "Times the WebSocket client reconnected and resubscribed to headers.",
+	})
+
+	// WSStaleReconnects counts header subscriptions torn down because no header
+	// arrived within HEADER_STALE_TIMEOUT.
+	WSStaleReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_ws_stale_reconnects_total",
+		Help: "Times the header subscription delivered no header within the stale timeout and was reconnected.",
+	})
+
+	// BlocksObserved counts the headers received with a block number.
+	BlocksObserved = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_blocks_observed_total",
+		Help: "Headers received from the WebSocket subscription.",
+	})
+
+	// LastProcessedBlock is the number of the latest header received.
+	LastProcessedBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "preconf_last_processed_block",
+		Help: "Number of the latest header received.",
+	})
+
+	// BidderConnectionUp is 1 while the bidder node is reachable and 0 during an outage.
+	BidderConnectionUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "preconf_bidder_connection_up",
+		Help: "Whether the bidder node is reachable (1) or in an outage (0).",
+	})
+
+	// BidAmountWei is the distribution of bid amounts, from 1 microether to 10 ETH.
+	BidAmountWei = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "preconf_bid_amount_wei",
+		Help:    "Bid amounts in wei.",
+		Buckets: prometheus.ExponentialBuckets(1e12, 10, 8),
+	})
+
+	// HeaderToBidSeconds is the time from a header's arrival to submitting a bid
+	// built on it, from 1ms to about 8s.
+	HeaderToBidSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "preconf_header_to_bid_seconds",
+		Help:    "Time from header arrival to bid submission in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
+
+	// DepositTopUps counts top-ups of the bidder deposit, by result (ok or failed,
+	// including failures to read the balance).
+	DepositTopUps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_deposit_topups_total",
+		Help: "Top-ups of the bidder deposit of a window, by result (ok, failed).",
+	}, []string{"result"})
+
+	// DepositBalanceEth is the cached bidder deposit of the latest target block's
+	// window, lowered by the bids sent since it was read.
+	DepositBalanceEth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "preconf_deposit_balance_eth",
+		Help: "Bidder deposit of the latest target block's window in ETH.",
+	})
+
+	// InclusionWebhookFailures counts inclusion records the webhook couldn't
+	// deliver after all attempts.
+	InclusionWebhookFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "preconf_inclusion_webhook_failures_total",
+		Help: "Inclusion records the inclusion webhook failed to deliver.",
+	})
+
+	// BidBuildSeconds is the time from a header's arrival until the bids built on
+	// it are queued, including signing and blob commitments, from 1ms to about 8s.
+	BidBuildSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "preconf_bid_build_seconds",
+		Help:    "Time from header arrival until the bids built on it are queued in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
+
+	// StaleBids counts bids whose target block's header arrived before they were
+	// dispatched, by action (skipped or retargeted).
+	StaleBids = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "preconf_stale_bids_total",
+		Help: "Bids whose target block was reached before dispatch, by action (skipped, retargeted).",
+	}, []string{"action"})
+
+	// DecayStartSlotOffsetSeconds and DecayEndSlotOffsetSeconds are where each
+	// bid's decay window starts and ends relative to the start of its target
+	// block's slot, from 36s before to 36s after.
+	DecayStartSlotOffsetSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "preconf_decay_start_slot_offset_seconds",
+		Help:    "Start of each bid's decay window relative to the start of the target slot in seconds.",
+		Buckets: prometheus.LinearBuckets(-36, 3, 25),
+	})
+	DecayEndSlotOffsetSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "preconf_decay_end_slot_offset_seconds",
+		Help:    "End of each bid's decay window relative to the start of the target slot in seconds.",
+		Buckets: prometheus.LinearBuckets(-36, 3, 25),
+	})
+)
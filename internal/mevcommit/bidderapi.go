@@ -31,26 +31,113 @@ type BidderInterface interface {
 	SendBid(input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error)
 }
 
-// SendPreconfBid sends a preconfirmation bid to the bidder client
-func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber int64, randomEthAmount float64) {
-	// Get current time in milliseconds
-	currentTime := time.Now().UnixMilli()
+// ContextBidder is a BidderInterface whose sends can be bounded by a context,
+// such as Bidder and MultiBidder.
+type ContextBidder interface {
+	BidderInterface
+	SendBidContext(ctx context.Context, input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error)
+}
 
-	// Define bid decay start and end
-	decayStart := currentTime
-	decayEnd := currentTime + int64(time.Duration(36*time.Second).Milliseconds()) // Bid decay is 36 seconds (2 blocks)
+// sendBidContext sends the bid bounded by ctx if the bidder supports it.
+func sendBidContext(ctx context.Context, bidderClient BidderInterface, input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
+	if b, ok := bidderClient.(ContextBidder); ok {
+		return b.SendBidContext(ctx, input, amount, blockNumber, decayStart, decayEnd)
+	}
+	return bidderClient.SendBid(input, amount, blockNumber, decayStart, decayEnd)
+}
 
-	// Convert the random ETH amount to wei (1 ETH = 10^18 wei)
-	bigEthAmount := big.NewFloat(randomEthAmount)
-	weiPerEth := big.NewFloat(1e18)
-	bigWeiAmount := new(big.Float).Mul(bigEthAmount, weiPerEth)
+// loggerOf returns the logger of a bidder that has one, such as Bidder and
+// MultiBidder, so a bid is logged with the client's attributes, or slog.Default().
+func loggerOf(bidderClient BidderInterface) *slog.Logger {
+	if b, ok := bidderClient.(interface{ Logger() *slog.Logger }); ok {
+		return b.Logger()
+	}
+	return slog.Default()
+}
 
-	// Convert big.Float to big.Int
-	randomWeiAmount := new(big.Int)
-	bigWeiAmount.Int(randomWeiAmount)
+// SendPreconfBid sends a preconfirmation bid to the bidder client, decaying over the given window
+func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber int64, randomEthAmount float64, window DecayWindow) error {
+	return SendPreconfBidWei(bidderClient, input, blockNumber, EthToWei(randomEthAmount), window)
+}
+
+// SendPreconfBidWei sends a preconfirmation bid of an exact amount in wei, decaying over the given window.
+// Failures are logged and returned, so callers can tell an unreachable bidder node apart.
+func SendPreconfBidWei(bidderClient BidderInterface, input interface{}, blockNumber int64, amountWei *big.Int, window DecayWindow) error {
+	_, err := SendPreconfBidWithRetry(context.Background(), bidderClient, input, blockNumber, amountWei, window, BidRetryPolicy{})
+	return err
+}
+
+// BidResult is what providers answered to a bid.
+type BidResult struct {
+	Commitments []*pb.Commitment // Commitments received for the bid, in order.
+}
 
-	// Convert the amount to a string for the bidder
-	amount := randomWeiAmount.String()
+// Won reports whether a provider committed to the bid.
+func (r BidResult) Won() bool {
+	return len(r.Commitments) > 0
+}
+
+// Outcomes of a bid, as classified by ClassifyBid.
+const (
+	BidCommitted       = "committed"        // At least one provider committed to the bid.
+	BidSilentRejection = "silent_rejection" // The node took the bid without error, but no provider committed to it.
+	BidFailed          = "failed"           // The bid couldn't be sent, or its response stream failed.
+)
+
+// ClassifyBid tells a bid that providers committed to apart from a silent
+// rejection, where the bid went through but its stream ended without a
+// commitment, and from an explicit failure.
+//
+// Parameters:
+// - result: The commitments received for the bid.
+// - err: The error sending the bid or receiving its response.
+//
+// Returns:
+// - BidCommitted, BidSilentRejection or BidFailed.
+func ClassifyBid(result BidResult, err error) string {
+	switch {
+	case err != nil:
+		return BidFailed
+	case result.Won():
+		return BidCommitted
+	default:
+		return BidSilentRejection
+	}
+}
+
+// Providers returns the number of distinct providers that committed to the bid.
+func (r BidResult) Providers() int {
+	providers := make(map[string]struct{}, len(r.Commitments))
+	for _, commitment := range r.Commitments {
+		providers[strings.ToLower(commitment.GetProviderAddress())] = struct{}{}
+	}
+	return len(providers)
+}
+
+// SendPreconfBidWithRetry sends a preconfirmation bid like SendPreconfBidWei, retrying
+// with exponential backoff while the bidder node fails with a retryable gRPC status.
+// Only sending is retried: once the bid is sent the node may have accepted it. A
+// node refusing the bid with a status before any commitment counts as a failed send.
+//
+// Parameters:
+// - ctx: Bounds the retries and, for a ContextBidder, each attempt, typically
+// with a deadline from BidDeadline.
+// - policy: The number of attempts and the initial backoff.
+//
+// Returns:
+// - The commitments received for the bid.
+// - nil once the bid is sent, a *BidSendError if the node couldn't be given the
+// bid, or the error receiving its response.
+func SendPreconfBidWithRetry(ctx context.Context, bidderClient BidderInterface, input interface{}, blockNumber int64, amountWei *big.Int, window DecayWindow, policy BidRetryPolicy) (BidResult, error) {
+	logger := loggerOf(bidderClient)
+
+	// Define bid decay start and end
+	decayStart := window.Start
+	decayEnd := window.End
+
+	// The bidder takes the amount as a wei string
+	amount := amountWei.String()
+	randomEthAmount := WeiToEth(amountWei)
 
 	// Determine how to handle the input
 	var responseClient pb.Bidder_SendBidClient
@@ -59,7 +146,7 @@ func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber
 	case string:
 		// Input is a string, process it as a transaction hash
 		txHash := strings.TrimPrefix(v, "0x")
-		slog.Info("Sending bid with transaction hash",
+		logger.Info("Sending bid with transaction hash",
 			"txHash", txHash,
 			"amount", amount,
 			"blockNumber", blockNumber,
@@ -67,16 +154,18 @@ func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber
 			"decayEnd", decayEnd,
 		)
 		// Send the bid with tx hash string
-		responseClient, err = bidderClient.SendBid([]string{txHash}, amount, blockNumber, decayStart, decayEnd)
+		responseClient, err = retrySend(ctx, policy, func() (pb.Bidder_SendBidClient, error) {
+			return sendBidContext(ctx, bidderClient, []string{txHash}, amount, blockNumber, decayStart, decayEnd)
+		})
 
 	case *types.Transaction:
 		// Check for nil transaction
 		if v == nil {
-			slog.Warn("Transaction is nil, cannot send bid.")
-			return
+			logger.Warn("Transaction is nil, cannot send bid.")
+			return BidResult{}, fmt.Errorf("transaction is nil")
 		}
 		// Input is a transaction object, send the transaction object
-		slog.Info("Sending bid with transaction payload",
+		logger.Info("Sending bid with transaction payload",
 			"txHash", v.Hash().String(),
 			"amount", amount,
 			"blockNumber", blockNumber,
@@ -84,18 +173,58 @@ func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber
 			"decayEnd", decayEnd,
 		)
 		// Send the bid with the full transaction object
-		responseClient, err = bidderClient.SendBid([]*types.Transaction{v}, amount, blockNumber, decayStart, decayEnd)
+		responseClient, err = retrySend(ctx, policy, func() (pb.Bidder_SendBidClient, error) {
+			return sendBidContext(ctx, bidderClient, []*types.Transaction{v}, amount, blockNumber, decayStart, decayEnd)
+		})
+
+	case []string:
+		// Input is a bundle of transaction hashes, bid on together
+		txHashes := make([]string, len(v))
+		for i, hash := range v {
+			txHashes[i] = strings.TrimPrefix(hash, "0x")
+		}
+		logger.Info("Sending bid with bundle of transaction hashes",
+			"txHashes", txHashes,
+			"amount", amount,
+			"blockNumber", blockNumber,
+			"decayStart", decayStart,
+			"decayEnd", decayEnd,
+		)
+		responseClient, err = retrySend(ctx, policy, func() (pb.Bidder_SendBidClient, error) {
+			return sendBidContext(ctx, bidderClient, txHashes, amount, blockNumber, decayStart, decayEnd)
+		})
+
+	case []*types.Transaction:
+		// Input is a bundle of transaction payloads, bid on together
+		txHashes := make([]string, len(v))
+		for i, tx := range v {
+			if tx == nil {
+				logger.Warn("Transaction is nil, cannot send bid.")
+				return BidResult{}, fmt.Errorf("transaction %d of the bundle is nil", i)
+			}
+			txHashes[i] = tx.Hash().String()
+		}
+		logger.Info("Sending bid with bundle of transaction payloads",
+			"txHashes", txHashes,
+			"amount", amount,
+			"blockNumber", blockNumber,
+			"decayStart", decayStart,
+			"decayEnd", decayEnd,
+		)
+		responseClient, err = retrySend(ctx, policy, func() (pb.Bidder_SendBidClient, error) {
+			return sendBidContext(ctx, bidderClient, v, amount, blockNumber, decayStart, decayEnd)
+		})
 
 	default:
-		slog.Warn("Unsupported input type, must be string or *types.Transaction",
+		logger.Warn("Unsupported input type, must be string, *types.Transaction, []string or []*types.Transaction",
 			"inputType", fmt.Sprintf("%T", input),
 		)
-		return
+		return BidResult{}, fmt.Errorf("unsupported input type %T", input)
 	}
 
 	// Check if there was an error sending the bid
 	if err != nil {
-		slog.Warn("Failed to send bid",
+		logger.Warn("Failed to send bid",
 			"err", err,
 			"txHash", fmt.Sprintf("%v", input),
 			"amount", amount,
@@ -103,21 +232,32 @@ func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber
 			"decayStart", decayStart,
 			"decayEnd", decayEnd,
 		)
-		return
+		return BidResult{}, err
 	}
 
-	// Call Recv() to handle the response and complete the expectation in your tests
-	_, recvErr := responseClient.Recv()
+	// Read every commitment until the stream ends
+	var result BidResult
+	var recvErr error
+	for {
+		commitment, err := responseClient.Recv()
+		if err != nil {
+			recvErr = err
+			break
+		}
+		result.Commitments = append(result.Commitments, commitment)
+	}
 	if recvErr == io.EOF {
-		slog.Info("Bid response received: EOF",
+		logger.Info("Bid response received: EOF",
 			"txHash", fmt.Sprintf("%v", input),
+			"commitments", len(result.Commitments),
+			"providers", result.Providers(),
 			"blockNumber", blockNumber,
 			"amount_ETH", randomEthAmount,
 			"decayStart", decayStart,
 			"decayEnd", decayEnd,
 		)
 	} else if recvErr != nil {
-		slog.Warn("Error receiving bid response",
+		logger.Warn("Error receiving bid response",
 			"err", recvErr,
 			"txHash", fmt.Sprintf("%v", input),
 			"blockNumber", blockNumber,
@@ -125,32 +265,66 @@ func SendPreconfBid(bidderClient BidderInterface, input interface{}, blockNumber
 			"decayEnd", decayEnd,
 		)
 	} else {
-		slog.Info("Sent preconfirmation bid and received response",
+		logger.Info("Sent preconfirmation bid and received response",
 			"block", blockNumber,
 			"amount_ETH", randomEthAmount,
 			"decayStart", decayStart,
 			"decayEnd", decayEnd,
 		)
 	}
+
+	if recvErr == io.EOF {
+		return result, nil
+	}
+	return result, recvErr
+}
+
+// EthToWei converts an ETH amount to wei (1 ETH = 10^18 wei).
+func EthToWei(ethAmount float64) *big.Int {
+	bigWeiAmount := new(big.Float).Mul(big.NewFloat(ethAmount), big.NewFloat(1e18))
+	weiAmount := new(big.Int)
+	bigWeiAmount.Int(weiAmount)
+	return weiAmount
+}
+
+// WeiToEth converts a wei amount to ETH.
+func WeiToEth(weiAmount *big.Int) float64 {
+	ethAmount, _ := new(big.Float).Quo(new(big.Float).SetInt(weiAmount), big.NewFloat(1e18)).Float64()
+	return ethAmount
 }
 
 // SendBid handles sending a bid request after preparing the input data.
 func (b *Bidder) SendBid(input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
+	return b.SendBidContext(context.Background(), input, amount, blockNumber, decayStart, decayEnd)
+}
+
+// SendBidContext is SendBid bounded by ctx: once ctx is done the bid stream is
+// cancelled, keeping the commitments received until then. A stream that fails
+// before any commitment, such as a node refusing the bid, returns its error.
+func (b *Bidder) SendBidContext(ctx context.Context, input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
 	txHashes, rawTransactions, err := b.parseInput(input)
 	if err != nil {
 		return nil, err
 	}
 
-	bidRequest := b.createBidRequest(amount, blockNumber, decayStart, decayEnd, txHashes, rawTransactions)
+	bidRequest, err := b.createBidRequest(amount, blockNumber, decayStart, decayEnd, txHashes, rawTransactions)
+	if err != nil {
+		return nil, err
+	}
 
-	response, err := b.sendBidRequest(bidRequest)
+	response, err := b.sendBidRequest(ctx, bidRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	b.receiveBidResponses(response)
+	// The node answers a bid it refuses with a status on the stream rather than
+	// on the call, so that status is the send error, for retries to classify
+	commitments, err := b.receiveBidResponses(response)
+	if err != io.EOF && len(commitments) == 0 {
+		return nil, err
+	}
 
-	return response, nil
+	return &drainedBidStream{Bidder_SendBidClient: response, commitments: commitments, err: err}, nil
 }
 
 // parseInput processes the input and converts it to either transaction hashes or raw transactions.
@@ -169,7 +343,7 @@ func (b *Bidder) parseInput(input interface{}) ([]string, []string, error) {
 		for i, tx := range v {
 			rlpEncodedTx, err := tx.MarshalBinary()
 			if err != nil {
-				slog.Error("Failed to marshal transaction to raw format",
+				b.Logger().Error("Failed to marshal transaction to raw format",
 					"err", err,
 				)
 				return nil, nil, fmt.Errorf("failed to marshal transaction: %w", err)
@@ -177,7 +351,7 @@ func (b *Bidder) parseInput(input interface{}) ([]string, []string, error) {
 			rawTransactions[i] = hex.EncodeToString(rlpEncodedTx)
 		}
 	default:
-		slog.Warn("Unsupported input type, must be []string or []*types.Transaction",
+		b.Logger().Warn("Unsupported input type, must be []string or []*types.Transaction",
 			"inputType", fmt.Sprintf("%T", input),
 		)
 		return nil, nil, fmt.Errorf("unsupported input type: %T", input)
@@ -187,8 +361,8 @@ func (b *Bidder) parseInput(input interface{}) ([]string, []string, error) {
 }
 
 // createBidRequest builds a Bid request using the provided data.
-func (b *Bidder) createBidRequest(amount string, blockNumber, decayStart, decayEnd int64, txHashes, rawTransactions []string) *pb.Bid {
-	bidRequest := &pb.Bid{
+func (b *Bidder) createBidRequest(amount string, blockNumber, decayStart, decayEnd int64, txHashes, rawTransactions []string) (*pb.Bid, error) {
+	bidRequest := &BidRequest{
 		Amount:              amount,
 		BlockNumber:         blockNumber,
 		DecayStartTimestamp: decayStart,
@@ -201,15 +375,29 @@ func (b *Bidder) createBidRequest(amount string, blockNumber, decayStart, decayE
 		bidRequest.RawTransactions = rawTransactions
 	}
 
-	return bidRequest
+	// Let the bid's own transactions revert without voiding the commitment
+	if b.allowReverts {
+		revertingTxHashes, err := bidRequest.bundleTxHashes()
+		if err != nil {
+			return nil, err
+		}
+		bidRequest.RevertingTxHashes = revertingTxHashes
+	}
+
+	return bidRequest.Proto(), nil
+}
+
+// ObserveCommitments registers fn to be called with every commitment received for
+// a bid. It must be called before bids are sent.
+func (b *Bidder) ObserveCommitments(fn func(*pb.Commitment)) {
+	b.onCommitment = fn
 }
 
 // sendBidRequest sends the prepared bid request to the mev-commit client.
-func (b *Bidder) sendBidRequest(bidRequest *pb.Bid) (pb.Bidder_SendBidClient, error) {
-	ctx := context.Background()
+func (b *Bidder) sendBidRequest(ctx context.Context, bidRequest *pb.Bid) (pb.Bidder_SendBidClient, error) {
 	response, err := b.client.SendBid(ctx, bidRequest)
 	if err != nil {
-		slog.Error("Failed to send bid",
+		b.Logger().Error("Failed to send bid",
 			"err", err,
 		)
 		return nil, fmt.Errorf("failed to send bid: %w", err)
@@ -219,27 +407,62 @@ func (b *Bidder) sendBidRequest(bidRequest *pb.Bid) (pb.Bidder_SendBidClient, er
 }
 
 // receiveBidResponses processes the responses from the bid request.
-func (b *Bidder) receiveBidResponses(response pb.Bidder_SendBidClient) {
+//
+// Returns:
+// - The commitments received, and the error that ended the stream, io.EOF when
+// it ended normally.
+func (b *Bidder) receiveBidResponses(response pb.Bidder_SendBidClient) ([]*pb.Commitment, error) {
+	var commitments []*pb.Commitment
+	var endErr error
 	for {
 		msg, err := response.Recv()
 		if err == io.EOF {
 			// End of stream
+			endErr = err
 			break
 		}
 		if err != nil {
-			slog.Error("Failed to receive bid response",
+			b.Logger().Error("Failed to receive bid response",
 				"err", err,
 			)
-			continue
+			endErr = err
+			break
 		}
+		commitments = append(commitments, msg)
 
-		slog.Info("Bid accepted",
-			"commitmentDetails", msg,
-		)
+		if b.onCommitment != nil {
+			b.onCommitment(msg)
+		}
+		if b.recorder != nil {
+			_ = b.recorder.Record(msg)
+		} else {
+			b.Logger().Info("Bid accepted",
+				"commitment", commitmentFields(msg),
+			)
+		}
 	}
 
 	startTimeBeforeSaveResponses := time.Now()
-	slog.Info("End Time",
+	b.Logger().Info("End Time",
 		"time", startTimeBeforeSaveResponses,
 	)
+	return commitments, endErr
+}
+
+// drainedBidStream replays the commitments of a bid stream that SendBid already
+// read to its end, so callers still receive them.
+type drainedBidStream struct {
+	pb.Bidder_SendBidClient
+	commitments []*pb.Commitment
+	err         error // Ends the replay once the commitments are returned.
+}
+
+// Recv returns the next commitment, then the error that ended the stream.
+func (s *drainedBidStream) Recv() (*pb.Commitment, error) {
+	if len(s.commitments) == 0 {
+		return nil, s.err
+	}
+	commitment := s.commitments[0]
+	s.commitments = s.commitments[1:]
+	return commitment, nil
 }
@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
@@ -17,122 +18,138 @@ import (
 
 // MockBidderClient is a mock implementation of BidderInterface.
 type MockBidderClient struct {
-    mock.Mock
+	mock.Mock
 }
 
 func (m *MockBidderClient) SendBid(input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
-    args := m.Called(input, amount, blockNumber, decayStart, decayEnd)
-    return args.Get(0).(pb.Bidder_SendBidClient), args.Error(1)
+	args := m.Called(input, amount, blockNumber, decayStart, decayEnd)
+	return args.Get(0).(pb.Bidder_SendBidClient), args.Error(1)
 }
 
 // MockBidderSendBidClient is a mock implementation of pb.Bidder_SendBidClient.
 type MockBidderSendBidClient struct {
-    mock.Mock
+	mock.Mock
 }
 
 func (m *MockBidderSendBidClient) Recv() (*pb.Commitment, error) {
-    args := m.Called()
-    commitment, _ := args.Get(0).(*pb.Commitment)
-    return commitment, args.Error(1)
+	args := m.Called()
+	commitment, _ := args.Get(0).(*pb.Commitment)
+	return commitment, args.Error(1)
 }
 
-
 func (m *MockBidderSendBidClient) Header() (metadata.MD, error) {
-    return nil, nil
+	return nil, nil
 }
 
 func (m *MockBidderSendBidClient) Trailer() metadata.MD {
-    return nil
+	return nil
 }
 
 func (m *MockBidderSendBidClient) CloseSend() error {
-    return nil
+	return nil
 }
 
 func (m *MockBidderSendBidClient) Context() context.Context {
-    return context.Background()
+	return context.Background()
 }
 
 func (m *MockBidderSendBidClient) SendMsg(msg interface{}) error {
-    return nil
+	return nil
 }
 
 func (m *MockBidderSendBidClient) RecvMsg(msg interface{}) error {
-    return nil
+	return nil
 }
 
 // Define the custom mock transaction type outside of the test function
 type MockTransaction struct {
-    types.Transaction
-    mock.Mock
+	types.Transaction
+	mock.Mock
 }
 
 // Define the MarshalBinary method outside the test function
 func (m *MockTransaction) MarshalBinary() ([]byte, error) {
-    args := m.Called()
-    return args.Get(0).([]byte), args.Error(1)
+	args := m.Called()
+	return args.Get(0).([]byte), args.Error(1)
 }
 
 func TestSendPreconfBid(t *testing.T) {
-    // Initialize the mock Bidder client
-    mockBidder := new(MockBidderClient)
-    mockSendBidClient := new(MockBidderSendBidClient)
+	// Initialize the mock Bidder client
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
+
+	bidAmount := 1.0
+
+	// Correctly calculate bidAmountInWei as "1000000000000000000"
+	bigEthAmount := big.NewFloat(bidAmount)
+	weiPerEth := big.NewFloat(1e18)
+	bigWeiAmount := new(big.Float).Mul(bigEthAmount, weiPerEth)
+	randomWeiAmount := new(big.Int)
+	bigWeiAmount.Int(randomWeiAmount)
+	bidAmountInWei := randomWeiAmount.String() // "1000000000000000000"
+
+	// Define the hard-coded legitimate transaction hash
+	transactionHash := "0xae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"
+
+	// Expected input and parameters
+	expectedInput := []string{strings.TrimPrefix(transactionHash, "0x")} // "ae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"
+	expectedAmount := bidAmountInWei
+	expectedBlockNumber := int64(100)
 
-    bidAmount := 1.0
+	// Setup expectations for SendBid
+	mockBidder.On("SendBid",
+		expectedInput,
+		expectedAmount,
+		expectedBlockNumber,
+		mock.AnythingOfType("int64"), // decayStart
+		mock.AnythingOfType("int64"), // decayEnd
+	).Return(mockSendBidClient, nil)
+
+	// Setup expectations for Recv to return io.EOF (indicating end of response stream)
+	mockSendBidClient.On("Recv").Return(nil, io.EOF)
 
-    // Correctly calculate bidAmountInWei as "1000000000000000000"
-    bigEthAmount := big.NewFloat(bidAmount)
-    weiPerEth := big.NewFloat(1e18)
-    bigWeiAmount := new(big.Float).Mul(bigEthAmount, weiPerEth)
-    randomWeiAmount := new(big.Int)
-    bigWeiAmount.Int(randomWeiAmount)
-    bidAmountInWei := randomWeiAmount.String() // "1000000000000000000"
+	// Call SendPreconfBid with the transaction hash, block number, and bid amount
+	SendPreconfBid(mockBidder, transactionHash, expectedBlockNumber, bidAmount, NewDecayWindow(time.Now(), 36*time.Second))
 
-    // Define the hard-coded legitimate transaction hash
-    transactionHash := "0xae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"
+	// Assert that all expectations were met
+	mockBidder.AssertExpectations(t)
+	mockSendBidClient.AssertExpectations(t)
+}
 
-    // Expected input and parameters
-    expectedInput := []string{strings.TrimPrefix(transactionHash, "0x")} // "ae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"
-    expectedAmount := bidAmountInWei
-    expectedBlockNumber := int64(100)
+func TestSendPreconfBidWeiAboveInt64(t *testing.T) {
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
 
-    // Setup expectations for SendBid
-    mockBidder.On("SendBid",
-        expectedInput,
-        expectedAmount,
-        expectedBlockNumber,
-        mock.AnythingOfType("int64"), // decayStart
-        mock.AnythingOfType("int64"), // decayEnd
-    ).Return(mockSendBidClient, nil)
+	// 12.5 ETH doesn't fit an int64 of wei
+	amountWei, _ := new(big.Int).SetString("12500000000000000001", 10)
+	require.Equal(t, "12500000000000000000", EthToWei(12.5).String())
 
-    // Setup expectations for Recv to return io.EOF (indicating end of response stream)
-    mockSendBidClient.On("Recv").Return(nil, io.EOF)
+	mockBidder.On("SendBid", mock.Anything, "12500000000000000001", int64(100), mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(mockSendBidClient, nil)
+	mockSendBidClient.On("Recv").Return(nil, io.EOF)
 
-    // Call SendPreconfBid with the transaction hash, block number, and bid amount
-    SendPreconfBid(mockBidder, transactionHash, expectedBlockNumber, bidAmount)
+	SendPreconfBidWei(mockBidder, "0xae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2", 100, amountWei, NewDecayWindow(time.Now(), 36*time.Second))
 
-    // Assert that all expectations were met
-    mockBidder.AssertExpectations(t)
-    mockSendBidClient.AssertExpectations(t)
+	mockBidder.AssertExpectations(t)
+	mockSendBidClient.AssertExpectations(t)
 }
 
 func TestUnsupportedInputType(t *testing.T) {
-    // Initialize the mock Bidder client
-    mockBidder := new(MockBidderClient)
+	// Initialize the mock Bidder client
+	mockBidder := new(MockBidderClient)
 
-    // No expectations set because SendBid should not be called
+	// No expectations set because SendBid should not be called
 
-    // Call SendPreconfBid with an unsupported input type
-    SendPreconfBid(mockBidder, 12345, 100, 1.0)
+	// Call SendPreconfBid with an unsupported input type
+	SendPreconfBid(mockBidder, 12345, 100, 1.0, NewDecayWindow(time.Now(), 36*time.Second))
 
-    // Assert that SendBid was not called
-    mockBidder.AssertNotCalled(t, "SendBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	// Assert that SendBid was not called
+	mockBidder.AssertNotCalled(t, "SendBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestSendBidWithTxHashes(t *testing.T) {
-    // Initialize the mock Bidder client
-    mockBidder := new(MockBidderClient)
-    mockSendBidClient := new(MockBidderSendBidClient)
+	// Initialize the mock Bidder client
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
 
 	// Setup parameters for SendBid with txHashes
 	transactionHashes := []string{"0x1234567890abcdef", "0xfedcba0987654321"}
@@ -168,88 +185,133 @@ func TestSendBidWithTxHashes(t *testing.T) {
 	mockSendBidClient.AssertExpectations(t)
 }
 func TestSendBidUnsupportedInputType(t *testing.T) {
-    // Initialize the mock Bidder client and BidderSendBidClient
-    mockBidder := new(MockBidderClient)
-    mockSendBidClient := new(MockBidderSendBidClient)
+	// Initialize the mock Bidder client and BidderSendBidClient
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
 
-    // Set up SendBid mock to return mockSendBidClient with an error
-    mockBidder.On("SendBid", mock.AnythingOfType("int"), mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-        Return(mockSendBidClient, errors.New("unsupported input type"))
+	// Set up SendBid mock to return mockSendBidClient with an error
+	mockBidder.On("SendBid", mock.AnythingOfType("int"), mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(mockSendBidClient, errors.New("unsupported input type"))
 
-    // Call SendBid with unsupported input type and verify the error
-    unsupportedInput := 12345
-    _, err := mockBidder.SendBid(unsupportedInput, "1000000000000000000", 100, 1000, 2000)
+	// Call SendBid with unsupported input type and verify the error
+	unsupportedInput := 12345
+	_, err := mockBidder.SendBid(unsupportedInput, "1000000000000000000", 100, 1000, 2000)
 
-    require.Error(t, err)
-    require.Contains(t, err.Error(), "unsupported input type")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported input type")
 }
 
-
 func TestSendBidWithRawTransactions(t *testing.T) {
-    // Initialize the mock Bidder client and SendBid client
-    mockBidder := new(MockBidderClient)
-    mockSendBidClient := new(MockBidderSendBidClient)
+	// Initialize the mock Bidder client and SendBid client
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
 
-    t.Run("TestSendBidWithRawTransactions", func(t *testing.T) {
-        expectedAmount := "1000000000000000000" // Example amount in wei
-        expectedBlockNumber := int64(100)
-        decayStart := int64(1000)
-        decayEnd := int64(2000)
+	t.Run("TestSendBidWithRawTransactions", func(t *testing.T) {
+		expectedAmount := "1000000000000000000" // Example amount in wei
+		expectedBlockNumber := int64(100)
+		decayStart := int64(1000)
+		decayEnd := int64(2000)
 
-        // Use *types.Transaction instead of MockTransaction to match SendBid function signature
-        tx := new(types.Transaction)
+		// Use *types.Transaction instead of MockTransaction to match SendBid function signature
+		tx := new(types.Transaction)
 
-        // Log to track the start of the test
-        t.Log("Starting TestSendBidWithRawTransactions")
+		// Log to track the start of the test
+		t.Log("Starting TestSendBidWithRawTransactions")
 
-        // Set up expectation for SendBid to return mockSendBidClient and a marshalling error
-        mockBidder.On("SendBid", mock.Anything, expectedAmount, expectedBlockNumber, decayStart, decayEnd).
-            Return(mockSendBidClient, errors.New("mock marshalling error")).Once()
+		// Set up expectation for SendBid to return mockSendBidClient and a marshalling error
+		mockBidder.On("SendBid", mock.Anything, expectedAmount, expectedBlockNumber, decayStart, decayEnd).
+			Return(mockSendBidClient, errors.New("mock marshalling error")).Once()
 
-        // Call SendBid with []*types.Transaction input
-        _, err := mockBidder.SendBid([]*types.Transaction{tx}, expectedAmount, expectedBlockNumber, decayStart, decayEnd)
+		// Call SendBid with []*types.Transaction input
+		_, err := mockBidder.SendBid([]*types.Transaction{tx}, expectedAmount, expectedBlockNumber, decayStart, decayEnd)
 
-        // Validate the error and log result
-        require.Error(t, err, "Expected an error due to mock marshalling error")
-        require.Contains(t, err.Error(), "mock marshalling error", "Error message should contain 'mock marshalling error'")
+		// Validate the error and log result
+		require.Error(t, err, "Expected an error due to mock marshalling error")
+		require.Contains(t, err.Error(), "mock marshalling error", "Error message should contain 'mock marshalling error'")
 
-        // Verify expectations
-        mockBidder.AssertExpectations(t)
+		// Verify expectations
+		mockBidder.AssertExpectations(t)
 
-        t.Log("TestSendBidWithRawTransactions completed")
-    })
+		t.Log("TestSendBidWithRawTransactions completed")
+	})
 }
 
 func TestSendBidSuccess(t *testing.T) {
-    mockBidder := new(MockBidderClient)
-    mockSendBidClient := new(MockBidderSendBidClient)
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
+
+	txHashes := []string{"0xabc123", "0xdef456"}
+	expectedAmount := "1000000000000000000"
+	expectedBlockNumber := int64(100)
+	decayStart := int64(1000)
+	decayEnd := int64(2000)
 
-    txHashes := []string{"0xabc123", "0xdef456"}
-    expectedAmount := "1000000000000000000"
-    expectedBlockNumber := int64(100)
-    decayStart := int64(1000)
-    decayEnd := int64(2000)
+	mockBidder.On("SendBid", mock.Anything, expectedAmount, expectedBlockNumber, decayStart, decayEnd).
+		Return(mockSendBidClient, nil).Once()
 
-    mockBidder.On("SendBid", mock.Anything, expectedAmount, expectedBlockNumber, decayStart, decayEnd).
-        Return(mockSendBidClient, nil).Once()
+	_, err := mockBidder.SendBid(txHashes, expectedAmount, expectedBlockNumber, decayStart, decayEnd)
 
-    _, err := mockBidder.SendBid(txHashes, expectedAmount, expectedBlockNumber, decayStart, decayEnd)
+	require.NoError(t, err, "Expected no error for successful bid")
+	mockBidder.AssertExpectations(t)
+}
+
+func TestSendBidRequestError(t *testing.T) {
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
 
-    require.NoError(t, err, "Expected no error for successful bid")
-    mockBidder.AssertExpectations(t)
+	// Provide the mockSendBidClient instead of nil
+	mockBidder.On("SendBid", mock.Anything, "1000000000000000000", int64(100), int64(1000), int64(2000)).
+		Return(mockSendBidClient, errors.New("mock send bid error"))
+
+	_, err := mockBidder.SendBid([]string{"0xabc123"}, "1000000000000000000", 100, 1000, 2000)
+
+	require.Error(t, err, "Expected an error due to mock send bid error")
+	require.Contains(t, err.Error(), "mock send bid error", "Error message should contain 'mock send bid error'")
 }
 
+func TestSendPreconfBidBundle(t *testing.T) {
+	mockBidder := new(MockBidderClient)
+	mockSendBidClient := new(MockBidderSendBidClient)
 
-func TestSendBidRequestError(t *testing.T) {
-    mockBidder := new(MockBidderClient)
-    mockSendBidClient := new(MockBidderSendBidClient)
+	// Every hash of the bundle goes out in a single bid
+	hashes := []string{"0x1234567890abcdef", "0xfedcba0987654321", "0x0011223344556677"}
+	mockBidder.On("SendBid", []string{"1234567890abcdef", "fedcba0987654321", "0011223344556677"}, "1000000000000000000", int64(100),
+		mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(mockSendBidClient, nil).Once()
+	mockSendBidClient.On("Recv").Return(nil, io.EOF)
 
-    // Provide the mockSendBidClient instead of nil
-    mockBidder.On("SendBid", mock.Anything, "1000000000000000000", int64(100), int64(1000), int64(2000)).
-        Return(mockSendBidClient, errors.New("mock send bid error"))
+	require.NoError(t, SendPreconfBid(mockBidder, hashes, 100, 1.0, NewDecayWindow(time.Now(), 36*time.Second)))
+	mockBidder.AssertExpectations(t)
+
+	// A bundle with a missing transaction isn't sent
+	require.Error(t, SendPreconfBid(mockBidder, []*types.Transaction{nil}, 100, 1.0, NewDecayWindow(time.Now(), 36*time.Second)))
+	mockBidder.AssertNumberOfCalls(t, "SendBid", 1)
+}
 
-    _, err := mockBidder.SendBid([]string{"0xabc123"}, "1000000000000000000", 100, 1000, 2000)
+func TestBidResultProviders(t *testing.T) {
+	require.Zero(t, BidResult{}.Providers())
+
+	// A provider committing twice counts once, whatever the address case
+	result := BidResult{Commitments: []*pb.Commitment{
+		{ProviderAddress: "0xAbC0000000000000000000000000000000000001"},
+		{ProviderAddress: "0xabc0000000000000000000000000000000000001"},
+		{ProviderAddress: "0x0000000000000000000000000000000000000002"},
+	}}
+	require.True(t, result.Won())
+	require.Equal(t, 2, result.Providers())
+}
+
+func TestClassifyBidSilentRejection(t *testing.T) {
+	// The node takes the bid and ends its stream without a commitment or an error
+	mockBidder := new(MockBidderClient)
+	emptyStream := new(MockBidderSendBidClient)
+	mockBidder.On("SendBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(emptyStream, nil)
+	emptyStream.On("Recv").Return(nil, io.EOF)
+
+	result, err := SendPreconfBidWithRetry(context.Background(), mockBidder, "0xabc", 100, big.NewInt(1), DecayWindow{Start: 1, End: 2}, BidRetryPolicy{})
+	require.NoError(t, err)
+	require.Equal(t, BidSilentRejection, ClassifyBid(result, err))
 
-    require.Error(t, err, "Expected an error due to mock send bid error")
-    require.Contains(t, err.Error(), "mock send bid error", "Error message should contain 'mock send bid error'")
+	// A commitment or an explicit failure isn't a silent rejection
+	require.Equal(t, BidCommitted, ClassifyBid(BidResult{Commitments: []*pb.Commitment{{}}}, nil))
+	require.Equal(t, BidFailed, ClassifyBid(BidResult{}, errors.New("stream reset")))
 }
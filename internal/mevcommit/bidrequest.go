@@ -0,0 +1,57 @@
+package mevcommit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+)
+
+// BidRequest holds every field of a mev-commit bid. Each field maps onto the
+// pb.Bid field of the same name, and TestBidRequestCoversProto fails when the
+// bidder API gains a field that isn't mapped here.
+type BidRequest struct {
+	TxHashes            []string // Hashes of the bundle's transactions, without 0x, for hash-only bids.
+	RawTransactions     []string // Hex-encoded signed transactions, for payload bids.
+	Amount              string   // Bid amount in wei.
+	BlockNumber         int64    // Target L1 block.
+	DecayStartTimestamp int64    // Unix milliseconds at which the bid starts to decay.
+	DecayEndTimestamp   int64    // Unix milliseconds at which the bid has fully decayed.
+	RevertingTxHashes   []string // Hashes of the bundle's transactions that are allowed to revert.
+}
+
+// Proto converts the request to the bidder API message.
+func (r *BidRequest) Proto() *pb.Bid {
+	return &pb.Bid{
+		TxHashes:            r.TxHashes,
+		RawTransactions:     r.RawTransactions,
+		Amount:              r.Amount,
+		BlockNumber:         r.BlockNumber,
+		DecayStartTimestamp: r.DecayStartTimestamp,
+		DecayEndTimestamp:   r.DecayEndTimestamp,
+		RevertingTxHashes:   r.RevertingTxHashes,
+	}
+}
+
+// bundleTxHashes returns the hashes of the request's transactions, decoding raw
+// transactions for payload bids.
+func (r *BidRequest) bundleTxHashes() ([]string, error) {
+	if len(r.RawTransactions) == 0 {
+		return r.TxHashes, nil
+	}
+	hashes := make([]string, len(r.RawTransactions))
+	for i, rawTx := range r.RawTransactions {
+		data, err := hex.DecodeString(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid raw transaction %d: %w", i, err)
+		}
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("invalid raw transaction %d: %w", i, err)
+		}
+		hashes[i] = strings.TrimPrefix(tx.Hash().Hex(), "0x")
+	}
+	return hashes, nil
+}
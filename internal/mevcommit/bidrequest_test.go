@@ -0,0 +1,105 @@
+package mevcommit
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBidRequestCoversProto fails when the bidder API's Bid message has a field
+// that BidRequest doesn't map, so that dependency updates surface new options.
+func TestBidRequestCoversProto(t *testing.T) {
+	var request BidRequest
+	v := reflect.ValueOf(&request).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			f.SetString("aa")
+		case reflect.Int64:
+			f.SetInt(1)
+		case reflect.Slice:
+			f.Set(reflect.ValueOf([]string{"aa"}))
+		default:
+			t.Fatalf("unhandled BidRequest field kind %s", f.Kind())
+		}
+	}
+
+	msg := request.Proto().ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		require.True(t, msg.Has(fd), "bidder API field %s is not mapped by BidRequest", fd.Name())
+	}
+	require.Equal(t, fields.Len(), v.NumField(), "BidRequest has fields the bidder API doesn't")
+}
+
+func TestAllowRevertsMarksBidTransactions(t *testing.T) {
+	var mu sync.Mutex
+	var received []*pb.Bid
+	fake, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{OnBid: func(bid *pb.Bid) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, bid)
+	}})
+	require.NoError(t, err)
+	defer fake.Close()
+
+	tx := signSlowly(t, 0).Tx
+	hash := strings.TrimPrefix(tx.Hash().Hex(), "0x")
+
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: fake.Addr(), AllowReverts: true})
+	require.NoError(t, err)
+	defer bidder.Close()
+	window := DecayWindow{Start: 1000, End: 2000}
+	SendPreconfBid(bidder, tx, 100, 0.001, window)
+	SendPreconfBid(bidder, tx.Hash().Hex(), 100, 0.001, window)
+
+	plain, err := NewBidderClient(BidderConfig{ServerAddress: fake.Addr()})
+	require.NoError(t, err)
+	defer plain.Close()
+	SendPreconfBid(plain, tx, 100, 0.001, window)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+	require.Equal(t, []string{hash}, received[0].RevertingTxHashes)
+	require.Len(t, received[0].RawTransactions, 1)
+	require.Equal(t, []string{hash}, received[1].RevertingTxHashes)
+	require.Equal(t, []string{hash}, received[1].TxHashes)
+	require.Empty(t, received[2].RevertingTxHashes)
+}
+
+func TestSendPreconfBidReturnsCommitments(t *testing.T) {
+	window := DecayWindow{Start: 1000, End: 2000}
+	hash := "0xae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"
+
+	// Every commitment of every provider reaches the caller
+	fake, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{CommitProbability: 1, Providers: 2})
+	require.NoError(t, err)
+	defer fake.Close()
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: fake.Addr()})
+	require.NoError(t, err)
+	defer bidder.Close()
+	result, err := SendPreconfBidWithRetry(context.Background(), bidder, hash, 100, big.NewInt(1000), window, BidRetryPolicy{})
+	require.NoError(t, err)
+	require.True(t, result.Won())
+	require.Len(t, result.Commitments, 2)
+
+	// A bid no provider commits to is lost
+	silent, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{CommitProbability: 0})
+	require.NoError(t, err)
+	defer silent.Close()
+	lost, err := NewBidderClient(BidderConfig{ServerAddress: silent.Addr()})
+	require.NoError(t, err)
+	defer lost.Close()
+	result, err = SendPreconfBidWithRetry(context.Background(), lost, hash, 100, big.NewInt(1000), window, BidRetryPolicy{})
+	require.NoError(t, err)
+	require.False(t, result.Won())
+}
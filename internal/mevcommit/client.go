@@ -6,9 +6,13 @@ package mevcommit
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math"
+	"math/rand"
+	"net"
+	"strconv"
 	"time"
 
 	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
@@ -21,19 +25,31 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 // BidderConfig holds the configuration settings for the mev-commit bidder node.
 type BidderConfig struct {
-	ServerAddress string `json:"server_address" yaml:"server_address"` // The address of the gRPC server for the bidder node.
-	LogFmt        string `json:"log_fmt" yaml:"log_fmt"`               // The format for logging output.
-	LogLevel      string `json:"log_level" yaml:"log_level"`           // The level of logging detail.
+	ServerAddress   string `json:"server_address" yaml:"server_address"`     // The address of the gRPC server for the bidder node.
+	LogFmt          string `json:"log_fmt" yaml:"log_fmt"`                   // The format for logging output.
+	LogLevel        string `json:"log_level" yaml:"log_level"`               // The level of logging detail.
+	CommitmentsFile string `json:"commitments_file" yaml:"commitments_file"` // Optional JSONL file that received commitments are appended to.
+	TLS             bool   `json:"tls" yaml:"tls"`                           // Connect to the bidder node over TLS, verifying its certificate.
+	TLSCAFile       string `json:"tls_ca_file" yaml:"tls_ca_file"`           // Optional PEM CA bundle to verify the certificate with instead of the system roots.
+	AllowReverts    bool   `json:"allow_reverts" yaml:"allow_reverts"`       // Mark each bid's transactions as allowed to revert.
+
+	Logger *slog.Logger `json:"-" yaml:"-"` // Writes the client's entries, slog.Default() if nil.
 }
 
 // Bidder utilizes the mev-commit bidder client to interact with the mev-commit chain.
 type Bidder struct {
-	client pb.BidderClient // gRPC client for interacting with the mev-commit bidder service.
+	client   pb.BidderClient     // gRPC client for interacting with the mev-commit bidder service.
+	recorder *CommitmentRecorder // Records the full commitment objects received for bids.
+
+	allowReverts bool                 // Whether bids mark their transactions as allowed to revert.
+	onCommitment func(*pb.Commitment) // Optional observer of received commitments.
+	logger       *slog.Logger         // Writes the client's entries, slog.Default() if nil.
 }
 
 // GethConfig holds configuration settings for a Geth node to connect to the mev-commit chain.
@@ -49,6 +65,31 @@ type AuthAcct struct {
 	Auth       *bind.TransactOpts // The transaction options for signing transactions.
 }
 
+// ErrMalformedAddress is returned for a bidder address that isn't a host and port.
+var ErrMalformedAddress = errors.New("malformed bidder address")
+
+// ValidateServerAddress checks that a bidder address is a host:port with a port
+// between 1 and 65535, so that a typo fails at startup instead of on the first bid.
+//
+// Parameters:
+// - addr: The address, such as localhost:13524.
+//
+// Returns:
+// - An error wrapping ErrMalformedAddress if the address can't be dialed as given.
+func ValidateServerAddress(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrMalformedAddress, addr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("%w %q: missing host", ErrMalformedAddress, addr)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("%w %q: port %q is not between 1 and 65535", ErrMalformedAddress, addr, port)
+	}
+	return nil
+}
+
 // NewBidderClient creates a new gRPC client connection to the bidder service and returns a Bidder instance.
 //
 // Parameters:
@@ -57,19 +98,68 @@ type AuthAcct struct {
 // Returns:
 // - A pointer to a Bidder struct, or an error if the connection fails.
 func NewBidderClient(cfg BidderConfig) (*Bidder, error) {
+	if err := ValidateServerAddress(cfg.ServerAddress); err != nil {
+		return nil, err
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	creds, err := transportCredentials(cfg)
+	if err != nil {
+		logger.Error("Failed to load bidder TLS configuration",
+			"error", err,
+			"tls_ca_file", cfg.TLSCAFile,
+		)
+		return nil, err
+	}
+
 	// Establish a gRPC connection to the bidder service
-	conn, err := grpc.NewClient(cfg.ServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(cfg.ServerAddress, grpc.WithTransportCredentials(creds))
 	if err != nil {
-		slog.Error("Failed to connect to gRPC server",
+		logger.Error("Failed to connect to gRPC server",
 			"error", err,
 			"server_address", cfg.ServerAddress,
 		)
 		return nil, err
 	}
 
+	recorder, err := NewCommitmentRecorder(cfg.CommitmentsFile)
+	if err != nil {
+		return nil, err
+	}
+	recorder.logger = logger
+
 	// Create a new bidder client using the gRPC connection
 	client := pb.NewBidderClient(conn)
-	return &Bidder{client: client}, nil
+	return &Bidder{client: client, recorder: recorder, allowReverts: cfg.AllowReverts, logger: logger}, nil
+}
+
+// Logger returns the logger the client writes its entries to.
+func (b *Bidder) Logger() *slog.Logger {
+	if b.logger == nil {
+		return slog.Default()
+	}
+	return b.logger
+}
+
+// transportCredentials returns TLS credentials when cfg enables TLS, and insecure ones otherwise.
+func transportCredentials(cfg BidderConfig) (credentials.TransportCredentials, error) {
+	if !cfg.TLS {
+		return insecure.NewCredentials(), nil
+	}
+	if cfg.TLSCAFile == "" {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+	}
+	return credentials.NewClientTLSFromFile(cfg.TLSCAFile, "")
+}
+
+// Close flushes and closes the resources held by the Bidder.
+func (b *Bidder) Close() error {
+	if b.recorder != nil {
+		return b.recorder.Close()
+	}
+	return nil
 }
 
 // NewGethClient connects to an Ethereum-compatible chain using the provided RPC endpoint.
@@ -125,7 +215,19 @@ func AuthenticateAddress(privateKeyHex string, client *ethclient.Client) (AuthAc
 		)
 		return AuthAcct{}, err
 	}
+	return AuthenticateKey(privateKey, client)
+}
 
+// AuthenticateKey builds the AuthAcct for a private key, such as one loaded with
+// LoadKeystore.
+//
+// Parameters:
+// - privateKey: The account's private key.
+// - client: The ethclient.Client to fetch the chain ID from.
+//
+// Returns:
+// - An AuthAcct struct, or an error if authentication fails.
+func AuthenticateKey(privateKey *ecdsa.PrivateKey, client *ethclient.Client) (AuthAcct, error) {
 	// Extract the public key from the private key
 	publicKey := privateKey.Public()
 	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
@@ -171,7 +273,33 @@ func AuthenticateAddress(privateKeyHex string, client *ethclient.Client) (AuthAc
 	}, nil
 }
 
-// ConnectRPCClientWithRetries attempts to connect to the RPC client with retries and exponential backoff.
+const (
+	rpcReconnectBackoff    = 10 * time.Second // Wait after the first failed attempt, doubling after each failure.
+	rpcReconnectMaxBackoff = 2 * time.Minute  // Ceiling of the doubled wait.
+)
+
+// rpcReconnectDelay returns the wait after failed attempt i, counted from 0:
+// rpcReconnectBackoff doubled i times and capped at rpcReconnectMaxBackoff,
+// of which a random second half is dropped so instances restarted together
+// don't reconnect in step.
+//
+// Parameters:
+// - attempt: The number of the failed attempt, from 0.
+// - random: Returns a random number in [0, 1), such as rand.Float64.
+//
+// Returns:
+// - The wait, in [backoff/2, backoff).
+func rpcReconnectDelay(attempt int, random func() float64) time.Duration {
+	backoff := rpcReconnectMaxBackoff
+	if attempt < 8 && rpcReconnectBackoff<<attempt < rpcReconnectMaxBackoff {
+		backoff = rpcReconnectBackoff << attempt
+	}
+	half := backoff / 2
+	return half + time.Duration(random()*float64(half))
+}
+
+// ConnectRPCClientWithRetries attempts to connect to the RPC client with retries and
+// exponential backoff, capped and jittered by rpcReconnectDelay.
 //
 // Parameters:
 // - rpcEndpoint: The RPC endpoint to connect to.
@@ -186,9 +314,8 @@ func ConnectRPCClientWithRetries(rpcEndpoint string, maxRetries int, timeout tim
 
 	for i := 0; i < maxRetries; i++ {
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-
 		rpcClient, err = ethclient.DialContext(ctx, rpcEndpoint)
+		cancel()
 		if err == nil {
 			slog.Info("Successfully connected to RPC client",
 				"rpc_endpoint", MaskEndpoint(rpcEndpoint),
@@ -197,12 +324,17 @@ func ConnectRPCClientWithRetries(rpcEndpoint string, maxRetries int, timeout tim
 			return rpcClient
 		}
 
+		if i == maxRetries-1 {
+			break
+		}
+		delay := rpcReconnectDelay(i, rand.Float64)
 		slog.Warn("Failed to connect to RPC client, retrying...",
 			"error", err,
 			"rpc_endpoint", MaskEndpoint(rpcEndpoint),
 			"attempt", i+1,
+			"retryIn", delay.String(),
 		)
-		time.Sleep(10 * time.Duration(math.Pow(2, float64(i))) * time.Second) // Exponential backoff
+		time.Sleep(delay)
 	}
 
 	slog.Error("Failed to connect to RPC client after maximum retries",
@@ -213,73 +345,74 @@ func ConnectRPCClientWithRetries(rpcEndpoint string, maxRetries int, timeout tim
 	return nil
 }
 
-// ConnectWSClient attempts to connect to the WebSocket client with continuous retries.
+// ConnectWSClient attempts to connect to one of the WebSocket endpoints with
+// continuous retries, trying every endpoint round robin before each wait.
 //
 // Parameters:
-// - wsEndpoint: The WebSocket endpoint to connect to.
+// - endpoints: The WebSocket endpoints to connect to.
 //
 // Returns:
 // - A pointer to an ethclient.Client if successful, or an error if unable to connect.
-func ConnectWSClient(wsEndpoint string) (*ethclient.Client, error) {
+func ConnectWSClient(endpoints *WSEndpoints) (*ethclient.Client, error) {
 	for {
-		wsClient, err := NewGethClient(wsEndpoint)
+		wsClient, err := endpoints.Dial(context.Background())
 		if err == nil {
 			return wsClient, nil
 		}
-		slog.Warn("Failed to connect to WebSocket client, retrying in 10 seconds...",
+		endpoints.logger.Warn("Failed to connect to any WebSocket endpoint, retrying in 10 seconds...",
 			"error", err,
-			"ws_endpoint", MaskEndpoint(wsEndpoint),
+			"endpoints", len(endpoints.endpoints),
 		)
 		time.Sleep(10 * time.Second)
 	}
 }
 
-// ReconnectWSClient attempts to reconnect to the WebSocket client with limited retries.
+// ReconnectWSClient attempts to reconnect to one of the WebSocket endpoints with
+// limited retries, starting with the endpoint after the one that failed.
 //
 // Parameters:
-// - wsEndpoint: The WebSocket endpoint to reconnect to.
+// - endpoints: The WebSocket endpoints to reconnect to.
 // - headers: The channel to subscribe to new headers.
 //
 // Returns:
 // - A pointer to an ethclient.Client and an ethereum.Subscription if successful, or nil values if all retries fail.
-func ReconnectWSClient(wsEndpoint string, headers chan *types.Header) (*ethclient.Client, ethereum.Subscription) {
+func ReconnectWSClient(endpoints *WSEndpoints, headers chan *types.Header) (*ethclient.Client, ethereum.Subscription) {
+	logger := endpoints.logger
 	var wsClient *ethclient.Client
 	var sub ethereum.Subscription
 	var err error
 
 	for i := 0; i < 10; i++ { // Retry logic for WebSocket connection
-		wsClient, err = ConnectWSClient(wsEndpoint)
+		wsClient, err = endpoints.Dial(context.Background())
 		if err == nil {
-			slog.Info("WebSocket client reconnected",
-				"ws_endpoint", MaskEndpoint(wsEndpoint),
+			logger.Info("WebSocket client reconnected",
+				"ws_endpoint", MaskEndpoint(endpoints.Active()),
 				"attempt", i+1,
 			)
 
 			// Create a context with a 15-second timeout for the subscription
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-			defer cancel()
-
 			sub, err = wsClient.SubscribeNewHead(ctx, headers)
+			cancel()
 			if err == nil {
 				return wsClient, sub
 			}
+			wsClient.Close()
 
-			slog.Warn("Failed to subscribe to new headers after reconnecting",
+			logger.Warn("Failed to subscribe to new headers after reconnecting",
 				"error", err,
 			)
 		}
 
-		slog.Warn("Failed to reconnect WebSocket client, retrying in 5 seconds...",
+		logger.Warn("Failed to reconnect WebSocket client, retrying in 5 seconds...",
 			"error", err,
-			"ws_endpoint", MaskEndpoint(wsEndpoint),
 			"attempt", i+1,
 		)
 		time.Sleep(5 * time.Second)
 	}
 
-	slog.Error("Failed to reconnect WebSocket client after maximum retries",
+	logger.Error("Failed to reconnect WebSocket client after maximum retries",
 		"error", err,
-		"ws_endpoint", MaskEndpoint(wsEndpoint),
 		"max_retries", 10,
 	)
 	return nil, nil
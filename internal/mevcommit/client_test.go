@@ -0,0 +1,41 @@
+package mevcommit
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateServerAddress(t *testing.T) {
+	for _, addr := range []string{"localhost:13524", "mev-commit-bidder:13524", "10.0.0.1:1", "[::1]:65535"} {
+		require.NoError(t, ValidateServerAddress(addr), addr)
+	}
+	for _, addr := range []string{"", "localhost", "mev-commit-bidder:", ":13524", "localhost:0", "localhost:65536", "localhost:port", "http://localhost:13524"} {
+		require.ErrorIs(t, ValidateServerAddress(addr), ErrMalformedAddress, addr)
+	}
+
+	_, err := NewBidderClient(BidderConfig{ServerAddress: "localhost"})
+	require.ErrorIs(t, err, ErrMalformedAddress)
+}
+
+func TestRPCReconnectDelay(t *testing.T) {
+	low := func() float64 { return 0 }
+	high := func() float64 { return 0.999 }
+	for attempt, backoff := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 2 * time.Minute, 2 * time.Minute} {
+		require.Equal(t, backoff/2, rpcReconnectDelay(attempt, low), attempt)
+		delay := rpcReconnectDelay(attempt, high)
+		require.Greater(t, delay, backoff*99/100, attempt)
+		require.Less(t, delay, backoff, attempt)
+	}
+	// Large attempt counts stay at the cap rather than overflowing
+	require.Equal(t, time.Minute, rpcReconnectDelay(100, low))
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		delay := rpcReconnectDelay(2, rng.Float64)
+		require.GreaterOrEqual(t, delay, 20*time.Second)
+		require.Less(t, delay, 40*time.Second)
+	}
+}
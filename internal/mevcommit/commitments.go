@@ -0,0 +1,128 @@
+package mevcommit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// CommitmentRecorder logs every received commitment as a nested structured object and,
+// when configured with a file path, appends each one as a line to a JSONL file.
+type CommitmentRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	logger  *slog.Logger // Writes the recorded commitments, slog.Default() if nil.
+}
+
+// NewCommitmentRecorder creates a CommitmentRecorder.
+//
+// Parameters:
+// - filePath: The JSONL file to append commitments to, or empty to only log them.
+//
+// Returns:
+// - A pointer to a CommitmentRecorder, or an error if the file cannot be opened.
+func NewCommitmentRecorder(filePath string) (*CommitmentRecorder, error) {
+	r := &CommitmentRecorder{}
+	if filePath == "" {
+		return r, nil
+	}
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		slog.Error("Failed to open commitments file",
+			"err", err,
+			"file_path", filePath,
+		)
+		return nil, fmt.Errorf("failed to open commitments file: %w", err)
+	}
+	r.file = file
+	r.encoder = json.NewEncoder(file)
+	return r, nil
+}
+
+// Record logs the full commitment and appends it to the commitments file if configured.
+func (r *CommitmentRecorder) Record(commitment *pb.Commitment) error {
+	fields := commitmentFields(commitment)
+
+	r.log().Info("Bid accepted",
+		"commitment", fields,
+	)
+
+	if r.file == nil {
+		return nil
+	}
+
+	record := api.CommitmentRecord{
+		SchemaVersion: api.CommitmentSchemaVersion,
+		ReceivedAt:    time.Now().UnixMilli(),
+		Commitment:    fields,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.encoder.Encode(record); err != nil {
+		r.log().Error("Failed to write commitment record",
+			"err", err,
+		)
+		return fmt.Errorf("failed to write commitment record: %w", err)
+	}
+	return nil
+}
+
+// log returns the recorder's logger.
+func (r *CommitmentRecorder) log() *slog.Logger {
+	if r.logger == nil {
+		return slog.Default()
+	}
+	return r.logger
+}
+
+// Close syncs and closes the commitments file if one was opened.
+func (r *CommitmentRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	syncErr := r.file.Sync()
+	err := r.file.Close()
+	r.file = nil
+	if err == nil {
+		err = syncErr
+	}
+	return err
+}
+
+// commitmentFields converts a commitment into its versioned record form, which holds
+// every field defined by the API keyed by its protobuf field name.
+func commitmentFields(commitment *pb.Commitment) api.Commitment {
+	return api.Commitment{
+		TxHashes:             nonNil(commitment.GetTxHashes()),
+		BidAmount:            commitment.GetBidAmount(),
+		BlockNumber:          commitment.GetBlockNumber(),
+		ReceivedBidDigest:    commitment.GetReceivedBidDigest(),
+		ReceivedBidSignature: commitment.GetReceivedBidSignature(),
+		CommitmentDigest:     commitment.GetCommitmentDigest(),
+		CommitmentSignature:  commitment.GetCommitmentSignature(),
+		ProviderAddress:      commitment.GetProviderAddress(),
+		DecayStartTimestamp:  commitment.GetDecayStartTimestamp(),
+		DecayEndTimestamp:    commitment.GetDecayEndTimestamp(),
+		DispatchTimestamp:    commitment.GetDispatchTimestamp(),
+		RevertingTxHashes:    nonNil(commitment.GetRevertingTxHashes()),
+	}
+}
+
+// nonNil returns an empty slice for nil so it is written as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package mevcommit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitmentRecorderRecordsAllFields(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "commitments.jsonl")
+	recorder, err := NewCommitmentRecorder(filePath)
+	require.NoError(t, err)
+
+	commitment := &pb.Commitment{
+		TxHashes:             []string{"ae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"},
+		BidAmount:            "1000000000000000",
+		BlockNumber:          100,
+		ReceivedBidDigest:    "bid-digest",
+		ReceivedBidSignature: "bid-signature",
+		CommitmentDigest:     "commitment-digest",
+		CommitmentSignature:  "commitment-signature",
+		ProviderAddress:      "0x0000000000000000000000000000000000000001",
+		DecayStartTimestamp:  1000,
+		DecayEndTimestamp:    2000,
+		DispatchTimestamp:    1500,
+		RevertingTxHashes:    []string{"feed"},
+	}
+
+	// Receive the commitment through the bidder's stream handling
+	stream := new(MockBidderSendBidClient)
+	stream.On("Recv").Return(commitment, nil).Once()
+	stream.On("Recv").Return(nil, io.EOF).Once()
+
+	bidder := &Bidder{recorder: recorder}
+	bidder.receiveBidResponses(stream)
+	require.NoError(t, bidder.Close())
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	var record struct {
+		ReceivedAt int64                  `json:"received_at"`
+		Commitment map[string]interface{} `json:"commitment"`
+	}
+	require.NoError(t, json.Unmarshal(data, &record))
+	require.NotZero(t, record.ReceivedAt)
+
+	// Every field defined by the API must be present in the record
+	fields := commitment.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		require.Contains(t, record.Commitment, string(fields.Get(i).Name()))
+	}
+	require.Equal(t, "commitment-digest", record.Commitment["commitment_digest"])
+	require.Equal(t, "100", record.Commitment["block_number"])
+
+	// The record conforms to the schema of its declared version
+	version, err := api.ValidateRecord("commitment", data)
+	require.NoError(t, err)
+	require.Equal(t, api.CommitmentSchemaVersion, version)
+}
@@ -1,4 +1,4 @@
-// Currently this package is not being used for anything. Leaving in to save the code, but this code has no dependencies on the functionality of the rest of the code. 
+// Currently this package is not being used for anything. Leaving in to save the code, but this code has no dependencies on the functionality of the rest of the code.
 package mevcommit
 
 import (
@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
 )
 
 // Global contract addresses
@@ -267,8 +268,10 @@ func DepositIntoWindow(client *ethclient.Client, depositWindow *big.Int, authAcc
 	// Set the value for the transaction to the minimum deposit amount
 	authAcct.Auth.Value = minDeposit
 
-	// Prepare and send the transaction to deposit into the specific window
-	tx, err := bidderRegistryContract.Transact(authAcct.Auth, "depositForSpecificWindow", depositWindow)
+	// Prepare and send the transaction to deposit into the specific window, signed through the key audit
+	opts := *authAcct.Auth
+	opts.Signer = keyaudit.AuditedSigner(authAcct.Auth.Signer, keyaudit.FeatureDeposit)
+	tx, err := bidderRegistryContract.Transact(&opts, "depositForSpecificWindow", depositWindow)
 	if err != nil {
 		slog.Error("Failed to create deposit transaction",
 			"err", err,
@@ -371,8 +374,10 @@ func WithdrawFromWindow(client *ethclient.Client, authAcct *AuthAcct, window *bi
 	// Bind the contract to the client
 	bidderRegistryContract := bind.NewBoundContract(BidderRegistryAddress, bidderRegistryABI, client, client, client)
 
-	// Prepare the withdrawal transaction
-	withdrawalTx, err := bidderRegistryContract.Transact(authAcct.Auth, "withdrawBidderAmountFromWindow", authAcct.Address, window)
+	// Prepare the withdrawal transaction, signed through the key audit
+	opts := *authAcct.Auth
+	opts.Signer = keyaudit.AuditedSigner(authAcct.Auth.Signer, keyaudit.FeatureWithdraw)
+	withdrawalTx, err := bidderRegistryContract.Transact(&opts, "withdrawBidderAmountFromWindow", authAcct.Address, window)
 	if err != nil {
 		slog.Error("Failed to create withdrawal transaction",
 			"err", err,
@@ -0,0 +1,181 @@
+package mevcommit
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DefaultBlocksPerWindow is the number of blocks in a bidder registry deposit
+// window on mev-commit.
+const DefaultBlocksPerWindow = 10
+
+// depositCacheTTL is how long a window's balance is trusted before it is fetched
+// again, and how long a failed top-up waits before it is tried again.
+const depositCacheTTL = 30 * time.Second
+
+// DepositClient is the part of the bidder API that reads and adds deposits.
+// pb.BidderClient implements it.
+type DepositClient interface {
+	GetDeposit(ctx context.Context, in *pb.GetDepositRequest, opts ...grpc.CallOption) (*pb.DepositResponse, error)
+	Deposit(ctx context.Context, in *pb.DepositRequest, opts ...grpc.CallOption) (*pb.DepositResponse, error)
+}
+
+// Deposits returns the bidder node's deposit API.
+func (b *Bidder) Deposits() DepositClient {
+	return b.client
+}
+
+// WindowOf returns the bidder registry window a block belongs to. Windows are
+// numbered from 1 and hold blocksPerWindow blocks each.
+func WindowOf(block, blocksPerWindow uint64) uint64 {
+	if block == 0 {
+		return 1
+	}
+	return (block-1)/blocksPerWindow + 1
+}
+
+// TopUp is a deposit made by a DepositManager.
+type TopUp struct {
+	Window  uint64
+	Balance *big.Int // The balance before the top-up, in wei.
+	Amount  *big.Int // The amount deposited, in wei.
+}
+
+// windowDeposit is the cached deposit of a window.
+type windowDeposit struct {
+	balance   *big.Int
+	fetchedAt time.Time
+	failedAt  time.Time // When a top-up of the window last failed.
+}
+
+// DepositManager keeps the bidder's deposit in the window of each target block
+// above a minimum, so bids don't fail for lack of funds. A window's balance is
+// fetched once and then lowered by the bids sent, and fetched again only after
+// a while, so headers don't each cost a call to the bidder node.
+type DepositManager struct {
+	client          DepositClient
+	min             *big.Int
+	topUp           *big.Int
+	blocksPerWindow uint64
+	now             func() time.Time
+
+	mu      sync.Mutex
+	windows map[uint64]*windowDeposit
+}
+
+// NewDepositManager creates a DepositManager.
+//
+// Parameters:
+// - client: The bidder node's deposit API.
+// - minWei: The deposit below which a window is topped up.
+// - topUpWei: The amount deposited by each top-up, at least minWei so that a
+// top-up of an empty window is enough.
+// - blocksPerWindow: Blocks per deposit window, DefaultBlocksPerWindow if 0.
+//
+// Returns:
+// - A pointer to a DepositManager, or an error if the amounts make no sense.
+func NewDepositManager(client DepositClient, minWei, topUpWei *big.Int, blocksPerWindow uint64) (*DepositManager, error) {
+	if minWei.Sign() <= 0 || topUpWei.Sign() <= 0 {
+		return nil, fmt.Errorf("minimum deposit and top-up amount must be positive")
+	}
+	if minWei.Cmp(topUpWei) > 0 {
+		return nil, fmt.Errorf("minimum deposit of %s wei is above the top-up amount of %s wei", minWei, topUpWei)
+	}
+	if blocksPerWindow == 0 {
+		blocksPerWindow = DefaultBlocksPerWindow
+	}
+	return &DepositManager{
+		client:          client,
+		min:             new(big.Int).Set(minWei),
+		topUp:           new(big.Int).Set(topUpWei),
+		blocksPerWindow: blocksPerWindow,
+		now:             time.Now,
+		windows:         make(map[uint64]*windowDeposit),
+	}, nil
+}
+
+// Ensure tops up the deposit of the target block's window by the top-up amount
+// if it is below the minimum. Calls are serialized; windows before the target's
+// are forgotten.
+//
+// Parameters:
+// - ctx: The context of the calls to the bidder node.
+// - targetBlock: The block about to be bid on.
+//
+// Returns:
+// - The top-up made, or nil if none was needed or a failed one is waiting to
+// be retried.
+// - An error if the balance couldn't be fetched or the deposit failed.
+func (m *DepositManager) Ensure(ctx context.Context, targetBlock uint64) (*TopUp, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	window := WindowOf(targetBlock, m.blocksPerWindow)
+	for w := range m.windows {
+		if w < window {
+			delete(m.windows, w)
+		}
+	}
+
+	now := m.now()
+	deposit, ok := m.windows[window]
+	if !ok || now.Sub(deposit.fetchedAt) >= depositCacheTTL {
+		resp, err := m.client.GetDeposit(ctx, &pb.GetDepositRequest{WindowNumber: wrapperspb.UInt64(window)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deposit of window %d: %w", window, err)
+		}
+		balance, ok := new(big.Int).SetString(resp.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("bidder node returned invalid deposit %q for window %d", resp.Amount, window)
+		}
+		if deposit == nil {
+			deposit = &windowDeposit{}
+			m.windows[window] = deposit
+		}
+		deposit.balance, deposit.fetchedAt = balance, now
+	}
+	if deposit.balance.Cmp(m.min) >= 0 || now.Sub(deposit.failedAt) < depositCacheTTL {
+		return nil, nil
+	}
+
+	topUp := &TopUp{Window: window, Balance: new(big.Int).Set(deposit.balance), Amount: new(big.Int).Set(m.topUp)}
+	_, err := m.client.Deposit(ctx, &pb.DepositRequest{Amount: m.topUp.String(), WindowNumber: wrapperspb.UInt64(window)})
+	if err != nil {
+		deposit.failedAt = now
+		return nil, fmt.Errorf("failed to deposit %s wei in window %d: %w", m.topUp, window, err)
+	}
+	deposit.balance.Add(deposit.balance, m.topUp)
+	return topUp, nil
+}
+
+// Spend lowers the cached deposit of the target block's window by a bid sent
+// for it, so that the cache follows the funds bids can draw on.
+//
+// Parameters:
+// - targetBlock: The block bid on.
+// - amountWei: The bid amount.
+func (m *DepositManager) Spend(targetBlock uint64, amountWei *big.Int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if deposit, ok := m.windows[WindowOf(targetBlock, m.blocksPerWindow)]; ok && amountWei != nil {
+		deposit.balance.Sub(deposit.balance, amountWei)
+	}
+}
+
+// Balance returns the cached deposit of the target block's window, or nil if it
+// hasn't been fetched.
+func (m *DepositManager) Balance(targetBlock uint64) *big.Int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if deposit, ok := m.windows[WindowOf(targetBlock, m.blocksPerWindow)]; ok {
+		return new(big.Int).Set(deposit.balance)
+	}
+	return nil
+}
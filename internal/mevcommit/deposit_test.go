@@ -0,0 +1,128 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// countingDeposits counts the calls to a DepositClient and can fail deposits.
+type countingDeposits struct {
+	DepositClient
+	gets, deposits int
+	failDeposits   bool
+}
+
+func (c *countingDeposits) GetDeposit(ctx context.Context, in *pb.GetDepositRequest, opts ...grpc.CallOption) (*pb.DepositResponse, error) {
+	c.gets++
+	return c.DepositClient.GetDeposit(ctx, in, opts...)
+}
+
+func (c *countingDeposits) Deposit(ctx context.Context, in *pb.DepositRequest, opts ...grpc.CallOption) (*pb.DepositResponse, error) {
+	c.deposits++
+	if c.failDeposits {
+		return nil, errors.New("auto deposit is enabled")
+	}
+	return c.DepositClient.Deposit(ctx, in, opts...)
+}
+
+// startDeposits serves a fake bidder node and returns its deposit API.
+func startDeposits(t *testing.T) (*fakebidder.Server, *countingDeposits) {
+	t.Helper()
+	fake, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() { fake.Close() })
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: fake.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { bidder.Close() })
+	return fake, &countingDeposits{DepositClient: bidder.Deposits()}
+}
+
+func TestWindowOf(t *testing.T) {
+	require.Equal(t, uint64(1), WindowOf(1, 10))
+	require.Equal(t, uint64(1), WindowOf(10, 10))
+	require.Equal(t, uint64(2), WindowOf(11, 10))
+	require.Equal(t, uint64(11), WindowOf(101, 10))
+}
+
+func TestDepositManagerTopsUpLowWindows(t *testing.T) {
+	fake, client := startDeposits(t)
+	manager, err := NewDepositManager(client, big.NewInt(100), big.NewInt(500), 10)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// An empty window is topped up once
+	topUp, err := manager.Ensure(ctx, 101)
+	require.NoError(t, err)
+	require.Equal(t, &TopUp{Window: 11, Balance: big.NewInt(0), Amount: big.NewInt(500)}, topUp)
+	require.Equal(t, big.NewInt(500), fake.DepositOf(11))
+
+	// Later headers of the window use the cached balance
+	for block := uint64(102); block <= 105; block++ {
+		topUp, err = manager.Ensure(ctx, block)
+		require.NoError(t, err)
+		require.Nil(t, topUp)
+	}
+	require.Equal(t, 1, client.gets)
+
+	// Bids draw the cached balance down until the next top-up
+	manager.Spend(106, big.NewInt(450))
+	require.Equal(t, big.NewInt(50), manager.Balance(106))
+	topUp, err = manager.Ensure(ctx, 106)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50), topUp.Balance)
+	require.Equal(t, big.NewInt(1000), fake.DepositOf(11))
+	require.Equal(t, big.NewInt(550), manager.Balance(106))
+	require.Equal(t, 1, client.gets)
+
+	// The next window starts from the node's balance and the old one is forgotten
+	_, err = manager.Ensure(ctx, 111)
+	require.NoError(t, err)
+	require.Equal(t, 2, client.gets)
+	require.Nil(t, manager.Balance(101))
+	require.Equal(t, big.NewInt(500), fake.DepositOf(12))
+}
+
+func TestDepositManagerRefetchesAndBacksOff(t *testing.T) {
+	_, client := startDeposits(t)
+	client.failDeposits = true
+	manager, err := NewDepositManager(client, big.NewInt(100), big.NewInt(500), 10)
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	manager.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err = manager.Ensure(ctx, 101)
+	require.ErrorContains(t, err, "auto deposit is enabled")
+	require.Equal(t, 1, client.deposits)
+
+	// A failed top-up isn't retried on every header
+	topUp, err := manager.Ensure(ctx, 102)
+	require.NoError(t, err)
+	require.Nil(t, topUp)
+	require.Equal(t, 1, client.deposits)
+	require.Equal(t, 1, client.gets)
+
+	// Once the cache expires the balance is fetched again and the top-up retried
+	now = now.Add(depositCacheTTL)
+	client.failDeposits = false
+	topUp, err = manager.Ensure(ctx, 103)
+	require.NoError(t, err)
+	require.NotNil(t, topUp)
+	require.Equal(t, 2, client.gets)
+	require.Equal(t, 2, client.deposits)
+}
+
+func TestNewDepositManagerRejectsNonsense(t *testing.T) {
+	_, err := NewDepositManager(nil, big.NewInt(500), big.NewInt(100), 10)
+	require.ErrorContains(t, err, "above the top-up amount")
+	_, err = NewDepositManager(nil, big.NewInt(0), big.NewInt(100), 10)
+	require.Error(t, err)
+}
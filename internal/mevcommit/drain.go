@@ -0,0 +1,59 @@
+package mevcommit
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GracefulBidDrain tracks in-flight bids so that shutdown can wait for their
+// SendBid streams to complete instead of cutting off commitments mid-stream.
+type GracefulBidDrain struct {
+	wg       sync.WaitGroup
+	inFlight atomic.Int64
+}
+
+// Go runs fn in a new goroutine and tracks it as an in-flight bid until it returns.
+func (d *GracefulBidDrain) Go(fn func()) {
+	d.wg.Add(1)
+	d.inFlight.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer d.inFlight.Add(-1)
+		fn()
+	}()
+}
+
+// InFlight returns the number of bids that have not completed yet.
+func (d *GracefulBidDrain) InFlight() int64 {
+	return d.inFlight.Load()
+}
+
+// Wait blocks until all in-flight bids complete or the timeout elapses.
+//
+// Parameters:
+// - timeout: The maximum time to wait for in-flight bids.
+//
+// Returns:
+// - true if all bids completed, false if the timeout elapsed first.
+func (d *GracefulBidDrain) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		slog.Info("All in-flight bids drained")
+		return true
+	case <-time.After(timeout):
+		n := d.InFlight()
+		slog.Warn("Forcefully closing in-flight bids",
+			"in_flight", n,
+			"timeout", timeout,
+		)
+		return false
+	}
+}
@@ -0,0 +1,24 @@
+package mevcommit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracefulBidDrainWaitsForInFlightBids(t *testing.T) {
+	drain := &GracefulBidDrain{}
+	release := make(chan struct{})
+
+	drain.Go(func() { <-release })
+	drain.Go(func() { <-release })
+	require.Equal(t, int64(2), drain.InFlight())
+
+	// Bids still running when the timeout elapses are reported as not drained
+	require.False(t, drain.Wait(10*time.Millisecond))
+
+	close(release)
+	require.True(t, drain.Wait(time.Second))
+	require.Equal(t, int64(0), drain.InFlight())
+}
@@ -0,0 +1,45 @@
+package mevcommit
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+)
+
+// DryRunBidder stands in for the bidder node under DRY_RUN. It logs the bids it
+// is given, with the raw encoding of payloads, instead of sending them, and
+// answers each with a stream that ends without a commitment.
+type DryRunBidder struct{}
+
+// SendBid logs the bid that would have been sent.
+func (DryRunBidder) SendBid(input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
+	var txHashes, rawTxs []string
+	switch v := input.(type) {
+	case []string:
+		txHashes = v
+	case []*types.Transaction:
+		for _, tx := range v {
+			raw, err := tx.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+			}
+			txHashes = append(txHashes, tx.Hash().String())
+			rawTxs = append(rawTxs, hexutil.Encode(raw))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported input type: %T", input)
+	}
+	slog.Info("Dry run, bid not sent",
+		"txHashes", txHashes,
+		"rawTxs", rawTxs,
+		"amount", amount,
+		"blockNumber", blockNumber,
+		"decayStart", decayStart,
+		"decayEnd", decayEnd,
+	)
+	return &drainedBidStream{err: io.EOF}, nil
+}
@@ -0,0 +1,28 @@
+package mevcommit
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunBidder(t *testing.T) {
+	window := NewDecayWindow(time.Now(), 12*time.Second)
+	policy := BidRetryPolicy{Attempts: 1}
+
+	result, err := SendPreconfBidWithRetry(context.Background(), DryRunBidder{}, "0x1234", 100, big.NewInt(1e15), window, policy)
+	require.NoError(t, err)
+	require.False(t, result.Won())
+
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+	result, err = SendPreconfBidWithRetry(context.Background(), DryRunBidder{}, []*types.Transaction{tx}, 100, big.NewInt(1e15), window, policy)
+	require.NoError(t, err)
+	require.Empty(t, result.Commitments)
+
+	_, err = DryRunBidder{}.SendBid(42, "1", 100, 0, 0)
+	require.ErrorContains(t, err, "unsupported input type")
+}
@@ -0,0 +1,30 @@
+package mevcommit
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// LoadKeystore decrypts the private key in a geth keystore JSON file, as
+// written by geth account new or clef.
+//
+// Parameters:
+// - path: The keystore file.
+// - password: The password the key is encrypted with.
+//
+// Returns:
+// - The private key, or an error if the file can't be read or decrypted.
+func LoadKeystore(path, password string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return key.PrivateKey, nil
+}
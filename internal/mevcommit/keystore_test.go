@@ -0,0 +1,61 @@
+package mevcommit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeyHex = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+// writeKeystore encrypts key into a keystore file with light scrypt parameters.
+func writeKeystore(t *testing.T, keyHex, password string) string {
+	t.Helper()
+	privateKey, err := crypto.HexToECDSA(keyHex)
+	require.NoError(t, err)
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	data, err := keystore.EncryptKey(key, password, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestLoadKeystore(t *testing.T) {
+	path := writeKeystore(t, testKeyHex, "hunter2")
+
+	key, err := LoadKeystore(path, "hunter2")
+	require.NoError(t, err)
+
+	// The keystore account authenticates as the same address as the raw key
+	client, err := ethclient.Dial(startRPCServer(t).URL)
+	require.NoError(t, err)
+	defer client.Close()
+	fromKeystore, err := AuthenticateKey(key, client)
+	require.NoError(t, err)
+	fromHex, err := AuthenticateAddress(testKeyHex, client)
+	require.NoError(t, err)
+	require.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", fromKeystore.Address.Hex())
+	require.Equal(t, fromHex.Address, fromKeystore.Address)
+	require.Equal(t, fromHex.Auth.From, fromKeystore.Auth.From)
+}
+
+func TestLoadKeystoreErrors(t *testing.T) {
+	path := writeKeystore(t, testKeyHex, "hunter2")
+
+	_, err := LoadKeystore(path, "wrong")
+	require.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	_, err = LoadKeystore(filepath.Join(t.TempDir(), "missing.json"), "hunter2")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,192 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+)
+
+// ParseServerAddresses splits a comma-separated list of bidder node addresses,
+// such as "localhost:13524,10.0.0.2:13524".
+//
+// Parameters:
+// - s: The list. Spaces around addresses and empty entries are ignored.
+//
+// Returns:
+// - The addresses in the order given, nil for an empty list.
+func ParseServerAddresses(s string) []string {
+	var addresses []string
+	for _, address := range strings.Split(s, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// BidderEndpoint is one of the bidder nodes a MultiBidder sends bids to.
+type BidderEndpoint struct {
+	Address string  // The address of the node, for logs and errors.
+	Bidder  *Bidder // The client connected to the node.
+}
+
+// MultiBidder sends each bid to several bidder nodes at once, so that a bid still
+// reaches the providers while one of the nodes is down.
+type MultiBidder struct {
+	endpoints []BidderEndpoint
+	timeout   time.Duration
+	logger    *slog.Logger
+}
+
+// NewMultiBidder creates a MultiBidder over the endpoints.
+//
+// Parameters:
+// - endpoints: The bidder nodes, at least one.
+// - timeout: How long each node is given to answer a bid, 0 for no limit.
+// - logger: Writes the outcome of each node, slog.Default() if nil.
+//
+// Returns:
+// - A pointer to the MultiBidder, or an error if there are no endpoints.
+func NewMultiBidder(endpoints []BidderEndpoint, timeout time.Duration, logger *slog.Logger) (*MultiBidder, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no bidder endpoints")
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("bidder endpoint timeout must not be negative, got %s", timeout)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MultiBidder{endpoints: endpoints, timeout: timeout, logger: logger}, nil
+}
+
+// Logger returns the logger the MultiBidder writes its entries to.
+func (m *MultiBidder) Logger() *slog.Logger {
+	return m.logger
+}
+
+// endpointOutcome is what one bidder node answered to a bid.
+type endpointOutcome struct {
+	commitments []*pb.Commitment
+	err         error // The error sending the bid or ending its stream, nil if accepted.
+}
+
+// SendBid sends the bid to every endpoint concurrently and waits for each to
+// answer or time out. The bid succeeds if at least one node accepts it: its
+// stream then replays the commitments of all nodes, in endpoint order. If every
+// node fails, the distinct errors are returned together.
+func (m *MultiBidder) SendBid(input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
+	return m.SendBidContext(context.Background(), input, amount, blockNumber, decayStart, decayEnd)
+}
+
+// SendBidContext is SendBid bounded by ctx: once ctx is done every endpoint
+// still answering is cancelled, whatever the endpoint timeout.
+func (m *MultiBidder) SendBidContext(ctx context.Context, input interface{}, amount string, blockNumber, decayStart, decayEnd int64) (pb.Bidder_SendBidClient, error) {
+	outcomes := make([]endpointOutcome, len(m.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range m.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcomes[i] = m.sendTo(ctx, endpoint, input, amount, blockNumber, decayStart, decayEnd)
+		}()
+	}
+	wg.Wait()
+
+	var commitments []*pb.Commitment
+	var failed []int
+	for i, outcome := range outcomes {
+		commitments = append(commitments, outcome.commitments...)
+		if outcome.err != nil {
+			failed = append(failed, i)
+			m.logger.Warn("Bidder node failed bid",
+				"serverAddress", m.endpoints[i].Address,
+				"err", outcome.err,
+				"blockNumber", blockNumber,
+			)
+			continue
+		}
+		m.logger.Info("Bidder node accepted bid",
+			"serverAddress", m.endpoints[i].Address,
+			"commitments", len(outcome.commitments),
+			"blockNumber", blockNumber,
+		)
+	}
+	if len(failed) < len(m.endpoints) {
+		return &drainedBidStream{commitments: commitments, err: io.EOF}, nil
+	}
+	return nil, m.joinErrors(outcomes)
+}
+
+// sendTo sends the bid to one endpoint, bounded by ctx and the endpoint timeout.
+func (m *MultiBidder) sendTo(ctx context.Context, endpoint BidderEndpoint, input interface{}, amount string, blockNumber, decayStart, decayEnd int64) endpointOutcome {
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	stream, err := endpoint.Bidder.SendBidContext(ctx, input, amount, blockNumber, decayStart, decayEnd)
+	if err != nil {
+		return endpointOutcome{err: err}
+	}
+	var outcome endpointOutcome
+	for {
+		commitment, err := stream.Recv()
+		if err != nil {
+			// A node that committed before its stream broke still accepted the bid
+			if err != io.EOF && len(outcome.commitments) == 0 {
+				outcome.err = err
+			}
+			return outcome
+		}
+		outcome.commitments = append(outcome.commitments, commitment)
+	}
+}
+
+// joinErrors combines the errors of the endpoints, naming the addresses that
+// failed with each distinct error once. The first error keeps its gRPC status,
+// so a bid all nodes refuse is retried as if sent to a single node.
+func (m *MultiBidder) joinErrors(outcomes []endpointOutcome) error {
+	var messages []string
+	addresses := make(map[string][]string)
+	first := make(map[string]error)
+	for i, outcome := range outcomes {
+		msg := outcome.err.Error()
+		if _, ok := first[msg]; !ok {
+			messages = append(messages, msg)
+			first[msg] = outcome.err
+		}
+		addresses[msg] = append(addresses[msg], m.endpoints[i].Address)
+	}
+
+	errs := make([]error, len(messages))
+	for i, msg := range messages {
+		errs[i] = fmt.Errorf("bidder nodes %s: %w", strings.Join(addresses[msg], ", "), first[msg])
+	}
+	return errors.Join(errs...)
+}
+
+// ObserveCommitments registers fn with every endpoint. It must be called before
+// bids are sent.
+func (m *MultiBidder) ObserveCommitments(fn func(*pb.Commitment)) {
+	for _, endpoint := range m.endpoints {
+		endpoint.Bidder.ObserveCommitments(fn)
+	}
+}
+
+// Close closes the client of every endpoint.
+func (m *MultiBidder) Close() error {
+	var errs []error
+	for _, endpoint := range m.endpoints {
+		errs = append(errs, endpoint.Bidder.Close())
+	}
+	return errors.Join(errs...)
+}
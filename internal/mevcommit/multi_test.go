@@ -0,0 +1,138 @@
+package mevcommit
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startFakeBidder starts a fake bidder node that commits to every bid.
+func startFakeBidder(t *testing.T, latency time.Duration) *fakebidder.Server {
+	t.Helper()
+	fake, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{CommitProbability: 1, Latency: latency})
+	require.NoError(t, err)
+	t.Cleanup(func() { fake.Close() })
+	return fake
+}
+
+// newMultiBidder connects a MultiBidder to the addresses.
+func newMultiBidder(t *testing.T, timeout time.Duration, addresses ...string) *MultiBidder {
+	t.Helper()
+	var endpoints []BidderEndpoint
+	for _, address := range addresses {
+		bidder, err := NewBidderClient(BidderConfig{ServerAddress: address})
+		require.NoError(t, err)
+		endpoints = append(endpoints, BidderEndpoint{Address: address, Bidder: bidder})
+	}
+	multi, err := NewMultiBidder(endpoints, timeout, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { multi.Close() })
+	return multi
+}
+
+// closedAddress returns a local address nothing is listening on.
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+	return addr
+}
+
+func sendMultiBid(t *testing.T, multi *MultiBidder) (BidResult, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return SendPreconfBidWithRetry(ctx, multi, "0xabc", 100, big.NewInt(1e15), DecayWindow{Start: 1, End: 2}, BidRetryPolicy{})
+}
+
+func TestParseServerAddresses(t *testing.T) {
+	require.Equal(t, []string{"localhost:13524", "10.0.0.2:13524"}, ParseServerAddresses(" localhost:13524, ,10.0.0.2:13524 "))
+	require.Nil(t, ParseServerAddresses(""))
+}
+
+func TestNewMultiBidderValidates(t *testing.T) {
+	_, err := NewMultiBidder(nil, time.Second, nil)
+	require.Error(t, err)
+	_, err = NewMultiBidder([]BidderEndpoint{{Address: "localhost:1"}}, -time.Second, nil)
+	require.Error(t, err)
+}
+
+func TestMultiBidderFanOut(t *testing.T) {
+	first := startFakeBidder(t, 0)
+	second := startFakeBidder(t, 0)
+	multi := newMultiBidder(t, 5*time.Second, first.Addr(), second.Addr())
+
+	var observed atomic.Int64
+	multi.ObserveCommitments(func(*pb.Commitment) { observed.Add(1) })
+
+	result, err := sendMultiBid(t, multi)
+	require.NoError(t, err)
+	require.Len(t, result.Commitments, 2)
+	require.Equal(t, uint64(1), first.Bids())
+	require.Equal(t, uint64(1), second.Bids())
+	require.Equal(t, int64(2), observed.Load())
+}
+
+func TestMultiBidderPartialFailure(t *testing.T) {
+	healthy := startFakeBidder(t, 0)
+	multi := newMultiBidder(t, 5*time.Second,
+		closedAddress(t),
+		startBidderServer(t, &pb.UnimplementedBidderServer{}),
+		healthy.Addr(),
+	)
+
+	result, err := sendMultiBid(t, multi)
+	require.NoError(t, err)
+	require.Len(t, result.Commitments, 1)
+	require.Equal(t, uint64(1), healthy.Bids())
+}
+
+func TestMultiBidderTimeout(t *testing.T) {
+	slow := startFakeBidder(t, 5*time.Second)
+	fast := startFakeBidder(t, 0)
+	multi := newMultiBidder(t, 200*time.Millisecond, slow.Addr(), fast.Addr())
+
+	start := time.Now()
+	result, err := sendMultiBid(t, multi)
+	require.NoError(t, err)
+	require.Len(t, result.Commitments, 1)
+	require.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestMultiBidderCancelledWithoutTimeout(t *testing.T) {
+	slow := startFakeBidder(t, 5*time.Second)
+	multi := newMultiBidder(t, 0, slow.Addr())
+
+	// Without an endpoint timeout, the caller's deadline still stops a hung node
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := SendPreconfBidWithRetry(ctx, multi, "0xabc", 100, big.NewInt(1e15), DecayWindow{Start: 1, End: 2}, BidRetryPolicy{})
+	require.ErrorIs(t, err, ErrBidGaveUp)
+	require.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestMultiBidderAllFail(t *testing.T) {
+	first := startBidderServer(t, &pb.UnimplementedBidderServer{})
+	second := startBidderServer(t, &pb.UnimplementedBidderServer{})
+	multi := newMultiBidder(t, 5*time.Second, first, second)
+
+	_, err := sendMultiBid(t, multi)
+	require.Error(t, err)
+	// Both nodes failed the same way, so the error is reported once for both
+	require.Equal(t, 1, strings.Count(err.Error(), "not implemented"))
+	require.Contains(t, err.Error(), first+", "+second)
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,180 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policies for headers received while the bidder node is unreachable.
+const (
+	OutagePolicySkip  = "skip"  // Build no transactions until the node answers again.
+	OutagePolicyQueue = "queue" // Keep building and queue hash-only bids to send on recovery.
+)
+
+// OutageDiscard is why a queued bid was dropped instead of sent.
+const (
+	OutageDiscardOverflow = "overflow" // The queue was full; the oldest bid made room.
+	OutageDiscardExpired  = "expired"  // Its target block had arrived by the time the node recovered.
+)
+
+// BidderOutage decides whether the bidder node is in an outage from the outcome
+// of sent bids: consecutive failures that show the node unreachable open the
+// outage, and a successful bid or probe ends it. While it is open, the policy
+// decides whether headers still build transactions, and queued bids are held
+// until recovery.
+type BidderOutage struct {
+	policy    string
+	failures  int
+	maxQueued int
+
+	mu          sync.Mutex
+	consecutive int
+	open        bool
+	queue       []PendingBid
+	discarded   map[string]uint64
+}
+
+// NewBidderOutage creates a BidderOutage.
+//
+// Parameters:
+// - policy: OutagePolicySkip or OutagePolicyQueue.
+// - failures: Consecutive unreachable failures that open an outage.
+// - maxQueued: Most bids held during an outage with the queue policy.
+//
+// Returns:
+// - A pointer to a BidderOutage, or an error if the policy is unknown.
+func NewBidderOutage(policy string, failures int, maxQueued int) (*BidderOutage, error) {
+	switch policy {
+	case OutagePolicySkip, OutagePolicyQueue:
+	default:
+		return nil, fmt.Errorf("unknown outage policy %q (must be %s or %s)", policy, OutagePolicySkip, OutagePolicyQueue)
+	}
+	if failures < 1 {
+		failures = 1
+	}
+	return &BidderOutage{
+		policy:    policy,
+		failures:  failures,
+		maxQueued: maxQueued,
+		discarded: make(map[string]uint64),
+	}, nil
+}
+
+// Unreachable reports whether an error shows the bidder node couldn't be reached,
+// as opposed to the node rejecting a bid.
+func Unreachable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// Record updates the outage from the outcome of a bid or probe. Errors that don't
+// show the node unreachable leave it unchanged.
+//
+// Returns:
+// - Whether the outage opened or ended with this outcome.
+func (o *BidderOutage) Record(err error) (opened, recovered bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch {
+	case err == nil:
+		o.consecutive = 0
+		recovered = o.open
+		o.open = false
+	case Unreachable(err):
+		o.consecutive++
+		if !o.open && o.consecutive >= o.failures {
+			o.open = true
+			opened = true
+		}
+	}
+	return opened, recovered
+}
+
+// Open reports whether the bidder node is in an outage.
+func (o *BidderOutage) Open() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.open
+}
+
+// Building reports whether headers should build transactions, which the skip
+// policy stops during an outage.
+func (o *BidderOutage) Building() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return !o.open || o.policy == OutagePolicyQueue
+}
+
+// Queue holds bids built during an outage, sent hash-only on recovery. Past
+// maxQueued bids the oldest are discarded.
+//
+// Returns:
+// - The bids discarded to make room.
+func (o *BidderOutage) Queue(bids ...PendingBid) []PendingBid {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, bid := range bids {
+		bid.HashOnly = true
+		o.queue = append(o.queue, bid)
+	}
+	var dropped []PendingBid
+	if over := len(o.queue) - o.maxQueued; over > 0 {
+		dropped = append(dropped, o.queue[:over]...)
+		o.queue = append([]PendingBid(nil), o.queue[over:]...)
+		o.discarded[OutageDiscardOverflow] += uint64(over)
+	}
+	return dropped
+}
+
+// Recover empties the queue once the outage has ended. Bids whose target block
+// has already arrived are discarded; the others get a decay window of their
+// original length starting now.
+//
+// Parameters:
+// - latestBlock: The latest block number seen.
+// - now: When the bids are sent.
+//
+// Returns:
+// - The bids to send, and those discarded as expired.
+func (o *BidderOutage) Recover(latestBlock uint64, now time.Time) (send, expired []PendingBid) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, bid := range o.queue {
+		if uint64(bid.BlockNumber) <= latestBlock {
+			expired = append(expired, bid)
+			continue
+		}
+		length := time.Duration(bid.Window.End-bid.Window.Start) * time.Millisecond
+		bid.Window = NewDecayWindow(now, length)
+		send = append(send, bid)
+	}
+	o.queue = nil
+	o.discarded[OutageDiscardExpired] += uint64(len(expired))
+	return send, expired
+}
+
+// Queued returns the number of bids held for recovery.
+func (o *BidderOutage) Queued() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.queue)
+}
+
+// Discarded returns the number of queued bids dropped for a reason.
+func (o *BidderOutage) Discarded(reason string) uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.discarded[reason]
+}
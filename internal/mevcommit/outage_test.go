@@ -0,0 +1,80 @@
+package mevcommit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errUnavailable = status.Error(codes.Unavailable, "connection refused")
+
+// simulateOutage opens an outage, then runs five headers through it the way the
+// bot does, bidding on the block after each header, and recovers at the last one.
+func simulateOutage(t *testing.T, policy string, maxQueued int) (*BidderOutage, []PendingBid, []PendingBid, int) {
+	t.Helper()
+	o, err := NewBidderOutage(policy, 2, maxQueued)
+	require.NoError(t, err)
+
+	opened, _ := o.Record(errUnavailable)
+	require.False(t, opened)
+	// A rejected bid shows the node is up, but doesn't count either way
+	o.Record(status.Error(codes.InvalidArgument, "bad bid"))
+	opened, _ = o.Record(errUnavailable)
+	require.True(t, opened)
+	require.True(t, o.Open())
+
+	built := 0
+	for block := uint64(101); block <= 105; block++ {
+		if !o.Building() {
+			continue
+		}
+		built++
+		window := NewDecayWindow(time.Now().Add(-time.Minute), 12*time.Second)
+		o.Queue(PendingBid{BlockNumber: int64(block + 1), Window: window})
+		o.Record(errUnavailable)
+	}
+
+	_, recovered := o.Record(nil)
+	require.True(t, recovered)
+	require.False(t, o.Open())
+	send, expired := o.Recover(105, time.Now())
+	return o, send, expired, built
+}
+
+func TestBidderOutageSkipPolicyBuildsNothing(t *testing.T) {
+	o, send, expired, built := simulateOutage(t, OutagePolicySkip, 8)
+	require.Zero(t, built)
+	require.Empty(t, send)
+	require.Empty(t, expired)
+	require.True(t, o.Building())
+}
+
+func TestBidderOutageQueuePolicySendsStillFutureBids(t *testing.T) {
+	o, send, expired, built := simulateOutage(t, OutagePolicyQueue, 3)
+	require.Equal(t, 5, built)
+
+	// Bids for 102 and 103 made room for later ones, 104 and 105 arrived during the
+	// outage, and only the bid for 106 is still ahead
+	require.Equal(t, uint64(2), o.Discarded(OutageDiscardOverflow))
+	require.Len(t, expired, 2)
+	require.Equal(t, uint64(2), o.Discarded(OutageDiscardExpired))
+	require.Len(t, send, 1)
+	require.Equal(t, int64(106), send[0].BlockNumber)
+	require.True(t, send[0].HashOnly)
+
+	// The decay window restarts now with its original length
+	require.InDelta(t, time.Now().UnixMilli(), send[0].Window.Start, 1000)
+	require.Equal(t, int64(12000), send[0].Window.End-send[0].Window.Start)
+	require.Zero(t, o.Queued())
+}
+
+func TestBidderOutageRejectsUnknownPolicy(t *testing.T) {
+	_, err := NewBidderOutage("retry", 3, 8)
+	require.Error(t, err)
+	require.True(t, Unreachable(errUnavailable))
+	require.False(t, Unreachable(errors.New("insufficient deposit")))
+}
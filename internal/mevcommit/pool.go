@@ -0,0 +1,271 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrNoHealthyClient is returned by ClientPool.Get when every connection is down.
+var ErrNoHealthyClient = errors.New("no healthy RPC client in the pool")
+
+// ClientDialer connects to an RPC endpoint.
+type ClientDialer func(ctx context.Context, endpoint string) (*ethclient.Client, error)
+
+// ClientPool keeps warm connections to a set of RPC endpoints. A background loop
+// checks each connection and replaces the ones that stop answering, so callers
+// always get a connection that answered its last check.
+type ClientPool struct {
+	dial     ClientDialer
+	interval time.Duration
+	timeout  time.Duration
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	conns []*pooledClient
+	next  int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// pooledClient is one connection of the pool; client is nil while it is down.
+type pooledClient struct {
+	endpoint string
+	client   *ethclient.Client
+}
+
+// UserAgentDialer returns a ClientDialer whose connections send userAgent as
+// the User-Agent header of their HTTP requests, and of WebSocket handshakes.
+func UserAgentDialer(userAgent string) ClientDialer {
+	return func(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+		client, err := rpc.DialOptions(ctx, endpoint, rpc.WithHeader("User-Agent", userAgent))
+		if err != nil {
+			return nil, err
+		}
+		return ethclient.NewClient(client), nil
+	}
+}
+
+// NewClientPool dials size connections to each endpoint. Connections that fail to
+// dial, or to answer, are retried by the health checks rather than failing the pool.
+//
+// Parameters:
+// - endpoints: The RPC endpoints to connect to.
+// - size: Connections per endpoint, at least 1.
+// - interval: Time between health checks of the connections.
+// - timeout: Timeout of each dial and health check.
+// - dial: Connects to an endpoint; nil dials with ethclient.DialContext.
+// - logger: Writes failed checks and reconnections, slog.Default() if nil.
+//
+// Returns:
+// - A pointer to a ClientPool whose health checks run until Close.
+func NewClientPool(endpoints []string, size int, interval, timeout time.Duration, dial ClientDialer, logger *slog.Logger) *ClientPool {
+	if size < 1 {
+		size = 1
+	}
+	if dial == nil {
+		dial = ethclient.DialContext
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	p := &ClientPool{
+		dial:     dial,
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, endpoint := range endpoints {
+		for i := 0; i < size; i++ {
+			p.conns = append(p.conns, &pooledClient{endpoint: endpoint})
+		}
+	}
+	p.check()
+	go p.run()
+	return p
+}
+
+// Get returns the next healthy connection, round robin.
+func (p *ClientPool) Get() (*ethclient.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for range p.conns {
+		conn := p.conns[p.next%len(p.conns)]
+		p.next++
+		if conn.client != nil {
+			return conn.client, nil
+		}
+	}
+	return nil, ErrNoHealthyClient
+}
+
+// Healthy returns the number of connections that answered their last check, and
+// the pool's size.
+func (p *ClientPool) Healthy() (healthy, size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		if conn.client != nil {
+			healthy++
+		}
+	}
+	return healthy, len(p.conns)
+}
+
+// Close stops the health checks and closes every connection.
+func (p *ClientPool) Close() error {
+	close(p.stop)
+	<-p.done
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		if conn.client != nil {
+			conn.client.Close()
+			conn.client = nil
+		}
+	}
+	return nil
+}
+
+// run checks the connections every interval until Close.
+func (p *ClientPool) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.check()
+		}
+	}
+}
+
+// check asks each connection for the latest block number, and redials the ones
+// that are down or don't answer. Connections are checked without holding the lock,
+// so callers keep getting the others meanwhile.
+func (p *ClientPool) check() {
+	p.mu.Lock()
+	conns := make([]pooledClient, len(p.conns))
+	for i, conn := range p.conns {
+		conns[i] = *conn
+	}
+	p.mu.Unlock()
+
+	for i, conn := range conns {
+		if conn.client != nil {
+			err := p.ping(conn.client)
+			if err == nil {
+				continue
+			}
+			p.logger.Warn("RPC connection failed its health check, reconnecting",
+				"endpoint", MaskEndpoint(conn.endpoint),
+				"error", err,
+			)
+			p.replace(i, conn.client, nil)
+			conn.client.Close()
+		}
+
+		client, err := p.connect(conn.endpoint)
+		if err != nil {
+			p.logger.Warn("Failed to connect pooled RPC client",
+				"endpoint", MaskEndpoint(conn.endpoint),
+				"error", err,
+			)
+			continue
+		}
+		if !p.replace(i, nil, client) {
+			// Reconnect got to it first
+			client.Close()
+		}
+	}
+}
+
+// connect dials the endpoint and checks the new connection answers.
+func (p *ClientPool) connect(endpoint string) (*ethclient.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	client, err := p.dial(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.ping(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// ping makes a cheap read-only call on the connection.
+func (p *ClientPool) ping(client *ethclient.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	_, err := client.BlockNumber(ctx)
+	return err
+}
+
+// replace swaps the client of connection i from old to replacement, reporting
+// whether it still held old.
+func (p *ClientPool) replace(i int, old, replacement *ethclient.Client) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conns[i].client != old {
+		return false
+	}
+	p.conns[i].client = replacement
+	return true
+}
+
+// Reconnect redials the connection of a client that failed a call, without
+// waiting for the next health check. The failed client is closed.
+//
+// Parameters:
+// - failed: The client whose call failed.
+//
+// Returns:
+// - The new client, or the next healthy one if failed was already replaced, or an
+// error if the redial fails; the connection then stays down until a health check
+// reconnects it.
+func (p *ClientPool) Reconnect(failed *ethclient.Client) (*ethclient.Client, error) {
+	p.mu.Lock()
+	i := -1
+	for j, conn := range p.conns {
+		if conn.client == failed {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		p.mu.Unlock()
+		return p.Get()
+	}
+	endpoint := p.conns[i].endpoint
+	p.conns[i].client = nil
+	p.mu.Unlock()
+	failed.Close()
+
+	client, err := p.connect(endpoint)
+	if err != nil {
+		p.logger.Warn("Failed to reconnect pooled RPC client",
+			"endpoint", MaskEndpoint(endpoint),
+			"error", err,
+		)
+		return nil, err
+	}
+	if !p.replace(i, nil, client) {
+		// A health check reconnected it meanwhile
+		client.Close()
+		return p.Get()
+	}
+	p.logger.Info("Pooled RPC client reconnected", "endpoint", MaskEndpoint(endpoint))
+	return client, nil
+}
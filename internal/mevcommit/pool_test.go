@@ -0,0 +1,102 @@
+package mevcommit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+// startRPCServer serves eth_blockNumber over HTTP.
+func startRPCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientPoolReplacesDeadConnections(t *testing.T) {
+	first := startRPCServer(t)
+	second := startRPCServer(t)
+
+	// The endpoint moves to the second server once the first dies
+	var mu sync.Mutex
+	url := first.URL
+	dial := func(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return ethclient.DialContext(ctx, url)
+	}
+
+	pool := NewClientPool([]string{"rpc"}, 2, 10*time.Millisecond, time.Second, dial, nil)
+	defer pool.Close()
+
+	healthy, size := pool.Healthy()
+	require.Equal(t, 2, healthy)
+	require.Equal(t, 2, size)
+	dead, err := pool.Get()
+	require.NoError(t, err)
+
+	first.Close()
+	mu.Lock()
+	url = second.URL
+	mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		for i := 0; i < size; i++ {
+			client, err := pool.Get()
+			if err != nil || client == dead {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	client, err := pool.Get()
+	require.NoError(t, err)
+	block, err := client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(16), block)
+}
+
+func TestClientPoolWithoutHealthyConnections(t *testing.T) {
+	srv := startRPCServer(t)
+	srv.Close()
+
+	pool := NewClientPool([]string{srv.URL}, 1, time.Hour, time.Second, nil, nil)
+	defer pool.Close()
+
+	_, err := pool.Get()
+	require.ErrorIs(t, err, ErrNoHealthyClient)
+	healthy, size := pool.Healthy()
+	require.Equal(t, 0, healthy)
+	require.Equal(t, 1, size)
+}
+
+func TestUserAgentDialer(t *testing.T) {
+	userAgents := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case userAgents <- r.UserAgent():
+		default:
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+
+	client, err := UserAgentDialer("preconf_bidder/1.2.3")(context.Background(), srv.URL)
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "preconf_bidder/1.2.3", <-userAgents)
+}
@@ -0,0 +1,152 @@
+package mevcommit
+
+import (
+	"container/heap"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PendingBid is a bid waiting to be sent by a TransactionPriorityQueueSender.
+type PendingBid struct {
+	Tx           *types.Transaction
+	BlockNumber  int64
+	BidAmountWei *big.Int    // Priority of the bid: higher amounts are sent first.
+	AmountEth    float64     // The same amount in ETH, as taken by SendPreconfBid.
+	AmountWei    *big.Int    // The exact amount to bid, as taken by SendPreconfBidWei.
+	HashOnly     bool        // Sent as a hash-only bid even when payloads are enabled.
+	Window       DecayWindow // The bid's decay window.
+	HeaderAt     time.Time   // When the header the bid was built for arrived.
+	// Bundle holds every transaction of a bid on several transactions at once,
+	// starting with Tx; nil for a bid on Tx alone.
+	Bundle []*types.Transaction
+	// Broadcast marks a hash-only bid whose transactions are sent as a bundle
+	// after the bid, rather than before it was queued.
+	Broadcast bool
+}
+
+// ShareChainPriority gives bids on a chain of transactions with sequential nonces
+// the priority of the highest among them. Enqueued together in nonce order, they
+// are then dispatched in that order, with earlier nonces first.
+func ShareChainPriority(bids []PendingBid) {
+	var highest *big.Int
+	for _, bid := range bids {
+		if highest == nil || bid.BidAmountWei.Cmp(highest) > 0 {
+			highest = bid.BidAmountWei
+		}
+	}
+	for i := range bids {
+		bids[i].BidAmountWei = highest
+	}
+}
+
+// queuedBid is a PendingBid with its arrival order, used to break ties.
+type queuedBid struct {
+	bid PendingBid
+	seq uint64
+}
+
+// bidHeap orders queued bids by decreasing bid amount, then by arrival.
+type bidHeap []queuedBid
+
+func (h bidHeap) Len() int { return len(h) }
+func (h bidHeap) Less(i, j int) bool {
+	if c := h[i].bid.BidAmountWei.Cmp(h[j].bid.BidAmountWei); c != 0 {
+		return c > 0
+	}
+	return h[i].seq < h[j].seq
+}
+func (h bidHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *bidHeap) Push(x any)   { *h = append(*h, x.(queuedBid)) }
+func (h *bidHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// TransactionPriorityQueueSender dispatches queued bids from a single goroutine,
+// highest bid amount first, so the most valuable bids reach the bidder while the
+// bidder window is fresh.
+type TransactionPriorityQueueSender struct {
+	dispatch func(PendingBid)
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  bidHeap
+	seq    uint64
+	closed bool
+	done   chan struct{}
+}
+
+// NewTransactionPriorityQueueSender creates a TransactionPriorityQueueSender and starts its
+// sender goroutine.
+//
+// Parameters:
+// - dispatch: Called for each bid in priority order. Bids are dispatched one at a time.
+//
+// Returns:
+// - A pointer to a TransactionPriorityQueueSender. Close it to stop the sender goroutine.
+func NewTransactionPriorityQueueSender(dispatch func(PendingBid)) *TransactionPriorityQueueSender {
+	s := &TransactionPriorityQueueSender{
+		dispatch: dispatch,
+		done:     make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	go s.run()
+	return s
+}
+
+// Enqueue adds bids to the queue. Bids enqueued together are added atomically, so
+// they are dispatched in priority order among themselves.
+func (s *TransactionPriorityQueueSender) Enqueue(bids ...PendingBid) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for _, bid := range bids {
+		heap.Push(&s.queue, queuedBid{bid: bid, seq: s.seq})
+		s.seq++
+	}
+	s.cond.Signal()
+}
+
+// Len returns the number of bids waiting to be dispatched.
+func (s *TransactionPriorityQueueSender) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.Len()
+}
+
+// Close stops accepting bids, dispatches the ones still queued, and waits for the
+// sender goroutine to exit.
+func (s *TransactionPriorityQueueSender) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Signal()
+	s.mu.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *TransactionPriorityQueueSender) run() {
+	defer close(s.done)
+	for {
+		s.mu.Lock()
+		for s.queue.Len() == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if s.queue.Len() == 0 {
+			s.mu.Unlock()
+			return
+		}
+		next := heap.Pop(&s.queue).(queuedBid)
+		s.mu.Unlock()
+
+		s.dispatch(next.bid)
+	}
+}
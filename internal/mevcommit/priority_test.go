@@ -0,0 +1,95 @@
+package mevcommit
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func pendingBid(blockNumber, amount int64) PendingBid {
+	return PendingBid{BlockNumber: blockNumber, BidAmountWei: big.NewInt(amount)}
+}
+
+func TestPriorityQueueSenderOrdersByBidAmount(t *testing.T) {
+	var mu sync.Mutex
+	var sent []int64
+	first := make(chan struct{})
+	release := make(chan struct{})
+
+	sender := NewTransactionPriorityQueueSender(func(b PendingBid) {
+		if b.BlockNumber == 0 {
+			close(first)
+			<-release // Hold the sender so the rest queue up
+			return
+		}
+		mu.Lock()
+		sent = append(sent, b.BidAmountWei.Int64())
+		mu.Unlock()
+	})
+
+	sender.Enqueue(pendingBid(0, 0))
+	<-first
+
+	// Enqueue concurrently while the sender is busy
+	var wg sync.WaitGroup
+	for g := int64(0); g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int64(0); i < 10; i++ {
+				sender.Enqueue(pendingBid(1, g*10+i+1))
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 100, sender.Len())
+
+	close(release)
+	require.NoError(t, sender.Close())
+
+	require.Len(t, sent, 100)
+	for i := 1; i < len(sent); i++ {
+		require.Greater(t, sent[i-1], sent[i], "bids must be sent highest amount first")
+	}
+}
+
+func TestPriorityQueueSenderTiesKeepArrivalOrder(t *testing.T) {
+	var sent []int64
+	gate := make(chan struct{})
+	sender := NewTransactionPriorityQueueSender(func(b PendingBid) {
+		<-gate
+		sent = append(sent, b.BlockNumber)
+	})
+
+	sender.Enqueue(pendingBid(1, 5), pendingBid(2, 5), pendingBid(3, 7), pendingBid(4, 5))
+	close(gate)
+	require.NoError(t, sender.Close())
+
+	// Bids enqueued together are queued atomically: highest amount first, then arrival order
+	require.Equal(t, []int64{3, 1, 2, 4}, sent)
+
+	// Bids enqueued after Close are dropped
+	sender.Enqueue(pendingBid(5, 100))
+	require.Equal(t, 0, sender.Len())
+}
+
+func TestPriorityQueueSenderDispatchesChainsInNonceOrder(t *testing.T) {
+	var sent []int64
+	gate := make(chan struct{})
+	sender := NewTransactionPriorityQueueSender(func(b PendingBid) {
+		<-gate
+		sent = append(sent, b.BlockNumber)
+	})
+
+	// The chain's bids are numbered by nonce; its highest amount outbids the other bid
+	chain := []PendingBid{pendingBid(1, 10), pendingBid(2, 30), pendingBid(3, 20)}
+	ShareChainPriority(chain)
+	sender.Enqueue(append([]PendingBid{pendingBid(4, 25), pendingBid(5, 35)}, chain...)...)
+
+	close(gate)
+	require.NoError(t, sender.Close())
+	require.Equal(t, int64(30), chain[0].BidAmountWei.Int64())
+	require.Equal(t, []int64{5, 1, 2, 3, 4}, sent)
+}
@@ -0,0 +1,65 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReachabilityReport is the outcome of a successful reachability check.
+type ReachabilityReport struct {
+	AutoDepositEnabled bool // Whether the bidder node has auto deposit enabled.
+	Windows            int  // Number of deposit windows the node reported.
+	Implemented        bool // False if the node answered but doesn't implement the status RPC.
+}
+
+// CheckReachability makes a read-only AutoDepositStatus call to verify the bidder
+// node answers gRPC requests. The API has no health or version RPC, and this call
+// needs no arguments or funds.
+//
+// Parameters:
+// - ctx: The context bounding the call.
+//
+// Returns:
+// - A ReachabilityReport, or the gRPC error if the node could not be reached.
+func (b *Bidder) CheckReachability(ctx context.Context) (ReachabilityReport, error) {
+	resp, err := b.client.AutoDepositStatus(ctx, &pb.EmptyMessage{})
+	if err != nil {
+		// An Unimplemented status still comes from the server, so the node is reachable
+		if status.Code(err) == codes.Unimplemented {
+			return ReachabilityReport{}, nil
+		}
+		return ReachabilityReport{}, err
+	}
+	return ReachabilityReport{
+		AutoDepositEnabled: resp.IsAutodepositEnabled,
+		Windows:            len(resp.WindowBalances),
+		Implemented:        true,
+	}, nil
+}
+
+// ReachabilitySuggestion returns the gRPC status code of a failed reachability
+// check and a human-readable suggestion for fixing it.
+func ReachabilitySuggestion(err error) (codes.Code, string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return codes.DeadlineExceeded, "the bidder node did not answer in time; check the address and that the port is reachable from here"
+	}
+
+	code := status.Code(err)
+	switch code {
+	case codes.Unavailable:
+		return code, "could not connect; check the bidder node is running, the host and port are correct, and TLS is enabled only if the node serves TLS"
+	case codes.DeadlineExceeded:
+		return code, "the bidder node did not answer in time; check the address and that the port is reachable from here"
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return code, "the bidder node rejected the request; check any credentials or proxy in front of it"
+	case codes.Internal:
+		return code, "the connection failed mid-request; if the node serves TLS enable it, and if it doesn't disable it"
+	default:
+		return code, fmt.Sprintf("unexpected error from the bidder node: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package mevcommit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// startBidderServer serves impl on a local port and returns its address.
+func startBidderServer(t *testing.T, impl pb.BidderServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pb.RegisterBidderServer(srv, impl)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestCheckReachability(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fake, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{})
+	require.NoError(t, err)
+	defer fake.Close()
+
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: fake.Addr()})
+	require.NoError(t, err)
+	report, err := bidder.CheckReachability(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ReachabilityReport{AutoDepositEnabled: true, Windows: 1, Implemented: true}, report)
+
+	// A node without the status RPC still counts as reachable
+	bidder, err = NewBidderClient(BidderConfig{ServerAddress: startBidderServer(t, &pb.UnimplementedBidderServer{})})
+	require.NoError(t, err)
+	report, err = bidder.CheckReachability(ctx)
+	require.NoError(t, err)
+	require.False(t, report.Implemented)
+}
+
+func TestCheckReachabilityUnavailable(t *testing.T) {
+	// Reserve a port and close it so nothing is listening
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: addr})
+	require.NoError(t, err)
+	_, err = bidder.CheckReachability(ctx)
+	require.Error(t, err)
+
+	code, suggestion := ReachabilitySuggestion(err)
+	require.Equal(t, codes.Unavailable, code)
+	require.Contains(t, suggestion, "bidder node is running")
+}
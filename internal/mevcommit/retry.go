@@ -0,0 +1,161 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kinds of BidSendError, for errors.Is.
+var (
+	// ErrBidGaveUp means every attempt failed with a retryable status, or the
+	// block deadline passed before the bid could be sent.
+	ErrBidGaveUp = errors.New("gave up sending bid")
+	// ErrBidRejected means the bidder node refused the bid with a status that
+	// retrying won't change, such as INVALID_ARGUMENT.
+	ErrBidRejected = errors.New("bid rejected by bidder node")
+)
+
+// BidSendError is returned when a bid can't be sent to the bidder node.
+type BidSendError struct {
+	Kind     error      // ErrBidGaveUp or ErrBidRejected.
+	Code     codes.Code // gRPC status of the last attempt.
+	Attempts int        // Attempts made, including the last.
+	Err      error      // Error of the last attempt.
+}
+
+func (e *BidSendError) Error() string {
+	return fmt.Sprintf("%v after %d attempts (%s): %v", e.Kind, e.Attempts, e.Code, e.Err)
+}
+
+// Unwrap returns the kind and the last attempt's error, so errors.Is matches
+// either and the gRPC status stays visible to status.Code.
+func (e *BidSendError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// DefaultRetryableBidCodes are the gRPC statuses a bid is sent again after,
+// unless BidRetryPolicy.RetryableCodes names others.
+var DefaultRetryableBidCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted}
+
+// RetryableBidStatus reports whether a bid that failed with the gRPC status code
+// may succeed when sent again by default, typically while the bidder node
+// restarts.
+func RetryableBidStatus(code codes.Code) bool {
+	return slices.Contains(DefaultRetryableBidCodes, code)
+}
+
+// ParseBidStatusCodes parses a comma-separated list of gRPC status names, such
+// as "UNAVAILABLE,DEADLINE_EXCEEDED".
+//
+// Parameters:
+// - s: The list, case-insensitive.
+//
+// Returns:
+// - The codes, nil for an empty list.
+// - An error for an unknown name or OK, which isn't a failure.
+func ParseBidStatusCodes(s string) ([]codes.Code, error) {
+	var parsed []codes.Code
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(`"` + name + `"`)); err != nil {
+			return nil, fmt.Errorf("unknown gRPC status %q", name)
+		}
+		if code == codes.OK {
+			return nil, fmt.Errorf("OK is not a failure status")
+		}
+		parsed = append(parsed, code)
+	}
+	return parsed, nil
+}
+
+// BidRetryPolicy controls how sending a bid is retried.
+type BidRetryPolicy struct {
+	Attempts int           // Attempts per bid, at least 1.
+	Backoff  time.Duration // Wait before the second attempt, doubling after each failure.
+	// RetryableCodes are the statuses a bid is sent again after, nil for
+	// DefaultRetryableBidCodes. Any other status is fatal and ends the bid at once.
+	RetryableCodes []codes.Code
+}
+
+// Retryable reports whether a bid that failed with the gRPC status code is sent
+// again under the policy.
+func (p BidRetryPolicy) Retryable(code codes.Code) bool {
+	if p.RetryableCodes == nil {
+		return RetryableBidStatus(code)
+	}
+	return slices.Contains(p.RetryableCodes, code)
+}
+
+// BidDeadline returns when retrying a bid for the target block stops being
+// useful: once the header of the target block is due.
+//
+// Parameters:
+// - now: The current time.
+// - latestBlock: The number of the latest header received.
+// - targetBlock: The block the bid is for.
+// - blockTime: The time between blocks.
+//
+// Returns:
+// - The deadline, now if the target block is already due.
+func BidDeadline(now time.Time, latestBlock, targetBlock uint64, blockTime time.Duration) time.Time {
+	if targetBlock <= latestBlock {
+		return now
+	}
+	return now.Add(time.Duration(targetBlock-latestBlock) * blockTime)
+}
+
+// retrySend calls send until it succeeds, fails with a status that isn't
+// retryable, runs out of attempts, or ctx is done. The backoff never sleeps past
+// the context's deadline.
+//
+// Returns:
+// - The result of the successful call, or a *BidSendError. Errors without a
+// gRPC status come from preparing the bid and are returned unchanged.
+func retrySend[T any](ctx context.Context, policy BidRetryPolicy, send func() (T, error)) (T, error) {
+	attempts := max(policy.Attempts, 1)
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		result, err := send()
+		if err == nil {
+			return result, nil
+		}
+		st, ok := status.FromError(err)
+		if !ok {
+			return result, err
+		}
+		gaveUp := &BidSendError{Kind: ErrBidGaveUp, Code: st.Code(), Attempts: attempt, Err: err}
+		// An attempt cut short by ctx hit the deadline or shutdown, not a refusal
+		if ctx.Err() != nil {
+			return result, gaveUp
+		}
+		if !policy.Retryable(st.Code()) {
+			return result, &BidSendError{Kind: ErrBidRejected, Code: st.Code(), Attempts: attempt, Err: err}
+		}
+		if attempt >= attempts {
+			return result, gaveUp
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(backoff).After(deadline) {
+			return result, gaveUp
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, gaveUp
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
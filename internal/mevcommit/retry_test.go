@@ -0,0 +1,207 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const retryTxHash = "0xae0a7a0fd02f7617d815000d6322e564dcaccad49fc0b4cb3084b6c6036c37a2"
+
+// sendWithRetry sends a bid for block 100 with the policy.
+func sendWithRetry(ctx context.Context, bidder BidderInterface, policy BidRetryPolicy) error {
+	_, err := SendPreconfBidWithRetry(ctx, bidder, retryTxHash, 100, big.NewInt(1000), NewDecayWindow(time.Now(), 36*time.Second), policy)
+	return err
+}
+
+// failSendBid makes the next SendBid call fail with the status code.
+func failSendBid(bidder *MockBidderClient, code codes.Code) {
+	bidder.On("SendBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return((*MockBidderSendBidClient)(nil), status.Error(code, code.String())).Once()
+}
+
+func TestSendPreconfBidRetriesTransientStatuses(t *testing.T) {
+	bidder := new(MockBidderClient)
+	stream := new(MockBidderSendBidClient)
+	failSendBid(bidder, codes.Unavailable)
+	failSendBid(bidder, codes.DeadlineExceeded)
+	bidder.On("SendBid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(stream, nil).Once()
+	stream.On("Recv").Return(nil, io.EOF)
+
+	err := sendWithRetry(context.Background(), bidder, BidRetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	require.NoError(t, err)
+	bidder.AssertNumberOfCalls(t, "SendBid", 3)
+	stream.AssertExpectations(t)
+}
+
+func TestSendPreconfBidGivesUpAfterAttempts(t *testing.T) {
+	bidder := new(MockBidderClient)
+	for i := 0; i < 3; i++ {
+		failSendBid(bidder, codes.Unavailable)
+	}
+
+	err := sendWithRetry(context.Background(), bidder, BidRetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	require.ErrorIs(t, err, ErrBidGaveUp)
+	require.NotErrorIs(t, err, ErrBidRejected)
+	var sendErr *BidSendError
+	require.True(t, errors.As(err, &sendErr))
+	require.Equal(t, 3, sendErr.Attempts)
+	require.Equal(t, codes.Unavailable, sendErr.Code)
+
+	// The status stays visible, so outages are still detected
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.True(t, Unreachable(err))
+	bidder.AssertNumberOfCalls(t, "SendBid", 3)
+}
+
+func TestSendPreconfBidDoesNotRetryRejections(t *testing.T) {
+	bidder := new(MockBidderClient)
+	failSendBid(bidder, codes.InvalidArgument)
+
+	err := sendWithRetry(context.Background(), bidder, BidRetryPolicy{Attempts: 5, Backoff: time.Millisecond})
+	require.ErrorIs(t, err, ErrBidRejected)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.False(t, Unreachable(err))
+	bidder.AssertNumberOfCalls(t, "SendBid", 1)
+}
+
+func TestSendPreconfBidStopsAtTheBlockDeadline(t *testing.T) {
+	bidder := new(MockBidderClient)
+	failSendBid(bidder, codes.Unavailable)
+
+	// A backoff past the deadline isn't waited out
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := sendWithRetry(ctx, bidder, BidRetryPolicy{Attempts: 5, Backoff: time.Second})
+	require.ErrorIs(t, err, ErrBidGaveUp)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	bidder.AssertNumberOfCalls(t, "SendBid", 1)
+}
+
+func TestBidDeadline(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	require.Equal(t, now.Add(12*time.Second), BidDeadline(now, 99, 100, 12*time.Second))
+	require.Equal(t, now.Add(6*time.Second), BidDeadline(now, 97, 100, 2*time.Second))
+	require.Equal(t, now, BidDeadline(now, 100, 100, 12*time.Second))
+	require.Equal(t, now, BidDeadline(now, 101, 100, 12*time.Second))
+}
+
+func TestSendPreconfBidAbortsOnFatalStatuses(t *testing.T) {
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.Unauthenticated, codes.PermissionDenied} {
+		t.Run(code.String(), func(t *testing.T) {
+			srv := &scriptedBidderServer{codes: []codes.Code{code, code, code}}
+
+			err := sendWithRetry(context.Background(), dialScripted(t, srv), BidRetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+			require.ErrorIs(t, err, ErrBidRejected)
+			var sendErr *BidSendError
+			require.True(t, errors.As(err, &sendErr))
+			require.Equal(t, code, sendErr.Code)
+			require.Equal(t, 1, sendErr.Attempts)
+			require.Equal(t, int32(1), srv.calls.Load())
+		})
+	}
+}
+
+func TestSendPreconfBidRetriesConfiguredCodes(t *testing.T) {
+	policy := BidRetryPolicy{Attempts: 2, Backoff: time.Millisecond, RetryableCodes: []codes.Code{codes.Unauthenticated}}
+
+	// A listed status is retried, even one that is fatal by default
+	srv := &scriptedBidderServer{codes: []codes.Code{codes.Unauthenticated, codes.Unauthenticated}}
+	require.ErrorIs(t, sendWithRetry(context.Background(), dialScripted(t, srv), policy), ErrBidGaveUp)
+	require.Equal(t, int32(2), srv.calls.Load())
+
+	// A status left out of the list is fatal, even one retried by default
+	srv = &scriptedBidderServer{codes: []codes.Code{codes.Unavailable, codes.Unavailable}}
+	require.ErrorIs(t, sendWithRetry(context.Background(), dialScripted(t, srv), policy), ErrBidRejected)
+	require.Equal(t, int32(1), srv.calls.Load())
+}
+
+func TestParseBidStatusCodes(t *testing.T) {
+	parsed, err := ParseBidStatusCodes(" unavailable, DEADLINE_EXCEEDED ,")
+	require.NoError(t, err)
+	require.Equal(t, []codes.Code{codes.Unavailable, codes.DeadlineExceeded}, parsed)
+
+	parsed, err = ParseBidStatusCodes("")
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	_, err = ParseBidStatusCodes("UNAVAILABLE,SOMETIMES")
+	require.Error(t, err)
+	_, err = ParseBidStatusCodes("OK")
+	require.Error(t, err)
+}
+
+// scriptedBidderServer fails each bid with the next of its codes, then ends the
+// stream of later bids without a commitment.
+type scriptedBidderServer struct {
+	pb.UnimplementedBidderServer
+	codes []codes.Code
+	delay time.Duration // How long each bid waits before it is answered.
+	calls atomic.Int32
+}
+
+func (s *scriptedBidderServer) SendBid(_ *pb.Bid, stream pb.Bidder_SendBidServer) error {
+	call := int(s.calls.Add(1)) - 1
+	select {
+	case <-time.After(s.delay):
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	}
+	if call < len(s.codes) {
+		return status.Error(s.codes[call], s.codes[call].String())
+	}
+	return nil
+}
+
+// dialScripted starts srv and returns a Bidder connected to it.
+func dialScripted(t *testing.T, srv *scriptedBidderServer) *Bidder {
+	t.Helper()
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: startBidderServer(t, srv)})
+	require.NoError(t, err)
+	t.Cleanup(func() { bidder.Close() })
+	return bidder
+}
+
+func TestSendPreconfBidRetriesNodeStatuses(t *testing.T) {
+	srv := &scriptedBidderServer{codes: []codes.Code{codes.Unavailable, codes.DeadlineExceeded}}
+
+	err := sendWithRetry(context.Background(), dialScripted(t, srv), BidRetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), srv.calls.Load())
+}
+
+func TestSendPreconfBidGivesUpOnNodeStatuses(t *testing.T) {
+	srv := &scriptedBidderServer{codes: []codes.Code{codes.Unavailable, codes.Unavailable, codes.Unavailable}}
+
+	err := sendWithRetry(context.Background(), dialScripted(t, srv), BidRetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	require.ErrorIs(t, err, ErrBidGaveUp)
+	var sendErr *BidSendError
+	require.True(t, errors.As(err, &sendErr))
+	require.Equal(t, 3, sendErr.Attempts)
+	require.Equal(t, codes.Unavailable, sendErr.Code)
+	require.Equal(t, int32(3), srv.calls.Load())
+}
+
+func TestSendPreconfBidStopsASlowNodeAtTheDeadline(t *testing.T) {
+	srv := &scriptedBidderServer{delay: 3 * time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := sendWithRetry(ctx, dialScripted(t, srv), BidRetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	require.ErrorIs(t, err, ErrBidGaveUp)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, int32(1), srv.calls.Load())
+}
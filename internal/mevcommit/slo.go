@@ -0,0 +1,75 @@
+package mevcommit
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DowngradeReasonLatencySLO is the reason recorded when a payload bid is downgraded
+// because its header arrived longer ago than the latency SLO.
+const DowngradeReasonLatencySLO = "latency_slo_exceeded"
+
+// Downgrade describes a payload bid that was sent as a hash-only bid.
+type Downgrade struct {
+	Reason  string
+	Elapsed time.Duration // Time from header arrival to dispatch.
+	SLO     time.Duration
+}
+
+// PayloadLatencySLO bounds the time from header arrival to dispatching a payload
+// bid. A bid that would be dispatched later is downgraded to a hash-only bid, which
+// is smaller and faster to send, after its transaction is delivered to builders so
+// that the hash can be acted on.
+type PayloadLatencySLO struct {
+	max     time.Duration
+	deliver func(tx *types.Transaction, blockNumber int64) error
+	now     func() time.Time
+
+	downgrades atomic.Uint64
+}
+
+// NewPayloadLatencySLO creates a PayloadLatencySLO.
+//
+// Parameters:
+// - max: The maximum time from header arrival to dispatch, 0 to never downgrade.
+// - deliver: Delivers a transaction for the target block, e.g. as a bundle.
+//
+// Returns:
+// - A pointer to a PayloadLatencySLO.
+func NewPayloadLatencySLO(max time.Duration, deliver func(tx *types.Transaction, blockNumber int64) error) *PayloadLatencySLO {
+	return &PayloadLatencySLO{max: max, deliver: deliver, now: time.Now}
+}
+
+// BidInput returns the input to send a payload bid with: the transaction itself,
+// or its hash once the SLO is exceeded. If the transaction cannot be delivered,
+// the payload bid is sent late instead, since its hash alone could not be included.
+//
+// Parameters:
+// - bid: The bid about to be dispatched.
+//
+// Returns:
+// - The input for SendPreconfBid.
+// - The downgrade, or nil if the bid is sent with its payload.
+// - The delivery error, if the downgrade was abandoned.
+func (s *PayloadLatencySLO) BidInput(bid PendingBid) (interface{}, *Downgrade, error) {
+	if s.max <= 0 || bid.HeaderAt.IsZero() {
+		return bid.Tx, nil, nil
+	}
+	elapsed := s.now().Sub(bid.HeaderAt)
+	if elapsed <= s.max {
+		return bid.Tx, nil, nil
+	}
+	if err := s.deliver(bid.Tx, bid.BlockNumber); err != nil {
+		return bid.Tx, nil, fmt.Errorf("failed to deliver transaction for hash-only bid: %w", err)
+	}
+	s.downgrades.Add(1)
+	return bid.Tx.Hash().String(), &Downgrade{Reason: DowngradeReasonLatencySLO, Elapsed: elapsed, SLO: s.max}, nil
+}
+
+// Downgrades returns the number of bids downgraded to hash-only bids.
+func (s *PayloadLatencySLO) Downgrades() uint64 {
+	return s.downgrades.Load()
+}
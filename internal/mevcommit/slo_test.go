@@ -0,0 +1,104 @@
+package mevcommit
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/stretchr/testify/require"
+)
+
+// signSlowly builds a pending bid for a header that arrived before a signing step
+// taking delay.
+func signSlowly(t *testing.T, delay time.Duration) PendingBid {
+	t.Helper()
+	headerAt := time.Now()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	time.Sleep(delay)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		To:        &to,
+		Gas:       21_000,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+	})
+	require.NoError(t, err)
+	return PendingBid{
+		Tx:           tx,
+		BlockNumber:  100,
+		BidAmountWei: big.NewInt(1e15),
+		AmountEth:    0.001,
+		Window:       NewDecayWindow(time.Now(), 12*time.Second),
+		HeaderAt:     headerAt,
+	}
+}
+
+func TestPayloadLatencySLODowngradesSlowBids(t *testing.T) {
+	var mu sync.Mutex
+	var received []*pb.Bid
+	fake, err := fakebidder.Start("127.0.0.1:0", fakebidder.Config{OnBid: func(bid *pb.Bid) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, bid)
+	}})
+	require.NoError(t, err)
+	defer fake.Close()
+	bidder, err := NewBidderClient(BidderConfig{ServerAddress: fake.Addr()})
+	require.NoError(t, err)
+	defer bidder.Close()
+
+	var delivered []*types.Transaction
+	slo := NewPayloadLatencySLO(20*time.Millisecond, func(tx *types.Transaction, blockNumber int64) error {
+		require.EqualValues(t, 100, blockNumber)
+		delivered = append(delivered, tx)
+		return nil
+	})
+
+	// Fast signing keeps the payload
+	fast := signSlowly(t, 0)
+	input, downgrade, err := slo.BidInput(fast)
+	require.NoError(t, err)
+	require.Nil(t, downgrade)
+	SendPreconfBid(bidder, input, fast.BlockNumber, fast.AmountEth, fast.Window)
+
+	// Slow signing exceeds the SLO: the tx is delivered and bid on by hash
+	slow := signSlowly(t, 50*time.Millisecond)
+	input, downgrade, err = slo.BidInput(slow)
+	require.NoError(t, err)
+	require.NotNil(t, downgrade)
+	require.Equal(t, DowngradeReasonLatencySLO, downgrade.Reason)
+	require.Greater(t, downgrade.Elapsed, downgrade.SLO)
+	require.Equal(t, []*types.Transaction{slow.Tx}, delivered)
+	SendPreconfBid(bidder, input, slow.BlockNumber, slow.AmountEth, slow.Window)
+	require.EqualValues(t, 1, slo.Downgrades())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2)
+	require.Len(t, received[0].RawTransactions, 1)
+	require.Empty(t, received[0].TxHashes)
+	require.Equal(t, []string{strings.TrimPrefix(slow.Tx.Hash().Hex(), "0x")}, received[1].TxHashes)
+	require.Empty(t, received[1].RawTransactions)
+}
+
+func TestPayloadLatencySLOKeepsPayloadWhenDeliveryFails(t *testing.T) {
+	slo := NewPayloadLatencySLO(time.Millisecond, func(*types.Transaction, int64) error {
+		return errors.New("builder unreachable")
+	})
+	bid := signSlowly(t, 10*time.Millisecond)
+	input, downgrade, err := slo.BidInput(bid)
+	require.ErrorContains(t, err, "builder unreachable")
+	require.Nil(t, downgrade)
+	require.Equal(t, bid.Tx, input)
+	require.Zero(t, slo.Downgrades())
+}
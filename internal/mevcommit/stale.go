@@ -0,0 +1,85 @@
+package mevcommit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Policies for a bid whose target block has been reached by the time it is
+// dispatched, usually because building and signing its transaction was slow.
+const (
+	StaleBidSkip     = "skip"     // Drop the bid.
+	StaleBidRetarget = "retarget" // Bid for the next target block if the transaction is still valid.
+)
+
+// Actions taken on a bid by StaleBidGuard.Check.
+const (
+	StaleBidFresh      = ""           // The target block is still ahead.
+	StaleBidSkipped    = "skipped"    // The bid is dropped.
+	StaleBidRetargeted = "retargeted" // The bid is sent for a later block.
+)
+
+// StaleBidGuard stops bids for blocks that are already built from being sent as
+// they are.
+type StaleBidGuard struct {
+	policy string
+	offset uint64
+	valid  func(tx *types.Transaction) bool
+}
+
+// NewStaleBidGuard creates a StaleBidGuard.
+//
+// Parameters:
+// - policy: StaleBidSkip or StaleBidRetarget.
+// - offset: Blocks ahead of the latest header a retargeted bid is for.
+// - valid: Reports whether a transaction can still be included, e.g. that its
+// nonce is unused and its fee caps cover the base fees. Only asked when
+// retargeting.
+//
+// Returns:
+// - A pointer to a StaleBidGuard, or an error for an unknown policy.
+func NewStaleBidGuard(policy string, offset uint64, valid func(tx *types.Transaction) bool) (*StaleBidGuard, error) {
+	switch policy {
+	case StaleBidSkip, StaleBidRetarget:
+	default:
+		return nil, fmt.Errorf("unknown stale bid policy %q (must be %s or %s)", policy, StaleBidSkip, StaleBidRetarget)
+	}
+	return &StaleBidGuard{policy: policy, offset: max(offset, 1), valid: valid}, nil
+}
+
+// Check compares a bid's target block with the latest header seen. A bid for a
+// block whose header already arrived is skipped or, under StaleBidRetarget and
+// while all its transactions are valid, retargeted to latestBlock+offset with a
+// decay window of the same length starting now.
+//
+// Parameters:
+// - bid: The bid about to be dispatched.
+// - latestBlock: The number of the latest header received.
+// - now: The current time.
+//
+// Returns:
+// - The bid to send, retargeted if needed.
+// - StaleBidFresh, StaleBidSkipped or StaleBidRetargeted.
+func (g *StaleBidGuard) Check(bid PendingBid, latestBlock uint64, now time.Time) (PendingBid, string) {
+	if bid.BlockNumber < 0 || uint64(bid.BlockNumber) > latestBlock {
+		return bid, StaleBidFresh
+	}
+	if g.policy != StaleBidRetarget {
+		return bid, StaleBidSkipped
+	}
+	txs := bid.Bundle
+	if len(txs) == 0 {
+		txs = []*types.Transaction{bid.Tx}
+	}
+	for _, tx := range txs {
+		if !g.valid(tx) {
+			return bid, StaleBidSkipped
+		}
+	}
+	duration := time.Duration(bid.Window.End-bid.Window.Start) * time.Millisecond
+	bid.BlockNumber = int64(latestBlock + g.offset)
+	bid.Window = NewDecayWindow(now, duration)
+	return bid, StaleBidRetargeted
+}
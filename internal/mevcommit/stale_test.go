@@ -0,0 +1,99 @@
+package mevcommit
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// buildDuringHeaders builds a bid for header 100 with offset 1 while fake
+// headers keep arriving every interval, as a slow blob builder would.
+func buildDuringHeaders(t *testing.T, latest *atomic.Uint64, buildTime, interval time.Duration) PendingBid {
+	t.Helper()
+	latest.Store(100)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				latest.Add(1)
+			}
+		}
+	}()
+	bid := signSlowly(t, buildTime)
+	bid.BlockNumber = 101
+	close(done)
+	<-stopped
+	return bid
+}
+
+func TestStaleBidGuardSkipsBidsForPassedBlocks(t *testing.T) {
+	guard, err := NewStaleBidGuard(StaleBidSkip, 1, func(*types.Transaction) bool { return true })
+	require.NoError(t, err)
+
+	var latest atomic.Uint64
+	bid := buildDuringHeaders(t, &latest, 60*time.Millisecond, 10*time.Millisecond)
+	require.Greater(t, latest.Load(), uint64(101), "headers arrived while building")
+	_, action := guard.Check(bid, latest.Load(), time.Now())
+	require.Equal(t, StaleBidSkipped, action)
+
+	// A fast builder's bid goes out unchanged
+	latest.Store(100)
+	sent, action := guard.Check(bid, latest.Load(), time.Now())
+	require.Equal(t, StaleBidFresh, action)
+	require.Equal(t, bid, sent)
+
+	// The target block's header arriving is enough to make the bid stale
+	_, action = guard.Check(bid, 101, time.Now())
+	require.Equal(t, StaleBidSkipped, action)
+}
+
+func TestStaleBidGuardRetargetsValidTransactions(t *testing.T) {
+	guard, err := NewStaleBidGuard(StaleBidRetarget, 2, func(*types.Transaction) bool { return true })
+	require.NoError(t, err)
+
+	var latest atomic.Uint64
+	bid := buildDuringHeaders(t, &latest, 60*time.Millisecond, 10*time.Millisecond)
+	seen := latest.Load()
+	now := time.Now()
+	sent, action := guard.Check(bid, seen, now)
+	require.Equal(t, StaleBidRetargeted, action)
+	require.Equal(t, int64(seen+2), sent.BlockNumber)
+	require.Equal(t, now.UnixMilli(), sent.Window.Start)
+	require.Equal(t, bid.Window.End-bid.Window.Start, sent.Window.End-sent.Window.Start)
+	require.Equal(t, bid.Tx, sent.Tx)
+	require.Equal(t, bid.AmountEth, sent.AmountEth)
+}
+
+func TestStaleBidGuardSkipsInvalidTransactions(t *testing.T) {
+	bid := signSlowly(t, 0)
+	bid.BlockNumber = 101
+	other := signSlowly(t, 0)
+	bid.Bundle = []*types.Transaction{bid.Tx, other.Tx}
+
+	// One invalid transaction in a bundle rules out retargeting the whole bid
+	var asked []*types.Transaction
+	guard, err := NewStaleBidGuard(StaleBidRetarget, 1, func(tx *types.Transaction) bool {
+		asked = append(asked, tx)
+		return tx != other.Tx
+	})
+	require.NoError(t, err)
+	sent, action := guard.Check(bid, 101, time.Now())
+	require.Equal(t, StaleBidSkipped, action)
+	require.Equal(t, int64(101), sent.BlockNumber)
+	require.Len(t, asked, 2)
+}
+
+func TestNewStaleBidGuardRejectsUnknownPolicies(t *testing.T) {
+	_, err := NewStaleBidGuard("wait", 1, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package mevcommit
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DecayWindow is the period over which a bid decays, in Unix milliseconds.
+type DecayWindow struct {
+	Start int64
+	End   int64
+}
+
+// NewDecayWindow creates a decay window starting at start and lasting duration.
+func NewDecayWindow(start time.Time, duration time.Duration) DecayWindow {
+	startMs := start.UnixMilli()
+	return DecayWindow{Start: startMs, End: startMs + duration.Milliseconds()}
+}
+
+// MaxDecayTiming bounds the decay start offset and duration, far beyond any slot timing.
+const MaxDecayTiming = time.Hour
+
+// DecayTiming places the decay windows of bids relative to when they are built.
+type DecayTiming struct {
+	StartOffset time.Duration // Delay from building a bid to the start of its decay.
+	Duration    time.Duration // Decay duration of bids whose profile doesn't set one.
+}
+
+// NewDecayTiming creates a DecayTiming from millisecond settings.
+//
+// Parameters:
+// - startOffsetMs: Delay of the decay start, at least 0.
+// - durationMs: Decay duration, more than 0.
+//
+// Returns:
+// - The DecayTiming, or an error if a setting is out of range.
+func NewDecayTiming(startOffsetMs, durationMs int64) (DecayTiming, error) {
+	maxMs := MaxDecayTiming.Milliseconds()
+	if startOffsetMs < 0 || startOffsetMs > maxMs {
+		return DecayTiming{}, fmt.Errorf("decay start offset %dms must be between 0 and %dms", startOffsetMs, maxMs)
+	}
+	if durationMs <= 0 || durationMs > maxMs {
+		return DecayTiming{}, fmt.Errorf("decay duration %dms must be more than 0 and at most %dms", durationMs, maxMs)
+	}
+	return DecayTiming{
+		StartOffset: time.Duration(startOffsetMs) * time.Millisecond,
+		Duration:    time.Duration(durationMs) * time.Millisecond,
+	}, nil
+}
+
+// Window returns the decay window of a bid built at now that decays over duration.
+func (t DecayTiming) Window(now time.Time, duration time.Duration) DecayWindow {
+	return NewDecayWindow(now.Add(t.StartOffset), duration)
+}
+
+// Overlaps reports whether two decay windows share any instant.
+func (w DecayWindow) Overlaps(other DecayWindow) bool {
+	return w.Start < other.End && other.Start < w.End
+}
+
+// DecayJitter varies the decay windows of simultaneous bids for the same block,
+// so that they differ. Jittered windows end early by up to the maximum jitter and
+// stay within the original window, which is the one reserved for the block.
+type DecayJitter struct {
+	max time.Duration
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewDecayJitter creates a DecayJitter.
+//
+// Parameters:
+// - max: The most a window ends early. Zero leaves windows unchanged.
+// - rng: The source of the jitter.
+//
+// Returns:
+// - A pointer to a DecayJitter.
+func NewDecayJitter(max time.Duration, rng *rand.Rand) *DecayJitter {
+	return &DecayJitter{max: max, rng: rng}
+}
+
+// Windows returns the windows of n simultaneous bids sharing the base window. Each
+// ends a distinct number of milliseconds early, in [0, max], and at least 1ms after
+// it starts. Windows repeat only when there are more bids than possible offsets.
+func (j *DecayJitter) Windows(base DecayWindow, n int) []DecayWindow {
+	windows := make([]DecayWindow, n)
+	maxMs := j.max.Milliseconds()
+	if limit := base.End - base.Start - 1; maxMs > limit {
+		maxMs = limit
+	}
+	if maxMs <= 0 {
+		for i := range windows {
+			windows[i] = base
+		}
+		return windows
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var offsets []int
+	for i := range windows {
+		if len(offsets) == 0 {
+			offsets = j.rng.Perm(int(maxMs) + 1)
+		}
+		windows[i] = DecayWindow{Start: base.Start, End: base.End - int64(offsets[0])}
+		offsets = offsets[1:]
+	}
+	return windows
+}
+
+// BidWindowAlignment ensures no two bids for the same target block have overlapping
+// decay windows, which would cause the bidder to be charged twice for the block.
+// Overlapping windows for different blocks are allowed.
+type BidWindowAlignment struct {
+	mu      sync.Mutex
+	windows map[int64][]DecayWindow // target block -> reserved windows
+}
+
+// NewBidWindowAlignment creates an empty BidWindowAlignment.
+func NewBidWindowAlignment() *BidWindowAlignment {
+	return &BidWindowAlignment{windows: make(map[int64][]DecayWindow)}
+}
+
+// Reserve records the decay window of a bid for the target block, returning an error
+// without recording it if it overlaps a window already reserved for that block.
+func (a *BidWindowAlignment) Reserve(blockNumber int64, window DecayWindow) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, existing := range a.windows[blockNumber] {
+		if window.Overlaps(existing) {
+			return fmt.Errorf("decay window [%d, %d] overlaps [%d, %d] for block %d",
+				window.Start, window.End, existing.Start, existing.End, blockNumber)
+		}
+	}
+	a.windows[blockNumber] = append(a.windows[blockNumber], window)
+	return nil
+}
+
+// Prune forgets the windows of target blocks below blockNumber.
+func (a *BidWindowAlignment) Prune(blockNumber int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for block := range a.windows {
+		if block < blockNumber {
+			delete(a.windows, block)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package mevcommit
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidWindowAlignment(t *testing.T) {
+	alignment := NewBidWindowAlignment()
+	now := time.UnixMilli(1_700_000_000_000)
+	decay := 36 * time.Second
+
+	// Five bids dispatched from one header for blocks 100-104 share a window but not a block
+	reserved := make(map[int64][]DecayWindow)
+	for block := int64(100); block <= 104; block++ {
+		window := NewDecayWindow(now, decay)
+		require.NoError(t, alignment.Reserve(block, window))
+		reserved[block] = append(reserved[block], window)
+	}
+
+	// The next header's bids overlap in time for blocks 101-104 and must be rejected
+	next := now.Add(12 * time.Second)
+	for block := int64(101); block <= 105; block++ {
+		window := NewDecayWindow(next, decay)
+		err := alignment.Reserve(block, window)
+		if block == 105 {
+			require.NoError(t, err)
+			reserved[block] = append(reserved[block], window)
+			continue
+		}
+		require.Error(t, err)
+	}
+
+	// A later window that starts after the previous one ends is accepted
+	later := NewDecayWindow(now.Add(decay), decay)
+	require.NoError(t, alignment.Reserve(100, later))
+	reserved[100] = append(reserved[100], later)
+
+	for block, windows := range reserved {
+		for i := range windows {
+			for j := i + 1; j < len(windows); j++ {
+				require.False(t, windows[i].Overlaps(windows[j]), "block %d windows overlap", block)
+			}
+		}
+	}
+
+	// Pruned blocks can be reserved again
+	alignment.Prune(105)
+	require.NoError(t, alignment.Reserve(104, NewDecayWindow(next, decay)))
+}
+
+func TestDecayTimingBounds(t *testing.T) {
+	timing, err := NewDecayTiming(0, 36000)
+	require.NoError(t, err)
+	now := time.UnixMilli(1_000_000)
+	require.Equal(t, DecayWindow{Start: 1_000_000, End: 1_036_000}, timing.Window(now, timing.Duration))
+
+	// A delayed start moves the whole window
+	timing, err = NewDecayTiming(12000, 6000)
+	require.NoError(t, err)
+	require.Equal(t, DecayWindow{Start: 1_012_000, End: 1_018_000}, timing.Window(now, timing.Duration))
+
+	max := MaxDecayTiming.Milliseconds()
+	_, err = NewDecayTiming(max, max)
+	require.NoError(t, err)
+
+	for _, tc := range []struct{ startOffsetMs, durationMs int64 }{
+		{0, 0},
+		{0, -1},
+		{-1, 36000},
+		{max + 1, 36000},
+		{0, max + 1},
+		{0, math.MaxInt64},
+		{math.MaxInt64, 36000},
+	} {
+		_, err := NewDecayTiming(tc.startOffsetMs, tc.durationMs)
+		require.Error(t, err, "%+v", tc)
+	}
+}
+
+func TestDecayJitterWindowsAreDistinctAndInRange(t *testing.T) {
+	base := NewDecayWindow(time.UnixMilli(1_700_000_000_000), 12*time.Second)
+	jitter := NewDecayJitter(500*time.Millisecond, rand.New(rand.NewSource(1)))
+
+	windows := jitter.Windows(base, 6)
+	require.Len(t, windows, 6)
+	seen := make(map[DecayWindow]bool)
+	for _, w := range windows {
+		require.False(t, seen[w], "simultaneous bids must get distinct windows")
+		seen[w] = true
+		require.Equal(t, base.Start, w.Start)
+		require.LessOrEqual(t, w.End, base.End)
+		require.GreaterOrEqual(t, w.End, base.End-500)
+	}
+
+	// Without jitter, or without room for it, every bid keeps the base window
+	require.Equal(t, []DecayWindow{base, base}, NewDecayJitter(0, rand.New(rand.NewSource(1))).Windows(base, 2))
+	short := DecayWindow{Start: 0, End: 1}
+	require.Equal(t, []DecayWindow{short}, jitter.Windows(short, 1))
+}
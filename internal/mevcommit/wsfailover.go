@@ -0,0 +1,97 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// WSEndpoints dials one of several WebSocket endpoints, so that the bot fails
+// over to another node when the one it uses goes down. Endpoints are tried
+// round robin, each dial starting after the endpoint that last connected.
+type WSEndpoints struct {
+	endpoints []string
+	dial      ClientDialer
+	logger    *slog.Logger
+
+	mu     sync.Mutex
+	next   int
+	active string
+}
+
+// NewWSEndpoints creates a WSEndpoints over the endpoints.
+//
+// Parameters:
+// - endpoints: The WebSocket endpoints, at least one, in the order to try them.
+// - dial: Connects to an endpoint; nil dials with NewGethClient.
+// - logger: Writes the failed dials and the active endpoint, slog.Default() if nil.
+//
+// Returns:
+// - A pointer to the WSEndpoints, or an error if there are no endpoints.
+func NewWSEndpoints(endpoints []string, dial ClientDialer, logger *slog.Logger) (*WSEndpoints, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no WebSocket endpoints")
+	}
+	if dial == nil {
+		dial = func(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+			return NewGethClient(endpoint)
+		}
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &WSEndpoints{endpoints: endpoints, dial: dial, logger: logger}, nil
+}
+
+// Dial tries each endpoint once, round robin, and returns the first connection
+// made. The endpoint of the last connection is tried last, so a reconnect after
+// it failed moves to the next node.
+//
+// Returns:
+// - The connection, or the errors of every endpoint if none connects.
+func (w *WSEndpoints) Dial(ctx context.Context) (*ethclient.Client, error) {
+	w.mu.Lock()
+	start := w.next
+	w.mu.Unlock()
+
+	var errs []error
+	for i := range w.endpoints {
+		index := (start + i) % len(w.endpoints)
+		endpoint := w.endpoints[index]
+		client, err := w.dial(ctx, endpoint)
+		if err != nil {
+			w.logger.Warn("Failed to connect to WebSocket endpoint",
+				"error", err,
+				"ws_endpoint", MaskEndpoint(endpoint),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", MaskEndpoint(endpoint), err))
+			continue
+		}
+
+		w.mu.Lock()
+		w.next = index + 1
+		changed := w.active != endpoint
+		w.active = endpoint
+		w.mu.Unlock()
+		if changed {
+			w.logger.Info("WebSocket endpoint active",
+				"ws_endpoint", MaskEndpoint(endpoint),
+				"index", index,
+				"endpoints", len(w.endpoints),
+			)
+		}
+		return client, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Active returns the endpoint of the last connection made, empty before the first.
+func (w *WSEndpoints) Active() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.active
+}
@@ -0,0 +1,65 @@
+package mevcommit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWSDialer connects to every endpoint not marked down, recording the dials.
+type fakeWSDialer struct {
+	down  map[string]bool
+	dials []string
+}
+
+func (d *fakeWSDialer) dial(ctx context.Context, endpoint string) (*ethclient.Client, error) {
+	d.dials = append(d.dials, endpoint)
+	if d.down[endpoint] {
+		return nil, errors.New("connection refused")
+	}
+	return new(ethclient.Client), nil
+}
+
+func TestNewWSEndpointsRequiresOne(t *testing.T) {
+	_, err := NewWSEndpoints(nil, nil, nil)
+	require.Error(t, err)
+}
+
+func TestWSEndpointsFailover(t *testing.T) {
+	dialer := &fakeWSDialer{down: map[string]bool{}}
+	endpoints, err := NewWSEndpoints([]string{"wss://a", "wss://b", "wss://c"}, dialer.dial, nil)
+	require.NoError(t, err)
+	require.Empty(t, endpoints.Active())
+
+	_, err = endpoints.Dial(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "wss://a", endpoints.Active())
+
+	// The active node goes down: the reconnect moves on to the next one
+	dialer.down["wss://a"] = true
+	dialer.down["wss://b"] = true
+	_, err = endpoints.Dial(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "wss://c", endpoints.Active())
+	require.Equal(t, []string{"wss://a", "wss://b", "wss://c"}, dialer.dials)
+
+	// Round robin wraps around past the end of the list
+	dialer.down = map[string]bool{"wss://c": true}
+	_, err = endpoints.Dial(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "wss://a", endpoints.Active())
+}
+
+func TestWSEndpointsAllDown(t *testing.T) {
+	dialer := &fakeWSDialer{down: map[string]bool{"wss://a": true, "wss://b": true}}
+	endpoints, err := NewWSEndpoints([]string{"wss://a", "wss://b"}, dialer.dial, nil)
+	require.NoError(t, err)
+
+	_, err = endpoints.Dial(context.Background())
+	require.ErrorContains(t, err, "connection refused")
+	require.Equal(t, []string{"wss://a", "wss://b"}, dialer.dials)
+	require.Empty(t, endpoints.Active())
+}
@@ -0,0 +1,267 @@
+// Package node follows the chain head of the execution node.
+package node
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Conn is a node connection that headers can be subscribed to. *ethclient.Client
+// implements it.
+type Conn interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	Close()
+}
+
+// Dialer opens a new connection to the node.
+type Dialer[C Conn] func(ctx context.Context) (C, error)
+
+// Current holds the connection a HeaderStream is using. A reconnect closes the
+// connection it replaces, so long-lived consumers get the connection from Get
+// on each call rather than keeping one.
+type Current[C Conn] struct {
+	conn atomic.Pointer[C]
+}
+
+// NewCurrent creates a Current holding conn.
+func NewCurrent[C Conn](conn C) *Current[C] {
+	current := &Current[C]{}
+	current.conn.Store(&conn)
+	return current
+}
+
+// Get returns the connection in use.
+func (c *Current[C]) Get() C {
+	return *c.conn.Load()
+}
+
+// swap replaces the connection in use and returns the previous one.
+func (c *Current[C]) swap(conn C) C {
+	return *c.conn.Swap(&conn)
+}
+
+// StreamConfig configures a HeaderStream.
+type StreamConfig struct {
+	// StaleTimeout is how long the stream waits for a header before it tears
+	// the subscription down and reconnects, 0 to wait forever. Proxies that
+	// close idle connections can stop a subscription without an error.
+	StaleTimeout time.Duration
+	// Grace is added to StaleTimeout before the first header of a connection,
+	// which can take longer to arrive.
+	Grace time.Duration
+	// RetryBackoff is the wait between failed reconnection attempts.
+	RetryBackoff time.Duration
+	// Logger writes the stream's errors and reconnections, slog.Default() if nil.
+	Logger *slog.Logger
+}
+
+// Event reports a change of the stream's connection.
+type Event[C Conn] struct {
+	Connected bool          // Whether the stream has just reconnected, rather than lost its connection.
+	Conn      C             // The new connection once reconnected.
+	Err       error         // The subscription error that ended the connection, nil if it went stale.
+	StaleFor  time.Duration // Time without a header before a stale connection was torn down.
+}
+
+// HeaderStream subscribes to new headers and keeps the subscription alive,
+// reconnecting when it fails or goes stale. Headers are read from Headers and
+// connection changes from Events, which must both be drained until the
+// stream's context is done.
+type HeaderStream[C Conn] struct {
+	dial Dialer[C]
+	cfg  StreamConfig
+
+	headers chan *types.Header
+	events  chan Event[C]
+	done    chan struct{}
+
+	current *Current[C]
+
+	mu  sync.Mutex
+	sub ethereum.Subscription // nil while disconnected.
+	in  chan *types.Header    // Headers of sub.
+}
+
+// NewHeaderStream subscribes to headers over the current connection and starts
+// following them until ctx is done. Reconnections replace the connection in current.
+//
+// Parameters:
+// - ctx: Stops the stream when done.
+// - current: Holds the connection to subscribe over first.
+// - dial: Opens the connections to reconnect over.
+// - cfg: The stale timeout and retry settings.
+//
+// Returns:
+// - A pointer to a HeaderStream, or the error subscribing over the current connection.
+func NewHeaderStream[C Conn](ctx context.Context, current *Current[C], dial Dialer[C], cfg StreamConfig) (*HeaderStream[C], error) {
+	in := make(chan *types.Header)
+	sub, err := current.Get().SubscribeNewHead(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 5 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	s := &HeaderStream[C]{
+		dial:    dial,
+		cfg:     cfg,
+		headers: make(chan *types.Header),
+		events:  make(chan Event[C]),
+		done:    make(chan struct{}),
+		current: current,
+		sub:     sub,
+		in:      in,
+	}
+	go s.run(ctx)
+	return s, nil
+}
+
+// Headers returns the channel new headers are delivered on.
+func (s *HeaderStream[C]) Headers() <-chan *types.Header {
+	return s.headers
+}
+
+// Events returns the channel connection changes are delivered on.
+func (s *HeaderStream[C]) Events() <-chan Event[C] {
+	return s.events
+}
+
+// Close waits for the stream to stop, once its context is done, then
+// unsubscribes and closes the connection.
+func (s *HeaderStream[C]) Close() error {
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsubscribe()
+	s.current.Get().Close()
+	return nil
+}
+
+// run forwards headers, and reconnects when the subscription fails or no header
+// arrives within the stale timeout.
+func (s *HeaderStream[C]) run(ctx context.Context) {
+	defer close(s.done)
+
+	stale := s.newStaleTimer()
+	defer stale.Stop()
+	lastHeader := time.Now()
+	for {
+		s.mu.Lock()
+		in, sub := s.in, s.sub
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case header := <-in:
+			lastHeader = time.Now()
+			stale.Reset(s.cfg.StaleTimeout)
+			select {
+			case s.headers <- header:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		case err := <-sub.Err():
+			s.cfg.Logger.Warn("Subscription error", "error", err)
+			if !s.emit(ctx, Event[C]{Err: err}) {
+				return
+			}
+		case <-stale.C:
+			staleFor := time.Since(lastHeader)
+			s.cfg.Logger.Warn("No header received within the stale timeout, reconnecting",
+				"staleFor", staleFor.String(),
+				"staleTimeout", s.cfg.StaleTimeout.String(),
+			)
+			if !s.emit(ctx, Event[C]{StaleFor: staleFor}) {
+				return
+			}
+		}
+
+		if !s.reconnect(ctx) {
+			return
+		}
+		lastHeader = time.Now()
+		stale.Stop()
+		stale = s.newStaleTimer()
+	}
+}
+
+// newStaleTimer starts the stale timeout of a new connection, including the
+// grace period. It never fires without a stale timeout.
+func (s *HeaderStream[C]) newStaleTimer() *time.Timer {
+	stale := time.NewTimer(s.cfg.StaleTimeout + s.cfg.Grace)
+	if s.cfg.StaleTimeout <= 0 {
+		stale.Stop()
+	}
+	return stale
+}
+
+// reconnect replaces the connection, retrying until it subscribes or ctx is done.
+// The old connection stays in use by other consumers until then, since a stale
+// subscription doesn't mean the connection is broken.
+//
+// Returns:
+// - Whether the stream reconnected.
+func (s *HeaderStream[C]) reconnect(ctx context.Context) bool {
+	s.mu.Lock()
+	s.unsubscribe()
+	s.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		conn, err := s.dial(ctx)
+		if err == nil {
+			in := make(chan *types.Header)
+			var sub ethereum.Subscription
+			sub, err = conn.SubscribeNewHead(ctx, in)
+			if err == nil {
+				s.mu.Lock()
+				s.sub, s.in = sub, in
+				s.current.swap(conn).Close()
+				s.mu.Unlock()
+				s.cfg.Logger.Info("Header subscription reconnected", "attempt", attempt)
+				return s.emit(ctx, Event[C]{Connected: true, Conn: conn})
+			}
+			conn.Close()
+		}
+		s.cfg.Logger.Warn("Failed to reconnect header subscription, retrying",
+			"error", err,
+			"attempt", attempt,
+			"retryIn", s.cfg.RetryBackoff.String(),
+		)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(s.cfg.RetryBackoff):
+		}
+	}
+}
+
+// unsubscribe ends the current subscription. s.mu must be held.
+func (s *HeaderStream[C]) unsubscribe() {
+	if s.sub == nil {
+		return
+	}
+	s.sub.Unsubscribe()
+	s.sub = nil
+	s.in = nil
+}
+
+// emit delivers an event unless ctx is done first.
+func (s *HeaderStream[C]) emit(ctx context.Context, event Event[C]) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,219 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSub is a subscription that fails when told to.
+type fakeSub struct {
+	errc         chan error
+	unsubscribed atomic.Bool
+}
+
+func (s *fakeSub) Err() <-chan error { return s.errc }
+func (s *fakeSub) Unsubscribe()      { s.unsubscribed.Store(true) }
+
+// fakeConn is a connection whose headers are sent by the test.
+type fakeConn struct {
+	mu     sync.Mutex
+	ch     chan<- *types.Header
+	sub    *fakeSub
+	closed atomic.Bool
+}
+
+func (c *fakeConn) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ch = ch
+	c.sub = &fakeSub{errc: make(chan error, 1)}
+	return c.sub, nil
+}
+
+func (c *fakeConn) Close() { c.closed.Store(true) }
+
+// send delivers a header for block n. It is called from its own goroutine.
+func (c *fakeConn) send(t *testing.T, n int64) {
+	t.Helper()
+	c.mu.Lock()
+	ch := c.ch
+	c.mu.Unlock()
+	select {
+	case ch <- &types.Header{Number: big.NewInt(n)}:
+	case <-time.After(time.Second):
+		t.Error("stream did not take the header")
+	}
+}
+
+// dialer hands out new fake connections, after failing the first fails dials.
+type dialer struct {
+	fails atomic.Int32
+	conns chan *fakeConn
+}
+
+func newDialer(fails int32) *dialer {
+	d := &dialer{conns: make(chan *fakeConn, 10)}
+	d.fails.Store(fails)
+	return d
+}
+
+func (d *dialer) dial(ctx context.Context) (*fakeConn, error) {
+	if d.fails.Add(-1) >= 0 {
+		return nil, errors.New("connection refused")
+	}
+	conn := &fakeConn{}
+	d.conns <- conn
+	return conn, nil
+}
+
+func startStream(t *testing.T, d *dialer, cfg StreamConfig) (*HeaderStream[*fakeConn], *Current[*fakeConn], *fakeConn) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &fakeConn{}
+	current := NewCurrent(conn)
+	stream, err := NewHeaderStream(ctx, current, d.dial, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		cancel()
+		stream.Close()
+	})
+	return stream, current, conn
+}
+
+func receiveHeader(t *testing.T, stream *HeaderStream[*fakeConn]) uint64 {
+	t.Helper()
+	select {
+	case header := <-stream.Headers():
+		return header.Number.Uint64()
+	case <-time.After(time.Second):
+		t.Fatal("no header")
+		return 0
+	}
+}
+
+func receiveEvent(t *testing.T, stream *HeaderStream[*fakeConn]) Event[*fakeConn] {
+	t.Helper()
+	select {
+	case event := <-stream.Events():
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event")
+		return Event[*fakeConn]{}
+	}
+}
+
+func TestHeaderStreamReconnectsWhenStale(t *testing.T) {
+	d := newDialer(1)
+	stream, current, conn := startStream(t, d, StreamConfig{StaleTimeout: 50 * time.Millisecond, RetryBackoff: time.Millisecond})
+
+	go conn.send(t, 1)
+	require.Equal(t, uint64(1), receiveHeader(t, stream))
+
+	// Without another header the subscription is torn down and replaced
+	event := receiveEvent(t, stream)
+	require.False(t, event.Connected)
+	require.NoError(t, event.Err)
+	require.GreaterOrEqual(t, event.StaleFor, 50*time.Millisecond)
+	require.True(t, conn.sub.unsubscribed.Load())
+
+	// The first dial fails and is retried, and the old connection is closed once replaced
+	event = receiveEvent(t, stream)
+	require.True(t, event.Connected)
+	replacement := <-d.conns
+	require.Same(t, replacement, event.Conn)
+	require.Same(t, replacement, current.Get())
+	require.True(t, conn.closed.Load())
+
+	go replacement.send(t, 2)
+	require.Equal(t, uint64(2), receiveHeader(t, stream))
+}
+
+func TestHeaderStreamResetsOnEveryHeader(t *testing.T) {
+	stream, _, conn := startStream(t, newDialer(0), StreamConfig{StaleTimeout: 80 * time.Millisecond})
+
+	for n := int64(1); n <= 8; n++ {
+		time.Sleep(30 * time.Millisecond)
+		go conn.send(t, n)
+		require.Equal(t, uint64(n), receiveHeader(t, stream))
+	}
+	select {
+	case event := <-stream.Events():
+		t.Fatalf("headers arriving within the timeout reconnected: %+v", event)
+	default:
+	}
+}
+
+func TestHeaderStreamGracePeriod(t *testing.T) {
+	stream, _, _ := startStream(t, newDialer(0), StreamConfig{StaleTimeout: 50 * time.Millisecond, Grace: 150 * time.Millisecond})
+
+	// The first header may take the timeout plus the grace period
+	select {
+	case event := <-stream.Events():
+		t.Fatalf("reconnected during the grace period: %+v", event)
+	case <-time.After(150 * time.Millisecond):
+	}
+	event := receiveEvent(t, stream)
+	require.GreaterOrEqual(t, event.StaleFor, 200*time.Millisecond)
+}
+
+func TestHeaderStreamReconnectsOnSubscriptionError(t *testing.T) {
+	d := newDialer(0)
+	stream, _, conn := startStream(t, d, StreamConfig{})
+
+	conn.sub.errc <- errors.New("EOF")
+	event := receiveEvent(t, stream)
+	require.False(t, event.Connected)
+	require.EqualError(t, event.Err, "EOF")
+
+	event = receiveEvent(t, stream)
+	require.True(t, event.Connected)
+	go event.Conn.send(t, 3)
+	require.Equal(t, uint64(3), receiveHeader(t, stream))
+}
+
+func TestHeaderStreamCloseUnsubscribes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &fakeConn{}
+	stream, err := NewHeaderStream(ctx, NewCurrent(conn), newDialer(0).dial, StreamConfig{StaleTimeout: time.Hour})
+	require.NoError(t, err)
+
+	cancel()
+	require.NoError(t, stream.Close())
+	require.True(t, conn.sub.unsubscribed.Load())
+	require.True(t, conn.closed.Load())
+}
+
+func TestHeaderStreamConsumerFollowsStaleReconnect(t *testing.T) {
+	d := newDialer(0)
+	stream, current, conn := startStream(t, d, StreamConfig{StaleTimeout: 50 * time.Millisecond})
+
+	// A consumer resolving the connection on each call, as the nonce monitor does
+	errClosed := errors.New("use of closed connection")
+	call := func() error {
+		if current.Get().closed.Load() {
+			return errClosed
+		}
+		return nil
+	}
+	require.NoError(t, call())
+
+	// A quiet link goes stale; the consumer still works while the stream reconnects
+	event := receiveEvent(t, stream)
+	require.False(t, event.Connected)
+	require.NoError(t, call())
+
+	event = receiveEvent(t, stream)
+	require.True(t, event.Connected)
+	require.True(t, conn.closed.Load())
+	require.NoError(t, call())
+	require.Same(t, <-d.conns, current.Get())
+}
@@ -0,0 +1,111 @@
+// Package selftest reports the stages of a one-shot end-to-end bid.
+package selftest
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Stages of a self-test, in the order they are reached.
+const (
+	StageBuild      = "build transaction"
+	StageBid        = "send bid"
+	StageCommitment = "receive commitment"
+	StageInclusion  = "target block inclusion"
+)
+
+// Stages lists the stages in order.
+var Stages = []string{StageBuild, StageBid, StageCommitment, StageInclusion}
+
+// Result is the outcome of a stage.
+type Result struct {
+	Stage   string
+	Reached bool // Whether the stage has an outcome.
+	Passed  bool
+	Detail  string
+	Elapsed time.Duration // Since the self-test started.
+}
+
+// Report collects the outcome of each stage. It is safe for concurrent use.
+type Report struct {
+	start time.Time
+	now   func() time.Time
+
+	mu      sync.Mutex
+	results map[string]Result
+}
+
+// NewReport creates a Report for a self-test starting now.
+func NewReport() *Report {
+	return &Report{
+		start:   time.Now(),
+		now:     time.Now,
+		results: make(map[string]Result),
+	}
+}
+
+// Pass records that the stage passed. Only the first outcome of a stage is kept.
+func (r *Report) Pass(stage, detail string) {
+	r.record(stage, true, detail)
+}
+
+// Fail records that the stage failed. Only the first outcome of a stage is kept.
+func (r *Report) Fail(stage, detail string) {
+	r.record(stage, false, detail)
+}
+
+func (r *Report) record(stage string, passed bool, detail string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.results[stage]; ok {
+		return
+	}
+	r.results[stage] = Result{Stage: stage, Reached: true, Passed: passed, Detail: detail, Elapsed: r.now().Sub(r.start)}
+}
+
+// Results returns the outcome of every stage in order. Stages without an outcome
+// failed as not reached.
+func (r *Report) Results() []Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]Result, len(Stages))
+	for i, stage := range Stages {
+		result, ok := r.results[stage]
+		if !ok {
+			result = Result{Stage: stage, Detail: "not reached"}
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// Passed reports whether every stage passed.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results() {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Print writes a line per stage and the overall verdict.
+func (r *Report) Print(w io.Writer) {
+	for _, result := range r.Results() {
+		status, elapsed := "FAIL", "-"
+		if result.Passed {
+			status = "PASS"
+		}
+		if result.Reached {
+			elapsed = result.Elapsed.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%-4s  %-24s %8s  %s\n", status, result.Stage, elapsed, result.Detail)
+	}
+	if r.Passed() {
+		fmt.Fprintln(w, "selftest passed")
+	} else {
+		fmt.Fprintln(w, "selftest failed")
+	}
+}
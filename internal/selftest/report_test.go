@@ -0,0 +1,35 @@
+package selftest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	report := NewReport()
+	clock := report.start
+	report.now = func() time.Time { return clock }
+
+	clock = clock.Add(50 * time.Millisecond)
+	report.Pass(StageBuild, "tx 0x1")
+	clock = clock.Add(100 * time.Millisecond)
+	report.Pass(StageBid, "block 10")
+	report.Fail(StageBid, "ignored, the stage already passed")
+	require.False(t, report.Passed())
+
+	var out strings.Builder
+	report.Print(&out)
+	require.Equal(t, ""+
+		"PASS  build transaction            50ms  tx 0x1\n"+
+		"PASS  send bid                    150ms  block 10\n"+
+		"FAIL  receive commitment              -  not reached\n"+
+		"FAIL  target block inclusion          -  not reached\n"+
+		"selftest failed\n", out.String())
+
+	report.Pass(StageCommitment, "")
+	report.Pass(StageInclusion, "")
+	require.True(t, report.Passed())
+}
@@ -0,0 +1,94 @@
+package shutdown
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Drain tracks the target blocks of unresolved bids so a draining instance can
+// stop bidding but keep running until those bids' blocks are final.
+type Drain struct {
+	confirmations uint64
+
+	mu         sync.Mutex
+	draining   bool
+	deadline   time.Time
+	unresolved map[uint64]int // Target block -> number of bids on it.
+}
+
+// NewDrain creates a Drain that treats a bid as resolved once its target block
+// has the given number of confirmations on top of it.
+func NewDrain(confirmations uint64) *Drain {
+	return &Drain{
+		confirmations: confirmations,
+		unresolved:    make(map[uint64]int),
+	}
+}
+
+// Track records a bid on the target block.
+func (d *Drain) Track(targetBlock uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unresolved[targetBlock]++
+}
+
+// Observe resolves the bids whose target block is confirmed at the given head.
+func (d *Drain) Observe(head uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for block := range d.unresolved {
+		if block+d.confirmations <= head {
+			delete(d.unresolved, block)
+		}
+	}
+}
+
+// Start begins draining, giving up on unresolved bids after timeout. Calling it
+// again while draining has no effect.
+func (d *Drain) Start(now time.Time, timeout time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return
+	}
+	d.draining = true
+	d.deadline = now.Add(timeout)
+}
+
+// Draining reports whether draining has started, in which case no new bids should be sent.
+func (d *Drain) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Unresolved returns the number of bids whose target block isn't confirmed yet.
+func (d *Drain) Unresolved() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var n int
+	for _, count := range d.unresolved {
+		n += count
+	}
+	return n
+}
+
+// Done reports whether a drain has finished: every bid is resolved or the drain timed out.
+func (d *Drain) Done(now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining && (len(d.unresolved) == 0 || !now.Before(d.deadline))
+}
+
+// ReadinessHandler answers 200 until draining starts and 503 afterwards, so
+// orchestrators stop routing to an instance that is about to exit.
+func (d *Drain) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d.Draining() {
+			http.Error(w, "draining", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+}
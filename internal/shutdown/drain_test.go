@@ -0,0 +1,69 @@
+package shutdown
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainWaitsForUnresolvedBids(t *testing.T) {
+	d := NewDrain(2)
+	now := time.Unix(1_700_000_000, 0)
+
+	d.Track(101)
+	d.Track(102)
+	d.Observe(100)
+	require.False(t, d.Done(now), "not draining yet")
+
+	d.Start(now, time.Minute)
+	require.True(t, d.Draining())
+	require.Equal(t, 2, d.Unresolved())
+
+	// Block 101 needs head 103 to have two confirmations
+	d.Observe(102)
+	require.Equal(t, 2, d.Unresolved())
+	require.False(t, d.Done(now.Add(12*time.Second)))
+
+	d.Observe(103)
+	require.Equal(t, 1, d.Unresolved())
+	require.False(t, d.Done(now.Add(24*time.Second)))
+
+	d.Observe(104)
+	require.Equal(t, 0, d.Unresolved())
+	require.True(t, d.Done(now.Add(36*time.Second)))
+}
+
+func TestDrainTimesOut(t *testing.T) {
+	d := NewDrain(2)
+	now := time.Unix(1_700_000_000, 0)
+	d.Track(101)
+	d.Track(102)
+
+	d.Start(now, 30*time.Second)
+	// A second signal doesn't extend the deadline
+	d.Start(now.Add(20*time.Second), 30*time.Second)
+
+	require.False(t, d.Done(now.Add(29*time.Second)))
+	require.True(t, d.Done(now.Add(30*time.Second)))
+	require.Equal(t, 2, d.Unresolved())
+}
+
+func TestDrainReadiness(t *testing.T) {
+	d := NewDrain(0)
+	srv := httptest.NewServer(d.ReadinessHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	d.Start(time.Now(), time.Minute)
+	resp, err = http.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
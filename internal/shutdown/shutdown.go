@@ -0,0 +1,101 @@
+// Package shutdown coordinates how the bidder stops: draining unresolved bids
+// before exit, and closing its writers in a fixed order so the last log lines
+// and audit records reach disk.
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Close orders. Lower orders are closed first; writers that others log
+// through, like the logger, should close last.
+const (
+	OrderClients = 0
+	OrderWriters = 50
+	OrderLogger  = 100
+)
+
+type entry struct {
+	name   string
+	order  int
+	closer io.Closer
+}
+
+// Registry holds the closers to run on shutdown.
+type Registry struct {
+	mu      sync.Mutex
+	entries []entry
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a closer to run on shutdown. Closers with the same order run
+// in reverse registration order, like defers.
+func (r *Registry) Register(name string, order int, c io.Closer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry{name: name, order: order, closer: c})
+}
+
+// Close runs the registered closers in order and empties the registry, so a
+// second call is a no-op.
+//
+// Parameters:
+// - timeout: How long to wait for all closers before giving up on the remaining ones.
+//
+// Returns:
+// - The joined close errors, or an error naming the closers still pending at the timeout.
+func (r *Registry) Close(timeout time.Duration) error {
+	r.mu.Lock()
+	entries := r.entries
+	r.entries = nil
+	r.mu.Unlock()
+
+	// Reverse first so the stable sort keeps same-order closers in LIFO order
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].order < entries[j].order })
+
+	var (
+		mu      sync.Mutex
+		errs    []error
+		pending = len(entries)
+		current string
+	)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, e := range entries {
+			mu.Lock()
+			current = e.name
+			mu.Unlock()
+			err := e.closer.Close()
+			mu.Lock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("close %s: %w", e.name, err))
+			}
+			pending--
+			mu.Unlock()
+		}
+	}()
+
+	select {
+	case <-done:
+		return errors.Join(errs...)
+	case <-time.After(timeout):
+		mu.Lock()
+		defer mu.Unlock()
+		slog.Warn("Shutdown timed out closing writers", "closing", current, "pending", pending)
+		return errors.Join(append(errs, fmt.Errorf("shutdown timed out after %s closing %s (%d pending)", timeout, current, pending))...)
+	}
+}
@@ -0,0 +1,55 @@
+package shutdown
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestRegistryClosesInOrder(t *testing.T) {
+	r := NewRegistry()
+	var closed []string
+	record := func(name string) closerFunc {
+		return func() error { closed = append(closed, name); return nil }
+	}
+
+	r.Register("logger", OrderLogger, record("logger"))
+	r.Register("audit", OrderWriters, record("audit"))
+	r.Register("bidder", OrderClients, record("bidder"))
+	r.Register("commitments", OrderWriters, record("commitments"))
+
+	require.NoError(t, r.Close(time.Second))
+	require.Equal(t, []string{"bidder", "commitments", "audit", "logger"}, closed)
+
+	// A second close is a no-op
+	require.NoError(t, r.Close(time.Second))
+	require.Len(t, closed, 4)
+}
+
+func TestRegistryCollectsErrors(t *testing.T) {
+	r := NewRegistry()
+	r.Register("bad", OrderWriters, closerFunc(func() error { return errors.New("disk full") }))
+	ran := false
+	r.Register("logger", OrderLogger, closerFunc(func() error { ran = true; return nil }))
+
+	err := r.Close(time.Second)
+	require.ErrorContains(t, err, "close bad: disk full")
+	require.True(t, ran)
+}
+
+func TestRegistryTimeout(t *testing.T) {
+	r := NewRegistry()
+	block := make(chan struct{})
+	defer close(block)
+	r.Register("stuck", OrderWriters, closerFunc(func() error { <-block; return nil }))
+
+	err := r.Close(10 * time.Millisecond)
+	require.ErrorContains(t, err, "timed out")
+	require.ErrorContains(t, err, "stuck")
+}
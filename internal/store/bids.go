@@ -0,0 +1,213 @@
+// Package store keeps a history of the bids sent, for analysis against
+// on-chain settlement after the fact.
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Formats of the bid history, chosen with BID_LOG_FORMAT.
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// ResultDryRun is the result of a bid that was only logged under DRY_RUN.
+const ResultDryRun = "dry_run"
+
+// Modes of a bid in its BidEntry.
+const (
+	ModePayload = "payload" // The bid carried the raw transactions.
+	ModeHash    = "hash"    // The bid carried only transaction hashes.
+)
+
+// defaultBufferSize is the number of entries that can wait for the writer
+// before new ones are dropped.
+const defaultBufferSize = 1024
+
+// megabyte is the unit of BidRecorderConfig.MaxSizeMB.
+const megabyte = 1 << 20
+
+// csvHeader names the columns of the CSV format, in BidEntry order.
+var csvHeader = []string{"sent_at", "block_number", "tx_hashes", "amount_wei", "decay_start", "decay_end", "mode", "result", "commitments", "error"}
+
+// BidEntry is one bid in the bid history.
+type BidEntry struct {
+	SentAt      int64    `json:"sent_at"`      // Unix milliseconds.
+	BlockNumber int64    `json:"block_number"` // Target block of the bid.
+	TxHashes    []string `json:"tx_hashes"`
+	AmountWei   string   `json:"amount_wei"`
+	DecayStart  int64    `json:"decay_start"` // Unix milliseconds.
+	DecayEnd    int64    `json:"decay_end"`   // Unix milliseconds.
+	Mode        string   `json:"mode"`        // ModePayload or ModeHash.
+	Result      string   `json:"result"`      // From mevcommit.ClassifyBid, or ResultDryRun.
+	Commitments int      `json:"commitments"` // Commitments received for the bid.
+	Error       string   `json:"error,omitempty"`
+}
+
+// BidRecorderConfig configures a BidRecorder.
+type BidRecorderConfig struct {
+	Path       string // The file to append entries to.
+	Format     string // FormatJSONL or FormatCSV, FormatJSONL if empty.
+	MaxSizeMB  int    // Size at which the file is rotated, 0 to never rotate it.
+	MaxBackups int    // Number of rotated files to keep, at least 1.
+	BufferSize int    // Entries that can wait for the writer, defaultBufferSize if 0.
+
+	maxBytes int64 // Overrides MaxSizeMB in tests.
+}
+
+// BidRecorder appends an entry per bid to the bid history. Entries are written
+// by a background goroutine, so recording never blocks the bid that made it.
+type BidRecorder struct {
+	format  string
+	file    *rotatingFile
+	entries chan BidEntry
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	mu     sync.RWMutex // Keeps Record from sending on entries once it is closed.
+	closed bool
+}
+
+// NewBidRecorder opens the bid history file and starts its writer.
+//
+// Parameters:
+// - cfg: The file, format and rotation.
+//
+// Returns:
+// - A pointer to the BidRecorder, or an error for an unknown format or a file
+// that can't be opened.
+func NewBidRecorder(cfg BidRecorderConfig) (*BidRecorder, error) {
+	format := strings.ToLower(cfg.Format)
+	var header []byte
+	switch format {
+	case "", FormatJSONL:
+		format = FormatJSONL
+	case FormatCSV:
+		var err error
+		if header, err = csvLine(csvHeader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown bid log format %q (must be %s or %s)", cfg.Format, FormatJSONL, FormatCSV)
+	}
+
+	maxBytes := cfg.maxBytes
+	if maxBytes == 0 {
+		maxBytes = int64(cfg.MaxSizeMB) * megabyte
+	}
+	file, err := openRotatingFile(cfg.Path, maxBytes, cfg.MaxBackups, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bid log: %w", err)
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	r := &BidRecorder{
+		format:  format,
+		file:    file,
+		entries: make(chan BidEntry, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go r.write()
+	return r, nil
+}
+
+// Record queues an entry for writing. When the writer has fallen a full buffer
+// behind, the entry is dropped and counted instead of waiting.
+func (r *BidRecorder) Record(entry BidEntry) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		r.dropped.Add(1)
+		return
+	}
+	select {
+	case r.entries <- entry:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of entries dropped because the buffer was full or
+// the recorder closed.
+func (r *BidRecorder) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// write encodes queued entries to the file until the recorder is closed.
+func (r *BidRecorder) write() {
+	defer close(r.done)
+	for entry := range r.entries {
+		line, err := r.encode(entry)
+		if err == nil {
+			_, err = r.file.Write(line)
+		}
+		if err != nil {
+			slog.Error("Failed to write bid log entry", "err", err, "blockNumber", entry.BlockNumber)
+		}
+	}
+}
+
+// encode returns the line of an entry in the recorder's format.
+func (r *BidRecorder) encode(entry BidEntry) ([]byte, error) {
+	if r.format == FormatCSV {
+		return csvLine([]string{
+			strconv.FormatInt(entry.SentAt, 10),
+			strconv.FormatInt(entry.BlockNumber, 10),
+			strings.Join(entry.TxHashes, ";"),
+			entry.AmountWei,
+			strconv.FormatInt(entry.DecayStart, 10),
+			strconv.FormatInt(entry.DecayEnd, 10),
+			entry.Mode,
+			entry.Result,
+			strconv.Itoa(entry.Commitments),
+			entry.Error,
+		})
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// csvLine encodes one CSV record with its line ending.
+func csvLine(fields []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(fields); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Close stops accepting entries, waits for the queued ones to be written, and
+// syncs and closes the file.
+func (r *BidRecorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.entries)
+	r.mu.Unlock()
+
+	<-r.done
+	if dropped := r.Dropped(); dropped > 0 {
+		slog.Warn("Bid log entries dropped", "dropped", dropped)
+	}
+	return r.file.Close()
+}
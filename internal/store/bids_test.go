@@ -0,0 +1,159 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEntry(block int64) BidEntry {
+	return BidEntry{
+		SentAt:      1_700_000_000_000,
+		BlockNumber: block,
+		TxHashes:    []string{"0xaa", "0xbb"},
+		AmountWei:   "1000000000000000",
+		DecayStart:  1_700_000_000_000,
+		DecayEnd:    1_700_000_012_000,
+		Mode:        ModeHash,
+		Result:      "committed",
+		Commitments: 2,
+	}
+}
+
+// readLines returns the lines of a file.
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestBidRecorderFlushesOnClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bids.jsonl")
+	r, err := NewBidRecorder(BidRecorderConfig{Path: path})
+	require.NoError(t, err)
+	for block := int64(1); block <= 100; block++ {
+		r.Record(testEntry(block))
+	}
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close(), "closing twice is harmless")
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 100)
+	var entry BidEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[99]), &entry))
+	require.Equal(t, testEntry(100), entry)
+
+	// Entries recorded after closing are dropped, not written
+	r.Record(testEntry(101))
+	require.Equal(t, uint64(1), r.Dropped())
+	require.Len(t, readLines(t, path), 100)
+}
+
+func TestBidRecorderCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bids.csv")
+	r, err := NewBidRecorder(BidRecorderConfig{Path: path, Format: "CSV"})
+	require.NoError(t, err)
+	entry := testEntry(7)
+	entry.Result = "failed"
+	entry.Error = "rpc error: code = Unavailable, desc = down"
+	r.Record(entry)
+	require.NoError(t, r.Close())
+
+	// Reopening an existing file doesn't repeat the header
+	r, err = NewBidRecorder(BidRecorderConfig{Path: path, Format: FormatCSV})
+	require.NoError(t, err)
+	r.Record(testEntry(8))
+	require.NoError(t, r.Close())
+
+	require.Equal(t, []string{
+		"sent_at,block_number,tx_hashes,amount_wei,decay_start,decay_end,mode,result,commitments,error",
+		`1700000000000,7,0xaa;0xbb,1000000000000000,1700000000000,1700000012000,hash,failed,2,"rpc error: code = Unavailable, desc = down"`,
+		"1700000000000,8,0xaa;0xbb,1000000000000000,1700000000000,1700000012000,hash,committed,2,",
+	}, readLines(t, path))
+}
+
+func TestBidRecorderUnknownFormat(t *testing.T) {
+	_, err := NewBidRecorder(BidRecorderConfig{Path: filepath.Join(t.TempDir(), "bids.xml"), Format: "xml"})
+	require.ErrorContains(t, err, "unknown bid log format")
+}
+
+func TestBidRecorderRotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bids.csv")
+	r, err := NewBidRecorder(BidRecorderConfig{Path: path, Format: FormatCSV, MaxBackups: 2, maxBytes: 400})
+	require.NoError(t, err)
+	for block := int64(1); block <= 20; block++ {
+		r.Record(testEntry(block))
+	}
+	require.NoError(t, r.Close())
+
+	// Only the current file and two backups are kept, each starting with the
+	// header and within the size limit unless it holds a single entry
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{path, path + ".1", path + ".2"}, files)
+	var blocks []int
+	for _, file := range []string{path + ".2", path + ".1", path} {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		require.LessOrEqual(t, info.Size(), int64(400))
+		lines := readLines(t, file)
+		require.True(t, strings.HasPrefix(lines[0], "sent_at,"))
+		for _, line := range lines[1:] {
+			block, err := strconv.Atoi(strings.Split(line, ",")[1])
+			require.NoError(t, err)
+			blocks = append(blocks, block)
+		}
+	}
+	// The kept files hold the latest entries, in order
+	require.NotEmpty(t, blocks)
+	require.Equal(t, 20, blocks[len(blocks)-1])
+	for i := 1; i < len(blocks); i++ {
+		require.Less(t, blocks[i-1], blocks[i])
+	}
+}
+
+func TestBidRecorderConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bids.jsonl")
+	r, err := NewBidRecorder(BidRecorderConfig{Path: path, BufferSize: 1000})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 10; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				r.Record(testEntry(int64(worker*100 + i)))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, r.Close())
+
+	// Every entry is written whole, or counted as dropped if the buffer filled up
+	lines := readLines(t, path)
+	require.Equal(t, 1000, len(lines)+int(r.Dropped()))
+	seen := make(map[int64]bool)
+	for _, line := range lines {
+		var entry BidEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		require.False(t, seen[entry.BlockNumber])
+		seen[entry.BlockNumber] = true
+	}
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// rotatingFile appends to a file that is rotated once a write would take it past
+// maxBytes. Rotated files are numbered like logrotate's, path.1 being the newest,
+// and only maxBackups of them are kept.
+type rotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	header     []byte // Written at the start of every new file, nil for none.
+
+	file *os.File
+	size int64
+}
+
+// openRotatingFile opens path for appending, writing the header if it is new.
+func openRotatingFile(path string, maxBytes int64, maxBackups int, header []byte) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: max(maxBackups, 1),
+		header:     header,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the current file and writes the header to it if it is empty.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat %s: %w", f.path, err)
+	}
+	f.file = file
+	f.size = info.Size()
+	if f.size == 0 && len(f.header) > 0 {
+		n, err := file.Write(f.header)
+		f.size += int64(n)
+		if err != nil {
+			return fmt.Errorf("failed to write header to %s: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// Write appends p whole to the current file, rotating first if p would take a
+// file that already holds records past maxBytes.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	if f.maxBytes > 0 && f.size > int64(len(f.header)) && f.size+int64(len(p)) > f.maxBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file to path.1, shifting older files up and dropping
+// the one beyond maxBackups, and starts a new file.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", f.path, err)
+	}
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(backupPath(f.path, i), backupPath(f.path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate %s: %w", f.path, err)
+		}
+	}
+	if err := os.Rename(f.path, backupPath(f.path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", f.path, err)
+	}
+	return f.open()
+}
+
+// Close syncs and closes the current file.
+func (f *rotatingFile) Close() error {
+	syncErr := f.file.Sync()
+	err := f.file.Close()
+	if err == nil {
+		err = syncErr
+	}
+	return err
+}
+
+// backupPath returns the path of the nth most recent rotated file.
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
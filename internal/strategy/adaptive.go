@@ -0,0 +1,53 @@
+package strategy
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// AdaptiveBlobCount sends fewer blobs per transaction when blob space is expensive
+// and more when it is cheap. At or below the low fee it sends the maximum, at or
+// above the high fee the minimum, and in between it scales linearly.
+type AdaptiveBlobCount struct {
+	min     uint
+	max     uint
+	lowFee  *big.Int
+	highFee *big.Int
+}
+
+// NewAdaptiveBlobCount creates an AdaptiveBlobCount.
+//
+// Parameters:
+// - min: Blobs per transaction when blob space is expensive, at least 1.
+// - max: Blobs per transaction when blob space is cheap, at least min.
+// - lowFeeWei: Blob base fee at or below which the maximum is sent.
+// - highFeeWei: Blob base fee at or above which the minimum is sent, above lowFeeWei.
+//
+// Returns:
+// - A pointer to an AdaptiveBlobCount, or an error if the bounds or thresholds are invalid.
+func NewAdaptiveBlobCount(min, max uint, lowFeeWei, highFeeWei *big.Int) (*AdaptiveBlobCount, error) {
+	if min < 1 || min > max {
+		return nil, fmt.Errorf("adaptive blob count bounds [%d, %d] must satisfy 1 <= min <= max", min, max)
+	}
+	if lowFeeWei.Cmp(highFeeWei) >= 0 {
+		return nil, fmt.Errorf("adaptive blob count low fee %s wei must be below high fee %s wei", lowFeeWei, highFeeWei)
+	}
+	return &AdaptiveBlobCount{min: min, max: max, lowFee: lowFeeWei, highFee: highFeeWei}, nil
+}
+
+// Count returns the blobs per transaction at the given blob base fee. Without a
+// blob base fee, the minimum is sent.
+func (a *AdaptiveBlobCount) Count(blobBaseFee *big.Int) uint {
+	switch {
+	case blobBaseFee == nil || blobBaseFee.Cmp(a.highFee) >= 0:
+		return a.min
+	case blobBaseFee.Cmp(a.lowFee) <= 0:
+		return a.max
+	}
+	// Drop one blob for each equal share of the fee range crossed
+	above := new(big.Int).Sub(blobBaseFee, a.lowFee)
+	span := new(big.Int).Sub(a.highFee, a.lowFee)
+	steps := new(big.Int).Mul(above, new(big.Int).SetUint64(uint64(a.max-a.min)))
+	steps.Quo(steps, span)
+	return a.max - uint(steps.Uint64())
+}
@@ -0,0 +1,47 @@
+package strategy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveBlobCountMapsFeesToCounts(t *testing.T) {
+	// 1 to 6 blobs between 1 and 11 gwei, one blob less every 2 gwei
+	a, err := NewAdaptiveBlobCount(1, 6, big.NewInt(1e9), big.NewInt(11e9))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		feeWei int64
+		want   uint
+	}{
+		{1, 6},
+		{1e9, 6},
+		{2e9, 6},
+		{3e9, 5},
+		{6e9, 4},
+		{8e9, 3},
+		{10e9, 2},
+		{10999999999, 2},
+		{11e9, 1},
+		{500e9, 1},
+	} {
+		require.Equal(t, tc.want, a.Count(big.NewInt(tc.feeWei)), "fee %d wei", tc.feeWei)
+	}
+	require.Equal(t, uint(1), a.Count(nil), "no blob base fee before Cancun")
+}
+
+func TestAdaptiveBlobCountRejectsInvalidSettings(t *testing.T) {
+	_, err := NewAdaptiveBlobCount(0, 6, big.NewInt(1), big.NewInt(2))
+	require.Error(t, err)
+	_, err = NewAdaptiveBlobCount(4, 3, big.NewInt(1), big.NewInt(2))
+	require.Error(t, err)
+	_, err = NewAdaptiveBlobCount(1, 6, big.NewInt(2), big.NewInt(2))
+	require.Error(t, err)
+
+	fixed, err := NewAdaptiveBlobCount(3, 3, big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err)
+	require.Equal(t, uint(3), fixed.Count(big.NewInt(1)))
+	require.Equal(t, uint(3), fixed.Count(big.NewInt(5)))
+}
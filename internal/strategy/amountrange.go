@@ -0,0 +1,55 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+)
+
+// BidAmountRange bounds every bid amount after profile sampling and escalation,
+// so one build can run on networks with very different gas economics. A zero
+// bound is unset.
+type BidAmountRange struct {
+	MinEth float64
+	MaxEth float64
+}
+
+// NewBidAmountRange creates a BidAmountRange, checking that set bounds are
+// positive and that the minimum doesn't exceed the maximum.
+//
+// Parameters:
+// - minEth: The smallest bid amount in ETH, 0 for no minimum.
+// - maxEth: The largest bid amount in ETH, 0 for no maximum.
+//
+// Returns:
+// - The BidAmountRange, or an error if the bounds are invalid.
+func NewBidAmountRange(minEth, maxEth float64) (BidAmountRange, error) {
+	if minEth < 0 || maxEth < 0 {
+		return BidAmountRange{}, fmt.Errorf("bid amount bounds must be positive")
+	}
+	if minEth > 0 && maxEth > 0 && minEth > maxEth {
+		return BidAmountRange{}, fmt.Errorf("minimum bid amount %g ETH exceeds maximum %g ETH", minEth, maxEth)
+	}
+	return BidAmountRange{MinEth: minEth, MaxEth: maxEth}, nil
+}
+
+// Clamp returns the amount moved into the range.
+func (r BidAmountRange) Clamp(amountEth float64) float64 {
+	if r.MinEth > 0 {
+		amountEth = math.Max(amountEth, r.MinEth)
+	}
+	if r.MaxEth > 0 {
+		amountEth = math.Min(amountEth, r.MaxEth)
+	}
+	return amountEth
+}
+
+// String describes the range for logging, e.g. "[0.0002, 0.001] ETH".
+func (r BidAmountRange) String() string {
+	bound := func(v float64, unset string) string {
+		if v > 0 {
+			return fmt.Sprintf("%g", v)
+		}
+		return unset
+	}
+	return fmt.Sprintf("[%s, %s] ETH", bound(r.MinEth, "0"), bound(r.MaxEth, "unbounded"))
+}
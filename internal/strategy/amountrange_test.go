@@ -0,0 +1,36 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidAmountRangeClamps(t *testing.T) {
+	r, err := NewBidAmountRange(0.0002, 0.001)
+	require.NoError(t, err)
+	require.Equal(t, 0.0002, r.Clamp(0.0001))
+	require.Equal(t, 0.0005, r.Clamp(0.0005))
+	require.Equal(t, 0.001, r.Clamp(0.003))
+	require.Equal(t, "[0.0002, 0.001] ETH", r.String())
+
+	// Unset bounds leave amounts alone
+	unbounded, err := NewBidAmountRange(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0.003, unbounded.Clamp(0.003))
+	require.Equal(t, "[0, unbounded] ETH", unbounded.String())
+
+	minOnly, err := NewBidAmountRange(0.0002, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0.0002, minOnly.Clamp(0.0001))
+	require.Equal(t, 5.0, minOnly.Clamp(5))
+}
+
+func TestBidAmountRangeRejectsInvalidBounds(t *testing.T) {
+	_, err := NewBidAmountRange(0.001, 0.0002)
+	require.Error(t, err)
+	_, err = NewBidAmountRange(-0.001, 0.002)
+	require.Error(t, err)
+	_, err = NewBidAmountRange(0.001, -1)
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxBlobsPerTx is the most blobs a transaction can carry: EIP-4844 fits at
+// most 6 blobs in a block.
+const MaxBlobsPerTx = 6
+
+// ParseBlobCounts parses a comma-separated list of blob counts, e.g. "1,3,6".
+//
+// Returns:
+// - The counts in order, or an error if one isn't between 1 and MaxBlobsPerTx.
+func ParseBlobCounts(s string) ([]uint, error) {
+	var counts []uint
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		n, err := strconv.ParseUint(field, 10, 64)
+		if err != nil || n < 1 || n > MaxBlobsPerTx {
+			return nil, fmt.Errorf("blob count %q must be between 1 and %d", field, MaxBlobsPerTx)
+		}
+		counts = append(counts, uint(n))
+	}
+	return counts, nil
+}
+
+// BlobCycle sweeps blob counts, sending the next count of the list on each new
+// block and starting over after the last.
+type BlobCycle struct {
+	counts []uint
+
+	mu      sync.Mutex
+	next    int
+	block   uint64
+	current uint
+}
+
+// NewBlobCycle creates a BlobCycle over counts, which must not be empty.
+func NewBlobCycle(counts []uint) *BlobCycle {
+	return &BlobCycle{counts: counts}
+}
+
+// Count returns the blob count for a block. Every transaction built for the same
+// block gets the same count.
+func (c *BlobCycle) Count(blockNumber uint64) uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.current == 0 || blockNumber != c.block {
+		c.current = c.counts[c.next]
+		c.next = (c.next + 1) % len(c.counts)
+		c.block = blockNumber
+	}
+	return c.current
+}
+
+// String returns the counts as a comma-separated list.
+func (c *BlobCycle) String() string {
+	fields := make([]string, len(c.counts))
+	for i, n := range c.counts {
+		fields[i] = strconv.FormatUint(uint64(n), 10)
+	}
+	return strings.Join(fields, ",")
+}
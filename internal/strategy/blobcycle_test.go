@@ -0,0 +1,36 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlobCounts(t *testing.T) {
+	counts, err := ParseBlobCounts("1, 3,6")
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 3, 6}, counts)
+
+	counts, err = ParseBlobCounts("4")
+	require.NoError(t, err)
+	require.Equal(t, []uint{4}, counts)
+
+	for _, invalid := range []string{"", "0", "7", "1,,3", "-1", "two", "1,7"} {
+		_, err := ParseBlobCounts(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestBlobCycleAdvancesOnEachNewBlock(t *testing.T) {
+	c := NewBlobCycle([]uint{1, 3, 6})
+	require.Equal(t, "1,3,6", c.String())
+
+	require.Equal(t, uint(1), c.Count(100))
+	require.Equal(t, uint(1), c.Count(100)) // Same block, same count
+	require.Equal(t, uint(3), c.Count(101))
+	require.Equal(t, uint(6), c.Count(102))
+	require.Equal(t, uint(1), c.Count(103))
+
+	// Skipped blocks don't skip counts
+	require.Equal(t, uint(3), c.Count(110))
+}
@@ -0,0 +1,91 @@
+package strategy
+
+import (
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// BidAmountEscalator raises bid amounts after consecutive missed preconfs to avoid
+// persistently under-bidding. Once the last missedBids bids have all been missed,
+// each further miss escalates the amount by stepPct, compounding, up to
+// maxMultiplier times the base amount. An inclusion resets it to the base amount.
+type BidAmountEscalator struct {
+	missedBids    uint
+	stepPct       float64
+	maxMultiplier float64
+
+	mu     sync.Mutex
+	misses uint
+	level  int
+}
+
+// NewBidAmountEscalator creates a BidAmountEscalator.
+//
+// Parameters:
+// - missedBids: Consecutive missed bids before escalating, 0 to disable escalation.
+// - stepPct: Percentage the amount is raised by on each escalation.
+// - maxMultiplier: Cap on the escalated amount as a multiple of the base amount.
+//
+// Returns:
+// - A pointer to a BidAmountEscalator.
+func NewBidAmountEscalator(missedBids uint, stepPct, maxMultiplier float64) *BidAmountEscalator {
+	return &BidAmountEscalator{
+		missedBids:    missedBids,
+		stepPct:       stepPct,
+		maxMultiplier: maxMultiplier,
+	}
+}
+
+// Observe updates the escalation from a resolved bid's status. Bids of unknown
+// status leave the escalation unchanged.
+func (e *BidAmountEscalator) Observe(status string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch status {
+	case api.BidStatusIncluded:
+		e.misses = 0
+		e.level = 0
+	case api.BidStatusMissed:
+		e.misses++
+		if e.missedBids > 0 && e.misses >= e.missedBids && e.multiplier(e.level) < e.maxMultiplier {
+			e.level++
+		}
+	}
+}
+
+// Level returns the number of escalations currently applied.
+func (e *BidAmountEscalator) Level() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.level
+}
+
+// Apply returns the base amount scaled by the current escalation.
+func (e *BidAmountEscalator) Apply(baseAmount float64) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return baseAmount * e.multiplier(e.level)
+}
+
+// ApplyWei returns the base amount in wei scaled by the current escalation. The
+// amount is exact until it escalates.
+func (e *BidAmountEscalator) ApplyWei(baseWei *big.Int) *big.Int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.level == 0 {
+		return new(big.Int).Set(baseWei)
+	}
+	scaled := new(big.Float).Mul(new(big.Float).SetInt(baseWei), big.NewFloat(e.multiplier(e.level)))
+	wei, _ := scaled.Int(nil)
+	return wei
+}
+
+// multiplier returns the capped amount multiplier for an escalation level.
+func (e *BidAmountEscalator) multiplier(level int) float64 {
+	m := math.Pow(1+e.stepPct/100, float64(level))
+	return math.Min(m, math.Max(e.maxMultiplier, 1))
+}
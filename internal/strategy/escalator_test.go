@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidAmountEscalator(t *testing.T) {
+	e := NewBidAmountEscalator(3, 25, 2)
+
+	// Two misses are below the threshold
+	e.Observe(api.BidStatusMissed)
+	e.Observe(api.BidStatusMissed)
+	require.Equal(t, 0, e.Level())
+	require.Equal(t, 1.0, e.Apply(1))
+
+	// Every miss from the third on escalates by 25%, compounding
+	e.Observe(api.BidStatusMissed)
+	require.Equal(t, 1, e.Level())
+	require.Equal(t, 1.25, e.Apply(1))
+	e.Observe(api.BidStatusMissed)
+	require.InDelta(t, 1.5625, e.Apply(1), 1e-9)
+
+	// Unknown outcomes don't count either way
+	e.Observe(api.BidStatusUnknown)
+	require.Equal(t, 2, e.Level())
+
+	// Capped at 2x the base amount
+	for i := 0; i < 10; i++ {
+		e.Observe(api.BidStatusMissed)
+	}
+	require.Equal(t, 2.0, e.Apply(1))
+	require.Equal(t, 4, e.Level(), "the level stops rising once capped")
+
+	// An inclusion resets to the base amount and the miss count
+	e.Observe(api.BidStatusIncluded)
+	require.Equal(t, 0, e.Level())
+	require.Equal(t, 0.5, e.Apply(0.5))
+	e.Observe(api.BidStatusMissed)
+	require.Equal(t, 0, e.Level())
+}
+
+func TestBidAmountEscalatorApplyWei(t *testing.T) {
+	e := NewBidAmountEscalator(1, 25, 2)
+	base, _ := new(big.Int).SetString("12345678901234567891", 10)
+
+	// Unescalated amounts are exact, even past an int64 of wei
+	require.Equal(t, base.String(), e.ApplyWei(base).String())
+
+	e.Observe(api.BidStatusMissed)
+	require.Equal(t, "1250000000000000000", e.ApplyWei(big.NewInt(1e18)).String())
+}
+
+func TestBidAmountEscalatorDisabled(t *testing.T) {
+	e := NewBidAmountEscalator(0, 25, 5)
+	for i := 0; i < 10; i++ {
+		e.Observe(api.BidStatusMissed)
+	}
+	require.Equal(t, 0, e.Level())
+	require.Equal(t, 1.0, e.Apply(1))
+}
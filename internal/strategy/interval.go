@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"sync"
+	"time"
+)
+
+// SendInterval limits how often transactions are created: every Nth block, or
+// at most once per wall-clock interval. A block interval takes precedence over a
+// time interval when both are set.
+type SendInterval struct {
+	blocks uint64
+	every  time.Duration
+
+	mu        sync.Mutex
+	sent      bool
+	lastBlock uint64
+	lastAt    time.Time
+}
+
+// NewSendInterval creates a SendInterval.
+//
+// Parameters:
+// - blocks: Blocks from one send to the next, 0 or 1 for every block.
+// - every: Minimum time from one send to the next, 0 for no limit. Ignored if
+// blocks is above 1.
+//
+// Returns:
+// - A pointer to a SendInterval.
+func NewSendInterval(blocks uint64, every time.Duration) *SendInterval {
+	if blocks > 1 {
+		every = 0
+	}
+	return &SendInterval{blocks: blocks, every: every}
+}
+
+// Due reports whether transactions are created for the block, and if so counts
+// the interval from it. The first block is always due.
+//
+// Parameters:
+// - block: The number of the header received.
+// - now: When it was received.
+//
+// Returns:
+// - true if the interval has elapsed since the last send.
+func (s *SendInterval) Due(block uint64, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sent {
+		if s.blocks > 1 && block < s.lastBlock+s.blocks {
+			return false
+		}
+		if s.every > 0 && now.Sub(s.lastAt) < s.every {
+			return false
+		}
+	}
+	s.sent = true
+	s.lastBlock = block
+	s.lastAt = now
+	return true
+}
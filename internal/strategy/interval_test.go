@@ -0,0 +1,49 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendIntervalEveryNthBlock(t *testing.T) {
+	interval := NewSendInterval(3, 0)
+	start := time.Unix(1_700_000_000, 0)
+	var due []uint64
+	for block := uint64(100); block < 110; block++ {
+		if interval.Due(block, start) {
+			due = append(due, block)
+		}
+	}
+	require.Equal(t, []uint64{100, 103, 106, 109}, due)
+
+	// A missed header doesn't shift the schedule back
+	interval = NewSendInterval(3, 0)
+	require.True(t, interval.Due(100, start))
+	require.True(t, interval.Due(104, start))
+	require.False(t, interval.Due(106, start))
+}
+
+func TestSendIntervalByWallClock(t *testing.T) {
+	interval := NewSendInterval(0, 10*time.Second)
+	start := time.Unix(1_700_000_000, 0)
+	require.True(t, interval.Due(100, start))
+	require.False(t, interval.Due(101, start.Add(2*time.Second)))
+	require.False(t, interval.Due(105, start.Add(9*time.Second)))
+	require.True(t, interval.Due(106, start.Add(10*time.Second)))
+	require.False(t, interval.Due(107, start.Add(12*time.Second)))
+}
+
+func TestSendIntervalBlocksTakePrecedence(t *testing.T) {
+	interval := NewSendInterval(2, time.Hour)
+	start := time.Unix(1_700_000_000, 0)
+	require.True(t, interval.Due(100, start))
+	require.True(t, interval.Due(102, start.Add(time.Second)), "the time interval is ignored")
+
+	// Without limits every block is due
+	interval = NewSendInterval(0, 0)
+	for block := uint64(100); block < 103; block++ {
+		require.True(t, interval.Due(block, start))
+	}
+}
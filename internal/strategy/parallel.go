@@ -0,0 +1,43 @@
+package strategy
+
+import "sync"
+
+// PriceInOrder prices n bids with up to workers concurrent calls of price, for
+// pricing that is slow, such as querying an oracle. The prices are returned by
+// index whatever order the calls finish in, so the bids can still be sent in
+// index order, such as by nonce, without leaving nonce gaps.
+//
+// Parameters:
+// - n: The number of bids.
+// - workers: The most calls of price at once; 1 or less prices one bid at a time.
+// - price: Prices the bid at an index. It must be safe for concurrent use when workers > 1.
+//
+// Returns:
+// - The price of each bid, by index.
+func PriceInOrder[T any](n, workers int, price func(i int) T) []T {
+	prices := make([]T, n)
+	if workers <= 1 || n <= 1 {
+		for i := range prices {
+			prices[i] = price(i)
+		}
+		return prices
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				prices[i] = price(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return prices
+}
@@ -0,0 +1,72 @@
+package strategy
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceInOrderSendsInNonceOrder(t *testing.T) {
+	const n = 4
+	var mu sync.Mutex
+	var completed []int
+	// Later nonces price faster and higher, so they finish first and would be
+	// dispatched first if the prices were taken in completion order
+	prices := PriceInOrder(n, n, func(i int) *big.Int {
+		time.Sleep(time.Duration(n-i) * 20 * time.Millisecond)
+		mu.Lock()
+		completed = append(completed, i)
+		mu.Unlock()
+		return big.NewInt(int64(100 + i))
+	})
+	require.Equal(t, []int{3, 2, 1, 0}, completed)
+	require.Equal(t, []*big.Int{big.NewInt(100), big.NewInt(101), big.NewInt(102), big.NewInt(103)}, prices)
+
+	var chain []bb.PendingBid
+	for i, price := range prices {
+		tx := types.NewTx(&types.DynamicFeeTx{Nonce: uint64(i)})
+		chain = append(chain, bb.PendingBid{Tx: tx, BidAmountWei: price, AmountWei: price})
+	}
+	bb.ShareChainPriority(chain)
+
+	var sent []uint64
+	sender := bb.NewTransactionPriorityQueueSender(func(bid bb.PendingBid) {
+		sent = append(sent, bid.Tx.Nonce())
+	})
+	sender.Enqueue(chain...)
+	require.NoError(t, sender.Close())
+	require.Equal(t, []uint64{0, 1, 2, 3}, sent)
+}
+
+func TestPriceInOrderLimitsWorkers(t *testing.T) {
+	var mu sync.Mutex
+	running, most := 0, 0
+	prices := PriceInOrder(10, 3, func(i int) int {
+		mu.Lock()
+		running++
+		most = max(most, running)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return i * i
+	})
+	require.Equal(t, 3, most)
+	for i, price := range prices {
+		require.Equal(t, i*i, price)
+	}
+
+	// A single worker prices in order on the calling goroutine
+	var order []int
+	PriceInOrder(3, 1, func(i int) int {
+		order = append(order, i)
+		return i
+	})
+	require.Equal(t, []int{0, 1, 2}, order)
+}
@@ -0,0 +1,105 @@
+// Package strategy provides bid profiles and the logic for choosing which
+// profile and bid amount to use for each block.
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// DefaultDecayDuration is the bid decay window used when a profile doesn't set one
+// on a chain with the default block time.
+const DefaultDecayDuration = DecayBlocks * DefaultBlockTime
+
+// Profile is a named bidding strategy: how much to bid, how the bid decays,
+// and which kind of transaction to send.
+type Profile struct {
+	Name             string  `json:"name"`
+	BidAmount        float64 `json:"bid_amount"`         // Mean bid amount in ETH.
+	StdDevPercentage float64 `json:"std_dev_percentage"` // Std dev of the bid amount as a percentage of BidAmount.
+	DecayMs          uint64  `json:"decay_ms"`           // Bid decay window in milliseconds, 0 for the default.
+	NumBlob          uint    `json:"num_blob"`           // Number of blobs per transaction, 0 for an ETH transfer.
+	Weight           float64 `json:"weight"`             // Relative weight for the weighted selector.
+
+	rng *rand.Rand // Per-profile strategy state.
+}
+
+// Mode returns the transaction mode the profile bids with: ModeBlob if it sends
+// blobs, ModeTransfer otherwise.
+func (p *Profile) Mode() string {
+	if p.NumBlob > 0 {
+		return ModeBlob
+	}
+	return ModeTransfer
+}
+
+// DecayDuration returns the profile's bid decay window.
+func (p *Profile) DecayDuration() time.Duration {
+	if p.DecayMs == 0 {
+		return DefaultDecayDuration
+	}
+	return time.Duration(p.DecayMs) * time.Millisecond
+}
+
+// NextBidAmount draws the next bid amount in ETH from a normal distribution
+// around BidAmount, never going below BidAmount.
+func (p *Profile) NextBidAmount() float64 {
+	stdDev := p.BidAmount * p.StdDevPercentage / 100.0
+	amount := p.rng.NormFloat64()*stdDev + p.BidAmount
+	return math.Max(amount, p.BidAmount)
+}
+
+// validate checks that the profile's fields are usable.
+func (p *Profile) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+	if p.BidAmount <= 0 {
+		return fmt.Errorf("profile %s: bid_amount must be positive", p.Name)
+	}
+	if p.StdDevPercentage < 0 {
+		return fmt.Errorf("profile %s: std_dev_percentage cannot be negative", p.Name)
+	}
+	if p.Weight < 0 {
+		return fmt.Errorf("profile %s: weight cannot be negative", p.Name)
+	}
+	return nil
+}
+
+// LoadProfiles reads a JSON array of profiles from a file.
+//
+// Parameters:
+// - filePath: The path to the JSON profiles file.
+//
+// Returns:
+// - The validated profiles, or an error if the file is missing or invalid.
+func LoadProfiles(filePath string) ([]*Profile, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var profiles []*Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("profiles file %s defines no profiles", filePath)
+	}
+
+	seen := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("duplicate profile name %s", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return profiles, nil
+}
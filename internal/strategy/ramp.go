@@ -0,0 +1,229 @@
+package strategy
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/primev/preconf_blob_bidder/api"
+)
+
+// BlobRampSchedule returns the default ramp schedule from 1 to maxBlobs blobs per transaction.
+func BlobRampSchedule(maxBlobs uint) []uint {
+	schedule := make([]uint, maxBlobs)
+	for i := range schedule {
+		schedule[i] = uint(i) + 1
+	}
+	return schedule
+}
+
+// ParseBlobRampSchedule parses a comma-separated list of blob counts, e.g. "1,2,4,6".
+func ParseBlobRampSchedule(s string) ([]uint, error) {
+	var schedule []uint
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid blob ramp step %q: want a positive number of blobs", field)
+		}
+		schedule = append(schedule, uint(n))
+	}
+	return schedule, nil
+}
+
+// BlobRamp ramps the number of blobs per transaction for DA stress testing. Each
+// step bids with its own profile, named after the step, so that every bid is
+// labelled with the ramp step it was sent in.
+type BlobRamp struct {
+	steps      []*Profile
+	stepBlocks uint64
+
+	mu      sync.Mutex
+	started bool
+	start   uint64 // First block of the ramp.
+}
+
+// NewBlobRamp creates a BlobRamp.
+//
+// Parameters:
+// - base: The profile whose bid amount and decay every step uses.
+// - schedule: The blob counts of the steps, in order.
+// - stepBlocks: The number of blocks each step lasts. The last step lasts until exit.
+// - seed: The seed for the steps' bid amount draws.
+//
+// Returns:
+// - A pointer to a BlobRamp, or an error if the schedule is empty or a step lasts no blocks.
+func NewBlobRamp(base *Profile, schedule []uint, stepBlocks uint64, seed int64) (*BlobRamp, error) {
+	if len(schedule) == 0 {
+		return nil, fmt.Errorf("blob ramp schedule cannot be empty")
+	}
+	if stepBlocks == 0 {
+		return nil, fmt.Errorf("blob ramp steps must last at least one block")
+	}
+	r := &BlobRamp{stepBlocks: stepBlocks}
+	for i, numBlob := range schedule {
+		step := *base
+		step.Name = RampStepLabel(i, numBlob)
+		step.NumBlob = numBlob
+		step.rng = rand.New(rand.NewSource(seed + int64(i) + 1))
+		r.steps = append(r.steps, &step)
+	}
+	return r, nil
+}
+
+// RampStepLabel returns the label of the bids sent in a ramp step.
+func RampStepLabel(index int, numBlob uint) string {
+	return fmt.Sprintf("blob-ramp-%d-%dblobs", index+1, numBlob)
+}
+
+// Steps returns the profiles of the ramp's steps, in order.
+func (r *BlobRamp) Steps() []*Profile {
+	return r.steps
+}
+
+// Select returns the profile of the ramp step for the given block. The ramp starts
+// at the first block it is called with.
+func (r *BlobRamp) Select(blockNumber uint64) *Profile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.started || blockNumber < r.start {
+		r.started, r.start = true, blockNumber
+	}
+	index := (blockNumber - r.start) / r.stepBlocks
+	if index >= uint64(len(r.steps)) {
+		index = uint64(len(r.steps)) - 1
+	}
+	return r.steps[index]
+}
+
+// RampStepSummary holds the results of the bids sent in one ramp step.
+type RampStepSummary struct {
+	Label       string
+	NumBlob     uint
+	Bids        uint64
+	Committed   uint64   // Bids that received at least one commitment.
+	Included    uint64   // Bids whose transaction landed in the target block.
+	Missed      uint64   // Bids whose target block was produced without the transaction.
+	BlobFeesWei *big.Int // Blob fees paid by the included transactions.
+}
+
+// CommitmentRate returns the share of bids that received a commitment.
+func (s RampStepSummary) CommitmentRate() float64 {
+	if s.Bids == 0 {
+		return 0
+	}
+	return float64(s.Committed) / float64(s.Bids)
+}
+
+// InclusionRate returns the share of resolved bids that were included.
+func (s RampStepSummary) InclusionRate() float64 {
+	if s.Included+s.Missed == 0 {
+		return 0
+	}
+	return float64(s.Included) / float64(s.Included+s.Missed)
+}
+
+// rampBid identifies a bid by its transaction and target block.
+type rampBid struct {
+	tx    common.Hash
+	block uint64
+}
+
+// RampStats compares the results of the steps of a BlobRamp.
+type RampStats struct {
+	mu      sync.Mutex
+	order   []string
+	byLabel map[string]*RampStepSummary
+	bids    map[rampBid]string // Bid -> step label, until its first commitment.
+}
+
+// NewRampStats creates RampStats for the steps of the ramp.
+func NewRampStats(ramp *BlobRamp) *RampStats {
+	s := &RampStats{byLabel: make(map[string]*RampStepSummary), bids: make(map[rampBid]string)}
+	for _, step := range ramp.Steps() {
+		s.order = append(s.order, step.Name)
+		s.byLabel[step.Name] = &RampStepSummary{Label: step.Name, NumBlob: step.NumBlob, BlobFeesWei: new(big.Int)}
+	}
+	return s
+}
+
+// RecordBid counts a bid sent in the step with the given label.
+func (s *RampStats) RecordBid(label string, txHash common.Hash, blockNumber uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	step, ok := s.byLabel[label]
+	if !ok {
+		return
+	}
+	step.Bids++
+	s.bids[rampBid{tx: txHash, block: blockNumber}] = label
+}
+
+// RecordCommitment counts the first commitment received for a bid.
+func (s *RampStats) RecordCommitment(txHash common.Hash, blockNumber uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := rampBid{tx: txHash, block: blockNumber}
+	if label, ok := s.bids[key]; ok {
+		s.byLabel[label].Committed++
+		delete(s.bids, key)
+	}
+}
+
+// RecordResolved counts a resolved bid, labelled by its Profile.
+//
+// Parameters:
+// - record: The resolved bid.
+// - blobFeeWei: The blob fee its transaction paid if it was included, or nil.
+func (s *RampStats) RecordResolved(record *api.BidRecord, blobFeeWei *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Bids without a commitment by now won't get one
+	delete(s.bids, rampBid{tx: common.HexToHash(record.TxHash), block: record.BlockNumber})
+
+	step, ok := s.byLabel[record.Profile]
+	if !ok {
+		return
+	}
+	switch record.Status {
+	case api.BidStatusIncluded:
+		step.Included++
+		if blobFeeWei != nil {
+			step.BlobFeesWei.Add(step.BlobFeesWei, blobFeeWei)
+		}
+	case api.BidStatusMissed:
+		step.Missed++
+	}
+}
+
+// Summary returns the results of each step, in ramp order.
+func (s *RampStats) Summary() []RampStepSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary := make([]RampStepSummary, len(s.order))
+	for i, label := range s.order {
+		summary[i] = *s.byLabel[label]
+		summary[i].BlobFeesWei = new(big.Int).Set(s.byLabel[label].BlobFeesWei)
+	}
+	return summary
+}
+
+// LogSummary logs the results of each step side by side.
+func (s *RampStats) LogSummary() {
+	for _, step := range s.Summary() {
+		slog.Info("Blob ramp step summary",
+			"step", step.Label,
+			"numBlob", step.NumBlob,
+			"bids", step.Bids,
+			"commitmentRate", step.CommitmentRate(),
+			"inclusionRate", step.InclusionRate(),
+			"included", step.Included,
+			"missed", step.Missed,
+			"blobFeesWei", step.BlobFeesWei.String(),
+		)
+	}
+}
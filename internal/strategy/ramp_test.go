@@ -0,0 +1,75 @@
+package strategy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobRampSteps(t *testing.T) {
+	base := &Profile{Name: "default", BidAmount: 0.001, DecayMs: 500}
+	ramp, err := NewBlobRamp(base, BlobRampSchedule(3), 2, 1)
+	require.NoError(t, err)
+
+	// Synthetic headers from block 100: two blocks per step, then the last step holds
+	var labels []string
+	var blobs []uint
+	for n := uint64(100); n < 108; n++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(n)}
+		step := ramp.Select(header.Number.Uint64())
+		labels = append(labels, step.Name)
+		blobs = append(blobs, step.NumBlob)
+		require.Equal(t, ModeBlob, step.Mode())
+		require.Equal(t, 500*1e6, float64(step.DecayDuration()))
+		require.GreaterOrEqual(t, step.NextBidAmount(), 0.001)
+	}
+	require.Equal(t, []uint{1, 1, 2, 2, 3, 3, 3, 3}, blobs)
+	require.Equal(t, []string{
+		"blob-ramp-1-1blobs", "blob-ramp-1-1blobs",
+		"blob-ramp-2-2blobs", "blob-ramp-2-2blobs",
+		"blob-ramp-3-3blobs", "blob-ramp-3-3blobs", "blob-ramp-3-3blobs", "blob-ramp-3-3blobs",
+	}, labels)
+	require.Equal(t, "default", base.Name)
+	require.Zero(t, base.NumBlob)
+
+	schedule, err := ParseBlobRampSchedule("1, 4,6")
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 4, 6}, schedule)
+	_, err = ParseBlobRampSchedule("1,0")
+	require.Error(t, err)
+	_, err = NewBlobRamp(base, nil, 2, 1)
+	require.Error(t, err)
+	_, err = NewBlobRamp(base, schedule, 0, 1)
+	require.Error(t, err)
+}
+
+func TestRampStats(t *testing.T) {
+	ramp, err := NewBlobRamp(&Profile{Name: "default", BidAmount: 0.001}, []uint{1, 2}, 1, 1)
+	require.NoError(t, err)
+	stats := NewRampStats(ramp)
+	one, two := ramp.Steps()[0].Name, ramp.Steps()[1].Name
+	txA, txB, txC := common.Hash{0xa}, common.Hash{0xb}, common.Hash{0xc}
+
+	stats.RecordBid(one, txA, 101)
+	stats.RecordBid(two, txB, 102)
+	stats.RecordBid(two, txC, 103)
+	stats.RecordCommitment(txA, 101)
+	stats.RecordCommitment(txA, 101) // A second provider's commitment
+	stats.RecordCommitment(txB, 102)
+
+	stats.RecordResolved(&api.BidRecord{TxHash: txA.Hex(), BlockNumber: 101, Profile: one, Status: api.BidStatusIncluded}, big.NewInt(131072))
+	stats.RecordResolved(&api.BidRecord{TxHash: txB.Hex(), BlockNumber: 102, Profile: two, Status: api.BidStatusIncluded}, big.NewInt(262144))
+	stats.RecordResolved(&api.BidRecord{TxHash: txC.Hex(), BlockNumber: 103, Profile: two, Status: api.BidStatusMissed}, nil)
+	stats.RecordCommitment(txC, 103) // Too late to count
+
+	summary := stats.Summary()
+	require.Len(t, summary, 2)
+	require.Equal(t, RampStepSummary{Label: one, NumBlob: 1, Bids: 1, Committed: 1, Included: 1, BlobFeesWei: big.NewInt(131072)}, summary[0])
+	require.Equal(t, RampStepSummary{Label: two, NumBlob: 2, Bids: 2, Committed: 1, Included: 1, Missed: 1, BlobFeesWei: big.NewInt(262144)}, summary[1])
+	require.Equal(t, 0.5, summary[1].CommitmentRate())
+	require.Equal(t, 0.5, summary[1].InclusionRate())
+}
@@ -0,0 +1,51 @@
+package strategy
+
+import "sync"
+
+// CycleRetry schedules a failed bid cycle to be retried on the next header with the
+// same profile. Retries never stack: at most one retry is pending, and after
+// maxRetries consecutive failures the cycle is abandoned and the next header starts fresh.
+type CycleRetry struct {
+	maxRetries uint
+
+	mu      sync.Mutex
+	attempt uint
+	profile *Profile
+}
+
+// NewCycleRetry creates a CycleRetry allowing up to maxRetries retries of a failed cycle.
+func NewCycleRetry(maxRetries uint) *CycleRetry {
+	return &CycleRetry{maxRetries: maxRetries}
+}
+
+// Pending returns the profile and retry attempt number for the next header when a retry is scheduled.
+func (r *CycleRetry) Pending() (*Profile, uint, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.profile == nil {
+		return nil, 0, false
+	}
+	return r.profile, r.attempt, true
+}
+
+// Fail records a failed cycle and reports whether it will be retried on the next header.
+func (r *CycleRetry) Fail(profile *Profile) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attempt >= r.maxRetries {
+		r.attempt = 0
+		r.profile = nil
+		return false
+	}
+	r.attempt++
+	r.profile = profile
+	return true
+}
+
+// Succeed clears any pending retry.
+func (r *CycleRetry) Succeed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempt = 0
+	r.profile = nil
+}
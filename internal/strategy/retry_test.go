@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCycleRetryOnNextHeader(t *testing.T) {
+	profiles := twoProfiles()
+	selector, err := NewProfileSelector(SelectorRoundRobin, profiles, 1)
+	require.NoError(t, err)
+	retry := NewCycleRetry(2)
+
+	// runCycle simulates a header arriving, returning the profile and attempt used
+	runCycle := func(blockNumber uint64, fail bool) (string, uint) {
+		profile, attempt, ok := retry.Pending()
+		if !ok {
+			profile = selector.Select(blockNumber)
+		}
+		if fail {
+			retry.Fail(profile)
+		} else {
+			retry.Succeed()
+		}
+		return profile.Name, attempt
+	}
+
+	// A failure is retried on the next header with the same profile
+	name, attempt := runCycle(100, true)
+	require.Equal(t, "cheap", name)
+	require.Equal(t, uint(0), attempt)
+
+	name, attempt = runCycle(101, true)
+	require.Equal(t, "cheap", name)
+	require.Equal(t, uint(1), attempt)
+
+	name, attempt = runCycle(102, true)
+	require.Equal(t, "cheap", name)
+	require.Equal(t, uint(2), attempt)
+
+	// Retries are exhausted, so the next header starts a fresh cycle
+	name, attempt = runCycle(103, false)
+	require.Equal(t, "blob", name)
+	require.Equal(t, uint(0), attempt)
+
+	// A successful retry clears the pending retry
+	runCycle(104, true)
+	name, attempt = runCycle(105, false)
+	require.Equal(t, "cheap", name)
+	require.Equal(t, uint(1), attempt)
+	_, _, ok := retry.Pending()
+	require.False(t, ok)
+}
+
+func TestCycleRetryDisabled(t *testing.T) {
+	retry := NewCycleRetry(0)
+	require.False(t, retry.Fail(&Profile{Name: "a"}))
+	_, _, ok := retry.Pending()
+	require.False(t, ok)
+}
@@ -0,0 +1,169 @@
+package strategy
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+)
+
+// Supported profile selectors.
+const (
+	SelectorRoundRobin = "round-robin"
+	SelectorParity     = "parity"
+	SelectorWeighted   = "weighted"
+)
+
+// ProfileSelector chooses which profile to bid with for each block.
+type ProfileSelector struct {
+	kind     string
+	profiles []*Profile
+
+	mu   sync.Mutex
+	next int
+	rng  *rand.Rand
+}
+
+// NewProfileSelector creates a ProfileSelector and initializes each profile's strategy state.
+//
+// Parameters:
+// - kind: One of round-robin, parity, or weighted.
+// - profiles: The profiles to select from.
+// - seed: The seed for the selector's and profiles' random number generators.
+//
+// Returns:
+// - A pointer to a ProfileSelector, or an error if the selector kind or profiles are invalid.
+func NewProfileSelector(kind string, profiles []*Profile, seed int64) (*ProfileSelector, error) {
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("at least one profile is required")
+	}
+
+	switch kind {
+	case SelectorRoundRobin, SelectorParity:
+	case SelectorWeighted:
+		var total float64
+		for _, p := range profiles {
+			total += p.Weight
+		}
+		if total <= 0 {
+			return nil, fmt.Errorf("weighted selector requires at least one profile with a positive weight")
+		}
+	default:
+		return nil, fmt.Errorf("unknown profile selector %q (must be %s, %s, or %s)", kind, SelectorRoundRobin, SelectorParity, SelectorWeighted)
+	}
+
+	for i, p := range profiles {
+		p.rng = rand.New(rand.NewSource(seed + int64(i) + 1))
+	}
+
+	return &ProfileSelector{
+		kind:     kind,
+		profiles: profiles,
+		rng:      rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Profiles returns the profiles the selector chooses from.
+func (s *ProfileSelector) Profiles() []*Profile {
+	return s.profiles
+}
+
+// Select returns the profile to use for the given block.
+func (s *ProfileSelector) Select(blockNumber uint64) *Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.kind {
+	case SelectorParity:
+		return s.profiles[blockNumber%uint64(len(s.profiles))]
+	case SelectorWeighted:
+		var total float64
+		for _, p := range s.profiles {
+			total += p.Weight
+		}
+		pick := s.rng.Float64() * total
+		for _, p := range s.profiles {
+			if pick < p.Weight {
+				return p
+			}
+			pick -= p.Weight
+		}
+		return s.profiles[len(s.profiles)-1]
+	default:
+		p := s.profiles[s.next]
+		s.next = (s.next + 1) % len(s.profiles)
+		return p
+	}
+}
+
+// ProfileStats holds bid accounting for a profile or for all profiles combined.
+type ProfileStats struct {
+	Bids     uint64
+	SpendEth float64
+}
+
+// Accounting tracks bids per profile alongside the shared budget totals.
+type Accounting struct {
+	mu        sync.Mutex
+	total     ProfileStats
+	byProfile map[string]*ProfileStats
+	order     []string
+}
+
+// NewAccounting creates an empty Accounting.
+func NewAccounting() *Accounting {
+	return &Accounting{byProfile: make(map[string]*ProfileStats)}
+}
+
+// RecordBid adds a bid to the profile's and the shared totals.
+func (a *Accounting) RecordBid(profile string, amountEth float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats, ok := a.byProfile[profile]
+	if !ok {
+		stats = &ProfileStats{}
+		a.byProfile[profile] = stats
+		a.order = append(a.order, profile)
+	}
+	stats.Bids++
+	stats.SpendEth += amountEth
+	a.total.Bids++
+	a.total.SpendEth += amountEth
+}
+
+// Profile returns the accounting for a single profile.
+func (a *Accounting) Profile(name string) ProfileStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if stats, ok := a.byProfile[name]; ok {
+		return *stats
+	}
+	return ProfileStats{}
+}
+
+// Total returns the shared accounting across all profiles.
+func (a *Accounting) Total() ProfileStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.total
+}
+
+// LogSummary logs the bid accounting broken down by profile.
+func (a *Accounting) LogSummary() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, name := range a.order {
+		stats := a.byProfile[name]
+		slog.Info("Profile summary",
+			"profile", name,
+			"bids", stats.Bids,
+			"spend_ETH", stats.SpendEth,
+		)
+	}
+	slog.Info("Bid summary",
+		"bids", a.total.Bids,
+		"spend_ETH", a.total.SpendEth,
+	)
+}
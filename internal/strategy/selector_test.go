@@ -0,0 +1,89 @@
+package strategy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func twoProfiles() []*Profile {
+	return []*Profile{
+		{Name: "cheap", BidAmount: 0.001, StdDevPercentage: 0, DecayMs: 12000},
+		{Name: "blob", BidAmount: 0.002, StdDevPercentage: 0, NumBlob: 1},
+	}
+}
+
+func TestProfilesAlternateAcrossHeaders(t *testing.T) {
+	for _, kind := range []string{SelectorRoundRobin, SelectorParity} {
+		t.Run(kind, func(t *testing.T) {
+			selector, err := NewProfileSelector(kind, twoProfiles(), 1)
+			require.NoError(t, err)
+			accounting := NewAccounting()
+
+			var chosen []string
+			for blockNumber := uint64(100); blockNumber < 106; blockNumber++ {
+				profile := selector.Select(blockNumber)
+				chosen = append(chosen, profile.Name)
+				accounting.RecordBid(profile.Name, profile.NextBidAmount())
+			}
+
+			require.Equal(t, []string{"cheap", "blob", "cheap", "blob", "cheap", "blob"}, chosen)
+			require.Equal(t, ProfileStats{Bids: 3, SpendEth: 0.003}, roundStats(accounting.Profile("cheap")))
+			require.Equal(t, ProfileStats{Bids: 3, SpendEth: 0.006}, roundStats(accounting.Profile("blob")))
+			require.Equal(t, ProfileStats{Bids: 6, SpendEth: 0.009}, roundStats(accounting.Total()))
+		})
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+	profiles := twoProfiles()
+	profiles[0].Weight = 1
+	profiles[1].Weight = 0
+
+	selector, err := NewProfileSelector(SelectorWeighted, profiles, 1)
+	require.NoError(t, err)
+	for blockNumber := uint64(0); blockNumber < 20; blockNumber++ {
+		require.Equal(t, "cheap", selector.Select(blockNumber).Name)
+	}
+
+	profiles[0].Weight = 0
+	_, err = NewProfileSelector(SelectorWeighted, profiles, 1)
+	require.Error(t, err)
+}
+
+func TestProfileDecayDuration(t *testing.T) {
+	profiles := twoProfiles()
+	require.Equal(t, 12*time.Second, profiles[0].DecayDuration())
+	require.Equal(t, DefaultDecayDuration, profiles[1].DecayDuration())
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "profiles.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`[
+		{"name": "a", "bid_amount": 0.001, "std_dev_percentage": 50},
+		{"name": "b", "bid_amount": 0.002, "num_blob": 2, "decay_ms": 24000}
+	]`), 0o644))
+	profiles, err := LoadProfiles(valid)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	require.Equal(t, uint(2), profiles[1].NumBlob)
+
+	duplicate := filepath.Join(dir, "duplicate.json")
+	require.NoError(t, os.WriteFile(duplicate, []byte(`[
+		{"name": "a", "bid_amount": 0.001},
+		{"name": "a", "bid_amount": 0.002}
+	]`), 0o644))
+	_, err = LoadProfiles(duplicate)
+	require.ErrorContains(t, err, "duplicate profile name")
+}
+
+// roundStats rounds the spend to avoid floating point noise in comparisons.
+func roundStats(s ProfileStats) ProfileStats {
+	s.SpendEth = float64(int64(s.SpendEth*1e9+0.5)) / 1e9
+	return s
+}
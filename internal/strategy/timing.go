@@ -0,0 +1,163 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultBlockTime is the slot time of Ethereum mainnet and its testnets, used
+// when the block time is neither configured nor detected.
+const DefaultBlockTime = 12 * time.Second
+
+// MaxBlockTime bounds a configured or detected block time.
+const MaxBlockTime = time.Minute
+
+// Multiples of the block time that timing defaults are derived from, and that
+// explicit settings may not exceed.
+const (
+	DecayBlocks           = 3    // Default decay window, 36s on a 12s chain.
+	DrainTimeoutBlocks    = 10   // Default drain timeout, 120s on a 12s chain.
+	MaxDecayBlocks        = 32   // Longest decay window or decay start offset.
+	MaxDrainTimeoutBlocks = 1000 // Longest drain timeout.
+)
+
+// BlockTiming holds the timing defaults derived from a chain's block time, so the
+// bot behaves the same on a 12s chain and a devnet with sub-second blocks.
+type BlockTiming struct {
+	BlockTime          time.Duration
+	DecayDuration      time.Duration // Decay window of bids whose profile sets none.
+	DrainTimeout       time.Duration // Longest drain before exiting with bids unresolved.
+	NonceCheckInterval time.Duration // Time between nonce checks, two and a half blocks.
+}
+
+// NewBlockTiming derives the timing defaults of a chain.
+//
+// Parameters:
+// - blockTime: Time between blocks, more than 0 and at most MaxBlockTime.
+//
+// Returns:
+// - The BlockTiming, or an error if the block time is out of range.
+func NewBlockTiming(blockTime time.Duration) (BlockTiming, error) {
+	if blockTime <= 0 || blockTime > MaxBlockTime {
+		return BlockTiming{}, fmt.Errorf("block time %s must be more than 0 and at most %s", blockTime, MaxBlockTime)
+	}
+	return BlockTiming{
+		BlockTime:          blockTime,
+		DecayDuration:      DecayBlocks * blockTime,
+		DrainTimeout:       DrainTimeoutBlocks * blockTime,
+		NonceCheckInterval: blockTime * 5 / 2,
+	}, nil
+}
+
+// Validate checks that explicit timing settings stay within sane multiples of the
+// block time.
+//
+// Parameters:
+// - decayStartOffset: Delay from building a bid to the start of its decay.
+// - decay: A decay window.
+// - drainTimeout: Longest drain before exiting.
+//
+// Returns:
+// - An error naming the first setting that is too long, or nil.
+func (t BlockTiming) Validate(decayStartOffset, decay, drainTimeout time.Duration) error {
+	maxDecay := MaxDecayBlocks * t.BlockTime
+	if decayStartOffset > maxDecay {
+		return fmt.Errorf("decay start offset %s exceeds %d blocks of %s", decayStartOffset, MaxDecayBlocks, t.BlockTime)
+	}
+	if decay > maxDecay {
+		return fmt.Errorf("decay duration %s exceeds %d blocks of %s", decay, MaxDecayBlocks, t.BlockTime)
+	}
+	if maxDrain := MaxDrainTimeoutBlocks * t.BlockTime; drainTimeout > maxDrain {
+		return fmt.Errorf("drain timeout %s exceeds %d blocks of %s", drainTimeout, MaxDrainTimeoutBlocks, t.BlockTime)
+	}
+	return nil
+}
+
+// Policies for a decay window that ends before its target block is proposed,
+// which happens when OFFSET targets blocks further out than the decay window.
+const (
+	OffsetDecayError  = "error"  // Refuse to start.
+	OffsetDecayExtend = "extend" // Lengthen the window until the target block is due.
+	OffsetDecayAnchor = "anchor" // Delay the window so it ends when the target block is due.
+)
+
+// ErrDecayBeforeTarget is returned under OffsetDecayError when a decay window
+// ends before its target block is proposed.
+var ErrDecayBeforeTarget = errors.New("decay window ends before the target block")
+
+// ValidateOffsetDecayPolicy checks that policy is one of the OffsetDecay policies.
+func ValidateOffsetDecayPolicy(policy string) error {
+	switch policy {
+	case OffsetDecayError, OffsetDecayExtend, OffsetDecayAnchor:
+		return nil
+	}
+	return fmt.Errorf("unknown offset decay policy %q (must be %s, %s or %s)", policy, OffsetDecayError, OffsetDecayExtend, OffsetDecayAnchor)
+}
+
+// TargetHorizon returns roughly how long after a header the block ahead blocks
+// later is proposed.
+func (t BlockTiming) TargetHorizon(ahead uint64) time.Duration {
+	return time.Duration(ahead) * t.BlockTime
+}
+
+// FitDecay places the decay window of a bid for the block ahead blocks after the
+// latest header, so that a bid is still worth something when its block is built.
+//
+// Parameters:
+// - policy: One of the OffsetDecay policies.
+// - ahead: How many blocks after the latest header the target block is.
+// - startOffset: Configured delay from building the bid to the start of its decay.
+// - decay: Configured decay window.
+//
+// Returns:
+// - The delay to the start of the decay and its duration, unchanged when the
+// window already reaches the target block.
+// - Whether the configured window ended before the target block.
+// - An error wrapping ErrDecayBeforeTarget under OffsetDecayError.
+func (t BlockTiming) FitDecay(policy string, ahead uint64, startOffset, decay time.Duration) (time.Duration, time.Duration, bool, error) {
+	horizon := t.TargetHorizon(ahead)
+	if startOffset+decay >= horizon {
+		return startOffset, decay, false, nil
+	}
+	switch policy {
+	case OffsetDecayExtend:
+		return startOffset, horizon - startOffset, true, nil
+	case OffsetDecayAnchor:
+		return horizon - decay, decay, true, nil
+	}
+	return startOffset, decay, true, fmt.Errorf("%w: it ends %s after the bid, but the block %d ahead is due after %s of %s blocks",
+		ErrDecayBeforeTarget, startOffset+decay, ahead, horizon, t.BlockTime)
+}
+
+// SlotStart returns when the slot of the block ahead blocks after a header
+// starts, counting whole block times from the header's timestamp, which is the
+// start of its own slot.
+func (t BlockTiming) SlotStart(headerTime uint64, ahead uint64) time.Time {
+	return time.Unix(int64(headerTime), 0).Add(t.TargetHorizon(ahead))
+}
+
+// SlotOffsets is where a decay window falls relative to the start of the slot of
+// its target block. Negative offsets are before the slot starts.
+type SlotOffsets struct {
+	DecayStart time.Duration
+	DecayEnd   time.Duration
+}
+
+// InSlotOffsets returns where a decay window falls in the slot of the block
+// ahead blocks after a header.
+//
+// Parameters:
+// - headerTime: Timestamp of the header the bid was built on, in seconds.
+// - ahead: How many blocks after the header the target block is.
+// - decayStart, decayEnd: The decay window.
+//
+// Returns:
+// - The offsets of the window's start and end from the start of the target slot.
+func (t BlockTiming) InSlotOffsets(headerTime uint64, ahead uint64, decayStart, decayEnd time.Time) SlotOffsets {
+	slotStart := t.SlotStart(headerTime, ahead)
+	return SlotOffsets{
+		DecayStart: decayStart.Sub(slotStart),
+		DecayEnd:   decayEnd.Sub(slotStart),
+	}
+}
@@ -0,0 +1,151 @@
+package strategy
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTimingDerivesDefaults(t *testing.T) {
+	tests := []struct {
+		blockTime          time.Duration
+		decay              time.Duration
+		drainTimeout       time.Duration
+		nonceCheckInterval time.Duration
+	}{
+		{time.Second, 3 * time.Second, 10 * time.Second, 2500 * time.Millisecond},
+		{2 * time.Second, 6 * time.Second, 20 * time.Second, 5 * time.Second},
+		{12 * time.Second, 36 * time.Second, 2 * time.Minute, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.blockTime.String(), func(t *testing.T) {
+			timing, err := NewBlockTiming(tt.blockTime)
+			require.NoError(t, err)
+			require.Equal(t, tt.blockTime, timing.BlockTime)
+			require.Equal(t, tt.decay, timing.DecayDuration)
+			require.Equal(t, tt.drainTimeout, timing.DrainTimeout)
+			require.Equal(t, tt.nonceCheckInterval, timing.NonceCheckInterval)
+
+			// The derived defaults are always valid
+			require.NoError(t, timing.Validate(0, timing.DecayDuration, timing.DrainTimeout))
+		})
+	}
+
+	// The 12s defaults are the ones the bot has always used
+	mainnet, err := NewBlockTiming(DefaultBlockTime)
+	require.NoError(t, err)
+	require.Equal(t, DefaultDecayDuration, mainnet.DecayDuration)
+}
+
+func TestBlockTimingValidatesExplicitSettings(t *testing.T) {
+	tests := []struct {
+		blockTime        time.Duration
+		decayStartOffset time.Duration
+		decay            time.Duration
+		drainTimeout     time.Duration
+		wantErr          bool
+	}{
+		// 36s decay is fine on a 12s chain and a 2s chain, but 36 blocks of a 1s chain
+		{12 * time.Second, 0, 36 * time.Second, 2 * time.Minute, false},
+		{2 * time.Second, 0, 36 * time.Second, 2 * time.Minute, false},
+		{time.Second, 0, 36 * time.Second, 2 * time.Minute, true},
+		{time.Second, 32 * time.Second, 3 * time.Second, 10 * time.Second, false},
+		{time.Second, 33 * time.Second, 3 * time.Second, 10 * time.Second, true},
+		{time.Second, 0, 3 * time.Second, 1000 * time.Second, false},
+		{time.Second, 0, 3 * time.Second, 1001 * time.Second, true},
+	}
+	for _, tt := range tests {
+		timing, err := NewBlockTiming(tt.blockTime)
+		require.NoError(t, err)
+		err = timing.Validate(tt.decayStartOffset, tt.decay, tt.drainTimeout)
+		if tt.wantErr {
+			require.Error(t, err, "%+v", tt)
+		} else {
+			require.NoError(t, err, "%+v", tt)
+		}
+	}
+}
+
+func TestBlockTimingRejectsInvalidBlockTimes(t *testing.T) {
+	_, err := NewBlockTiming(0)
+	require.Error(t, err)
+	_, err = NewBlockTiming(-time.Second)
+	require.Error(t, err)
+	_, err = NewBlockTiming(2 * time.Minute)
+	require.Error(t, err)
+	_, err = NewBlockTiming(500 * time.Millisecond)
+	require.NoError(t, err)
+}
+
+func TestFitDecayToOffset(t *testing.T) {
+	timing, err := NewBlockTiming(12 * time.Second)
+	require.NoError(t, err)
+	const decay = 30 * time.Second
+
+	tests := []struct {
+		offset    uint64
+		policy    string
+		start     time.Duration
+		duration  time.Duration
+		mismatch  bool
+		wantError bool
+	}{
+		// One block out, the window outlasts the target block under every policy
+		{1, OffsetDecayError, 0, decay, false, false},
+		{1, OffsetDecayExtend, 0, decay, false, false},
+		{1, OffsetDecayAnchor, 0, decay, false, false},
+		// Three blocks out the target block is due after 36s
+		{3, OffsetDecayError, 0, decay, true, true},
+		{3, OffsetDecayExtend, 0, 36 * time.Second, true, false},
+		{3, OffsetDecayAnchor, 6 * time.Second, decay, true, false},
+		// Ten blocks out it is due after two minutes
+		{10, OffsetDecayError, 0, decay, true, true},
+		{10, OffsetDecayExtend, 0, 120 * time.Second, true, false},
+		{10, OffsetDecayAnchor, 90 * time.Second, decay, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%s", tt.offset, tt.policy), func(t *testing.T) {
+			require.NoError(t, ValidateOffsetDecayPolicy(tt.policy))
+			start, duration, mismatch, err := timing.FitDecay(tt.policy, tt.offset, 0, decay)
+			if tt.wantError {
+				require.ErrorIs(t, err, ErrDecayBeforeTarget)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.start, start)
+			require.Equal(t, tt.duration, duration)
+			require.Equal(t, tt.mismatch, mismatch)
+			if !tt.wantError {
+				require.GreaterOrEqual(t, start+duration, timing.TargetHorizon(tt.offset), "the window reaches the target block")
+			}
+		})
+	}
+
+	// A start offset is kept when extending
+	start, duration, _, err := timing.FitDecay(OffsetDecayExtend, 10, 2*time.Second, decay)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, start)
+	require.Equal(t, 118*time.Second, duration)
+
+	require.Error(t, ValidateOffsetDecayPolicy("ignore"))
+}
+
+func TestInSlotOffsets(t *testing.T) {
+	timing, err := NewBlockTiming(12 * time.Second)
+	require.NoError(t, err)
+
+	// Header at 1000s, bid 1.5s after it with a 36s window, on the next block
+	headerTime := uint64(1000)
+	bidAt := time.Unix(1001, 500_000_000)
+	require.Equal(t, time.Unix(1012, 0), timing.SlotStart(headerTime, 1))
+	offsets := timing.InSlotOffsets(headerTime, 1, bidAt, bidAt.Add(36*time.Second))
+	require.Equal(t, -10500*time.Millisecond, offsets.DecayStart)
+	require.Equal(t, 25500*time.Millisecond, offsets.DecayEnd)
+
+	// Two blocks ahead, the same window starts a slot earlier relative to its target
+	offsets = timing.InSlotOffsets(headerTime, 2, bidAt, bidAt.Add(36*time.Second))
+	require.Equal(t, -22500*time.Millisecond, offsets.DecayStart)
+	require.Equal(t, 13500*time.Millisecond, offsets.DecayEnd)
+}
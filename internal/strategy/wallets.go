@@ -0,0 +1,141 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Transaction modes a wallet can be assigned to.
+const (
+	ModeTransfer = "transfer"
+	ModeBlob     = "blob"
+)
+
+// WalletSelector picks the wallet that sends each block's transaction, according
+// to the modes each wallet is assigned to.
+type WalletSelector struct {
+	modes [][]string // Wallet index -> allowed modes, highest priority first.
+
+	mu   sync.Mutex
+	next map[string]int // Mode -> round-robin position among its preferred wallets.
+}
+
+// NewWalletSelector creates a WalletSelector from a mode assignment such as
+// "0=blob;1=transfer,blob;0xAbC...=transfer". Each entry names a wallet by its
+// index in the pool or its address and lists its allowed modes in priority order.
+// Wallets without an entry may send every mode, after the wallets that list it.
+//
+// Parameters:
+// - spec: The mode assignment, empty to let every wallet send every mode.
+// - wallets: The addresses of the wallet pool, in index order.
+//
+// Returns:
+// - A pointer to a WalletSelector, or an error if the assignment is invalid.
+func NewWalletSelector(spec string, wallets []common.Address) (*WalletSelector, error) {
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("at least one wallet is required")
+	}
+	s := &WalletSelector{modes: make([][]string, len(wallets)), next: make(map[string]int)}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, list, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid wallet mode entry %q: want <wallet>=<mode>[,<mode>]", entry)
+		}
+		index, err := walletIndex(strings.TrimSpace(key), wallets)
+		if err != nil {
+			return nil, err
+		}
+		if s.modes[index] != nil {
+			return nil, fmt.Errorf("wallet %d is assigned modes more than once", index)
+		}
+		for _, mode := range strings.Split(list, ",") {
+			mode = strings.TrimSpace(mode)
+			if mode != ModeTransfer && mode != ModeBlob {
+				return nil, fmt.Errorf("unknown mode %q for wallet %d (must be %s or %s)", mode, index, ModeTransfer, ModeBlob)
+			}
+			s.modes[index] = append(s.modes[index], mode)
+		}
+	}
+	return s, nil
+}
+
+// walletIndex resolves a wallet index or address to its index in the pool.
+func walletIndex(key string, wallets []common.Address) (int, error) {
+	if common.IsHexAddress(key) {
+		address := common.HexToAddress(key)
+		for i, w := range wallets {
+			if w == address {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("wallet %s is not in the wallet pool", key)
+	}
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || index >= len(wallets) {
+		return 0, fmt.Errorf("invalid wallet %q: want an address or an index below %d", key, len(wallets))
+	}
+	return index, nil
+}
+
+// rank returns the priority of mode for the wallet, lower first, or false if the
+// wallet may not send it.
+func (s *WalletSelector) rank(index int, mode string) (int, bool) {
+	if s.modes[index] == nil {
+		return 2, true // Every mode, after wallets that list it.
+	}
+	for i, m := range s.modes[index] {
+		if m == mode {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Supports reports whether any wallet may send mode.
+func (s *WalletSelector) Supports(mode string) bool {
+	for i := range s.modes {
+		if _, ok := s.rank(i, mode); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the index of the wallet to send a mode's transaction with. Wallets
+// that give the mode the same priority are used in turn.
+//
+// Returns:
+// - The wallet index, or an error if no wallet may send the mode.
+func (s *WalletSelector) Select(mode string) (int, error) {
+	bestRank := -1
+	var best []int
+	for i := range s.modes {
+		r, ok := s.rank(i, mode)
+		if !ok {
+			continue
+		}
+		if bestRank == -1 || r < bestRank {
+			best, bestRank = nil, r
+		}
+		if r == bestRank {
+			best = append(best, i)
+		}
+	}
+	if len(best) == 0 {
+		return 0, fmt.Errorf("no wallet is assigned to %s transactions", mode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := best[s.next[mode]%len(best)]
+	s.next[mode]++
+	return index, nil
+}
@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testWallets = []common.Address{
+	common.HexToAddress("0x00000000000000000000000000000000000000a0"),
+	common.HexToAddress("0x00000000000000000000000000000000000000a1"),
+	common.HexToAddress("0x00000000000000000000000000000000000000a2"),
+}
+
+func selectN(t *testing.T, s *WalletSelector, mode string, n int) []int {
+	t.Helper()
+	var got []int
+	for i := 0; i < n; i++ {
+		index, err := s.Select(mode)
+		require.NoError(t, err)
+		got = append(got, index)
+	}
+	return got
+}
+
+func TestWalletSelectorMatchesModes(t *testing.T) {
+	// Wallet 0 only sends blobs, wallet 1 prefers transfers but can send blobs,
+	// and wallet 2, named by address, only sends transfers
+	s, err := NewWalletSelector("0=blob; 1=transfer,blob; 0x00000000000000000000000000000000000000A2=transfer", testWallets)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{0, 0, 0}, selectN(t, s, ModeBlob, 3))
+	require.Equal(t, []int{1, 2, 1, 2}, selectN(t, s, ModeTransfer, 4))
+	require.True(t, s.Supports(ModeBlob))
+}
+
+func TestWalletSelectorDefaults(t *testing.T) {
+	// Unlisted wallets send every mode, after the wallets that list it
+	s, err := NewWalletSelector("1=transfer", testWallets)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 1}, selectN(t, s, ModeTransfer, 2))
+	require.Equal(t, []int{0, 2, 0}, selectN(t, s, ModeBlob, 3))
+
+	s, err = NewWalletSelector("", testWallets)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 0}, selectN(t, s, ModeBlob, 4))
+}
+
+func TestWalletSelectorNoEligibleWallet(t *testing.T) {
+	s, err := NewWalletSelector("0=transfer;1=transfer;2=transfer", testWallets)
+	require.NoError(t, err)
+	require.False(t, s.Supports(ModeBlob))
+	_, err = s.Select(ModeBlob)
+	require.EqualError(t, err, "no wallet is assigned to blob transactions")
+}
+
+func TestWalletSelectorInvalid(t *testing.T) {
+	for spec, want := range map[string]string{
+		"3=blob":        "invalid wallet \"3\"",
+		"0=blobs":       "unknown mode \"blobs\"",
+		"0":             "invalid wallet mode entry",
+		"0=blob;0=blob": "assigned modes more than once",
+		"0x00000000000000000000000000000000000000ff=blob": "not in the wallet pool",
+	} {
+		_, err := NewWalletSelector(spec, testWallets)
+		require.ErrorContains(t, err, want, spec)
+	}
+	_, err := NewWalletSelector("", nil)
+	require.Error(t, err)
+}
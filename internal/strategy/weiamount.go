@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Bid amount strategies of a WeiAmount.
+const (
+	AmountStrategyUniform = "uniform" // Uniformly random between the minimum and maximum.
+	AmountStrategyFixed   = "fixed"   // Always the minimum.
+)
+
+// PricerProfile names the default pricing, sampling amounts from the bid profile,
+// alongside the WeiAmount strategies.
+const PricerProfile = "profile"
+
+// Bid modes: random amounts from the profiles, or one fixed amount for deterministic benchmarks.
+const (
+	BidModeRandom = "random"
+	BidModeFixed  = "fixed"
+)
+
+// WeiAmount draws bid amounts in wei. Unlike profile amounts it never goes
+// through float64, so large amounts keep their exact value.
+type WeiAmount struct {
+	strategy string
+	min      *big.Int
+	max      *big.Int
+}
+
+// ParseWei parses a decimal wei amount.
+func ParseWei(s string) (*big.Int, error) {
+	wei, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid wei amount %q", s)
+	}
+	if wei.Sign() <= 0 {
+		return nil, fmt.Errorf("wei amount %s must be positive", s)
+	}
+	return wei, nil
+}
+
+// NewWeiAmount creates a WeiAmount.
+//
+// Parameters:
+// - strategy: AmountStrategyUniform or AmountStrategyFixed.
+// - minWei: The decimal minimum amount in wei, the only amount used by the fixed strategy.
+// - maxWei: The decimal maximum amount in wei, required by the uniform strategy.
+//
+// Returns:
+// - A pointer to a WeiAmount, or an error if the strategy or amounts are invalid.
+func NewWeiAmount(strategy, minWei, maxWei string) (*WeiAmount, error) {
+	min, err := ParseWei(minWei)
+	if err != nil {
+		return nil, fmt.Errorf("minimum: %w", err)
+	}
+
+	switch strategy {
+	case AmountStrategyFixed:
+		return &WeiAmount{strategy: strategy, min: min, max: min}, nil
+	case AmountStrategyUniform:
+		max, err := ParseWei(maxWei)
+		if err != nil {
+			return nil, fmt.Errorf("maximum: %w", err)
+		}
+		if min.Cmp(max) > 0 {
+			return nil, fmt.Errorf("minimum %s wei exceeds maximum %s wei", min, max)
+		}
+		return &WeiAmount{strategy: strategy, min: min, max: max}, nil
+	default:
+		return nil, fmt.Errorf("unknown bid amount strategy %q (must be %s or %s)", strategy, AmountStrategyUniform, AmountStrategyFixed)
+	}
+}
+
+// NewFixedWeiAmount creates a WeiAmount that always returns amountWei.
+func NewFixedWeiAmount(amountWei *big.Int) *WeiAmount {
+	return &WeiAmount{strategy: AmountStrategyFixed, min: amountWei, max: amountWei}
+}
+
+// Next returns the amount of the next bid.
+func (a *WeiAmount) Next() *big.Int {
+	span := new(big.Int).Sub(a.max, a.min)
+	if span.Sign() == 0 {
+		return new(big.Int).Set(a.min)
+	}
+	n, err := rand.Int(rand.Reader, span.Add(span, big.NewInt(1)))
+	if err != nil {
+		return new(big.Int).Set(a.min)
+	}
+	return n.Add(n, a.min)
+}
+
+// Strategy returns how the amounts are drawn: AmountStrategyUniform or AmountStrategyFixed.
+func (a *WeiAmount) Strategy() string {
+	return a.strategy
+}
+
+// String describes the amounts for logging, e.g. "uniform [200000000000000, 1000000000000000] wei".
+func (a *WeiAmount) String() string {
+	if a.strategy == AmountStrategyFixed {
+		return fmt.Sprintf("fixed %s wei", a.min)
+	}
+	return fmt.Sprintf("uniform [%s, %s] wei", a.min, a.max)
+}
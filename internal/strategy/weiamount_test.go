@@ -0,0 +1,53 @@
+package strategy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeiAmountUniformStaysInRange(t *testing.T) {
+	// 10 to 20 ETH, past what an int64 of wei can hold
+	a, err := NewWeiAmount(AmountStrategyUniform, "10000000000000000000", "20000000000000000000")
+	require.NoError(t, err)
+	min, _ := new(big.Int).SetString("10000000000000000000", 10)
+	max, _ := new(big.Int).SetString("20000000000000000000", 10)
+	for i := 0; i < 100; i++ {
+		amount := a.Next()
+		require.False(t, amount.IsInt64())
+		require.True(t, amount.Cmp(min) >= 0 && amount.Cmp(max) <= 0, amount.String())
+	}
+	require.Equal(t, "uniform [10000000000000000000, 20000000000000000000] wei", a.String())
+}
+
+func TestWeiAmountFixedUsesMinimum(t *testing.T) {
+	a, err := NewWeiAmount(AmountStrategyFixed, "200000000000000", "")
+	require.NoError(t, err)
+	require.Equal(t, "200000000000000", a.Next().String())
+	require.Equal(t, "200000000000000", a.Next().String())
+
+	fixed := NewFixedWeiAmount(big.NewInt(42))
+	require.Equal(t, int64(42), fixed.Next().Int64())
+	require.Equal(t, "fixed 42 wei", fixed.String())
+
+	// Equal bounds are fixed too
+	a, err = NewWeiAmount(AmountStrategyUniform, "7", "7")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), a.Next().Int64())
+}
+
+func TestWeiAmountRejectsInvalidSettings(t *testing.T) {
+	for _, tc := range []struct{ strategy, min, max string }{
+		{AmountStrategyUniform, "1000", "999"},
+		{AmountStrategyUniform, "1000", ""},
+		{AmountStrategyUniform, "1e15", "2000"},
+		{AmountStrategyFixed, "0.001", ""},
+		{AmountStrategyFixed, "-5", ""},
+		{AmountStrategyFixed, "0", ""},
+		{"gaussian", "1", "2"},
+	} {
+		_, err := NewWeiAmount(tc.strategy, tc.min, tc.max)
+		require.Error(t, err, "%+v", tc)
+	}
+}
@@ -0,0 +1,160 @@
+// Package watch follows the pending transactions of a set of senders, so the
+// bot can bid on transactions produced by another system instead of its own.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Values of MODE.
+const (
+	ModeBuild = "build" // Build, sign and bid on the bot's own transactions.
+	ModeWatch = "watch" // Bid on pending transactions of WATCH_ADDRESSES.
+)
+
+// DefaultSeenSize is the number of transaction hashes a Watcher remembers to
+// avoid bidding on a transaction twice.
+const DefaultSeenSize = 10_000
+
+// SubscribeFunc subscribes to the full transactions entering the pending pool.
+type SubscribeFunc func(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error)
+
+// DialSubscriber returns a SubscribeFunc that opens a connection of its own to a
+// WebSocket endpoint for each subscription, closed when it is unsubscribed. The
+// node must support full transactions in newPendingTransactions subscriptions.
+func DialSubscriber(endpoint string) SubscribeFunc {
+	return func(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error) {
+		client, err := rpc.DialContext(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		sub, err := gethclient.New(client).SubscribeFullPendingTransactions(ctx, ch)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		return &closingSubscription{Subscription: sub, client: client}, nil
+	}
+}
+
+// closingSubscription closes its connection when unsubscribed.
+type closingSubscription struct {
+	ethereum.Subscription
+	client *rpc.Client
+}
+
+// Unsubscribe ends the subscription and closes the connection.
+func (s *closingSubscription) Unsubscribe() {
+	s.Subscription.Unsubscribe()
+	s.client.Close()
+}
+
+// ParseAddresses parses comma-separated addresses such as WATCH_ADDRESSES.
+func ParseAddresses(s string) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !common.IsHexAddress(field) {
+			return nil, fmt.Errorf("%q is not an address", field)
+		}
+		addresses = append(addresses, common.HexToAddress(field))
+	}
+	return addresses, nil
+}
+
+// Watcher picks the pending transactions of a set of senders out of a
+// subscription. Each transaction is delivered once, as long as its hash is
+// among the last seenSize delivered.
+type Watcher struct {
+	subscribe SubscribeFunc
+	signer    types.Signer
+	senders   map[common.Address]struct{}
+	seen      lru.BasicLRU[common.Hash, struct{}]
+}
+
+// NewWatcher creates a Watcher.
+//
+// Parameters:
+// - subscribe: Subscribes to pending transactions, e.g. DialSubscriber.
+// - chainID: The chain ID the senders are recovered with.
+// - senders: The addresses whose transactions are delivered.
+// - seenSize: Delivered hashes remembered to skip repeats, DefaultSeenSize if not positive.
+//
+// Returns:
+// - A pointer to a Watcher.
+func NewWatcher(subscribe SubscribeFunc, chainID *big.Int, senders []common.Address, seenSize int) *Watcher {
+	if seenSize < 1 {
+		seenSize = DefaultSeenSize
+	}
+	w := &Watcher{
+		subscribe: subscribe,
+		signer:    types.LatestSignerForChainID(chainID),
+		senders:   make(map[common.Address]struct{}, len(senders)),
+		seen:      lru.NewBasicLRU[common.Hash, struct{}](seenSize),
+	}
+	for _, sender := range senders {
+		w.senders[sender] = struct{}{}
+	}
+	return w
+}
+
+// Run subscribes and calls fn with each new pending transaction of a watched
+// sender, until ctx is done or the subscription fails. Run can be called again
+// to resubscribe; transactions delivered before are still skipped.
+//
+// Returns:
+// - ctx's error, or the error that ended the subscription.
+func (w *Watcher) Run(ctx context.Context, fn func(*types.Transaction)) error {
+	ch := make(chan *types.Transaction, 256)
+	sub, err := w.subscribe(ctx, ch)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to pending transactions: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				err = fmt.Errorf("pending transaction subscription closed")
+			}
+			return err
+		case tx := <-ch:
+			if w.match(tx) {
+				fn(tx)
+			}
+		}
+	}
+}
+
+// match reports whether tx is from a watched sender and not delivered before,
+// and remembers it if so.
+func (w *Watcher) match(tx *types.Transaction) bool {
+	if tx == nil || w.seen.Contains(tx.Hash()) {
+		return false
+	}
+	from, err := types.Sender(w.signer, tx)
+	if err != nil {
+		return false
+	}
+	if _, ok := w.senders[from]; !ok {
+		return false
+	}
+	w.seen.Add(tx.Hash(), struct{}{})
+	return true
+}
@@ -0,0 +1,111 @@
+package watch
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+)
+
+var chainID = big.NewInt(17000)
+
+// feedSubscriber simulates a pending transaction subscription with a feed.
+type feedSubscriber struct {
+	feed event.Feed
+	subs chan event.Subscription
+}
+
+func newFeedSubscriber() *feedSubscriber {
+	return &feedSubscriber{subs: make(chan event.Subscription, 4)}
+}
+
+func (f *feedSubscriber) subscribe(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error) {
+	sub := f.feed.Subscribe(ch)
+	f.subs <- sub
+	return sub, nil
+}
+
+func signedTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+	t.Helper()
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       21000,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestWatcherDeliversWatchedSendersOnce(t *testing.T) {
+	watched, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	subscriber := newFeedSubscriber()
+	watcher := NewWatcher(subscriber.subscribe, chainID, []common.Address{crypto.PubkeyToAddress(watched.PublicKey)}, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	delivered := make(chan *types.Transaction, 16)
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx, func(tx *types.Transaction) { delivered <- tx }) }()
+	<-subscriber.subs
+
+	first, second, third := signedTx(t, watched, 0), signedTx(t, watched, 1), signedTx(t, watched, 2)
+	for _, tx := range []*types.Transaction{first, signedTx(t, other, 0), first, second, third, second} {
+		subscriber.feed.Send(tx)
+	}
+	// The first transaction has left the two-entry LRU, so it is delivered again
+	subscriber.feed.Send(first)
+
+	var hashes []common.Hash
+	for i := 0; i < 4; i++ {
+		select {
+		case tx := <-delivered:
+			hashes = append(hashes, tx.Hash())
+		case <-time.After(time.Second):
+			t.Fatal("transaction not delivered")
+		}
+	}
+	require.Equal(t, []common.Hash{first.Hash(), second.Hash(), third.Hash(), first.Hash()}, hashes)
+	require.Empty(t, delivered)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestWatcherReturnsSubscriptionErrors(t *testing.T) {
+	failing := func(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error) {
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			return errors.New("connection lost")
+		}), nil
+	}
+	watcher := NewWatcher(failing, chainID, nil, 0)
+	require.EqualError(t, watcher.Run(context.Background(), func(*types.Transaction) {}), "connection lost")
+
+	refused := func(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error) {
+		return nil, errors.New("notifications not supported")
+	}
+	watcher = NewWatcher(refused, chainID, nil, 0)
+	require.ErrorContains(t, watcher.Run(context.Background(), func(*types.Transaction) {}), "notifications not supported")
+}
+
+func TestParseAddresses(t *testing.T) {
+	addresses, err := ParseAddresses(" 0x0000000000000000000000000000000000000001,,0x0000000000000000000000000000000000000002 ")
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")}, addresses)
+
+	_, err = ParseAddresses("0x1,nope")
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	"github.com/urfave/cli/v2"
+)
+
+// keyAuditCommand returns the key-audit subcommand, which summarizes a key audit
+// log per day and flags signatures made while signing was paused.
+func keyAuditCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "key-audit",
+		Usage:     "Summarize a key audit log and flag signatures made during pauses",
+		ArgsUsage: "<file>",
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("usage: key-audit <file>", 2)
+			}
+
+			file, err := os.Open(c.Args().First())
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			defer file.Close()
+
+			summary, err := keyaudit.Summarize(file)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to read %s: %v", c.Args().First(), err), 1)
+			}
+
+			for _, day := range summary.Days {
+				var features []string
+				for feature, n := range day.ByFeature {
+					features = append(features, fmt.Sprintf("%s=%d", feature, n))
+				}
+				sort.Strings(features)
+				fmt.Printf("%s: %d signatures (%s)\n", day.Day, day.Signatures, strings.Join(features, " "))
+			}
+			for _, a := range summary.Anomalies {
+				fmt.Printf("line %d: %s signed for %s during pause (%s)\n", a.Line, a.Record.Address.Hex(), a.Record.Feature, a.Reason)
+			}
+			fmt.Printf("%d days, %d anomalies\n", len(summary.Days), len(summary.Anomalies))
+			if len(summary.Anomalies) > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
@@ -2,23 +2,26 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
-	"math"
-	"math/big"
-	"math/rand"
-	"net/url"
 	"os"
-	"strconv"
-	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	"github.com/primev/preconf_blob_bidder/internal/config"
 	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	"github.com/primev/preconf_blob_bidder/internal/metrics"
 	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/shutdown"
+	"github.com/primev/preconf_blob_bidder/internal/store"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+	"github.com/primev/preconf_blob_bidder/internal/watch"
 	"github.com/urfave/cli/v2"
 )
 
@@ -35,6 +38,135 @@ const (
 	FlagNumBlob                   = "num-blob"
 	FlagDefaultTimeout            = "default-timeout"
 	FlagRunDurationMinutes        = "run-duration-minutes"
+	FlagShutdownBidDrainTimeout   = "shutdown-bid-drain-timeout-sec"
+	FlagProposerAllowlist         = "proposer-allowlist"
+	FlagBeaconEndpoint            = "beacon-endpoint"
+	FlagTxABIFile                 = "tx-abi-file"
+	FlagCommitmentsFile           = "commitments-file"
+	FlagTxCountWindow             = "tx-count-window"
+	FlagBidProfilesFile           = "bid-profiles-file"
+	FlagBidProfileSelector        = "bid-profile-selector"
+	FlagBidBlockRange             = "bid-block-range"
+	FlagFeePercentileWindow       = "fee-percentile-window"
+	FlagBidCycleRetries           = "bid-cycle-retries"
+	FlagLogFile                   = "log-file"
+	FlagLogTimestampFormat        = "log-timestamp-format"
+	FlagBidderTLS                 = "bidder-tls"
+	FlagBidderTLSCAFile           = "bidder-tls-ca-file"
+	FlagConfigFile                = "config"
+	FlagDrainSignal               = "drain-signal"
+	FlagDrainTimeout              = "drain-timeout-sec"
+	FlagDrainConfirmations        = "drain-confirmations"
+	FlagHealthAddr                = "health-addr"
+	FlagHeartbeatInterval         = "heartbeat-interval"
+	FlagBidRecordsFile            = "bid-records-file"
+	FlagEscalationMissedBids      = "escalation-missed-bids"
+	FlagEscalationStepPct         = "escalation-step-pct"
+	FlagEscalationMaxMultiplier   = "escalation-max-multiplier"
+	FlagTipAsBaseFeePct           = "tip-as-base-fee-pct"
+	FlagTipFloorWei               = "tip-floor-wei"
+	FlagTxDedup                   = "tx-dedup"
+	FlagBidLatencySLO             = "bid-latency-slo"
+	FlagKZGTrustedSetup           = "kzg-trusted-setup"
+	FlagTxMaxLifetime             = "tx-max-lifetime"
+	FlagBidAllowReverts           = "bid-allow-reverts"
+	FlagPrivateKeys               = "private-keys"
+	FlagWalletModes               = "wallet-modes"
+	FlagBlobRamp                  = "blob-ramp"
+	FlagBlobRampMax               = "blob-ramp-max"
+	FlagBlobRampStepBlocks        = "blob-ramp-step-blocks"
+	FlagBlobRampSchedule          = "blob-ramp-schedule"
+	FlagExitOnCriteria            = "exit-on-criteria"
+	FlagNonceCheckInterval        = "nonce-check-interval"
+	FlagNoncePauseChecks          = "nonce-pause-checks"
+	FlagBlobChainLength           = "blob-chain-length"
+	FlagDecayJitter               = "decay-jitter"
+	FlagBidMinEth                 = "bid-min-eth"
+	FlagBidMaxEth                 = "bid-max-eth"
+	FlagBidAmountStrategy         = "bid-amount-strategy"
+	FlagBidAmountMin              = "bid-amount-min"
+	FlagBidAmountMax              = "bid-amount-max"
+	FlagBidMode                   = "bid-mode"
+	FlagBidAmountEth              = "bid-amount-eth"
+	FlagTransferScheduleCSV       = "transfer-schedule-csv"
+	FlagBidderOutagePolicy        = "bidder-outage-policy"
+	FlagBidderOutageFailures      = "bidder-outage-failures"
+	FlagBidderOutageQueueSize     = "bidder-outage-queue-size"
+	FlagDecayStartOffsetMs        = "decay-start-offset-ms"
+	FlagDecayDurationMs           = "decay-duration-ms"
+	FlagAdaptiveBlobCount         = "adaptive-blob-count"
+	FlagAdaptiveBlobMin           = "adaptive-blob-min"
+	FlagAdaptiveBlobMax           = "adaptive-blob-max"
+	FlagAdaptiveBlobLowFeeWei     = "adaptive-blob-low-fee-wei"
+	FlagAdaptiveBlobHighFeeWei    = "adaptive-blob-high-fee-wei"
+	FlagKeyAudit                  = "key-audit"
+	FlagKeyAuditFile              = "key-audit-file"
+	FlagRpcPoolSize               = "rpc-pool-size"
+	FlagRpcPoolHealthInterval     = "rpc-pool-health-interval"
+	FlagBlockTime                 = "chain-block-time"
+	FlagBidRetryAttempts          = "bid-retry-attempts"
+	FlagBidRetryBackoff           = "bid-retry-backoff"
+	FlagNumBlobs                  = "num-blobs"
+	FlagBidRecordStrategy         = "bid-record-strategy"
+	FlagTxType                    = "tx-type"
+	FlagMetricsPort               = "metrics-port"
+	FlagOffsetDecayPolicy         = "offset-decay-policy"
+	FlagTxPerBlock                = "tx-per-block"
+	FlagBidOutcomeLogBlocks       = "bid-outcome-log-blocks"
+	FlagBackrunTo                 = "backrun-to"
+	FlagBackrunData               = "backrun-data"
+	FlagDryRun                    = "dry-run"
+	FlagMetricsAddr               = "metrics-addr"
+	FlagBlobSidecar               = "blob-sidecar"
+	FlagKeystorePath              = "keystore-path"
+	FlagKeystorePassword          = "keystore-password"
+	FlagPrettyLog                 = "pretty-log"
+	FlagBlobSeed                  = "blob-seed"
+	FlagMaxConcurrentBundles      = "max-concurrent-bundles"
+	FlagBundleOverflowPolicy      = "bundle-overflow-policy"
+	FlagBlobDataPath              = "blob-data-path"
+	FlagNonceResyncFailures       = "nonce-resync-failures"
+	FlagHTTPUserAgent             = "http-user-agent"
+	FlagMode                      = "mode"
+	FlagWatchAddresses            = "watch-addresses"
+	FlagBidRetryableCodes         = "bid-retryable-codes"
+	FlagStaleBidPolicy            = "stale-bid-policy"
+	FlagGasFeeCapGwei             = "gas-fee-cap-gwei"
+	FlagGasTipGwei                = "gas-tip-gwei"
+	FlagBlobFeeCapGwei            = "blob-fee-cap-gwei"
+	FlagSendIntervalBlocks        = "send-interval-blocks"
+	FlagSendIntervalSeconds       = "send-interval-seconds"
+	FlagInclusionWebhookURL       = "inclusion-webhook-url"
+	FlagInclusionWebhookTimeout   = "inclusion-webhook-timeout"
+	FlagInclusionWebhookAttempts  = "inclusion-webhook-attempts"
+	FlagMinDeposit                = "min-deposit"
+	FlagTopUpAmount               = "top-up-amount"
+	FlagDepositBlocksPerWindow    = "deposit-blocks-per-window"
+	FlagBaseFeeMultiplier         = "base-fee-multiplier"
+	FlagMaxFeeGwei                = "max-fee-gwei"
+	FlagTokenTransfer             = "token-transfer"
+	FlagTokenAddress              = "token-address"
+	FlagTokenAmount               = "token-amount"
+	FlagLogDedupWindow            = "log-dedup-window"
+	FlagHeaderStaleTimeout        = "header-stale-timeout"
+	FlagBidPricingWorkers         = "bid-pricing-workers"
+	FlagTransferAmountWei         = "transfer-amount-wei"
+	FlagBidEndpointTimeout        = "bid-endpoint-timeout"
+	FlagMaxRuntime                = "max-runtime"
+	FlagBlobFeeAsGasFeeMultiple   = "blob-fee-as-gas-fee-multiple"
+	FlagBidLogFile                = "bid-log-file"
+	FlagBidLogFormat              = "bid-log-format"
+	FlagBidLogMaxSizeMB           = "bid-log-max-size-mb"
+	FlagBidLogMaxBackups          = "bid-log-max-backups"
+	FlagSilentRejectionWebhookURL = "silent-rejection-webhook-url"
+	FlagBroadcastDelay            = "broadcast-delay"
+	FlagWsEndpoints               = "ws-endpoints"
+	FlagFundingPrivateKey         = "funding-private-key"
+	FlagRebalanceMinBalance       = "rebalance-min-balance"
+	FlagRebalanceTopUpAmount      = "rebalance-top-up-amount"
+	FlagRebalanceMaxTotal         = "rebalance-max-total"
+	FlagRebalanceInterval         = "rebalance-interval"
+	FlagRebalanceCooldown         = "rebalance-cooldown"
 
 	// New flags for AppName and Version
 	FlagAppName = "app-name"
@@ -43,6 +175,23 @@ const (
 	FlagPriorityFee = "priority-fee"
 )
 
+// closeTimeout bounds how long exit waits for log and audit writers to flush
+const closeTimeout = 5 * time.Second
+
+// Blocks that block time detection averages over at startup, and afterwards
+// between re-estimates from received headers.
+const (
+	blockTimeSpan           = 64
+	blockTimeReestimateSpan = 256
+)
+
+// How long the bidder node has to answer the reachability check at startup.
+const bidderStartupTimeout = 10 * time.Second
+
+// Extra time the first header of a new subscription has to arrive in before
+// HEADER_STALE_TIMEOUT applies.
+const headerStreamGrace = 30 * time.Second
+
 // promptForInput prompts the user for input and returns the entered string
 func promptForInput(prompt string) string {
 	fmt.Printf("%s: ", prompt)
@@ -53,547 +202,981 @@ func promptForInput(prompt string) string {
 	return input
 }
 
-// validateWebSocketURL validates and formats the WebSocket URL
-func validateWebSocketURL(input string) (string, error) {
-	if input == "" {
-		return "", fmt.Errorf("endpoint cannot be empty")
+// detectBlockTime estimates the chain's block time from the timestamps of recent
+// headers, falling back to the default if the node can't be reached or the chain
+// is too short to measure.
+//
+// Parameters:
+// - wsEndpoint: The endpoint of the node to read headers from.
+//
+// Returns:
+// - The detected or default block time.
+func detectBlockTime(wsEndpoint string) time.Duration {
+	client, err := bb.NewGethClient(wsEndpoint)
+	if err != nil {
+		slog.Warn("Failed to detect block time, using the default", "blockTime", strategy.DefaultBlockTime.String(), "error", err)
+		return strategy.DefaultBlockTime
 	}
+	defer client.Close()
 
-	if !strings.Contains(input, "://") {
-		input = "ws://" + input
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	blockTime, err := ee.EstimateBlockTime(ctx, client, blockTimeSpan)
+	if err == nil {
+		_, err = strategy.NewBlockTiming(blockTime)
 	}
-
-	parsedURL, err := url.Parse(input)
 	if err != nil {
-		return "", fmt.Errorf("invalid URL format: %v", err)
+		slog.Warn("Failed to detect block time, using the default", "blockTime", strategy.DefaultBlockTime.String(), "error", err)
+		return strategy.DefaultBlockTime
 	}
+	slog.Info("Detected block time", "blockTime", blockTime.String(), "blocks", blockTimeSpan)
+	return blockTime
+}
 
-	if parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
-		return "", fmt.Errorf("invalid scheme: %s (only ws:// or wss:// are supported)", parsedURL.Scheme)
+// recordInclusionFees fetches the receipt of an included transaction and adds
+// its gas and blob fees, and the bid that landed it, to the fee stats and metrics.
+// It returns the fees, or nil if the receipt can't be fetched or was counted before.
+func recordInclusionFees(ctx context.Context, client *ethclient.Client, block *types.Block, record *api.BidRecord, fees *bids.FeeTracker) *bids.FeeTotals {
+	hash := common.HexToHash(record.TxHash)
+	tx := block.Transaction(hash)
+	if tx == nil {
+		return nil
 	}
-
-	if parsedURL.Host == "" {
-		return "", fmt.Errorf("URL must include a host")
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		slog.Warn("Failed to fetch receipt of included transaction", "txHash", record.TxHash, "error", err)
+		return nil
 	}
-
-	return parsedURL.String(), nil
-}
-
-// validatePrivateKey ensures the private key is a 64-character hexadecimal string
-func validatePrivateKey(input string) error {
-	if len(input) != 64 {
-		return fmt.Errorf("private key must be 64 hex characters")
+	paid := fees.RecordInclusion(tx, receipt, bb.EthToWei(record.AmountEth))
+	if paid == nil {
+		return nil
 	}
-	return nil
-}
-
-func getOrDefault(c *cli.Context, flagName, envVar, defaultValue string) string {
-    val := c.String(flagName)
-    if val == "" {
-        val = os.Getenv(envVar)
-        if val == "" {
-            val = defaultValue
-        }
-    }
-    return val
-}
-
-func getOrDefaultBool(c *cli.Context, flagName, envVar string, defaultValue bool) bool {
-    // 1. Check if the flag was explicitly set via command line
-    if c.IsSet(flagName) {
-        return c.Bool(flagName) // Use the flag's value
-    }
-
-    // 2. Check if the environment variable exists AND has a non-empty value
-    envVal, envVarExists := os.LookupEnv(envVar)
-    if envVarExists && envVal != "" {
-        // Environment variable exists and is not empty, try to parse it
-        parsedVal, err := strconv.ParseBool(envVal)
-        if err == nil {
-            return parsedVal // Parsed successfully, use parsed value
-        }
-        slog.Warn("Environment variable set but could not be parsed as bool. Using default value.", "envVar", envVar, "value", envVal, "error", err, "default", defaultValue)
-    }
 
-    // 3. Either the environment variable is not set, or it's empty.
-    //    In either case, use the default value (which is true in your case)
-    return defaultValue
+	txType := bids.TxTypeLabel(tx.Type())
+	metrics.IncludedGasUsed.WithLabelValues(txType).Add(float64(paid.GasUsed))
+	metrics.IncludedBlobGasUsed.WithLabelValues(txType).Add(float64(paid.BlobGasUsed))
+	metrics.IncludedCostGwei.WithLabelValues(txType, "gas").Add(bb.WeiToEth(paid.GasFeeWei) * 1e9)
+	metrics.IncludedCostGwei.WithLabelValues(txType, "blob").Add(bb.WeiToEth(paid.BlobFeeWei) * 1e9)
+	metrics.IncludedCostGwei.WithLabelValues(txType, "bid").Add(bb.WeiToEth(paid.BidWei) * 1e9)
+	return paid
 }
 
-func getOrDefaultUint64(c *cli.Context, flagName, envVar string, defaultValue uint64) uint64 {
-    val := c.Uint64(flagName)
-    if !c.IsSet(flagName) {
-        envVal := os.Getenv(envVar)
-        if envVal == "" {
-            return defaultValue
-        }
-        parsedVal, err := strconv.ParseUint(envVal, 10, 64)
-        if err != nil {
-            return defaultValue
-        }
-        return parsedVal
-    }
-    return val
-}
-
-func getOrDefaultFloat64(c *cli.Context, flagName, envVar string, defaultValue float64) float64 {
-    val := c.Float64(flagName)
-    if !c.IsSet(flagName) {
-        envVal := os.Getenv(envVar)
-        if envVal == "" {
-            return defaultValue
-        }
-        parsedVal, err := strconv.ParseFloat(envVal, 64)
-        if err != nil {
-            return defaultValue
-        }
-        return parsedVal
-    }
-    return val
+// bidLogEntry describes a bid that was sent, with the input it was sent as and
+// what the bidder node answered, for the bid history.
+func bidLogEntry(bid bb.PendingBid, input interface{}, sentAt time.Time, result bb.BidResult, err error, dryRun bool) store.BidEntry {
+	entry := store.BidEntry{
+		SentAt:      sentAt.UnixMilli(),
+		BlockNumber: bid.BlockNumber,
+		TxHashes:    bidTxHashes(bid),
+		DecayStart:  bid.Window.Start,
+		DecayEnd:    bid.Window.End,
+		Mode:        store.ModeHash,
+		Commitments: len(result.Commitments),
+	}
+	if bid.AmountWei != nil {
+		entry.AmountWei = bid.AmountWei.String()
+	}
+	switch input.(type) {
+	case *types.Transaction, []*types.Transaction:
+		entry.Mode = store.ModePayload
+	}
+	entry.Result = bb.ClassifyBid(result, err)
+	if err != nil {
+		entry.Error = err.Error()
+	} else if dryRun {
+		entry.Result = store.ResultDryRun
+	}
+	return entry
 }
 
-func getOrDefaultUint(c *cli.Context, flagName, envVar string, defaultValue uint) uint {
-    val := c.Uint(flagName)
-    if !c.IsSet(flagName) {
-        envVal := os.Getenv(envVar)
-        if envVal == "" {
-            return defaultValue
-        }
-        parsedVal, err := strconv.ParseUint(envVal, 10, 64)
-        if err != nil {
-            return defaultValue
-        }
-        return uint(parsedVal)
-    }
-    return val
+// bidTxHashes returns the hashes of the transactions a bid is on.
+func bidTxHashes(bid bb.PendingBid) []string {
+	txs := bid.Bundle
+	if len(txs) == 0 {
+		txs = []*types.Transaction{bid.Tx}
+	}
+	txHashes := make([]string, len(txs))
+	for i, tx := range txs {
+		txHashes[i] = tx.Hash().String()
+	}
+	return txHashes
 }
 
 func main() {
-    app := &cli.App{
-        Name:  "Preconf Bidder",
-        Usage: "A tool for bidding in mev-commit preconfirmation auctions for blobs and eth transfers.",
-        Action: func(c *cli.Context) error {
-            // Retrieve AppName and Version from flags or environment variables, with defaults
-            appName := getOrDefault(c, FlagAppName, "APP_NAME", "preconf_bidder")
-            version := getOrDefault(c, FlagVersion, "VERSION", "0.8.0")
-
-            // Initialize the custom pretty-print JSON handler with INFO level
-            handler := NewCustomJSONHandler(os.Stderr, slog.LevelInfo)
-
-            // Add default attributes to every log entry
-            logger := slog.New(handler).With(
-                slog.String("app", appName),
-                slog.String("version", version),
-            )
-
-            slog.SetDefault(logger)
-
-            fmt.Println("-----------------------------------------------------------------------------------------------")
-            fmt.Println("Welcome to Preconf Bidder!")
-            fmt.Println("")
-            fmt.Println("This is a quickstart tool to make preconf bids on mev-commit chain.")
-            fmt.Println("")
-            fmt.Println("If you already know what you're doing, you can skip the prompts by providing flags upfront.")
-            fmt.Println("For example:")
-            fmt.Println("  ./biddercli --private-key <your_64_char_hex_key> --ws-endpoint wss://your-node.com/ws")
-            fmt.Println("")
-            fmt.Println("Available flags include:")
-            fmt.Println("  --private-key            Your private key for signing transactions (64 hex chars)")
-            fmt.Println("  --ws-endpoint            The WebSocket endpoint for your Ethereum node")
-            fmt.Println("  --rpc-endpoint           The RPC endpoint if not using payload")
-            fmt.Println("  --bid-amount             The amount to bid (in ETH), default 0.001")
-            fmt.Println("  --priority-fee           The priority fee in wei, default 1")
-            fmt.Println("  --bid-amount-std-dev-percentage  Std dev percentage of bid amount, default 100.0")
-            fmt.Println("  --num-blob                       Number of blob transactions to send, default 0 makes the tx an eth transfer")
-            fmt.Println("  --default-timeout        Default client context timeout in seconds, default 15")
-            fmt.Println("  --run-duration-minutes   Duration to run the bidder in minutes (0 for infinite)")
-            fmt.Println("  --app-name               Application name for logging")
-            fmt.Println("  --version                Application version for logging")
-            fmt.Println("")
-            fmt.Println("You can also set environment variables like WS_ENDPOINT and PRIVATE_KEY.")
-            fmt.Println("For more details, check the documentation: https://docs.primev.xyz/get-started/bidders/best-practices")
-            fmt.Println("-----------------------------------------------------------------------------------------------")
-            fmt.Println()
-
-            // Get values from flags, environment, or use defaults
-            serverAddress := getOrDefault(c, FlagServerAddress, "SERVER_ADDRESS", "localhost:13524")
-            usePayload := getOrDefaultBool(c, FlagUsePayload, "USE_PAYLOAD", true)
-            rpcEndpoint := getOrDefault(c, FlagRpcEndpoint, "RPC_ENDPOINT", "https://ethereum-holesky-rpc.publicnode.com")
-            wsEndpoint := getOrDefault(c, FlagWsEndpoint, "WS_ENDPOINT", "wss://ethereum-holesky-rpc.publicnode.com")
-            privateKeyHex := getOrDefault(c, FlagPrivateKey, "PRIVATE_KEY", "") // No default, required
-            offset := getOrDefaultUint64(c, FlagOffset, "OFFSET", 1)
-            bidAmount := getOrDefaultFloat64(c, FlagBidAmount, "BID_AMOUNT", 0.001)
-            priorityFee := getOrDefaultUint64(c, FlagPriorityFee, "PRIORITY_FEE", 1)
-            stdDevPercentage := getOrDefaultFloat64(c, FlagBidAmountStdDevPercentage, "BID_AMOUNT_STD_DEV_PERCENTAGE", 100.0)
-            numBlob := getOrDefaultUint(c, FlagNumBlob, "NUM_BLOB", 0)
-            defaultTimeoutSeconds := getOrDefaultUint(c, FlagDefaultTimeout, "DEFAULT_TIMEOUT", 15)
-            runDurationMinutes := getOrDefaultUint(c, FlagRunDurationMinutes, "RUN_DURATION_MINUTES", 0)
-
-            // Validate wsEndpoint if provided
-            if wsEndpoint != "" {
-                var err error
-                wsEndpoint, err = validateWebSocketURL(wsEndpoint)
-                if err != nil {
-                    slog.Error("WS_ENDPOINT validation error", "err", err)
-                    return err
-                }
-            }
-            
-            // Interactive prompts if wsEndpoint or privateKeyHex are not provided
-            if wsEndpoint == "" {
-                fmt.Println("First, we need the WebSocket endpoint for your Ethereum node.")
-                fmt.Println("This is where we'll connect to receive real-time blockchain updates.")
-                fmt.Println("For example: wss://your-node-provider.com/ws")
-                fmt.Println()
-                var err error
-                for {
-                    wsEndpoint = promptForInput("Please enter your WebSocket endpoint")
-                    wsEndpoint, err = validateWebSocketURL(wsEndpoint)
-                    if err == nil {
-                        break
-                    }
-                    fmt.Printf("Error: %s\nPlease try again.\n\n", err)
-                }
-                fmt.Println()
-            }
-
-            if privateKeyHex == "" {
-                fmt.Println("A private key is needed to sign transactions.")
-                fmt.Println("A private key is a 64-character hexadecimal string.")
-                fmt.Println()
-                var err error
-                for {
-                    privateKeyHex = promptForInput("Please enter your private key")
-                    err = validatePrivateKey(privateKeyHex)
-                    if err == nil {
-                        break
-                    }
-                    fmt.Printf("Error: %s\nPlease try again.\n\n", err)
-                }
-                fmt.Println()
-            }
-
-            defaultTimeout := time.Duration(defaultTimeoutSeconds) * time.Second
-            var endTime time.Time
-            if runDurationMinutes > 0 {
-                endTime = time.Now().Add(time.Duration(runDurationMinutes) * time.Minute)
-                slog.Info("Bidder will run until", "endTime", endTime)
-            } else {
-                slog.Info("Bidder will run indefinitely")
-            }
-
-            fmt.Println("Great! Here's what we have:")
-            fmt.Printf(" - WebSocket Endpoint: %s\n", wsEndpoint)
-            fmt.Printf(" - Private Key: Provided (hidden)\n")
-            fmt.Printf(" - Server Address: %s\n", serverAddress)
-            fmt.Printf(" - Use Payload: %v\n", usePayload)
-            fmt.Printf(" - Bid Amount: %f ETH\n", bidAmount)
-			fmt.Printf(" - Priority Fee: %d wei\n", priorityFee)
-            fmt.Printf(" - Standard Deviation: %f%%\n", stdDevPercentage)
-            fmt.Printf(" - Number of Blobs: %d\n", numBlob)
-            fmt.Printf(" - Default Timeout: %d seconds\n", defaultTimeoutSeconds)
-            if runDurationMinutes > 0 {
-                fmt.Printf(" - Run Duration: %d minutes\n", runDurationMinutes)
-            } else {
-                fmt.Printf(" - Run Duration: infinite\n")
-            }
-            fmt.Println()
-            fmt.Println("We will now connect to the blockchain and start sending transactions.")
-            fmt.Println("Please wait...")
-            fmt.Println()
+	// Writers that must be flushed before exit, on both the graceful and the error path
+	closers := shutdown.NewRegistry()
 
-            slog.Info("Configuration values",
-                "appName", appName,
-                "version", version,
-                "serverAddress", serverAddress,
-                "rpcEndpoint", bb.MaskEndpoint(rpcEndpoint),
-                "wsEndpoint", bb.MaskEndpoint(wsEndpoint),
-                "offset", offset,
-                "usePayload", usePayload,
-                "bidAmount", bidAmount,
-                "priorityFee", priorityFee,
-                "stdDevPercentage", stdDevPercentage,
-                "numBlob", numBlob,
-                "privateKeyProvided", privateKeyHex != "",
-                "defaultTimeoutSeconds", defaultTimeoutSeconds,
-            )
-
-            cfg := bb.BidderConfig{
-                ServerAddress: serverAddress,
-            }
-
-            bidderClient, err := bb.NewBidderClient(cfg)
-            if err != nil {
-                slog.Error("Failed to connect to mev-commit bidder API", "error", err)
-                return fmt.Errorf("failed to connect to mev-commit bidder API: %w", err)
-            }
-
-            slog.Info("Connected to mev-commit client")
-
-            timeout := defaultTimeout
-
-            var rpcClient *ethclient.Client
-            if !usePayload {
-                rpcClient = bb.ConnectRPCClientWithRetries(rpcEndpoint, 5, timeout)
-                if rpcClient == nil {
-                    slog.Error("Failed to connect to RPC client", "rpcEndpoint", bb.MaskEndpoint(rpcEndpoint))
-                } else {
-                    slog.Info("Geth client connected (rpc)",
-                        "endpoint", bb.MaskEndpoint(rpcEndpoint),
-                    )
-                }
-            }
-
-            wsClient, err := bb.ConnectWSClient(wsEndpoint)
-            if err != nil {
-                slog.Error("Failed to connect to WebSocket client", "error", err)
-                return fmt.Errorf("failed to connect to WebSocket client: %w", err)
-            }
-            slog.Info("Geth client connected (ws)",
-                "endpoint", bb.MaskEndpoint(wsEndpoint),
-            )
-
-            headers := make(chan *types.Header)
-            sub, err := wsClient.SubscribeNewHead(context.Background(), headers)
-            if err != nil {
-                slog.Error("Failed to subscribe to new blocks", "error", err)
-                return fmt.Errorf("failed to subscribe to new blocks: %w", err)
-            }
-            
-            if privateKeyHex == "" {
-				slog.Error("Private key is required")
-				return fmt.Errorf("private key is required")
-			}
-
-            authAcct, err := bb.AuthenticateAddress(privateKeyHex, wsClient)
-            if err != nil {
-                slog.Error("Failed to authenticate private key", "error", err)
-                return fmt.Errorf("failed to authenticate private key: %w", err)
-            }
-
-            for {
-                if runDurationMinutes > 0 && time.Now().After(endTime) {
-                    slog.Info("Run duration reached, shutting down")
-                    return nil
-                }
-
-                select {
-                case err := <-sub.Err():
-                    slog.Warn("Subscription error", "error", err)
-                    wsClient, sub = bb.ReconnectWSClient(wsEndpoint, headers)
-                    continue
-                case header := <-headers:
-                    var signedTx *types.Transaction
-                    var blockNumber uint64
-                    if numBlob == 0 {
-                        // Perform ETH Transfer
-                        amount := big.NewInt(1e9)
-                        signedTx, blockNumber, err = ee.SelfETHTransfer(wsClient, authAcct, amount, offset, big.NewInt(int64(priorityFee)))
-                    } else {
-                        // Execute Blob Transaction
-                        signedTx, blockNumber, err = ee.ExecuteBlobTransaction(wsClient, authAcct, int(numBlob), offset, big.NewInt(int64(priorityFee)))
-                    }
-
-                    if signedTx == nil {
-                        slog.Error("Transaction was not signed or created.")
-                    } else {
-                        slog.Info("Transaction sent successfully")
-                    }
-
-                    if err != nil {
-                        slog.Error("Failed to execute transaction", "error", err)
-                    }
-
-                    slog.Info("New block received",
-                        "blockNumber", header.Number.Uint64(),
-                        "timestamp", header.Time,
-                        "hash", header.Hash().String(),
-                    )
-
-                    stdDev := bidAmount * stdDevPercentage / 100.0
-                    randomEthAmount := rand.NormFloat64()*stdDev + bidAmount
-                    randomEthAmount = math.Max(randomEthAmount, bidAmount)
-
-                    if usePayload {
-                        bb.SendPreconfBid(bidderClient, signedTx, int64(blockNumber), randomEthAmount)
-                    } else {
-                        _, err = ee.SendBundle(rpcEndpoint, signedTx, blockNumber)
-                        if err != nil {
-                            slog.Error("Failed to send transaction",
-                                "rpcEndpoint", bb.MaskEndpoint(rpcEndpoint),
-                                "error", err,
-                            )
-                        }
-                        bb.SendPreconfBid(bidderClient, signedTx.Hash().String(), int64(blockNumber), randomEthAmount)
-                    }
-
-                    if err != nil {
-                        slog.Error("Failed to execute transaction", "error", err)
-                        continue
-                    }
-                }
-            }
-        },
-        Flags: []cli.Flag{
-            &cli.StringFlag{
-                Name:    FlagEnv,
-                Usage:   "Path to .env file",
-                EnvVars: []string{"ENV_FILE"},
-            },
-            &cli.StringFlag{
-                Name:    FlagServerAddress,
-                Usage:   "Address of the server",
-                EnvVars: []string{"SERVER_ADDRESS"},
-                Value:   "localhost:13524",
-            },
-            &cli.BoolFlag{
-                Name:    FlagUsePayload,
-                Usage:   "Use payload for transactions",
-                EnvVars: []string{"USE_PAYLOAD"},
-                Value:   true,
-            },
-            &cli.StringFlag{
-                Name:     FlagRpcEndpoint,
-                Usage:    "RPC endpoint when use-payload is false",
-                EnvVars:  []string{"RPC_ENDPOINT"},
-                Required: false,
-            },
-            &cli.StringFlag{
-                Name:     FlagWsEndpoint,
-                Usage:    "WebSocket endpoint for transactions",
-                EnvVars:  []string{"WS_ENDPOINT"},
-                Value:    "wss://ethereum-holesky-rpc.publicnode.com",
-                Required: false,
-            },
-            &cli.StringFlag{
-                Name:      FlagPrivateKey,
-                Usage:     "Private key for signing transactions",
-                EnvVars:   []string{"PRIVATE_KEY"},
-                Required:  false,
-                Hidden:    true,
-                TakesFile: false,
-            },
-            &cli.Uint64Flag{
-                Name:    FlagOffset,
-                Usage:   "Offset is how many blocks ahead to bid for the preconf transaction",
-                EnvVars: []string{"OFFSET"},
-                Value:   1,
-            },
-            &cli.Float64Flag{
-                Name:    FlagBidAmount,
-                Usage:   "Amount to bid (in ETH)",
-                EnvVars: []string{"BID_AMOUNT"},
-                Value:   0.001,
-            },
-            &cli.Float64Flag{
-                Name:    FlagBidAmountStdDevPercentage,
-                Usage:   "Standard deviation percentage for bid amount",
-                EnvVars: []string{"BID_AMOUNT_STD_DEV_PERCENTAGE"},
-                Value:   100.0,
-            },
-            &cli.UintFlag{
-                Name:    FlagNumBlob,
-                Usage:   "Number of blobs to send (0 for ETH transfer)",
-                EnvVars: []string{"NUM_BLOB"},
-                Value:   0,
-            },
-            &cli.UintFlag{
-                Name:    FlagDefaultTimeout,
-                Usage:   "Default timeout in seconds",
-                EnvVars: []string{"DEFAULT_TIMEOUT"},
-                Value:   15,
-            },
-            &cli.UintFlag{
-                Name:    FlagRunDurationMinutes,
-                Usage:   "Duration to run the bidder in minutes (0 to run indefinitely)",
-                EnvVars: []string{"RUN_DURATION_MINUTES"},
-                Value:   0,
-            },
-            &cli.StringFlag{
-                Name:    FlagAppName,
-                Usage:   "Application name, for logging purposes",
-                EnvVars: []string{"APP_NAME"},
-                Value:   "preconf_bidder",
-            },
-            &cli.StringFlag{
-                Name:    FlagVersion,
-                Usage:   "mev-commit version, for logging purposes",
-                EnvVars: []string{"VERSION"},
-                Value:   "0.8.0",
-            },
-            &cli.Int64Flag{
-                Name:    FlagPriorityFee,
-                Usage:   "Priority fee in wei",
-                EnvVars: []string{"PRIORITY_FEE"},
-                Value:   1,
-            },
-        },
-    }
-
-    if err := app.Run(os.Args); err != nil {
-        slog.Error("Application error", "error", err)
-        os.Exit(1)
-    }
-}
+	// Secrets mounted as files stand in for their env vars before the flags read them
+	if err := config.LoadSecretFiles(config.SecretVars...); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read secret files:", err)
+		os.Exit(1)
+	}
 
-// CustomJSONHandler is a custom slog.Handler that formats logs as pretty-printed JSON with customized timestamp
-type CustomJSONHandler struct {
-	encoder *json.Encoder
-	level   slog.Level
-}
+	app := newApp(closers)
 
-// NewCustomJSONHandler creates a new instance of CustomJSONHandler
-func NewCustomJSONHandler(w io.Writer, level slog.Level) *CustomJSONHandler {
-	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "  ") // Set indentation for pretty-printing
-	return &CustomJSONHandler{
-		encoder: encoder,
-		level:   level,
+	if err := app.Run(os.Args); err != nil {
+		slog.Error("Application error", "error", err)
+		// Best-effort flush so the error above and the last audit records reach disk
+		closers.Close(closeTimeout)
+		var unmet *bids.UnmetCriteriaError
+		if errors.As(err, &unmet) {
+			os.Exit(bids.ExitCodeCriteriaUnmet)
+		}
+		os.Exit(1)
 	}
-}
-
-// Handle processes each log record
-func (h *CustomJSONHandler) Handle(ctx context.Context, r slog.Record) error {
-	if r.Level < h.level {
-		return nil // Skip logs below the set level
+	if err := closers.Close(closeTimeout); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to close writers:", err)
 	}
-
-	// Create a map to hold the log entry
-	logEntry := make(map[string]interface{})
-
-	// Customize the timestamp to include only milliseconds
-	logEntry["time"] = r.Time.Format("2006-01-02T15:04:05.000Z07:00") // RFC3339 with milliseconds
-
-	// Set the log level
-	logEntry["level"] = r.Level.String()
-
-	// Set the message
-	logEntry["msg"] = r.Message
-
-	// Add all other attributes
-	r.Attrs(func(attr slog.Attr) bool {
-		logEntry[attr.Key] = attr.Value.Any()
-		return true
-	})
-
-	// Encode the log entry as pretty JSON
-	return h.encoder.Encode(logEntry)
 }
 
-// Enabled checks if the handler is enabled for the given level
-func (h *CustomJSONHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.level
-}
-
-// WithAttrs returns a new handler with the given attributes
-func (h *CustomJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	// Create a new handler and copy attributes if necessary
-	// Since we're retaining field names, we don't need to handle attrs specially here
-	return h
-}
+// newApp builds the CLI application.
+//
+// Parameters:
+// - closers: Collects the writers opened by the bot, to be flushed before exit.
+func newApp(closers *shutdown.Registry) *cli.App {
+	app := &cli.App{
+		Name:  "Preconf Bidder",
+		Usage: "A tool for bidding in mev-commit preconfirmation auctions for blobs and eth transfers.",
+		Commands: []*cli.Command{
+			testBidderCommand(),
+			validateRecordsCommand(),
+			keyAuditCommand(),
+			reconcileCommand(),
+		},
+		Action: func(c *cli.Context) error {
+			cfg, err := loadRunConfig(c)
+			if err != nil {
+				return err
+			}
+			r, err := newRunner(c, cfg, closers)
+			if err != nil {
+				return err
+			}
+			if err := r.validate(); err != nil {
+				return err
+			}
+			return r.run()
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    FlagEnv,
+				Usage:   "Path to .env file",
+				EnvVars: []string{"ENV_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagServerAddress,
+				Usage:   "Address of the bidder node, or a comma-separated list of nodes to send each bid to",
+				EnvVars: []string{"SERVER_ADDRESS"},
+				Value:   "localhost:13524",
+			},
+			&cli.BoolFlag{
+				Name:    FlagBidderTLS,
+				Usage:   "Connect to the bidder node over TLS and verify its certificate",
+				EnvVars: []string{"BIDDER_TLS"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidderTLSCAFile,
+				Usage:   "PEM CA bundle used to verify the bidder node certificate instead of the system roots",
+				EnvVars: []string{"BIDDER_TLS_CA_FILE"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagUsePayload,
+				Usage:   "Use payload for transactions",
+				EnvVars: []string{"USE_PAYLOAD"},
+				Value:   true,
+			},
+			&cli.StringFlag{
+				Name:     FlagRpcEndpoint,
+				Usage:    "RPC endpoint when use-payload is false",
+				EnvVars:  []string{"RPC_ENDPOINT"},
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     FlagWsEndpoint,
+				Usage:    "WebSocket endpoint for transactions",
+				EnvVars:  []string{"WS_ENDPOINT"},
+				Value:    "wss://ethereum-holesky-rpc.publicnode.com",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:    FlagWsEndpoints,
+				Usage:   "Comma-separated WebSocket endpoints, tried round robin when connecting and reconnecting; replaces WS_ENDPOINT",
+				EnvVars: []string{"WS_ENDPOINTS"},
+			},
+			&cli.StringFlag{
+				Name:      FlagPrivateKey,
+				Usage:     "Private key for signing transactions",
+				EnvVars:   []string{"PRIVATE_KEY"},
+				Required:  false,
+				Hidden:    true,
+				TakesFile: false,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagOffset,
+				Usage:   "Offset is how many blocks ahead to bid for the preconf transaction",
+				EnvVars: []string{"OFFSET"},
+				Value:   1,
+			},
+			&cli.Float64Flag{
+				Name:    FlagBidAmount,
+				Usage:   "Amount to bid (in ETH)",
+				EnvVars: []string{"BID_AMOUNT"},
+				Value:   0.001,
+			},
+			&cli.Float64Flag{
+				Name:    FlagBidAmountStdDevPercentage,
+				Usage:   "Standard deviation percentage for bid amount",
+				EnvVars: []string{"BID_AMOUNT_STD_DEV_PERCENTAGE"},
+				Value:   100.0,
+			},
+			&cli.UintFlag{
+				Name:    FlagNumBlob,
+				Usage:   "Number of blobs to send (0 for ETH transfer)",
+				EnvVars: []string{"NUM_BLOB"},
+				Value:   0,
+			},
+			&cli.StringFlag{
+				Name:    FlagNumBlobs,
+				Usage:   "Comma-separated blob counts from 1 to 6, e.g. 1,3,6, sent in turn on successive blocks instead of NUM_BLOB",
+				EnvVars: []string{"NUM_BLOBS"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagBidRecordStrategy,
+				Usage:   "Add the pricer, transaction mode, observed base and blob fees, and guards that applied to each bid record",
+				EnvVars: []string{"BID_RECORD_STRATEGY"},
+			},
+			&cli.Uint64Flag{
+				Name:    FlagBidPricingWorkers,
+				Usage:   "Bids of a chain that are priced at once; they are still sent in nonce order",
+				EnvVars: []string{"BID_PRICING_WORKERS"},
+				Value:   1,
+			},
+			&cli.StringFlag{
+				Name:    FlagTxType,
+				Usage:   "Registered transaction generator that profiles without blobs bid with",
+				EnvVars: []string{"TX_TYPE"},
+				Value:   ee.GeneratorTransfer,
+			},
+			&cli.BoolFlag{
+				Name:    FlagTokenTransfer,
+				Usage:   "Bid with ERC-20 transfers of TOKEN_ADDRESS instead of ETH transfers",
+				EnvVars: []string{"TOKEN_TRANSFER"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTokenAddress,
+				Usage:   "ERC-20 contract that token transfers call",
+				EnvVars: []string{"TOKEN_ADDRESS"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTokenAmount,
+				Usage:   "Tokens sent per transfer in the token's base units",
+				EnvVars: []string{"TOKEN_AMOUNT"},
+				Value:   "1",
+			},
+			&cli.UintFlag{
+				Name:    FlagMetricsPort,
+				Usage:   "Port to serve Prometheus metrics on at /metrics, 0 to disable",
+				EnvVars: []string{"METRICS_PORT"},
+				Value:   9090,
+			},
+			&cli.StringFlag{
+				Name:    FlagOffsetDecayPolicy,
+				Usage:   "What to do when OFFSET targets blocks due after the decay window ends: error at startup, extend the window, or anchor it to end when the target block is due",
+				EnvVars: []string{"OFFSET_DECAY_POLICY"},
+				Value:   strategy.OffsetDecayExtend,
+			},
+			&cli.UintFlag{
+				Name:    FlagTxPerBlock,
+				Usage:   "Transactions built per block by profiles without blobs, with nonces allocated locally, and bid on together in one bid",
+				EnvVars: []string{"TX_PER_BLOCK"},
+				Value:   1,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagBidOutcomeLogBlocks,
+				Usage:   "Blocks between log lines of the bids won and lost, 0 to disable",
+				EnvVars: []string{"BID_OUTCOME_LOG_BLOCKS"},
+				Value:   10,
+			},
+			&cli.StringFlag{
+				Name:    FlagBackrunTo,
+				Usage:   "Address of a backrun call built after the transactions of profiles without blobs, at the next nonce, and bid on together with them",
+				EnvVars: []string{"BACKRUN_TO"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBackrunData,
+				Usage:   "Hex calldata of the backrun call",
+				EnvVars: []string{"BACKRUN_DATA"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagDryRun,
+				Usage:   "Build and sign transactions and bids as usual, but log them instead of sending bids to the bidder node or transactions to the RPC endpoint",
+				EnvVars: []string{"DRY_RUN"},
+			},
+			&cli.UintFlag{
+				Name:    FlagDefaultTimeout,
+				Usage:   "Default timeout in seconds",
+				EnvVars: []string{"DEFAULT_TIMEOUT"},
+				Value:   15,
+			},
+			&cli.UintFlag{
+				Name:    FlagRunDurationMinutes,
+				Usage:   "Duration to run the bidder in minutes (0 to run indefinitely), overriding MAX_RUNTIME",
+				EnvVars: []string{"RUN_DURATION_MINUTES"},
+				Value:   0,
+			},
+			&cli.DurationFlag{
+				Name:    FlagMaxRuntime,
+				Usage:   "How long the bidder runs before shutting down, e.g. 336h or 30m (0 to run indefinitely)",
+				EnvVars: []string{"MAX_RUNTIME"},
+				Value:   14 * 24 * time.Hour,
+			},
+			&cli.UintFlag{
+				Name:    FlagShutdownBidDrainTimeout,
+				Usage:   "Seconds to wait for in-flight bids to complete on shutdown",
+				EnvVars: []string{"SHUTDOWN_BID_DRAIN_TIMEOUT_SEC"},
+				Value:   10,
+			},
+			&cli.StringFlag{
+				Name:    FlagProposerAllowlist,
+				Usage:   "Comma-separated validator pubkeys or indices to bid on",
+				EnvVars: []string{"PROPOSER_ALLOWLIST"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBeaconEndpoint,
+				Usage:   "Beacon node API endpoint for proposer lookahead",
+				EnvVars: []string{"BEACON_ENDPOINT"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTxABIFile,
+				Usage:   "ABI file used to decode transaction calldata and revert data in logs",
+				EnvVars: []string{"TX_ABI_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagCommitmentsFile,
+				Usage:   "JSONL file that received commitments are appended to",
+				EnvVars: []string{"COMMITMENTS_FILE"},
+			},
+			&cli.UintFlag{
+				Name:    FlagTxCountWindow,
+				Usage:   "Number of blocks in the rolling transactions-per-block average",
+				EnvVars: []string{"TX_COUNT_WINDOW"},
+				Value:   20,
+			},
+			&cli.StringFlag{
+				Name:    FlagBidProfilesFile,
+				Usage:   "JSON file of named bid profiles to select between per block",
+				EnvVars: []string{"BID_PROFILES_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidProfileSelector,
+				Usage:   "How bid profiles are selected per block: round-robin, parity, or weighted",
+				EnvVars: []string{"BID_PROFILE_SELECTOR"},
+				Value:   "round-robin",
+			},
+			&cli.Uint64Flag{
+				Name:    FlagBidBlockRange,
+				Usage:   "Number of consecutive target blocks to bid on per header",
+				EnvVars: []string{"BID_BLOCK_RANGE"},
+				Value:   1,
+			},
+			&cli.UintFlag{
+				Name:    FlagFeePercentileWindow,
+				Usage:   "Number of recent blocks used to estimate the p90 priority fee",
+				EnvVars: []string{"FEE_PERCENTILE_WINDOW"},
+				Value:   10,
+			},
+			&cli.UintFlag{
+				Name:    FlagBidCycleRetries,
+				Usage:   "Times a failed bid cycle is retried on the next header",
+				EnvVars: []string{"BID_CYCLE_RETRIES"},
+				Value:   0,
+			},
+			&cli.StringFlag{
+				Name:    FlagDrainSignal,
+				Usage:   "Signal that stops bidding and exits once bids on upcoming blocks are confirmed: SIGTERM, SIGHUP, SIGUSR1, SIGUSR2, or none",
+				EnvVars: []string{"DRAIN_SIGNAL"},
+				Value:   "SIGTERM",
+			},
+			&cli.UintFlag{
+				Name:    FlagDrainTimeout,
+				Usage:   "Maximum seconds to drain before exiting with bids still unresolved",
+				EnvVars: []string{"DRAIN_TIMEOUT_SEC"},
+				Value:   120,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagDrainConfirmations,
+				Usage:   "Confirmations on a bid's target block before a drain considers it resolved",
+				EnvVars: []string{"DRAIN_CONFIRMATIONS"},
+				Value:   2,
+			},
+			&cli.StringFlag{
+				Name:    FlagBlobSidecar,
+				Usage:   "How blob transactions of hash-only bids are delivered: full, or omit to send the execution part without the blobs, which relays following EIP-4844 reject",
+				EnvVars: []string{"BLOB_SIDECAR"},
+				Value:   ee.BlobSidecarFull,
+			},
+			&cli.UintFlag{
+				Name:    FlagMaxConcurrentBundles,
+				Usage:   "Most bundle submissions in flight at once, 0 for no limit",
+				EnvVars: []string{"MAX_CONCURRENT_BUNDLES"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBundleOverflowPolicy,
+				Usage:   "What happens to bundles over MAX_CONCURRENT_BUNDLES: queue to wait for a free slot, or drop",
+				EnvVars: []string{"BUNDLE_OVERFLOW_POLICY"},
+				Value:   ee.BundleOverflowQueue,
+			},
+			&cli.DurationFlag{
+				Name:    FlagBroadcastDelay,
+				Usage:   "Time after a hash-only bid is sent before its transactions are sent as a bundle, capped at the target block; 0 sends them before the bid",
+				EnvVars: []string{"BROADCAST_DELAY"},
+			},
+			&cli.StringFlag{
+				Name:    FlagMode,
+				Usage:   "build to bid on transactions the bot builds and signs, or watch to bid on pending transactions of WATCH_ADDRESSES",
+				EnvVars: []string{"MODE"},
+				Value:   watch.ModeBuild,
+			},
+			&cli.StringFlag{
+				Name:    FlagWatchAddresses,
+				Usage:   "Comma-separated sender addresses whose pending transactions are bid on with MODE=watch",
+				EnvVars: []string{"WATCH_ADDRESSES"},
+			},
+			&cli.StringFlag{
+				Name:    FlagHTTPUserAgent,
+				Usage:   "User-Agent header of bundle submissions, RPC pool requests and beacon API requests, <app name>/<version> by default",
+				EnvVars: []string{"HTTP_USER_AGENT"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBlobDataPath,
+				Usage:   "File or directory (files concatenated in name order) packed into the blobs of every blob transaction instead of random data",
+				EnvVars: []string{"BLOB_DATA_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBlobSeed,
+				Usage:   "Seed for the blob data; with a seed every run sends the same sequence of blobs, commitments and versioned hashes",
+				EnvVars: []string{"BLOB_SEED"},
+			},
+			&cli.StringFlag{
+				Name:    FlagKeystorePath,
+				Usage:   "Geth keystore JSON file to load the private key from, instead of PRIVATE_KEY",
+				EnvVars: []string{"KEYSTORE_PATH"},
+			},
+			&cli.StringFlag{
+				Name:    FlagKeystorePassword,
+				Usage:   "Password of the keystore file, or set KEYSTORE_PASSWORD_FILE to read it from a file",
+				EnvVars: []string{"KEYSTORE_PASSWORD"},
+			},
+			&cli.StringFlag{
+				Name:    FlagMetricsAddr,
+				Usage:   "Address to serve Prometheus metrics on at /metrics, e.g. :2112 or 127.0.0.1:2112, instead of METRICS_PORT",
+				EnvVars: []string{"METRICS_ADDR"},
+			},
+			&cli.StringFlag{
+				Name:    FlagHealthAddr,
+				Usage:   "Address to serve /readyz on, which fails once a drain starts",
+				EnvVars: []string{"HEALTH_ADDR"},
+			},
+			&cli.DurationFlag{
+				Name:    FlagHeartbeatInterval,
+				Usage:   "Interval between \"alive\" log lines, 0 to disable",
+				EnvVars: []string{"HEARTBEAT_INTERVAL"},
+				Value:   time.Minute,
+			},
+			&cli.StringFlag{
+				Name:    FlagBidRecordsFile,
+				Usage:   "JSONL file that each bid is appended to once its target block resolves it as included or missed",
+				EnvVars: []string{"BID_RECORDS_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidLogFile,
+				Usage:   "File that every bid sent is appended to with its result, for later analysis",
+				EnvVars: []string{"BID_LOG_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidLogFormat,
+				Usage:   "Format of BID_LOG_FILE: jsonl or csv",
+				EnvVars: []string{"BID_LOG_FORMAT"},
+				Value:   store.FormatJSONL,
+			},
+			&cli.UintFlag{
+				Name:    FlagBidLogMaxSizeMB,
+				Usage:   "Size in MB at which BID_LOG_FILE is rotated, 0 to never rotate it",
+				EnvVars: []string{"BID_LOG_MAX_SIZE_MB"},
+				Value:   100,
+			},
+			&cli.UintFlag{
+				Name:    FlagBidLogMaxBackups,
+				Usage:   "Number of rotated BID_LOG_FILE files to keep",
+				EnvVars: []string{"BID_LOG_MAX_BACKUPS"},
+				Value:   10,
+			},
+			&cli.UintFlag{
+				Name:    FlagEscalationMissedBids,
+				Usage:   "Consecutive missed bids before the bid amount is escalated, 0 to disable",
+				EnvVars: []string{"ESCALATION_MISSED_BIDS"},
+				Value:   3,
+			},
+			&cli.Float64Flag{
+				Name:    FlagEscalationStepPct,
+				Usage:   "Percentage the bid amount is raised by on each escalation",
+				EnvVars: []string{"ESCALATION_STEP_PCT"},
+				Value:   25,
+			},
+			&cli.Float64Flag{
+				Name:    FlagEscalationMaxMultiplier,
+				Usage:   "Cap on the escalated bid amount as a multiple of the base amount",
+				EnvVars: []string{"ESCALATION_MAX_MULTIPLIER"},
+				Value:   5,
+			},
+			&cli.Float64Flag{
+				Name:    FlagTipAsBaseFeePct,
+				Usage:   "Set the priority fee as a percentage of the current base fee instead of PRIORITY_FEE, 0 to disable",
+				EnvVars: []string{"TIP_AS_BASE_FEE_PCT"},
+			},
+			&cli.Uint64Flag{
+				Name:    FlagTipFloorWei,
+				Usage:   "Minimum priority fee in wei when TIP_AS_BASE_FEE_PCT is set",
+				EnvVars: []string{"TIP_FLOOR_WEI"},
+				Value:   1,
+			},
+			&cli.Float64Flag{
+				Name:    FlagGasFeeCapGwei,
+				Usage:   "Fixed max fee per gas in gwei for every transaction, 0 for the latest base fee plus the tip",
+				EnvVars: []string{"GAS_FEE_CAP_GWEI"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagGasTipGwei,
+				Usage:   "Fixed priority fee in gwei for transfers and blob transactions instead of PRIORITY_FEE, 0 to disable",
+				EnvVars: []string{"GAS_TIP_GWEI", "MAX_PRIORITY_FEE_GWEI"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagBlobFeeCapGwei,
+				Usage:   "Fixed max fee per blob gas in gwei, 0 for 10% over the next blob base fee",
+				EnvVars: []string{"BLOB_FEE_CAP_GWEI"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagBlobFeeAsGasFeeMultiple,
+				Usage:   "Derive the max fee per blob gas as the max fee per gas times this, instead of from the blob base fee; 0 to not",
+				EnvVars: []string{"BLOB_FEE_AS_GAS_FEE_MULTIPLE"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagBaseFeeMultiplier,
+				Usage:   "Multiplier on the latest base fee in the max fee per gas",
+				EnvVars: []string{"BASE_FEE_MULTIPLIER"},
+				Value:   1,
+			},
+			&cli.Float64Flag{
+				Name:    FlagMaxFeeGwei,
+				Usage:   "Skip blocks whose max fee per gas would exceed this many gwei, 0 for no limit",
+				EnvVars: []string{"MAX_FEE_GWEI"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagTxDedup,
+				Usage:   "Rebid on the pending transaction of a nonce instead of broadcasting an identical rebuild",
+				EnvVars: []string{"TX_DEDUP"},
+				Value:   true,
+			},
+			&cli.DurationFlag{
+				Name:    FlagBidLatencySLO,
+				Usage:   "Maximum time from header arrival to sending a payload bid before it is downgraded to a hash-only bid delivered as a bundle, 0 to disable",
+				EnvVars: []string{"BID_LATENCY_SLO"},
+			},
+			&cli.StringFlag{
+				Name:    FlagKZGTrustedSetup,
+				Usage:   "KZG trusted setup JSON file used for blob commitments and proofs instead of the embedded mainnet setup",
+				EnvVars: []string{"KZG_TRUSTED_SETUP"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTxMaxLifetime,
+				Usage:   "Duration (e.g. 2m) or number of blocks after which a transaction that isn't included is abandoned, 0 to disable",
+				EnvVars: []string{"TX_MAX_LIFETIME"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagBidAllowReverts,
+				Usage:   "List each bid's transactions as allowed to revert, so a reverted transaction doesn't void the commitment",
+				EnvVars: []string{"BID_ALLOW_REVERTS"},
+			},
+			&cli.StringFlag{
+				Name:    FlagPrivateKeys,
+				Usage:   "Comma-separated private keys of additional wallets, numbered from 1 after PRIVATE_KEY",
+				EnvVars: []string{"PRIVATE_KEYS"},
+				Hidden:  true,
+			},
+			&cli.StringFlag{
+				Name:    FlagWalletModes,
+				Usage:   "Transaction modes per wallet index or address in priority order, e.g. \"0=blob;1=transfer,blob\"; unlisted wallets send any mode",
+				EnvVars: []string{"WALLET_MODES"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagBlobRamp,
+				Usage:   "Blob-only stress mode: ramp the blobs per transaction and summarize inclusion, commitments and blob fees per step",
+				EnvVars: []string{"BLOB_RAMP"},
+			},
+			&cli.UintFlag{
+				Name:    FlagBlobRampMax,
+				Usage:   "Blobs per transaction at the last ramp step",
+				EnvVars: []string{"BLOB_RAMP_MAX"},
+				Value:   6,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagBlobRampStepBlocks,
+				Usage:   "Number of blocks each ramp step lasts",
+				EnvVars: []string{"BLOB_RAMP_STEP_BLOCKS"},
+				Value:   10,
+			},
+			&cli.StringFlag{
+				Name:    FlagBlobRampSchedule,
+				Usage:   "Comma-separated blob counts of the ramp steps, e.g. 1,2,4,6, instead of 1 to BLOB_RAMP_MAX",
+				EnvVars: []string{"BLOB_RAMP_SCHEDULE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagExitOnCriteria,
+				Usage:   "Comma-separated success criteria checked on exit, e.g. min_commitments=1,min_inclusion_rate=0.5; unmet criteria exit with code 2",
+				EnvVars: []string{"EXIT_ON_CRITERIA"},
+			},
+			&cli.DurationFlag{
+				Name:    FlagNonceCheckInterval,
+				Usage:   "Interval between checks of wallet nonces against eth_getTransactionCount, 0 to disable",
+				EnvVars: []string{"NONCE_CHECK_INTERVAL"},
+				Value:   30 * time.Second,
+			},
+			&cli.UintFlag{
+				Name:    FlagNoncePauseChecks,
+				Usage:   "Consecutive divergent nonce checks that can't resync before a wallet stops bidding",
+				EnvVars: []string{"NONCE_PAUSE_CHECKS"},
+				Value:   3,
+			},
+			&cli.UintFlag{
+				Name:    FlagNonceResyncFailures,
+				Usage:   "Consecutive bid cycles that fail to send before a wallet's nonces resync from its pending count",
+				EnvVars: []string{"NONCE_RESYNC_FAILURES"},
+				Value:   1,
+			},
+			&cli.UintFlag{
+				Name:    FlagBlobChainLength,
+				Usage:   "Number of blob transactions with sequential nonces sent from one wallet for the same block, each bid on",
+				EnvVars: []string{"BLOB_CHAIN_LENGTH"},
+				Value:   1,
+			},
+			&cli.DurationFlag{
+				Name:    FlagDecayJitter,
+				Usage:   "Most that the decay windows of simultaneous bids for a block end early, so that they differ; 0 disables",
+				EnvVars: []string{"DECAY_JITTER"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagBidMinEth,
+				Usage:   "Smallest amount any bid is raised to, in ETH, after sampling and escalation; 0 leaves it unset",
+				EnvVars: []string{"BID_MIN_ETH"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagBidMaxEth,
+				Usage:   "Largest amount any bid is lowered to, in ETH, after sampling and escalation; 0 leaves it unset",
+				EnvVars: []string{"BID_MAX_ETH"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidAmountStrategy,
+				Usage:   "uniform or fixed to draw bid amounts in wei between --bid-amount-min and --bid-amount-max instead of from profiles; empty uses the profiles",
+				EnvVars: []string{"BID_AMOUNT_STRATEGY"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidAmountMin,
+				Usage:   "Smallest bid amount in wei, the only one used by the fixed strategy",
+				EnvVars: []string{"BID_AMOUNT_MIN"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidAmountMax,
+				Usage:   "Largest bid amount in wei for the uniform strategy",
+				EnvVars: []string{"BID_AMOUNT_MAX"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidMode,
+				Usage:   "random to sample bid amounts from the profiles, or fixed to send exactly --bid-amount-eth on every bid",
+				EnvVars: []string{"BID_MODE"},
+				Value:   strategy.BidModeRandom,
+			},
+			&cli.Float64Flag{
+				Name:    FlagBidAmountEth,
+				Usage:   "Amount of every bid in ETH in fixed bid mode",
+				EnvVars: []string{"BID_AMOUNT_ETH"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTransferScheduleCSV,
+				Usage:   "CSV file of recipient,value (wei) rows; transfers use the next row on each block instead of a self transfer, looping when exhausted",
+				EnvVars: []string{"TRANSFER_SCHEDULE_CSV"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTransferAmountWei,
+				Usage:   "Wei sent by each ETH transfer without a schedule, as an integer",
+				EnvVars: []string{"TRANSFER_AMOUNT_WEI"},
+				Value:   "1000000000",
+			},
+			&cli.StringFlag{
+				Name:    FlagBidderOutagePolicy,
+				Usage:   "skip to build no transactions while the bidder node is unreachable, or queue to keep building and send up to --bidder-outage-queue-size hash-only bids on recovery",
+				EnvVars: []string{"BIDDER_OUTAGE_POLICY"},
+				Value:   bb.OutagePolicySkip,
+			},
+			&cli.UintFlag{
+				Name:    FlagBidderOutageFailures,
+				Usage:   "Consecutive bids failing because the bidder node is unreachable before an outage starts",
+				EnvVars: []string{"BIDDER_OUTAGE_FAILURES"},
+				Value:   3,
+			},
+			&cli.UintFlag{
+				Name:    FlagBidderOutageQueueSize,
+				Usage:   "Most hash-only bids queued during an outage with the queue policy; older ones are discarded",
+				EnvVars: []string{"BIDDER_OUTAGE_QUEUE_SIZE"},
+				Value:   8,
+			},
+			&cli.Int64Flag{
+				Name:    FlagDecayStartOffsetMs,
+				Usage:   "Delay from building a bid to the start of its decay, in milliseconds, e.g. 12000 to start decaying a slot later",
+				EnvVars: []string{"DECAY_START_OFFSET_MS"},
+			},
+			&cli.Int64Flag{
+				Name:    FlagDecayDurationMs,
+				Usage:   "Decay duration of bids whose profile doesn't set decay_ms, in milliseconds",
+				EnvVars: []string{"DECAY_DURATION_MS"},
+				Value:   strategy.DefaultDecayDuration.Milliseconds(),
+			},
+			&cli.BoolFlag{
+				Name:    FlagAdaptiveBlobCount,
+				Usage:   "Scale the blobs per transaction of blob profiles by the blob base fee, between the adaptive bounds",
+				EnvVars: []string{"ADAPTIVE_BLOB_COUNT"},
+			},
+			&cli.UintFlag{
+				Name:    FlagAdaptiveBlobMin,
+				Usage:   "Blobs per transaction at or above the adaptive high fee",
+				EnvVars: []string{"ADAPTIVE_BLOB_MIN"},
+				Value:   1,
+			},
+			&cli.UintFlag{
+				Name:    FlagAdaptiveBlobMax,
+				Usage:   "Blobs per transaction at or below the adaptive low fee",
+				EnvVars: []string{"ADAPTIVE_BLOB_MAX"},
+				Value:   6,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagAdaptiveBlobLowFeeWei,
+				Usage:   "Blob base fee in wei at or below which the most blobs are sent",
+				EnvVars: []string{"ADAPTIVE_BLOB_LOW_FEE_WEI"},
+				Value:   1_000_000_000,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagAdaptiveBlobHighFeeWei,
+				Usage:   "Blob base fee in wei at or above which the fewest blobs are sent",
+				EnvVars: []string{"ADAPTIVE_BLOB_HIGH_FEE_WEI"},
+				Value:   30_000_000_000,
+			},
+			&cli.StringFlag{
+				Name:    FlagKeyAudit,
+				Usage:   "Record every signature in an append-only log: off, on, or required",
+				EnvVars: []string{"KEY_AUDIT"},
+				Value:   keyaudit.ModeOff,
+			},
+			&cli.StringFlag{
+				Name:    FlagKeyAuditFile,
+				Usage:   "Key audit log file",
+				EnvVars: []string{"KEY_AUDIT_FILE"},
+				Value:   "key_audit.jsonl",
+			},
+			&cli.UintFlag{
+				Name:    FlagRpcPoolSize,
+				Usage:   "Warm connections kept to the RPC endpoint",
+				EnvVars: []string{"RPC_POOL_SIZE"},
+				Value:   2,
+			},
+			&cli.DurationFlag{
+				Name:    FlagRpcPoolHealthInterval,
+				Usage:   "Interval between health checks of pooled RPC connections, which replace dead ones",
+				EnvVars: []string{"RPC_POOL_HEALTH_INTERVAL"},
+				Value:   30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:    FlagHeaderStaleTimeout,
+				Usage:   "Reconnect the header subscription when no header arrives for this long, 0 to wait forever",
+				EnvVars: []string{"HEADER_STALE_TIMEOUT"},
+				Value:   30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:    FlagBidEndpointTimeout,
+				Usage:   "How long each bidder node in SERVER_ADDRESS is given to answer a bid when there are several, 0 for no limit",
+				EnvVars: []string{"BID_ENDPOINT_TIMEOUT"},
+				Value:   10 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:    FlagBlockTime,
+				Usage:   "Block time that the decay, drain timeout and nonce check defaults derive from, e.g. 2s, or auto to detect it from recent headers",
+				EnvVars: []string{"BLOCK_TIME"},
+				Value:   "auto",
+			},
+			&cli.UintFlag{
+				Name:    FlagBidRetryAttempts,
+				Usage:   "Attempts to send a bid while the bidder node fails with a retryable gRPC status, within the bid's block",
+				EnvVars: []string{"BID_RETRY_ATTEMPTS"},
+				Value:   3,
+			},
+			&cli.DurationFlag{
+				Name:    FlagBidRetryBackoff,
+				Usage:   "Wait before the first retry of a bid, doubling after each failure",
+				EnvVars: []string{"BID_RETRY_BACKOFF"},
+				Value:   100 * time.Millisecond,
+			},
+			&cli.Float64Flag{
+				Name:    FlagMinDeposit,
+				Usage:   "Bidder deposit in ETH below which the window of the target block is topped up, 0 to disable",
+				EnvVars: []string{"MIN_DEPOSIT"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagTopUpAmount,
+				Usage:   "ETH deposited in a window by each top-up, at least MIN_DEPOSIT",
+				EnvVars: []string{"TOP_UP_AMOUNT"},
+			},
+			&cli.StringFlag{
+				Name:    FlagFundingPrivateKey,
+				Usage:   "Private key of a wallet outside the pool that tops up pool wallets below REBALANCE_MIN_BALANCE, empty to disable",
+				EnvVars: []string{"FUNDING_PRIVATE_KEY"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagRebalanceMinBalance,
+				Usage:   "Balance in ETH below which a pool wallet is topped up from FUNDING_PRIVATE_KEY",
+				EnvVars: []string{"REBALANCE_MIN_BALANCE"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagRebalanceTopUpAmount,
+				Usage:   "ETH sent to a pool wallet by each top-up",
+				EnvVars: []string{"REBALANCE_TOP_UP_AMOUNT"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagRebalanceMaxTotal,
+				Usage:   "Most ETH sent by top-ups over the session, 0 for no limit",
+				EnvVars: []string{"REBALANCE_MAX_TOTAL"},
+			},
+			&cli.DurationFlag{
+				Name:    FlagRebalanceInterval,
+				Usage:   "Interval between checks of the pool wallets' balances",
+				EnvVars: []string{"REBALANCE_INTERVAL"},
+				Value:   time.Minute,
+			},
+			&cli.DurationFlag{
+				Name:    FlagRebalanceCooldown,
+				Usage:   "Least time between two top-ups of the same wallet, so one still pending isn't repeated",
+				EnvVars: []string{"REBALANCE_COOLDOWN"},
+				Value:   10 * time.Minute,
+			},
+			&cli.Uint64Flag{
+				Name:    FlagDepositBlocksPerWindow,
+				Usage:   "Blocks per bidder registry deposit window",
+				EnvVars: []string{"DEPOSIT_BLOCKS_PER_WINDOW"},
+				Value:   bb.DefaultBlocksPerWindow,
+			},
+			&cli.StringFlag{
+				Name:    FlagInclusionWebhookURL,
+				Usage:   "URL to POST a JSON inclusion record to when a bid's transaction is included, empty to disable",
+				EnvVars: []string{"INCLUSION_WEBHOOK_URL"},
+			},
+			&cli.DurationFlag{
+				Name:    FlagInclusionWebhookTimeout,
+				Usage:   "Timeout of each inclusion webhook request",
+				EnvVars: []string{"INCLUSION_WEBHOOK_TIMEOUT"},
+				Value:   bids.DefaultWebhookTimeout,
+			},
+			&cli.UintFlag{
+				Name:    FlagInclusionWebhookAttempts,
+				Usage:   "Attempts per inclusion webhook call, retrying network errors and 429 or 5xx responses",
+				EnvVars: []string{"INCLUSION_WEBHOOK_ATTEMPTS"},
+				Value:   bids.DefaultWebhookAttempts,
+			},
+			&cli.StringFlag{
+				Name:    FlagSilentRejectionWebhookURL,
+				Usage:   "URL to POST a JSON record to when the bidder node takes a bid without error but no provider commits to it, empty to disable",
+				EnvVars: []string{"SILENT_REJECTION_WEBHOOK_URL"},
+			},
+			&cli.Uint64Flag{
+				Name:    FlagSendIntervalBlocks,
+				Usage:   "Create transactions only every Nth block, 0 or 1 for every block; takes precedence over SEND_INTERVAL_SECONDS",
+				EnvVars: []string{"SEND_INTERVAL_BLOCKS"},
+			},
+			&cli.Float64Flag{
+				Name:    FlagSendIntervalSeconds,
+				Usage:   "Create transactions at most once per this many seconds, 0 for every block",
+				EnvVars: []string{"SEND_INTERVAL_SECONDS"},
+			},
+			&cli.StringFlag{
+				Name:    FlagStaleBidPolicy,
+				Usage:   "Bids whose target block's header arrived while they were built are skipped (skip), or sent for latest block + OFFSET while their transactions are still valid (retarget)",
+				EnvVars: []string{"STALE_BID_POLICY"},
+				Value:   bb.StaleBidSkip,
+			},
+			&cli.StringFlag{
+				Name:    FlagBidRetryableCodes,
+				Usage:   "Comma-separated gRPC statuses a bid is retried after; any other status aborts the bid (Default UNAVAILABLE,DEADLINE_EXCEEDED,RESOURCE_EXHAUSTED,ABORTED)",
+				EnvVars: []string{"BID_RETRYABLE_CODES"},
+			},
+			&cli.BoolFlag{
+				Name:   FlagSelfTest,
+				Usage:  "Set by the selftest command to bid once and report each stage",
+				Hidden: true,
+			},
+			&cli.Float64Flag{
+				Name:   FlagSelfTestMaxBidAmount,
+				Usage:  "Set by the selftest command to cap its bid amount in ETH",
+				Hidden: true,
+			},
+			&cli.BoolFlag{
+				Name:   FlagDemo,
+				Usage:  "Set by the demo command to mark the run as a demo",
+				Hidden: true,
+			},
+			&cli.StringFlag{
+				Name:    FlagConfigFile,
+				Usage:   "YAML config file; values may reference env vars as ${VAR} or ${VAR:-default}",
+				EnvVars: []string{"CONFIG_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagLogFile,
+				Usage:   "File that JSON logs are also written to, rotated by size",
+				EnvVars: []string{"LOG_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagLogTimestampFormat,
+				Usage:   "Log timestamp format: rfc3339, rfc3339nano, unix, or unixmilli (default RFC3339 with milliseconds)",
+				EnvVars: []string{"LOG_TIMESTAMP_FORMAT"},
+			},
+			&cli.DurationFlag{
+				Name:    FlagLogDedupWindow,
+				Usage:   "Collapse repeats of a logged failure (same message and error) into one summary per window, 0 to log every one",
+				EnvVars: []string{"LOG_DEDUP_WINDOW"},
+				Value:   time.Minute,
+			},
+			&cli.BoolFlag{
+				Name:    FlagPrettyLog,
+				Usage:   "Pretty-print console logs; false writes one JSON object per line for log shippers",
+				EnvVars: []string{"PRETTY_LOG"},
+				Value:   true,
+			},
+			&cli.StringFlag{
+				Name:    FlagAppName,
+				Usage:   "Application name, for logging purposes",
+				EnvVars: []string{"APP_NAME"},
+				Value:   "preconf_bidder",
+			},
+			&cli.StringFlag{
+				Name:    FlagVersion,
+				Usage:   "mev-commit version, for logging purposes",
+				EnvVars: []string{"VERSION"},
+				Value:   "0.8.0",
+			},
+			&cli.Int64Flag{
+				Name:    FlagPriorityFee,
+				Usage:   "Priority fee in wei",
+				EnvVars: []string{"PRIORITY_FEE"},
+				Value:   1,
+			},
+		},
+	}
 
-// WithGroup returns a new handler with the given group name
-func (h *CustomJSONHandler) WithGroup(name string) slog.Handler {
-	// Groups can be handled if needed, but for simplicity, we ignore them here
-	return h
+	app.Commands = append(app.Commands, demoCommand(app.Action), selftestCommand(app.Action))
+	return app
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FlagReconcileBids             = "bids"
+	FlagReconcileCommitments      = "commitments"
+	FlagReconcileFrom             = "from"
+	FlagReconcileTo               = "to"
+	FlagReconcileSince            = "since"
+	FlagReconcileMaxDiscrepancies = "max-discrepancies"
+)
+
+// reconcileCommand returns the reconcile subcommand, which compares the
+// commitments the bidder node returned with the bid records written for them.
+func reconcileCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "reconcile",
+		Usage: "Compare the commitments file with the bid records file and report discrepancies",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    FlagReconcileBids,
+				Usage:   "Bid records JSONL file",
+				EnvVars: []string{"BID_RECORDS_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    FlagReconcileCommitments,
+				Usage:   "Commitments JSONL file",
+				EnvVars: []string{"COMMITMENTS_FILE"},
+			},
+			&cli.TimestampFlag{
+				Name:   FlagReconcileFrom,
+				Usage:  "Only reconcile commitments received at or after this RFC 3339 time",
+				Layout: time.RFC3339,
+			},
+			&cli.TimestampFlag{
+				Name:   FlagReconcileTo,
+				Usage:  "Only reconcile commitments received at or before this RFC 3339 time",
+				Layout: time.RFC3339,
+			},
+			&cli.DurationFlag{
+				Name:  FlagReconcileSince,
+				Usage: "Only reconcile commitments received in this long before now, instead of --from and --to",
+			},
+			&cli.IntFlag{
+				Name:  FlagReconcileMaxDiscrepancies,
+				Usage: "Discrepancies tolerated before exiting 1",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			bidsPath, commitmentsPath := c.String(FlagReconcileBids), c.String(FlagReconcileCommitments)
+			if bidsPath == "" || commitmentsPath == "" {
+				return cli.Exit("usage: reconcile --bids <file> --commitments <file> [--from <time>] [--to <time>] [--since <duration>]", 2)
+			}
+			var from, to time.Time
+			if t := c.Timestamp(FlagReconcileFrom); t != nil {
+				from = *t
+			}
+			if t := c.Timestamp(FlagReconcileTo); t != nil {
+				to = *t
+			}
+			if since := c.Duration(FlagReconcileSince); since > 0 {
+				from, to = time.Now().Add(-since), time.Time{}
+			}
+
+			bidsFile, err := os.Open(bidsPath)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			defer bidsFile.Close()
+			commitmentsFile, err := os.Open(commitmentsPath)
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			defer commitmentsFile.Close()
+
+			report, err := bids.Reconcile(bidsFile, commitmentsFile, from, to)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to reconcile: %v", err), 1)
+			}
+
+			for _, d := range report.Discrepancies {
+				fmt.Printf("line %d: %s tx=%v block=%d node_amount_wei=%s", d.Line, d.Kind, d.Commitment.TxHashes, d.Commitment.BlockNumber, d.Commitment.BidAmount)
+				if d.Record != nil {
+					fmt.Printf(" local_block=%d local_amount_wei=%s", d.Record.BlockNumber, bb.EthToWei(d.Record.AmountEth))
+				}
+				fmt.Println()
+			}
+			fmt.Printf("%d bid records, %d commitments, %d matched, %d discrepancies\n",
+				report.Bids, report.Commitments, report.Matched, len(report.Discrepancies))
+			if len(report.Discrepancies) > c.Int(FlagReconcileMaxDiscrepancies) {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
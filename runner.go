@@ -0,0 +1,1091 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/beacon"
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	"github.com/primev/preconf_blob_bidder/internal/config"
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	"github.com/primev/preconf_blob_bidder/internal/heartbeat"
+	"github.com/primev/preconf_blob_bidder/internal/keyaudit"
+	"github.com/primev/preconf_blob_bidder/internal/logging"
+	"github.com/primev/preconf_blob_bidder/internal/metrics"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/node"
+	"github.com/primev/preconf_blob_bidder/internal/selftest"
+	"github.com/primev/preconf_blob_bidder/internal/shutdown"
+	"github.com/primev/preconf_blob_bidder/internal/store"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/urfave/cli/v2"
+)
+
+// runner runs the bidder. It holds the clients and trackers shared by the
+// header loop, the bid sender and the background tasks it starts.
+type runner struct {
+	c       *cli.Context
+	cfg     *config.Config
+	closers *shutdown.Registry
+
+	bidderLog *slog.Logger
+	ethLog    *slog.Logger
+	wsLog     *slog.Logger
+
+	// Cancelled when the run finishes, stopping the background tasks
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration // DEFAULT_TIMEOUT of the RPC and beacon clients
+
+	// Set by the selftest command, which bids once on one block
+	selfTestReport   *selftest.Report
+	selfTestDeadline time.Time
+
+	// Decay of profiles without a decay window of their own, which follows the
+	// block time while it is re-estimated
+	defaultDecay       atomic.Int64
+	currentBlockTime   atomic.Int64
+	blockTimeEstimator *ee.BlockTimeEstimator
+
+	// The chain head, as last seen by the header stream
+	latestBlock  atomic.Uint64
+	latestHeader atomic.Pointer[types.Header]
+	connected    atomic.Bool
+	probing      atomic.Bool // Set while an outage probe is in flight
+
+	// Bidder nodes; the first reachable one also serves deposits and outage probes
+	endpoints      []bb.BidderEndpoint
+	bidderClient   *bb.Bidder
+	multiBidder    *bb.MultiBidder
+	bidder         bb.BidderInterface
+	depositManager *bb.DepositManager
+
+	// Ethereum nodes
+	rpcPool      *bb.ClientPool
+	wsFailover   *bb.WSEndpoints
+	wsConn       *node.Current[*ethclient.Client]
+	headerStream *node.HeaderStream[*ethclient.Client]
+
+	// Wallets and the nonces of their transactions
+	wallets         []bb.AuthAcct
+	walletAddresses []common.Address
+	walletSelector  *strategy.WalletSelector
+	nonceAllocator  *ee.NonceAllocator
+	nonceMonitor    *ee.NonceMonitor
+	dedup           *ee.TxDeduplicator
+
+	// Building, pricing and sending bids
+	calldataDecoder   *ee.CalldataDecoder
+	eligible          beacon.EligibleBlock
+	txCounts          *ee.TxCountTracker
+	feePercentiles    *ee.FeePercentileTracker
+	transferTip       ee.TipPolicy
+	blobTip           ee.TipPolicy
+	escalator         *strategy.BidAmountEscalator
+	profileAmountMu   sync.Mutex // Guards profile amount draws when bids are priced concurrently
+	alignment         *bb.BidWindowAlignment
+	windowJitter      *bb.DecayJitter
+	cycleRetry        *strategy.CycleRetry
+	scheduledTransfer ee.Transfer // A retried cycle keeps the scheduled transfer of its block
+	scheduledBlock    uint64
+	bundles           ee.BundleSender
+	latencySLO        *bb.PayloadLatencySLO
+	staleBids         *bb.StaleBidGuard
+	sender            *bb.TransactionPriorityQueueSender
+	drain             *bb.GracefulBidDrain
+	drainTimeout      time.Duration
+	blockDrain        *shutdown.Drain
+
+	// Outcomes of the bids sent
+	session                *bids.Session
+	lastOutcome            bids.Outcome // Outcomes at the previous periodic bid outcome log
+	accounting             *strategy.Accounting
+	inclusion              *bids.InclusionTracker
+	inclusionFees          *bids.FeeTracker
+	valueReport            *ee.PreconfValueReport
+	rampStats              *strategy.RampStats
+	bidRecords             *bids.Recorder
+	bidLog                 *store.BidRecorder
+	inclusionWebhook       *bids.Webhook
+	silentRejectionWebhook *bids.Webhook
+}
+
+// newRunner sets up logging for a run of the bidder and prints the welcome
+// message.
+//
+// Parameters:
+// - c: The CLI context of the run.
+// - cfg: The configuration, loaded but not yet validated.
+// - closers: Collects the writers opened by the run, to be flushed before exit.
+//
+// Returns:
+// - The runner, or an error if the logger cannot be set up.
+func newRunner(c *cli.Context, cfg *config.Config, closers *shutdown.Registry) (*runner, error) {
+	r := &runner{c: c, cfg: cfg, closers: closers}
+	// JSON to stderr at INFO level, pretty-printed unless PRETTY_LOG=false, plus a rotated JSON log file if configured
+	logger, logCloser, err := logging.InitializeLogger(logging.Config{
+		AppName:         cfg.AppName,
+		Version:         cfg.Version,
+		Level:           slog.LevelInfo,
+		FilePath:        cfg.LogFile,
+		TimestampFormat: cfg.LogTimestampFormat,
+		Compact:         !cfg.PrettyLog,
+		DedupWindow:     cfg.LogDedupWindow,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	closers.Register("logger", shutdown.OrderLogger, logCloser)
+
+	// Every log line of a demo run is marked so it cannot pass for a real one
+	demoMode := c.Bool(FlagDemo)
+	if demoMode {
+		logger = logger.With(slog.Bool("demo", true))
+	}
+	slog.SetDefault(logger)
+
+	// Subsystems log with a component attribute, so their entries can be filtered
+	r.bidderLog = logging.WithComponent(logger, logging.ComponentBidder)
+	r.ethLog = logging.WithComponent(logger, logging.ComponentEth)
+	r.wsLog = logging.WithComponent(logger, logging.ComponentWS)
+
+	printWelcome(demoMode)
+	return r, nil
+}
+
+// validate reports every invalid setting at once, before anything connects,
+// and derives the settings the run uses from the others.
+func (r *runner) validate() error {
+	r.cfg.DetectBlockTime = detectBlockTime
+	if err := r.cfg.Validate(); err != nil {
+		for _, problem := range config.Split(err) {
+			slog.Error("Invalid configuration", "problem", problem.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// run connects to the bidder and Ethereum nodes and bids on each new header
+// until the context is cancelled, the runtime is reached or a drain completes.
+//
+// Returns:
+// - The result of the session's exit criteria, or an error if the run could not start.
+func (r *runner) run() error {
+	if err := r.openKeyAudit(); err != nil {
+		return err
+	}
+	r.prepare()
+	r.prompt()
+	r.logConfig()
+	if err := r.loadSetupFiles(); err != nil {
+		return err
+	}
+	if err := r.connectBidders(); err != nil {
+		return err
+	}
+	if err := r.connectNodes(); err != nil {
+		return err
+	}
+
+	r.ctx, r.cancel = context.WithCancel(r.c.Context)
+	defer r.cancel()
+
+	if err := r.setupDeposits(); err != nil {
+		return err
+	}
+
+	// The drain signal stops bidding but keeps running until bids on upcoming
+	// blocks are confirmed; other termination signals exit right away
+	r.blockDrain = shutdown.NewDrain(r.cfg.DrainConfirmations)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	if r.cfg.DrainSignal != nil {
+		signal.Notify(signals, r.cfg.DrainSignal)
+	}
+	defer signal.Stop(signals)
+	drainCheck := time.NewTicker(time.Second)
+	defer drainCheck.Stop()
+
+	if err := r.startServers(); err != nil {
+		return err
+	}
+	if err := r.setupBidding(); err != nil {
+		return err
+	}
+	if err := r.subscribeHeaders(); err != nil {
+		return err
+	}
+	if err := r.authenticate(); err != nil {
+		return err
+	}
+	r.startNonceMonitor()
+	r.setupBlockTrackers()
+	if err := r.startRebalancer(); err != nil {
+		return err
+	}
+	r.startHeartbeat()
+	if err := r.startWatcher(); err != nil {
+		return err
+	}
+
+	// The runtime ends the loop even while no headers arrive
+	var runtimeReached <-chan time.Time
+	if r.cfg.MaxRuntime > 0 {
+		runtimeTimer := time.NewTimer(r.cfg.MaxRuntime)
+		defer runtimeTimer.Stop()
+		runtimeReached = runtimeTimer.C
+	}
+	return r.loop(signals, drainCheck.C, runtimeReached)
+}
+
+// openKeyAudit opens the key audit log unless KEY_AUDIT is off.
+func (r *runner) openKeyAudit() error {
+	// A required audit must never silently fall back to no audit, so an
+	// unwritable log stops the bot before any key signs
+	if r.cfg.KeyAuditMode != keyaudit.ModeOff {
+		auditLog, err := keyaudit.Open(r.cfg.KeyAuditFile)
+		if err != nil {
+			slog.Error("Failed to open key audit log", "file", r.cfg.KeyAuditFile, "error", err)
+			return err
+		}
+		keyaudit.Install(auditLog, r.cfg.KeyAuditMode == keyaudit.ModeRequired)
+		r.closers.Register("key audit", shutdown.OrderWriters, auditLog)
+	}
+	return nil
+}
+
+// auditPause records a pause in the key audit, so it can flag signatures made
+// during it.
+func (r *runner) auditPause(address common.Address, paused bool, reason string) {
+	if err := keyaudit.RecordPause(address, paused, reason); err != nil {
+		slog.Warn("Failed to record pause in key audit", "reason", reason, "error", err)
+	}
+}
+
+// prepare sets up the self-test report, the timeouts and the block timing of the run.
+func (r *runner) prepare() {
+	r.timeout = time.Duration(r.cfg.DefaultTimeoutSeconds) * time.Second
+	// A self-test bids once on one block; Validate has already narrowed the profiles to it
+	if r.cfg.SelfTest {
+		r.selfTestReport = selftest.NewReport()
+		r.selfTestDeadline = time.Now().Add(time.Duration(r.cfg.DrainTimeoutSec) * time.Second)
+		slog.Info("Running selftest", "bidAmount", r.cfg.Profiles[0].BidAmount, "numBlob", r.cfg.Profiles[0].NumBlob)
+	}
+	// Profiles without a decay window of their own use DECAY_DURATION_MS, or
+	// the decay derived from the block time while it is re-estimated
+	r.defaultDecay.Store(int64(r.cfg.DecayTiming.Duration))
+	r.currentBlockTime.Store(int64(r.cfg.BlockTime))
+	if r.cfg.BlobRamp != nil {
+		r.rampStats = strategy.NewRampStats(r.cfg.BlobRamp)
+	}
+}
+
+// prompt asks for the WebSocket endpoint and the private key if they are not
+// configured.
+func (r *runner) prompt() {
+	// Interactive prompts if wsEndpoint or privateKeyHex are not provided
+	if r.cfg.WSEndpoint == "" {
+		fmt.Println("First, we need the WebSocket endpoint for your Ethereum node.")
+		fmt.Println("This is where we'll connect to receive real-time blockchain updates.")
+		fmt.Println("For example: wss://your-node-provider.com/ws")
+		fmt.Println()
+		var err error
+		for {
+			r.cfg.WSEndpoint = promptForInput("Please enter your WebSocket endpoint")
+			r.cfg.WSEndpoint, err = config.ValidateWebSocketURL(r.cfg.WSEndpoint)
+			if err == nil {
+				break
+			}
+			fmt.Printf("Error: %s\nPlease try again.\n\n", err)
+		}
+		fmt.Println()
+	}
+	if len(r.cfg.WSEndpoints) == 0 {
+		r.cfg.WSEndpoints = []string{r.cfg.WSEndpoint}
+	}
+
+	if r.cfg.PrivateKey == "" && r.cfg.KeystorePath == "" {
+		fmt.Println("A private key is needed to sign transactions.")
+		fmt.Println("A private key is a 64-character hexadecimal string.")
+		fmt.Println()
+		var err error
+		for {
+			r.cfg.PrivateKey = promptForInput("Please enter your private key")
+			err = config.ValidatePrivateKey(r.cfg.PrivateKey)
+			if err == nil {
+				break
+			}
+			fmt.Printf("Error: %s\nPlease try again.\n\n", err)
+		}
+		fmt.Println()
+	}
+}
+
+// logConfig prints and logs the configuration of the run.
+func (r *runner) logConfig() {
+	if r.cfg.MaxRuntime > 0 {
+		slog.Info("Bidder will run until", "maxRuntime", r.cfg.MaxRuntime.String(), "endTime", time.Now().Add(r.cfg.MaxRuntime))
+	} else {
+		slog.Info("Bidder will run indefinitely")
+	}
+
+	fmt.Println("Great! Here's what we have:")
+	fmt.Printf(" - WebSocket Endpoint: %s\n", r.cfg.WSEndpoint)
+	if len(r.cfg.WSEndpoints) > 1 {
+		fmt.Printf(" - WebSocket Failover Endpoints: %d more\n", len(r.cfg.WSEndpoints)-1)
+	}
+	fmt.Printf(" - Private Key: Provided (hidden)\n")
+	fmt.Printf(" - Server Address: %s\n", r.cfg.ServerAddress)
+	fmt.Printf(" - Use Payload: %v\n", r.cfg.UsePayload)
+	fmt.Printf(" - Bid Amount: %f ETH\n", r.cfg.BidAmount)
+	fmt.Printf(" - Priority Fee: %d wei\n", r.cfg.PriorityFee)
+	fmt.Printf(" - Standard Deviation: %f%%\n", r.cfg.StdDevPercentage)
+	fmt.Printf(" - Number of Blobs: %d\n", r.cfg.NumBlob)
+	fmt.Printf(" - Default Timeout: %d seconds\n", r.cfg.DefaultTimeoutSeconds)
+	if r.cfg.MaxRuntime > 0 {
+		fmt.Printf(" - Max Runtime: %s\n", r.cfg.MaxRuntime)
+	} else {
+		fmt.Printf(" - Max Runtime: infinite\n")
+	}
+	fmt.Println()
+	fmt.Println("We will now connect to the blockchain and start sending transactions.")
+	fmt.Println("Please wait...")
+	fmt.Println()
+
+	weiAmountDesc := "profiles"
+	if r.cfg.WeiAmount != nil {
+		weiAmountDesc = r.cfg.WeiAmount.String()
+	}
+	slog.Info("Configuration values",
+		"appName", r.cfg.AppName,
+		"version", r.cfg.Version,
+		"serverAddress", r.cfg.ServerAddress,
+		"rpcEndpoint", bb.MaskEndpoint(r.cfg.RPCEndpoint),
+		"wsEndpoint", bb.MaskEndpoint(r.cfg.WSEndpoint),
+		"wsEndpoints", len(r.cfg.WSEndpoints),
+		"offset", r.cfg.Offset,
+		"usePayload", r.cfg.UsePayload,
+		"bidAmount", r.cfg.BidAmount,
+		"priorityFee", r.cfg.PriorityFee,
+		"stdDevPercentage", r.cfg.StdDevPercentage,
+		"numBlob", r.cfg.NumBlob,
+		"numBlobs", r.cfg.NumBlobs,
+		"bidRecordStrategy", r.cfg.BidRecordStrategy,
+		"txType", r.cfg.TxType,
+		"tokenAddress", r.cfg.TokenAddress,
+		"tokenAmount", r.cfg.TokenAmount,
+		"metricsPort", r.cfg.MetricsPort,
+		"metricsAddr", r.cfg.MetricsAddr,
+		"blobSidecar", r.cfg.BlobSidecar,
+		"blobSeed", r.cfg.BlobSeed,
+		"httpUserAgent", r.cfg.HTTPUserAgent,
+		"mode", r.cfg.Mode,
+		"watchAddresses", len(r.cfg.WatchAddresses),
+		"blobDataPath", r.cfg.BlobDataPath,
+		"maxConcurrentBundles", r.cfg.MaxConcurrentBundles,
+		"bundleOverflowPolicy", r.cfg.BundleOverflowPolicy,
+		"broadcastDelay", r.cfg.BroadcastDelay.String(),
+		"staleBidPolicy", r.cfg.StaleBidPolicy,
+		"sendIntervalBlocks", r.cfg.SendIntervalBlocks,
+		"sendIntervalSeconds", r.cfg.SendIntervalSeconds,
+		"minDeposit", r.cfg.MinDeposit,
+		"topUpAmount", r.cfg.TopUpAmount,
+		"walletRebalancing", r.cfg.FundingPrivateKey != "",
+		"depositBlocksPerWindow", r.cfg.DepositBlocksPerWindow,
+		"inclusionWebhookURL", bb.MaskEndpoint(r.cfg.InclusionWebhookURL),
+		"inclusionWebhookTimeout", r.cfg.InclusionWebhookTimeout.String(),
+		"inclusionWebhookAttempts", r.cfg.InclusionWebhookAttempts,
+		"silentRejectionWebhookURL", bb.MaskEndpoint(r.cfg.SilentRejectionWebhookURL),
+		"offsetDecayPolicy", r.cfg.OffsetDecayPolicy,
+		"txPerBlock", r.cfg.TxPerBlock,
+		"nonceResyncFailures", r.cfg.NonceResyncFailures,
+		"bidOutcomeLogBlocks", r.cfg.BidOutcomeLogBlocks,
+		"backrunTo", r.cfg.BackrunTo,
+		"dryRun", r.cfg.DryRun,
+		"privateKeyProvided", r.cfg.PrivateKey != "",
+		"keystorePath", r.cfg.KeystorePath,
+		"defaultTimeoutSeconds", r.cfg.DefaultTimeoutSeconds,
+		"drainTimeoutSeconds", r.cfg.ShutdownDrainTimeoutSec,
+		"proposerAllowlist", r.cfg.ProposerAllowlist,
+		"beaconEndpoint", bb.MaskEndpoint(r.cfg.BeaconEndpoint),
+		"txABIFile", r.cfg.TxABIFile,
+		"commitmentsFile", r.cfg.CommitmentsFile,
+		"txCountWindow", r.cfg.TxCountWindow,
+		"bidProfilesFile", r.cfg.BidProfilesFile,
+		"bidProfileSelector", r.cfg.BidProfileSelector,
+		"bidProfiles", len(r.cfg.Profiles),
+		"bidBlockRange", r.cfg.BidBlockRange,
+		"feePercentileWindow", r.cfg.FeePercentileWindow,
+		"bidCycleRetries", r.cfg.BidCycleRetries,
+		"bidderTLS", r.cfg.BidderTLS,
+		"bidderTLSCAFile", r.cfg.BidderTLSCAFile,
+		"drainSignal", r.cfg.DrainSignalName,
+		"drainTimeoutSec", r.cfg.DrainTimeoutSec,
+		"drainConfirmations", r.cfg.DrainConfirmations,
+		"healthAddr", r.cfg.HealthAddr,
+		"heartbeatInterval", r.cfg.HeartbeatInterval.String(),
+		"bidRecordsFile", r.cfg.BidRecordsFile,
+		"bidLogFile", r.cfg.BidLogFile,
+		"bidLogFormat", r.cfg.BidLogFormat,
+		"escalationMissedBids", r.cfg.EscalationMissedBids,
+		"escalationStepPct", r.cfg.EscalationStepPct,
+		"escalationMaxMultiplier", r.cfg.EscalationMaxMultiplier,
+		"tipAsBaseFeePct", r.cfg.TipAsBaseFeePct,
+		"tipFloorWei", r.cfg.TipFloorWei,
+		"gasFeeCapGwei", r.cfg.GasFeeCapGwei,
+		"gasTipGwei", r.cfg.GasTipGwei,
+		"blobFeeCapGwei", r.cfg.BlobFeeCapGwei,
+		"baseFeeMultiplier", r.cfg.BaseFeeMultiplier,
+		"blobFeeAsGasFeeMultiple", r.cfg.BlobFeeAsGasFeeMultiple,
+		"maxFeeGwei", r.cfg.MaxFeeGwei,
+		"txDedup", r.cfg.TxDedup,
+		"bidLatencySLO", r.cfg.BidLatencySLO.String(),
+		"kzgTrustedSetup", r.cfg.KZGTrustedSetup,
+		"txMaxLifetime", r.cfg.TxMaxLifetime.String(),
+		"bidAllowReverts", r.cfg.BidAllowReverts,
+		"walletModes", r.cfg.WalletModes,
+		"blobRamp", r.cfg.BlobRampEnabled,
+		"blobRampMax", r.cfg.BlobRampMax,
+		"blobRampStepBlocks", r.cfg.BlobRampStepBlocks,
+		"blobRampSchedule", r.cfg.BlobRampSchedule,
+		"exitOnCriteria", r.cfg.ExitCriteria.String(),
+		"nonceCheckInterval", r.cfg.NonceCheckInterval.String(),
+		"noncePauseChecks", r.cfg.NoncePauseChecks,
+		"blobChainLength", r.cfg.BlobChainLength,
+		"decayJitter", r.cfg.DecayJitter.String(),
+		"bidAmountRange", r.cfg.BidRange.String(),
+		"bidMode", r.cfg.BidMode,
+		"bidAmountStrategy", weiAmountDesc,
+		"transferScheduleCSV", r.cfg.TransferScheduleCSV,
+		"bidderOutagePolicy", r.cfg.BidderOutagePolicy,
+		"bidderOutageFailures", r.cfg.BidderOutageFailures,
+		"bidderOutageQueueSize", r.cfg.BidderOutageQueueSize,
+		"decayStartOffset", r.cfg.DecayTiming.StartOffset.String(),
+		"decayDuration", r.cfg.DecayTiming.Duration.String(),
+		"adaptiveBlobCount", r.cfg.AdaptiveBlobCount,
+		"adaptiveBlobMin", r.cfg.AdaptiveBlobMin,
+		"adaptiveBlobMax", r.cfg.AdaptiveBlobMax,
+		"adaptiveBlobLowFeeWei", r.cfg.AdaptiveBlobLowFeeWei,
+		"adaptiveBlobHighFeeWei", r.cfg.AdaptiveBlobHighFeeWei,
+		"keyAudit", r.cfg.KeyAuditMode,
+		"keyAuditFile", r.cfg.KeyAuditFile,
+		"rpcPoolSize", r.cfg.RPCPoolSize,
+		"rpcPoolHealthInterval", r.cfg.RPCPoolHealthInterval.String(),
+		"headerStaleTimeout", r.cfg.HeaderStaleTimeout.String(),
+		"bidPricingWorkers", r.cfg.BidPricingWorkers,
+		"transferAmountWei", r.cfg.TransferAmountWei,
+		"bidEndpointTimeout", r.cfg.BidEndpointTimeout.String(),
+		"blockTime", r.cfg.BlockTime.String(),
+		"bidRetryAttempts", r.cfg.BidRetry.Attempts,
+		"bidRetryBackoff", r.cfg.BidRetry.Backoff.String(),
+		"bidRetryableCodes", fmt.Sprint(r.cfg.BidRetry.RetryableCodes),
+		"logFile", r.cfg.LogFile,
+		"logTimestampFormat", r.cfg.LogTimestampFormat,
+		"logDedupWindow", r.cfg.LogDedupWindow.String(),
+		"prettyLog", r.cfg.PrettyLog,
+	)
+	if r.cfg.DryRun {
+		slog.Warn("Dry run: bids and transactions are logged, not sent")
+	}
+}
+
+// loadSetupFiles loads the KZG trusted setup and the ABI of sent transactions,
+// if configured.
+func (r *runner) loadSetupFiles() error {
+	// Blob commitments use the embedded mainnet setup unless another one is supplied
+	if r.cfg.KZGTrustedSetup != "" {
+		if err := ee.LoadTrustedSetup(r.cfg.KZGTrustedSetup); err != nil {
+			slog.Error("Failed to load KZG trusted setup", "error", err)
+			return err
+		}
+		slog.Info("Loaded KZG trusted setup", "path", r.cfg.KZGTrustedSetup)
+	}
+
+	// Decode our own calldata for readable logs when an ABI is supplied
+	if r.cfg.TxABIFile != "" {
+		var err error
+		r.calldataDecoder, err = ee.NewCalldataDecoder(r.cfg.TxABIFile)
+		if err != nil {
+			slog.Error("Failed to load TX_ABI_FILE", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// connectBidders connects to every bidder node in SERVER_ADDRESS and opens the
+// files that bids are recorded in.
+func (r *runner) connectBidders() error {
+	var err error
+	bidderCfg := bb.BidderConfig{
+		ServerAddress:   r.cfg.ServerAddress,
+		CommitmentsFile: r.cfg.CommitmentsFile,
+		TLS:             r.cfg.BidderTLS,
+		TLSCAFile:       r.cfg.BidderTLSCAFile,
+		AllowReverts:    r.cfg.BidAllowReverts,
+		Logger:          r.bidderLog,
+	}
+
+	// Connect to every bidder node in SERVER_ADDRESS
+	r.endpoints = make([]bb.BidderEndpoint, 0, len(r.cfg.ServerAddresses))
+	closeEndpoints := func() {
+		for _, endpoint := range r.endpoints {
+			endpoint.Bidder.Close()
+		}
+	}
+	for _, address := range r.cfg.ServerAddresses {
+		endpointCfg := bidderCfg
+		endpointCfg.ServerAddress = address
+		client, err := bb.NewBidderClient(endpointCfg)
+		if err != nil {
+			closeEndpoints()
+			slog.Error("Failed to connect to mev-commit bidder API", "error", err, "serverAddress", address)
+			return fmt.Errorf("failed to connect to mev-commit bidder API: %w", err)
+		}
+		r.endpoints = append(r.endpoints, bb.BidderEndpoint{Address: address, Bidder: client})
+	}
+	// The first reachable node also serves deposits and outage probes
+	r.bidderClient = r.endpoints[0].Bidder
+
+	// Creating the clients doesn't dial, so check that the nodes answer before
+	// bidding. A dry run never sends to them. With several nodes, the bot starts
+	// as long as one of them answers and warns about the others
+	if !r.cfg.DryRun {
+		logUnreachable := slog.Error
+		if len(r.endpoints) > 1 {
+			logUnreachable = slog.Warn
+		}
+		checkErrs := make([]error, len(r.endpoints))
+		var wg sync.WaitGroup
+		for i, endpoint := range r.endpoints {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				checkCtx, checkCancel := context.WithTimeout(r.c.Context, bidderStartupTimeout)
+				defer checkCancel()
+				_, checkErrs[i] = endpoint.Bidder.CheckReachability(checkCtx)
+			}()
+		}
+		wg.Wait()
+
+		var unreachable []error
+		reachable := -1
+		for i, err := range checkErrs {
+			if err == nil {
+				if reachable < 0 {
+					reachable = i
+				}
+				continue
+			}
+			code, suggestion := bb.ReachabilitySuggestion(err)
+			logUnreachable("Bidder node unreachable",
+				"serverAddress", r.endpoints[i].Address,
+				"code", code.String(),
+				"suggestion", suggestion,
+			)
+			unreachable = append(unreachable, fmt.Errorf("bidder node at %s is unreachable: %s: %w", r.endpoints[i].Address, code, err))
+		}
+		if reachable < 0 {
+			closeEndpoints()
+			return errors.Join(unreachable...)
+		}
+		r.bidderClient = r.endpoints[reachable].Bidder
+	}
+	r.multiBidder, err = bb.NewMultiBidder(r.endpoints, r.cfg.BidEndpointTimeout, r.bidderLog)
+	if err != nil {
+		closeEndpoints()
+		return err
+	}
+
+	// Receipts of included transactions give the fees paid on top of the bids
+	r.inclusionFees = bids.NewFeeTracker()
+
+	// Count commitments towards the session outcome; blob ramp steps are also
+	// compared by the share of their bids that get a commitment
+	r.session = bids.NewSession()
+	r.multiBidder.ObserveCommitments(func(commitment *pb.Commitment) {
+		r.session.RecordCommitment()
+		if r.selfTestReport != nil {
+			r.selfTestReport.Pass(selftest.StageCommitment, fmt.Sprintf("block %d", commitment.BlockNumber))
+		}
+		if r.rampStats == nil {
+			return
+		}
+		for _, txHash := range commitment.TxHashes {
+			r.rampStats.RecordCommitment(common.HexToHash(txHash), uint64(commitment.BlockNumber))
+		}
+	})
+
+	// Closing the bidders also closes the commitments file
+	r.closers.Register("bidder", shutdown.OrderWriters, r.multiBidder)
+
+	r.bidRecords, err = bids.NewRecorder(r.cfg.BidRecordsFile)
+	if err != nil {
+		slog.Error("Failed to open bid records file", "error", err)
+		return err
+	}
+	r.closers.Register("bid records", shutdown.OrderWriters, r.bidRecords)
+
+	// Every bid sent is also appended to the bid history, written in the
+	// background so that sending never waits on the file
+	if r.cfg.BidLogFile != "" {
+		r.bidLog, err = store.NewBidRecorder(store.BidRecorderConfig{
+			Path:       r.cfg.BidLogFile,
+			Format:     r.cfg.BidLogFormat,
+			MaxSizeMB:  int(r.cfg.BidLogMaxSizeMB),
+			MaxBackups: int(r.cfg.BidLogMaxBackups),
+		})
+		if err != nil {
+			slog.Error("Failed to open bid log", "error", err)
+			return err
+		}
+		r.closers.Register("bid log", shutdown.OrderWriters, r.bidLog)
+	}
+
+	slog.Info("Connected to mev-commit client")
+	return nil
+}
+
+// connectNodes connects to the RPC and WebSocket endpoints of the Ethereum node.
+func (r *runner) connectNodes() error {
+	// Block and receipt reads, and bundles, go through warm RPC connections,
+	// which are replaced in the background when they die, or right away when
+	// a bundle fails to reach them
+	if !r.cfg.UsePayload {
+		r.rpcPool = bb.NewClientPool([]string{r.cfg.RPCEndpoint}, int(r.cfg.RPCPoolSize), r.cfg.RPCPoolHealthInterval, r.timeout, bb.UserAgentDialer(r.cfg.HTTPUserAgent), r.ethLog)
+		r.closers.Register("rpc pool", shutdown.OrderClients, r.rpcPool)
+		healthy, size := r.rpcPool.Healthy()
+		slog.Info("Geth client pool connected (rpc)",
+			"endpoint", bb.MaskEndpoint(r.cfg.RPCEndpoint),
+			"healthy", healthy,
+			"size", size,
+		)
+	}
+
+	// Connections and reconnections go round robin through the WebSocket endpoints
+	var err error
+	r.wsFailover, err = bb.NewWSEndpoints(r.cfg.WSEndpoints, nil, r.wsLog)
+	if err != nil {
+		return err
+	}
+	wsClient, err := bb.ConnectWSClient(r.wsFailover)
+	if err != nil {
+		slog.Error("Failed to connect to WebSocket client", "error", err)
+		return fmt.Errorf("failed to connect to WebSocket client: %w", err)
+	}
+	slog.Info("Geth client connected (ws)",
+		"endpoint", bb.MaskEndpoint(r.wsFailover.Active()),
+	)
+	// Header stream reconnections close the connection they replace, so anything
+	// outliving one gets the WebSocket client from wsConn on each call
+	r.wsConn = node.NewCurrent(wsClient)
+	// Real bids on mainnet must be acknowledged with the selftest's --allow-mainnet
+	if r.cfg.SelfTest && !r.c.Bool(FlagAllowMainnet) {
+		chainID, err := wsClient.ChainID(r.c.Context)
+		if err != nil {
+			slog.Error("Failed to get chain ID", "error", err)
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		if chainID.Cmp(params.MainnetChainConfig.ChainID) == 0 {
+			slog.Error("Refusing to run selftest on mainnet without --allow-mainnet")
+			return fmt.Errorf("selftest sends a real bid on mainnet, pass --allow-mainnet to confirm")
+		}
+	}
+	return nil
+}
+
+// readClient returns a healthy pooled connection, or the WebSocket client without one.
+func (r *runner) readClient() *ethclient.Client {
+	if r.rpcPool != nil {
+		if client, err := r.rpcPool.Get(); err == nil {
+			return client
+		}
+	}
+	return r.wsConn.Get()
+}
+
+// setupDeposits keeps the deposit of each target block's window topped up,
+// except in a dry run, which spends nothing.
+func (r *runner) setupDeposits() error {
+	if r.cfg.MinDeposit > 0 && !r.cfg.DryRun {
+		var err error
+		r.depositManager, err = bb.NewDepositManager(r.bidderClient.Deposits(), bb.EthToWei(r.cfg.MinDeposit), bb.EthToWei(r.cfg.TopUpAmount), r.cfg.DepositBlocksPerWindow)
+		if err != nil {
+			slog.Error("Failed to create deposit manager", "error", err)
+			return fmt.Errorf("failed to create deposit manager: %w", err)
+		}
+	}
+	return nil
+}
+
+// ensureDeposit tops up the deposit of the target block's window if it is low.
+func (r *runner) ensureDeposit(targetBlock uint64) {
+	ensureCtx, ensureCancel := context.WithTimeout(r.ctx, time.Minute)
+	defer ensureCancel()
+	topUp, err := r.depositManager.Ensure(ensureCtx, targetBlock)
+	if err != nil {
+		metrics.DepositTopUps.WithLabelValues("failed").Inc()
+		slog.Warn("Failed to top up bidder deposit", "blockNumber", targetBlock, "error", err)
+		return
+	}
+	if balance := r.depositManager.Balance(targetBlock); balance != nil {
+		metrics.DepositBalanceEth.Set(bb.WeiToEth(balance))
+	}
+	if topUp != nil {
+		metrics.DepositTopUps.WithLabelValues("ok").Inc()
+		slog.Info("Topped up bidder deposit",
+			"blockNumber", targetBlock,
+			"window", topUp.Window,
+			"balanceEth", bb.WeiToEth(topUp.Balance),
+			"amountEth", bb.WeiToEth(topUp.Amount),
+		)
+	}
+}
+
+// startServers serves the readiness check and the metrics, if configured.
+func (r *runner) startServers() error {
+	if r.cfg.HealthAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/readyz", r.blockDrain.ReadinessHandler())
+		healthServer := &http.Server{Addr: r.cfg.HealthAddr, Handler: mux}
+		go func() {
+			if err := healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Health server failed", "addr", r.cfg.HealthAddr, "error", err)
+			}
+		}()
+		r.closers.Register("health server", shutdown.OrderClients, healthServer)
+		slog.Info("Serving readiness", "addr", r.cfg.HealthAddr, "path", "/readyz")
+	}
+	// Validate has set METRICS_ADDR from METRICS_PORT unless it was given
+	if r.cfg.MetricsAddr != "" {
+		// Bind before the header loop so a taken port fails the start
+		metricsListener, err := net.Listen("tcp", r.cfg.MetricsAddr)
+		if err != nil {
+			slog.Error("Failed to listen for metrics", "addr", r.cfg.MetricsAddr, "error", err)
+			return fmt.Errorf("failed to listen for metrics on %s: %w", r.cfg.MetricsAddr, err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer := &http.Server{Addr: r.cfg.MetricsAddr, Handler: mux}
+		go func() {
+			if err := metricsServer.Serve(metricsListener); err != nil && err != http.ErrServerClosed {
+				slog.Error("Metrics server failed", "addr", r.cfg.MetricsAddr, "error", err)
+			}
+		}()
+		r.closers.Register("metrics server", shutdown.OrderClients, metricsServer)
+		slog.Info("Serving metrics", "addr", r.cfg.MetricsAddr, "path", "/metrics")
+	}
+	return nil
+}
+
+// subscribeHeaders subscribes to new headers. The subscription reconnects when
+// it fails, or when no header arrives within HEADER_STALE_TIMEOUT.
+func (r *runner) subscribeHeaders() error {
+	var err error
+	r.headerStream, err = node.NewHeaderStream(r.ctx, r.wsConn, r.wsFailover.Dial, node.StreamConfig{
+		StaleTimeout: r.cfg.HeaderStaleTimeout,
+		Grace:        headerStreamGrace,
+		Logger:       r.wsLog,
+	})
+	if err != nil {
+		slog.Error("Failed to subscribe to new blocks", "error", err)
+		return fmt.Errorf("failed to subscribe to new blocks: %w", err)
+	}
+	return nil
+}
+
+// finish waits for the bids in flight, then logs the session's summaries and
+// checks its outcome against the exit criteria.
+func (r *runner) finish() error {
+	r.cancel()
+	r.sender.Close()
+	r.drain.Wait(r.drainTimeout)
+	// No bid is sent from here on, so headers and the WebSocket connection can go
+	r.headerStream.Close()
+	r.accounting.LogSummary()
+	r.inclusionFees.LogSummary()
+	if discarded := r.cfg.Outage.Discarded(bb.OutageDiscardOverflow) + r.cfg.Outage.Discarded(bb.OutageDiscardExpired); discarded > 0 {
+		slog.Info("Bidder outage summary",
+			"overflowDiscarded", r.cfg.Outage.Discarded(bb.OutageDiscardOverflow),
+			"expiredDiscarded", r.cfg.Outage.Discarded(bb.OutageDiscardExpired),
+			"stillQueued", r.cfg.Outage.Queued(),
+		)
+	}
+	if r.rampStats != nil {
+		r.rampStats.LogSummary()
+	}
+	bids, cheaper, savingsWei := r.valueReport.Summary()
+	slog.Info("Preconf value summary",
+		"bids", bids,
+		"cheaperThanP90Tip", cheaper,
+		"totalSavingsWei", savingsWei.String(),
+	)
+
+	if r.selfTestReport != nil {
+		r.selfTestReport.Print(r.c.App.Writer)
+		if !r.selfTestReport.Passed() {
+			return fmt.Errorf("selftest failed")
+		}
+	}
+
+	outcome := r.session.Outcome()
+	slog.Info("Session outcome",
+		"bids", outcome.Bids,
+		"commitments", outcome.Commitments,
+		"commitmentRate", outcome.CommitmentRate(),
+		"inclusionRate", outcome.InclusionRate(),
+		"won", outcome.Won,
+		"lost", outcome.Lost,
+		"multiProviderWon", outcome.MultiWon,
+		"winRate", outcome.WinRate(),
+	)
+	return r.cfg.ExitCriteria.Check(outcome)
+}
+
+// authenticate loads the wallet pool: PRIVATE_KEY is wallet 0 of the pool,
+// followed by PRIVATE_KEYS in order.
+func (r *runner) authenticate() error {
+	var authAcct bb.AuthAcct
+	var err error
+	switch {
+	case r.cfg.KeystoreKey != nil:
+		authAcct, err = bb.AuthenticateKey(r.cfg.KeystoreKey, r.wsConn.Get())
+	case r.cfg.PrivateKey != "":
+		authAcct, err = bb.AuthenticateAddress(r.cfg.PrivateKey, r.wsConn.Get())
+	default:
+		slog.Error("Private key is required")
+		return fmt.Errorf("private key is required")
+	}
+	if err != nil {
+		slog.Error("Failed to authenticate private key", "error", err)
+		return fmt.Errorf("failed to authenticate private key: %w", err)
+	}
+
+	r.wallets = []bb.AuthAcct{authAcct}
+	if r.cfg.PrivateKeys != "" {
+		for i, key := range strings.Split(r.cfg.PrivateKeys, ",") {
+			key = strings.TrimSpace(key)
+			wallet, err := bb.AuthenticateAddress(key, r.wsConn.Get())
+			if err != nil {
+				slog.Error("Failed to authenticate private key", "index", i, "error", err)
+				return fmt.Errorf("failed to authenticate key %d in PRIVATE_KEYS: %w", i, err)
+			}
+			r.wallets = append(r.wallets, wallet)
+		}
+	}
+	r.walletAddresses = make([]common.Address, len(r.wallets))
+	for i, wallet := range r.wallets {
+		r.walletAddresses[i] = wallet.Address
+	}
+	r.walletSelector, err = strategy.NewWalletSelector(r.cfg.WalletModes, r.walletAddresses)
+	if err != nil {
+		slog.Error("Invalid WALLET_MODES", "error", err)
+		return err
+	}
+	for _, profile := range r.cfg.Profiles {
+		if !r.walletSelector.Supports(profile.Mode()) {
+			slog.Error("No wallet is eligible for a bid profile", "profile", profile.Name, "mode", profile.Mode())
+			return fmt.Errorf("profile %s sends %s transactions, but no wallet is assigned to them", profile.Name, profile.Mode())
+		}
+	}
+	slog.Info("Wallet pool ready", "wallets", len(r.wallets), "walletModes", r.cfg.WalletModes)
+	return nil
+}
+
+// startNonceMonitor periodically compares each wallet's transaction counts with
+// our nonces in flight, and stops bidding from wallets whose nonces keep diverging.
+func (r *runner) startNonceMonitor() {
+	if r.cfg.NonceCheckInterval <= 0 {
+		return
+	}
+	r.nonceMonitor = ee.NewNonceMonitor(ee.ClientFunc(r.wsConn.Get), int(r.cfg.NoncePauseChecks))
+	go func() {
+		ticker := time.NewTicker(r.cfg.NonceCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			for i, wallet := range r.wallets {
+				wasPaused := r.nonceMonitor.Paused(wallet.Address)
+				check, err := r.nonceMonitor.Check(r.ctx, wallet.Address)
+				if err != nil {
+					slog.Warn("Failed to check wallet nonces", "wallet", i, "error", err)
+					continue
+				}
+				if paused := r.nonceMonitor.Paused(wallet.Address); paused != wasPaused {
+					r.auditPause(wallet.Address, paused, "nonce divergence")
+				}
+				if check.Action == ee.NonceActionOK {
+					continue
+				}
+				r.nonceAllocator.Resync(wallet.Address)
+				for _, hash := range check.Dropped {
+					if r.dedup != nil {
+						r.dedup.Forget(hash)
+					}
+				}
+				slog.Warn("Wallet nonces diverged from the chain",
+					"wallet", i,
+					"address", wallet.Address.Hex(),
+					"cause", check.Cause,
+					"action", check.Action,
+					"latestNonce", check.Latest,
+					"pendingNonce", check.Pending,
+					"trackedNonce", check.Tracked,
+					"inFlight", check.InFlight,
+					"dropped", len(check.Dropped),
+				)
+			}
+		}
+	}()
+}
+
+// setupBlockTrackers sets up the trackers fed by each block, and the tips.
+func (r *runner) setupBlockTrackers() {
+	// Only bid on blocks proposed by allowlisted validators when configured
+	if r.cfg.ProposerAllowlist != "" {
+		beaconClient := beacon.NewClient(r.cfg.BeaconEndpoint, r.timeout)
+		beaconClient.SetUserAgent(r.cfg.HTTPUserAgent)
+		r.eligible = beacon.NewProposerAllowlist(beaconClient, strings.Split(r.cfg.ProposerAllowlist, ","))
+		slog.Info("Proposer allowlist enabled",
+			"beaconEndpoint", bb.MaskEndpoint(r.cfg.BeaconEndpoint),
+		)
+	}
+
+	// Track block saturation from the transaction counts of confirmed blocks
+	r.txCounts = ee.NewTxCountTracker(ee.ClientTxCountFetcher{Client: r.wsConn.Get}, int(r.cfg.TxCountWindow))
+
+	// Track the priority fees that achieved inclusion to estimate the value of preconfs
+	r.feePercentiles = ee.NewFeePercentileTracker(int(r.cfg.FeePercentileWindow))
+
+	// PRIORITY_FEE is taken in wei for transfers and gwei for blob transactions,
+	// unless the tip is set in gwei for both or as a percentage of the base fee
+	r.transferTip = ee.FixedTip(new(big.Int).SetUint64(r.cfg.PriorityFee))
+	r.blobTip = ee.FixedTip(new(big.Int).Mul(new(big.Int).SetUint64(r.cfg.PriorityFee), big.NewInt(params.GWei)))
+	if r.cfg.GasTipGwei > 0 {
+		r.transferTip = ee.FixedTip(ee.GweiToWei(r.cfg.GasTipGwei))
+		r.blobTip = r.transferTip
+	} else if r.cfg.TipAsBaseFeePct > 0 {
+		r.transferTip = ee.BaseFeePercentTip(r.cfg.TipAsBaseFeePct, new(big.Int).SetUint64(r.cfg.TipFloorWei))
+		r.blobTip = r.transferTip
+	}
+
+	// A detected block time is re-estimated slowly from received headers. Only
+	// the derived decay window follows it; other derived timings keep their
+	// startup values
+	if r.cfg.BlockTimeSetting == "auto" {
+		r.blockTimeEstimator = ee.NewBlockTimeEstimator(r.cfg.BlockTime, blockTimeReestimateSpan)
+	}
+}
+
+// reestimateBlockTiming moves the derived decay window to a re-estimated block time.
+func (r *runner) reestimateBlockTiming(estimate time.Duration) {
+	timing, err := strategy.NewBlockTiming(estimate)
+	if err != nil {
+		slog.Warn("Ignoring re-estimated block time", "blockTime", estimate.String(), "error", err)
+		return
+	}
+	if !r.cfg.DecayDurationSet {
+		r.defaultDecay.Store(int64(timing.DecayDuration))
+	}
+	previous := time.Duration(r.currentBlockTime.Swap(int64(estimate)))
+	slog.Info("Block time re-estimated",
+		"blockTime", estimate.String(),
+		"previousBlockTime", previous.String(),
+		"decayDuration", time.Duration(r.defaultDecay.Load()).String(),
+	)
+}
+
+// startRebalancer tops up pool wallets running low from the funding wallet, one per check.
+func (r *runner) startRebalancer() error {
+	if r.cfg.FundingPrivateKey != "" && r.cfg.DryRun {
+		slog.Info("Wallet rebalancing disabled in dry run")
+	} else if r.cfg.FundingPrivateKey != "" {
+		funder, err := bb.AuthenticateAddress(r.cfg.FundingPrivateKey, r.wsConn.Get())
+		if err != nil {
+			slog.Error("Failed to authenticate funding key", "error", err)
+			return fmt.Errorf("failed to authenticate FUNDING_PRIVATE_KEY: %w", err)
+		}
+		// Its transfers would race the pool's own transactions for nonces
+		for _, address := range r.walletAddresses {
+			if address == funder.Address {
+				return fmt.Errorf("FUNDING_PRIVATE_KEY must not be a wallet of the pool (%s)", address.Hex())
+			}
+		}
+		rebalancer, err := ee.NewRebalancer(ee.ClientFunc(r.wsConn.Get), funder, r.cfg.Rebalance, r.transferTip)
+		if err != nil {
+			return err
+		}
+		slog.Info("Wallet rebalancing enabled",
+			"funder", funder.Address.Hex(),
+			"minBalanceWei", r.cfg.Rebalance.MinBalance.String(),
+			"topUpWei", r.cfg.Rebalance.TopUp.String(),
+			"interval", r.cfg.RebalanceInterval.String(),
+		)
+		go func() {
+			ticker := time.NewTicker(r.cfg.RebalanceInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-r.ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				topUp, err := rebalancer.Check(r.ctx, r.walletAddresses)
+				if err != nil {
+					slog.Warn("Failed to rebalance wallets", "error", err)
+				}
+				if topUp == nil {
+					continue
+				}
+				metrics.WalletTopUps.Inc()
+				slog.Info("Topped up wallet",
+					"wallet", topUp.Wallet.Hex(),
+					"balanceWei", topUp.Balance.String(),
+					"topUpWei", r.cfg.Rebalance.TopUp.String(),
+					"txHash", topUp.Tx.Hash().Hex(),
+					"sentWei", rebalancer.Sent().String(),
+				)
+			}
+		}()
+	}
+	return nil
+}
+
+// startHeartbeat logs an "alive" line periodically so quiet periods can be told
+// apart from a stall.
+func (r *runner) startHeartbeat() {
+	r.connected.Store(true)
+	go heartbeat.Run(r.ctx, slog.Default(), r.cfg.HeartbeatInterval, func() heartbeat.Status {
+		total := r.accounting.Total()
+		return heartbeat.Status{
+			Block:     r.latestBlock.Load(),
+			Connected: r.connected.Load(),
+			Bids:      total.Bids,
+			SpendEth:  total.SpendEth,
+			FeesEth:   bb.WeiToEth(r.inclusionFees.Total().FeesWei()),
+		}
+	})
+}
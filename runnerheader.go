@@ -0,0 +1,633 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	"github.com/primev/preconf_blob_bidder/internal/metrics"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/selftest"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+	"github.com/primev/preconf_blob_bidder/internal/watch"
+)
+
+// loop handles signals, drain checks and headers until the run finishes.
+func (r *runner) loop(signals <-chan os.Signal, drainCheck <-chan time.Time, runtimeReached <-chan time.Time) error {
+	for {
+		select {
+		case <-r.ctx.Done():
+			slog.Info("Context cancelled, shutting down")
+			return r.finish()
+		case <-runtimeReached:
+			slog.Info("Maximum runtime reached, shutting down", "maxRuntime", r.cfg.MaxRuntime.String())
+			return r.finish()
+		case sig := <-signals:
+			if sig != r.cfg.DrainSignal {
+				slog.Info("Received signal, shutting down", "signal", sig.String())
+				return r.finish()
+			}
+			if !r.blockDrain.Draining() {
+				r.blockDrain.Start(time.Now(), time.Duration(r.cfg.DrainTimeoutSec)*time.Second)
+				r.auditPause(common.Address{}, true, "drain")
+				slog.Info("Received drain signal, no longer bidding",
+					"signal", sig.String(),
+					"unresolvedBids", r.blockDrain.Unresolved(),
+					"drainTimeoutSec", r.cfg.DrainTimeoutSec,
+				)
+			}
+		case <-drainCheck:
+			if r.selfTestReport != nil && !r.blockDrain.Draining() && time.Now().After(r.selfTestDeadline) {
+				slog.Error("Selftest found no block to bid on", "drainTimeoutSec", r.cfg.DrainTimeoutSec)
+				return r.finish()
+			}
+			if r.blockDrain.Done(time.Now()) {
+				slog.Info("Drain complete, shutting down", "unresolvedBids", r.blockDrain.Unresolved())
+				return r.finish()
+			}
+		case event := <-r.headerStream.Events():
+			r.connected.Store(event.Connected)
+			if !event.Connected {
+				if event.StaleFor > 0 {
+					metrics.WSStaleReconnects.Inc()
+				}
+				continue
+			}
+			metrics.WSReconnects.Inc()
+			continue
+		case header := <-r.headerStream.Headers():
+			if r.onHeader(header) {
+				return r.finish()
+			}
+		}
+	}
+}
+
+// onHeader tracks a new header and bids on its target blocks.
+//
+// Returns:
+// - Whether the run should finish, as a self-test does when it cannot build its transaction.
+func (r *runner) onHeader(header *types.Header) bool {
+	// Some providers leave fields out of headers; without a number there is no block to bid on,
+	// while a missing base fee is fetched again when the transaction is built
+	if header == nil || header.Number == nil {
+		slog.Warn("Skipping header without a block number")
+		return false
+	}
+	if header.BaseFee == nil {
+		slog.Warn("Header has no base fee", "blockNumber", header.Number.Uint64())
+	}
+	headerAt := time.Now()
+	r.latestBlock.Store(header.Number.Uint64())
+	r.latestHeader.Store(header)
+	metrics.LastProcessedBlock.Set(float64(header.Number.Uint64()))
+	metrics.BlocksObserved.Inc()
+	if r.cfg.BidOutcomeLogBlocks > 0 && header.Number.Uint64()%r.cfg.BidOutcomeLogBlocks == 0 {
+		outcome := r.session.Outcome()
+		slog.Info("Bid outcomes",
+			"blockNumber", header.Number.Uint64(),
+			"wonThisInterval", outcome.Won-r.lastOutcome.Won,
+			"lostThisInterval", outcome.Lost-r.lastOutcome.Lost,
+			"won", outcome.Won,
+			"lost", outcome.Lost,
+			"multiProviderWon", outcome.MultiWon,
+			"winRate", outcome.WinRate(),
+		)
+		r.lastOutcome = outcome
+	}
+	if r.blockTimeEstimator != nil {
+		if estimate, changed := r.blockTimeEstimator.Observe(header.Number.Uint64(), header.Time); changed {
+			r.reestimateBlockTiming(estimate)
+		}
+	}
+	go r.observeBlock(header.Number.Uint64())
+
+	r.blockDrain.Observe(header.Number.Uint64())
+	if r.blockDrain.Draining() {
+		slog.Info("Draining, skipping block",
+			"blockNumber", header.Number.Uint64(),
+			"unresolvedBids", r.blockDrain.Unresolved(),
+		)
+		return false
+	}
+
+	if r.eligible != nil && !r.eligible.Eligible(r.ctx, header, r.cfg.Offset) {
+		return false
+	}
+
+	if r.cfg.Outage.Open() {
+		go r.probeOutage()
+	} else if r.cfg.Outage.Queued() > 0 {
+		r.flushOutageQueue()
+	}
+	if !r.cfg.Outage.Building() {
+		slog.Info("Bidder node unreachable, skipping block", "blockNumber", header.Number.Uint64())
+		return false
+	}
+	// Checked in the background, so a top-up doesn't hold up the block's bids
+	if r.depositManager != nil {
+		go r.ensureDeposit(header.Number.Uint64() + r.cfg.Offset)
+	}
+	if r.cfg.Mode == watch.ModeWatch {
+		return false
+	}
+
+	return r.bidOnHeader(header, headerAt)
+}
+
+// observeBlock updates the trackers fed by a confirmed block and resolves the
+// bids on it.
+func (r *runner) observeBlock(blockNumber uint64) {
+	if err := r.txCounts.Observe(r.ctx, blockNumber); err != nil {
+		slog.Warn("Failed to fetch block transaction count", "blockNumber", blockNumber, "error", err)
+		return
+	}
+	metrics.AvgTxsPerBlock.Set(r.txCounts.Average())
+
+	client := r.readClient()
+	block, err := client.BlockByNumber(r.ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		slog.Warn("Failed to fetch block body", "blockNumber", blockNumber, "error", err)
+		return
+	}
+	r.feePercentiles.ObserveBlock(block.BaseFee(), block.Transactions())
+
+	// Escalate bid amounts after consecutive bids miss their target block
+	for _, record := range r.inclusion.ObserveBlock(blockNumber, block.Transactions()) {
+		r.escalator.Observe(record.Status)
+		r.bidRecords.Record(record)
+		r.session.RecordResolved(record)
+		if record.Status == api.BidStatusIncluded {
+			paid := recordInclusionFees(r.ctx, client, block, record, r.inclusionFees)
+			if r.inclusionWebhook != nil {
+				var gasUsed, blobGasUsed uint64
+				if paid != nil {
+					gasUsed, blobGasUsed = paid.GasUsed, paid.BlobGasUsed
+				}
+				included := bids.NewInclusionRecord(record, gasUsed, blobGasUsed)
+				go func() {
+					if err := r.inclusionWebhook.Notify(r.ctx, included); err != nil {
+						metrics.InclusionWebhookFailures.Inc()
+						slog.Warn("Failed to call inclusion webhook", "txHash", included.TxHash, "error", err)
+					}
+				}()
+			}
+		}
+		if r.selfTestReport != nil {
+			detail := fmt.Sprintf("block %d, %s", record.BlockNumber, record.Status)
+			if record.Status == api.BidStatusIncluded {
+				r.selfTestReport.Pass(selftest.StageInclusion, detail)
+			} else {
+				r.selfTestReport.Fail(selftest.StageInclusion, detail)
+			}
+		}
+		if r.rampStats != nil {
+			var blobFee *big.Int
+			if tx := block.Transaction(common.HexToHash(record.TxHash)); tx != nil && record.Status == api.BidStatusIncluded {
+				blobFee = ee.BlobFee(tx, ee.BlobBaseFee(block.Header()))
+			}
+			r.rampStats.RecordResolved(record, blobFee)
+		}
+	}
+
+	// Transactions past their lifetime are no longer rebid on, freeing their nonce
+	for _, tx := range r.inclusion.AbandonExpired(blockNumber, time.Now()) {
+		if r.dedup != nil {
+			r.dedup.Forget(tx.TxHash)
+		}
+		if r.nonceMonitor != nil {
+			r.nonceMonitor.Forget(tx.TxHash)
+		}
+		slog.Warn("Abandoned transaction past its maximum lifetime",
+			"txHash", tx.TxHash.Hex(),
+			"firstBlock", tx.FirstBlock,
+			"age", time.Since(tx.SentAt).Round(time.Millisecond).String(),
+			"bids", tx.Bids,
+			"maxLifetime", r.cfg.TxMaxLifetime.String(),
+		)
+	}
+}
+
+// bidOnHeader builds the transactions of the header's profile and bids on them
+// for each target block.
+//
+// Returns:
+// - Whether the run should finish, as a self-test does when it cannot build its transaction.
+func (r *runner) bidOnHeader(header *types.Header, headerAt time.Time) bool {
+	// A failed cycle is retried on this header with the same profile,
+	// even if the send interval hasn't elapsed
+	profile, retryAttempt, retrying := r.cycleRetry.Pending()
+	if !retrying && !r.cfg.SendInterval.Due(header.Number.Uint64(), headerAt) {
+		slog.Info("Send interval not elapsed, skipping block",
+			"blockNumber", header.Number.Uint64(),
+			"timestamp", header.Time,
+			"hash", header.Hash().String(),
+		)
+		return false
+	}
+	if !retrying {
+		profile = r.cfg.ProfileSelector.Select(header.Number.Uint64())
+		if r.cfg.BlobRamp != nil {
+			profile = r.cfg.BlobRamp.Select(header.Number.Uint64())
+		}
+	}
+
+	// Send with a wallet assigned to the profile's transaction mode
+	walletIndex, err := r.walletSelector.Select(profile.Mode())
+	if err != nil {
+		slog.Error("No wallet for bid profile", "profile", profile.Name, "error", err)
+		return false
+	}
+	wallet := r.wallets[walletIndex]
+	if r.nonceMonitor != nil && r.nonceMonitor.Paused(wallet.Address) {
+		slog.Warn("Skipping bid from wallet with diverged nonces",
+			"blockNumber", header.Number.Uint64(),
+			"wallet", walletIndex,
+		)
+		return false
+	}
+
+	// Blob profiles send a chain of BLOB_CHAIN_LENGTH transactions with sequential nonces
+	var chain []*types.Transaction
+	var blockNumber uint64
+	var sentBlobs uint
+	var blobGuards []string
+	generator := ee.GeneratorBlob
+	if profile.NumBlob == 0 {
+		generator = r.cfg.TxType
+		// Build with the TX_TYPE generator, sending to the next scheduled recipient if there is a schedule
+		opts := ee.TxOptions{Value: r.cfg.TransferAmount, Nonces: r.nonceAllocator, Backrun: r.cfg.Backrun, Fees: r.cfg.FeeCaps, Header: header, Token: r.cfg.Token}
+		if r.cfg.TransferSchedule != nil {
+			if r.scheduledBlock != header.Number.Uint64() {
+				r.scheduledTransfer = r.cfg.TransferSchedule.Next()
+				r.scheduledBlock = header.Number.Uint64()
+			}
+			opts = ee.TxOptions{To: &r.scheduledTransfer.To, Value: r.scheduledTransfer.Value, Nonces: r.nonceAllocator, Backrun: r.cfg.Backrun, Fees: r.cfg.FeeCaps, Header: header, Token: r.cfg.Token}
+		}
+		chain, blockNumber, err = ee.BuildChain(r.wsConn.Get(), wallet, generator, opts, int(r.cfg.TxPerBlock), r.cfg.Offset, r.transferTip)
+	} else {
+		// Execute Blob Transaction
+		numBlobs := profile.NumBlob
+		if r.cfg.BlobCycle != nil {
+			numBlobs = r.cfg.BlobCycle.Count(header.Number.Uint64())
+			blobGuards = append(blobGuards, api.GuardBlobCycle)
+		}
+		if r.cfg.AdaptiveBlobs != nil {
+			blobBaseFee := ee.NextBlobBaseFee(header)
+			numBlobs = r.cfg.AdaptiveBlobs.Count(blobBaseFee)
+			blobGuards = append(blobGuards, api.GuardAdaptiveBlobCount)
+			slog.Info("Adapted blob count to blob base fee", "blobBaseFee", blobBaseFee, "numBlob", numBlobs)
+		}
+		sentBlobs = numBlobs
+		opts := ee.TxOptions{NumBlobs: int(numBlobs), Blobs: r.cfg.BlobSource, Nonces: r.nonceAllocator, Fees: r.cfg.FeeCaps, Header: header}
+		chain, blockNumber, err = ee.BuildChain(r.wsConn.Get(), wallet, ee.GeneratorBlob, opts, int(r.cfg.BlobChainLength), r.cfg.Offset, r.blobTip)
+	}
+	if errors.Is(err, ee.ErrFeeAboveCap) {
+		slog.Warn("Max fee per gas above MAX_FEE_GWEI, skipping block",
+			"blockNumber", header.Number.Uint64(),
+			"baseFee", header.BaseFee,
+			"error", err,
+		)
+		return false
+	}
+
+	// A pending transaction rebuilt unchanged is bid on again under its existing hash
+	if len(chain) > 0 && err == nil && r.dedup != nil {
+		deduped, duplicates := r.dedup.DedupChain(wallet.Address, chain)
+		for i, pending := range deduped {
+			if duplicates[i] {
+				slog.Info("Rebidding on pending transaction",
+					"txHash", pending.Hash().String(),
+					"nonce", pending.Nonce(),
+					"rebuiltTxHash", chain[i].Hash().String(),
+				)
+			}
+		}
+		chain = deduped
+	}
+	if err == nil && r.nonceMonitor != nil {
+		for _, signedTx := range chain {
+			r.nonceMonitor.Record(wallet.Address, signedTx.Nonce(), signedTx.Hash())
+		}
+	}
+
+	if len(chain) == 0 {
+		slog.Error("Transaction was not signed or created.")
+	} else {
+		slog.Info("Transaction sent successfully", "chainLength", len(chain))
+		metrics.TransactionsSent.WithLabelValues(generator).Add(float64(len(chain)))
+		for _, signedTx := range chain {
+			if r.calldataDecoder != nil && len(signedTx.Data()) > 0 {
+				decoded := r.calldataDecoder.DecodeCalldata(signedTx.Data())
+				slog.Info("Decoded transaction calldata",
+					"txHash", signedTx.Hash().String(),
+					"method", decoded.Method,
+					"args", decoded.Args,
+					"raw", decoded.Raw,
+				)
+			}
+		}
+	}
+
+	if err != nil {
+		slog.Error("Failed to execute transaction", "error", err)
+	}
+
+	var trackedNonce any
+	if next, ok := r.nonceAllocator.Next(wallet.Address); ok {
+		trackedNonce = next
+	}
+	slog.Info("New block received",
+		"blockNumber", header.Number.Uint64(),
+		"timestamp", header.Time,
+		"hash", header.Hash().String(),
+		"avgTxsPerBlock", r.txCounts.Average(),
+		"marketSaturated", r.txCounts.Saturated(),
+		"profile", profile.Name,
+		"wallet", walletIndex,
+		"trackedNonce", trackedNonce,
+		"retryAttempt", retryAttempt,
+	)
+
+	if len(chain) == 0 || err != nil {
+		// Nonces allocated to a chain that is never bid on would leave a gap
+		r.nonceAllocator.Resync(wallet.Address)
+		if r.selfTestReport != nil {
+			r.selfTestReport.Fail(selftest.StageBuild, fmt.Sprint(err))
+			return true
+		}
+		if r.cycleRetry.Fail(profile) {
+			slog.Warn("Bid cycle failed, retrying on next header",
+				"blockNumber", header.Number.Uint64(),
+				"retryAttempt", retryAttempt+1,
+			)
+		}
+		return false
+	}
+
+	if r.selfTestReport != nil {
+		r.selfTestReport.Pass(selftest.StageBuild, fmt.Sprintf("%s, nonce %d", chain[0].Hash().Hex(), chain[0].Nonce()))
+	}
+
+	decayDuration := time.Duration(r.defaultDecay.Load())
+	if profile.DecayMs != 0 {
+		decayDuration = profile.DecayDuration()
+	}
+	r.alignment.Prune(header.Number.Int64())
+
+	// Bid on each block in the range, never overlapping decay windows for the same block
+	cycleFailed := false
+	var pendingBids []bb.PendingBid
+	for i := uint64(0); i < r.cfg.BidBlockRange; i++ {
+		targetBlock := blockNumber + i
+		bidTiming := strategy.BlockTiming{BlockTime: time.Duration(r.currentBlockTime.Load())}
+		decayStart, decayFitted, _, _ := bidTiming.FitDecay(r.cfg.OffsetDecayPolicy, r.cfg.Offset+i, r.cfg.DecayTiming.StartOffset, decayDuration)
+		window := bb.NewDecayWindow(time.Now().Add(decayStart), decayFitted)
+		if err := r.alignment.Reserve(int64(targetBlock), window); err != nil {
+			slog.Warn("Skipping bid with overlapping decay window", "blockNumber", targetBlock, "error", err)
+			continue
+		}
+
+		// Bid on each transaction of the chain, sending earlier nonces first; the
+		// TX_PER_BLOCK transactions of a profile without blobs and their backrun are bid on as one bundle
+		bidTxs := chain
+		var bundle []*types.Transaction
+		if profile.NumBlob == 0 && len(chain) > 1 {
+			bidTxs = chain[:1]
+			bundle = chain
+		}
+		// Price the bids with up to BID_PRICING_WORKERS at once; they are still
+		// bid on in nonce order below
+		type bidPrice struct {
+			wei    *big.Int
+			eth    float64
+			pricer string
+			guards []string
+		}
+		prices := strategy.PriceInOrder(len(bidTxs), int(r.cfg.BidPricingWorkers), func(int) bidPrice {
+			var price bidPrice
+			if r.escalator.Level() > 0 {
+				price.guards = append(price.guards, api.GuardEscalated)
+			}
+			price.pricer = strategy.PricerProfile
+			if r.cfg.WeiAmount != nil {
+				price.pricer = r.cfg.WeiAmount.Strategy()
+				price.wei = r.escalator.ApplyWei(r.cfg.WeiAmount.Next())
+				price.eth = bb.WeiToEth(price.wei)
+				return price
+			}
+			// Profile amounts are drawn from a random source that isn't safe for concurrent use
+			r.profileAmountMu.Lock()
+			sampled := r.escalator.Apply(profile.NextBidAmount())
+			r.profileAmountMu.Unlock()
+			price.eth = r.cfg.BidRange.Clamp(sampled)
+			price.wei = bb.EthToWei(price.eth)
+			if price.eth > sampled {
+				price.guards = append(price.guards, api.GuardClampedMin)
+			} else if price.eth < sampled {
+				price.guards = append(price.guards, api.GuardClampedMax)
+			}
+			return price
+		})
+
+		var chainBids []bb.PendingBid
+		windows := r.windowJitter.Windows(window, len(bidTxs))
+		for j, signedTx := range bidTxs {
+			amountWei, randomEthAmount := prices[j].wei, prices[j].eth
+			pricer, guards := prices[j].pricer, prices[j].guards
+			var bidStrategy *api.BidStrategy
+			if r.cfg.BidRecordStrategy {
+				bidStrategy = bids.NewBidStrategy(pricer, generator, int(sentBlobs), header.BaseFee,
+					ee.NextBlobBaseFee(header), append(guards, blobGuards...))
+			}
+			r.accounting.RecordBid(profile.Name, randomEthAmount)
+			r.session.RecordBid()
+			r.blockDrain.Track(targetBlock)
+			slotOffsets := bidTiming.InSlotOffsets(header.Time, targetBlock-header.Number.Uint64(),
+				time.UnixMilli(windows[j].Start), time.UnixMilli(windows[j].End))
+			metrics.DecayStartSlotOffsetSeconds.Observe(slotOffsets.DecayStart.Seconds())
+			metrics.DecayEndSlotOffsetSeconds.Observe(slotOffsets.DecayEnd.Seconds())
+			r.inclusion.Track(&api.BidRecord{
+				TxHash:                 signedTx.Hash().Hex(),
+				BlockNumber:            targetBlock,
+				AmountEth:              randomEthAmount,
+				Profile:                profile.Name,
+				EscalationLevel:        r.escalator.Level(),
+				SentAt:                 time.Now().UnixMilli(),
+				DecayStartSlotOffsetMs: slotOffsets.DecayStart.Milliseconds(),
+				DecayEndSlotOffsetMs:   slotOffsets.DecayEnd.Milliseconds(),
+				Strategy:               bidStrategy,
+			})
+			if r.rampStats != nil {
+				r.rampStats.RecordBid(profile.Name, signedTx.Hash(), targetBlock)
+			}
+
+			if p90Tip := r.feePercentiles.Percentile(90); p90Tip != nil {
+				estimate := ee.EstimatePreconfValue(amountWei, signedTx.GasTipCap(), p90Tip, params.TxGas)
+				r.valueReport.Add(estimate)
+				slog.Info("Preconf value estimate",
+					"blockNumber", targetBlock,
+					"preconfCostWei", estimate.PreconfCostWei.String(),
+					"tipOnlyCostWei", estimate.TipOnlyCostWei.String(),
+					"savingsWei", estimate.SavingsWei.String(),
+				)
+			}
+
+			// A broadcast delay moves the bundle after the bid, in the worker
+			if !r.cfg.UsePayload && r.cfg.BroadcastDelay == 0 {
+				delivered := []*types.Transaction{signedTx}
+				if bundle != nil {
+					delivered = bundle
+				}
+				for _, tx := range delivered {
+					err = r.sendBundle(tx, targetBlock)
+					if err == nil {
+						continue
+					}
+					slog.Error("Failed to send transaction",
+						"rpcEndpoint", bb.MaskEndpoint(r.cfg.RPCEndpoint),
+						"error", err,
+					)
+					cycleFailed = true
+					if r.selfTestReport != nil {
+						r.selfTestReport.Fail(selftest.StageBid, err.Error())
+					}
+				}
+			}
+			chainBids = append(chainBids, bb.PendingBid{
+				Tx:           signedTx,
+				BlockNumber:  int64(targetBlock),
+				BidAmountWei: amountWei,
+				AmountEth:    randomEthAmount,
+				AmountWei:    amountWei,
+				Window:       windows[j],
+				HeaderAt:     headerAt,
+				Bundle:       bundle,
+				Broadcast:    !r.cfg.UsePayload && r.cfg.BroadcastDelay > 0,
+			})
+		}
+		bb.ShareChainPriority(chainBids)
+		pendingBids = append(pendingBids, chainBids...)
+	}
+	if len(pendingBids) > 0 {
+		metrics.BidBuildSeconds.Observe(time.Since(headerAt).Seconds())
+	}
+	if r.cfg.Outage.Open() {
+		dropped := r.cfg.Outage.Queue(pendingBids...)
+		metrics.OutageBidsDiscarded.WithLabelValues(bb.OutageDiscardOverflow).Add(float64(len(dropped)))
+		slog.Info("Bidder node unreachable, queued bids for recovery",
+			"blockNumber", header.Number.Uint64(),
+			"queued", r.cfg.Outage.Queued(),
+			"discarded", len(dropped),
+		)
+	} else {
+		r.sender.Enqueue(pendingBids...)
+	}
+
+	// A self-test waits for its target block like a drain once its bid is queued
+	if r.selfTestReport != nil && len(pendingBids) > 0 {
+		r.blockDrain.Start(time.Now(), time.Duration(r.cfg.DrainTimeoutSec)*time.Second)
+	}
+
+	if !cycleFailed {
+		r.nonceAllocator.Succeed(wallet.Address)
+	} else if r.nonceAllocator.Fail(wallet.Address) {
+		slog.Warn("Resyncing nonces after failed sends",
+			"wallet", walletIndex,
+			"failures", r.cfg.NonceResyncFailures,
+		)
+	}
+	if !cycleFailed {
+		r.cycleRetry.Succeed()
+	} else if r.cycleRetry.Fail(profile) {
+		slog.Warn("Bid cycle failed, retrying on next header",
+			"blockNumber", header.Number.Uint64(),
+			"retryAttempt", retryAttempt+1,
+		)
+	}
+	return false
+}
+
+// startWatcher bids on the pending transactions of the watched senders in watch
+// mode, instead of transactions built on each header.
+func (r *runner) startWatcher() error {
+	if r.cfg.Mode != watch.ModeWatch {
+		return nil
+	}
+	chainID, err := r.wsConn.Get().ChainID(r.ctx)
+	if err != nil {
+		slog.Error("Failed to fetch chain ID", "error", err)
+		return err
+	}
+	watcher := watch.NewWatcher(watch.DialSubscriber(r.wsFailover.Active()), chainID, r.cfg.WatchAddresses, watch.DefaultSeenSize)
+	go func() {
+		for {
+			err := watcher.Run(r.ctx, r.bidOnWatched)
+			if r.ctx.Err() != nil {
+				return
+			}
+			slog.Warn("Pending transaction subscription failed, resubscribing", "error", err)
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+	slog.Info("Watching pending transactions", "senders", len(r.cfg.WatchAddresses))
+	return nil
+}
+
+// bidOnWatched bids on a pending transaction of a watched sender.
+func (r *runner) bidOnWatched(tx *types.Transaction) {
+	profile := r.cfg.Profiles[0]
+	if r.blockDrain.Draining() || !r.cfg.Outage.Building() {
+		slog.Info("Not bidding on watched transaction", "txHash", tx.Hash().Hex(), "draining", r.blockDrain.Draining())
+		return
+	}
+	targetBlock := r.latestBlock.Load() + r.cfg.Offset
+	var amountWei *big.Int
+	var amountEth float64
+	if r.cfg.WeiAmount != nil {
+		amountWei = r.escalator.ApplyWei(r.cfg.WeiAmount.Next())
+		amountEth = bb.WeiToEth(amountWei)
+	} else {
+		amountEth = r.cfg.BidRange.Clamp(r.escalator.Apply(profile.NextBidAmount()))
+		amountWei = bb.EthToWei(amountEth)
+	}
+	slog.Info("Bidding on watched transaction",
+		"txHash", tx.Hash().Hex(),
+		"blockNumber", targetBlock,
+		"amount_ETH", amountEth,
+	)
+	r.accounting.RecordBid(profile.Name, amountEth)
+	r.session.RecordBid()
+	r.blockDrain.Track(targetBlock)
+	r.inclusion.Track(&api.BidRecord{
+		TxHash:          tx.Hash().Hex(),
+		BlockNumber:     targetBlock,
+		AmountEth:       amountEth,
+		Profile:         profile.Name,
+		EscalationLevel: r.escalator.Level(),
+		SentAt:          time.Now().UnixMilli(),
+	})
+	r.sender.Enqueue(bb.PendingBid{
+		Tx:           tx,
+		BlockNumber:  int64(targetBlock),
+		BidAmountWei: amountWei,
+		AmountEth:    amountEth,
+		AmountWei:    amountWei,
+		HashOnly:     true,
+		Window:       bb.NewDecayWindow(time.Now(), time.Duration(r.defaultDecay.Load())),
+		HeaderAt:     time.Now(),
+	})
+}
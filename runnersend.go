@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/primev/preconf_blob_bidder/internal/bids"
+	ee "github.com/primev/preconf_blob_bidder/internal/eth"
+	"github.com/primev/preconf_blob_bidder/internal/metrics"
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/primev/preconf_blob_bidder/internal/selftest"
+	"github.com/primev/preconf_blob_bidder/internal/strategy"
+)
+
+// setupBidding sets up the trackers of sent bids, and the bidder and bundle
+// sender that bids go out through.
+func (r *runner) setupBidding() error {
+	// Track in-flight bids so shutdown waits for their commitment streams
+	r.drain = &bb.GracefulBidDrain{}
+	r.drainTimeout = time.Duration(r.cfg.ShutdownDrainTimeoutSec) * time.Second
+	r.accounting = strategy.NewAccounting()
+	r.alignment = bb.NewBidWindowAlignment()
+	r.windowJitter = bb.NewDecayJitter(r.cfg.DecayJitter, rand.New(rand.NewSource(time.Now().UnixNano())))
+	r.cycleRetry = strategy.NewCycleRetry(r.cfg.BidCycleRetries)
+	r.valueReport = ee.NewPreconfValueReport()
+	r.inclusion = bids.NewInclusionTracker(r.cfg.TxMaxLifetime)
+	if r.cfg.InclusionWebhookURL != "" {
+		r.inclusionWebhook = bids.NewWebhook(r.cfg.InclusionWebhookURL, r.cfg.HTTPUserAgent, r.cfg.InclusionWebhookTimeout, int(r.cfg.InclusionWebhookAttempts))
+	}
+	// Silent rejections are posted with the timeout and attempts of the inclusion webhook
+	if r.cfg.SilentRejectionWebhookURL != "" {
+		r.silentRejectionWebhook = bids.NewWebhook(r.cfg.SilentRejectionWebhookURL, r.cfg.HTTPUserAgent, r.cfg.InclusionWebhookTimeout, int(r.cfg.InclusionWebhookAttempts))
+	}
+	r.escalator = strategy.NewBidAmountEscalator(r.cfg.EscalationMissedBids, r.cfg.EscalationStepPct, r.cfg.EscalationMaxMultiplier)
+	if r.cfg.TxDedup {
+		r.dedup = ee.NewTxDeduplicator()
+	}
+	// Building and submitting are separate steps: bids go to the bidder and the
+	// transactions of hash-only bids to the bundle sender, which a dry run swaps
+	// for stand-ins that log them
+	r.bidder = r.bidderClient
+	if len(r.endpoints) > 1 {
+		r.bidder = r.multiBidder
+	}
+	r.bundles = ee.RelaySender{RPCEndpoint: r.cfg.RPCEndpoint, UserAgent: r.cfg.HTTPUserAgent, Pool: r.rpcPool, Logger: r.ethLog}
+	if r.cfg.DryRun {
+		r.bidder = bb.DryRunBidder{}
+		r.bundles = ee.DryRunSender{}
+	}
+	var err error
+	if r.cfg.MaxConcurrentBundles > 0 {
+		r.bundles, err = ee.NewLimitedSender(r.bundles, int(r.cfg.MaxConcurrentBundles), r.cfg.BundleOverflowPolicy, func(depth int) {
+			metrics.BundleQueueDepth.Set(float64(depth))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Late payload bids go out as hash-only bids, with the tx delivered as a bundle
+	r.latencySLO = bb.NewPayloadLatencySLO(r.cfg.BidLatencySLO, func(tx *types.Transaction, blockNumber int64) error {
+		return r.sendBundle(tx, uint64(blockNumber))
+	})
+	// Transactions in flight outrun the pending count, so nonces are allocated locally
+	r.nonceAllocator = ee.NewNonceAllocator(int(r.cfg.NonceResyncFailures))
+	// A stale bid may be retargeted while its transactions could still be
+	// included: fee caps covering the latest base fees and nonces not yet used
+	r.staleBids, err = bb.NewStaleBidGuard(r.cfg.StaleBidPolicy, r.cfg.Offset, func(tx *types.Transaction) bool {
+		header := r.latestHeader.Load()
+		if header == nil || !ee.FeeCapsCover(tx, header) {
+			return false
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return false
+		}
+		nonce, err := r.wsConn.Get().NonceAt(r.ctx, from, nil)
+		return err == nil && nonce <= tx.Nonce()
+	})
+	if err != nil {
+		slog.Error("Failed to create stale bid guard", "error", err)
+		return fmt.Errorf("failed to create stale bid guard: %w", err)
+	}
+	// Dispatch each header's bids highest amount first while the bidder window is fresh
+	r.sender = bb.NewTransactionPriorityQueueSender(r.dispatch)
+	return nil
+}
+
+// sendBundle delivers a transaction for the block as a bundle.
+func (r *runner) sendBundle(tx *types.Transaction, blockNumber uint64) error {
+	err := r.bundles.SendBundle(ee.DeliveredTx(r.cfg.BlobSidecar, tx), blockNumber)
+	if err == nil && !r.cfg.DryRun {
+		metrics.BundlesSent.Inc()
+	}
+	if errors.Is(err, ee.ErrBundleDropped) {
+		metrics.BundlesDropped.Inc()
+	}
+	return err
+}
+
+// auditOutage records bidder outages as pauses under the skip policy, as no
+// transactions are signed during them.
+func (r *runner) auditOutage(opened, recovered bool) {
+	if r.cfg.BidderOutagePolicy != bb.OutagePolicySkip {
+		return
+	}
+	if opened || recovered {
+		r.auditPause(common.Address{}, opened, "bidder outage")
+	}
+}
+
+// dispatch sends a bid taken off the priority queue, unless its target block
+// was reached while it was built.
+func (r *runner) dispatch(bid bb.PendingBid) {
+	// A bid built too slowly for its target block is worthless as it is
+	staleTarget := bid.BlockNumber
+	var action string
+	bid, action = r.staleBids.Check(bid, r.latestBlock.Load(), time.Now())
+	if action != bb.StaleBidFresh {
+		metrics.StaleBids.WithLabelValues(action).Inc()
+		var buildLatency time.Duration
+		if !bid.HeaderAt.IsZero() {
+			buildLatency = time.Since(bid.HeaderAt)
+		}
+		slog.Warn("Bid target block already reached",
+			"txHash", bid.Tx.Hash().String(),
+			"blockNumber", staleTarget,
+			"latestBlock", r.latestBlock.Load(),
+			"action", action,
+			"retargetBlock", bid.BlockNumber,
+			"buildLatency", buildLatency.String(),
+		)
+		if action == bb.StaleBidSkipped {
+			return
+		}
+		r.blockDrain.Track(uint64(bid.BlockNumber))
+	}
+	var input interface{} = bid.Tx.Hash().String()
+	if len(bid.Bundle) > 0 {
+		// A bundle goes out whole in one bid; late bundles aren't downgraded
+		if r.cfg.UsePayload && !bid.HashOnly {
+			input = bid.Bundle
+		} else {
+			hashes := make([]string, len(bid.Bundle))
+			for i, tx := range bid.Bundle {
+				hashes[i] = tx.Hash().String()
+			}
+			input = hashes
+		}
+	} else if r.cfg.UsePayload && !bid.HashOnly {
+		var downgrade *bb.Downgrade
+		var err error
+		input, downgrade, err = r.latencySLO.BidInput(bid)
+		if err != nil {
+			slog.Error("Sending late payload bid", "txHash", bid.Tx.Hash().String(), "error", err)
+		}
+		if downgrade != nil {
+			metrics.PayloadBidDowngrades.WithLabelValues(downgrade.Reason).Inc()
+			slog.Warn("Downgraded payload bid to hash-only bid",
+				"txHash", bid.Tx.Hash().String(),
+				"blockNumber", bid.BlockNumber,
+				"reason", downgrade.Reason,
+				"elapsed", downgrade.Elapsed.String(),
+				"slo", downgrade.SLO.String(),
+			)
+		}
+	}
+	r.drain.Go(func() {
+		r.sendBid(bid, input)
+	})
+}
+
+// sendBid sends a bid and records its result.
+func (r *runner) sendBid(bid bb.PendingBid, input interface{}) {
+	// Retrying a bid is pointless once its target block is due
+	deadline := bb.BidDeadline(time.Now(), r.latestBlock.Load(), uint64(bid.BlockNumber), time.Duration(r.currentBlockTime.Load()))
+	sendCtx, sendCancel := context.WithDeadline(r.ctx, deadline)
+	if bid.AmountWei != nil {
+		amountWei, _ := new(big.Float).SetInt(bid.AmountWei).Float64()
+		metrics.BidAmountWei.Observe(amountWei)
+	}
+	if !bid.HeaderAt.IsZero() {
+		metrics.HeaderToBidSeconds.Observe(time.Since(bid.HeaderAt).Seconds())
+	}
+	sentAt := time.Now()
+	result, err := bb.SendPreconfBidWithRetry(sendCtx, r.bidder, input, bid.BlockNumber, bid.AmountWei, bid.Window, r.cfg.BidRetry)
+	sendCancel()
+	if r.selfTestReport != nil && err != nil {
+		r.selfTestReport.Fail(selftest.StageBid, err.Error())
+	}
+	if r.bidLog != nil {
+		r.bidLog.Record(bidLogEntry(bid, input, sentAt, result, err, r.cfg.DryRun))
+	}
+	// A bid the node took without error but no provider committed to is
+	// a silent rejection, tracked apart from failed bids
+	if !r.cfg.DryRun && bb.ClassifyBid(result, err) == bb.BidSilentRejection {
+		metrics.BidSilentRejections.Inc()
+		txHashes := bidTxHashes(bid)
+		slog.Warn("Bid silently rejected, no commitment received",
+			"txHashes", txHashes,
+			"blockNumber", bid.BlockNumber,
+			"amountWei", bid.AmountWei,
+		)
+		if r.silentRejectionWebhook != nil {
+			rejected := bids.NewSilentRejectionRecord(txHashes, uint64(bid.BlockNumber), bid.AmountWei, bid.Window.Start, bid.Window.End, sentAt, time.Now())
+			go func() {
+				if err := r.silentRejectionWebhook.Notify(r.ctx, rejected); err != nil {
+					slog.Warn("Failed to call silent rejection webhook", "blockNumber", rejected.BlockNumber, "error", err)
+				}
+			}()
+		}
+	}
+	if err != nil {
+		metrics.BidsFailed.Inc()
+	} else if !r.cfg.DryRun {
+		metrics.BidsSent.Inc()
+		if r.depositManager != nil {
+			r.depositManager.Spend(uint64(bid.BlockNumber), bid.AmountWei)
+		}
+		// A bid is won once a provider commits to it
+		r.session.RecordBidResult(result.Providers())
+		if result.Won() {
+			metrics.BidOutcomes.WithLabelValues("won").Inc()
+		} else {
+			metrics.BidOutcomes.WithLabelValues("lost").Inc()
+		}
+		switch providers := result.Providers(); {
+		case providers == 0:
+			metrics.BidProviders.WithLabelValues("0").Inc()
+		case providers == 1:
+			metrics.BidProviders.WithLabelValues("1").Inc()
+		default:
+			metrics.BidProviders.WithLabelValues("2+").Inc()
+		}
+	}
+	var sendErr *bb.BidSendError
+	if errors.As(err, &sendErr) {
+		switch {
+		case errors.Is(err, bb.ErrBidGaveUp):
+			metrics.BidSendFailures.WithLabelValues("gave_up", sendErr.Code.String()).Inc()
+		case errors.Is(err, bb.ErrBidRejected):
+			metrics.BidSendFailures.WithLabelValues("rejected", sendErr.Code.String()).Inc()
+		}
+	}
+	opened, recovered := r.cfg.Outage.Record(err)
+	if opened {
+		slog.Warn("Bidder node unreachable, outage started", "policy", r.cfg.BidderOutagePolicy, "error", err)
+	}
+	r.auditOutage(opened, recovered)
+	if r.cfg.Outage.Open() {
+		metrics.BidderConnectionUp.Set(0)
+	} else {
+		metrics.BidderConnectionUp.Set(1)
+	}
+	// The transactions go out whatever the bid's result, once providers
+	// had their head start
+	if bid.Broadcast {
+		delivered := []*types.Transaction{bid.Tx}
+		if len(bid.Bundle) > 0 {
+			delivered = bid.Bundle
+		}
+		send := func(tx *types.Transaction) error {
+			return r.sendBundle(tx, uint64(bid.BlockNumber))
+		}
+		// Nonces skipped by a failed broadcast are resynced like those of a failed send
+		resynced, err := ee.BroadcastBid(r.ctx, sentAt, r.cfg.BroadcastDelay, deadline, delivered, send, r.nonceAllocator)
+		if resynced {
+			slog.Warn("Resyncing nonces after failed sends",
+				"blockNumber", bid.BlockNumber,
+				"failures", r.cfg.NonceResyncFailures,
+			)
+		}
+		if err != nil {
+			slog.Error("Failed to send transaction",
+				"rpcEndpoint", bb.MaskEndpoint(r.cfg.RPCEndpoint),
+				"blockNumber", bid.BlockNumber,
+				"error", err,
+			)
+			if r.selfTestReport != nil {
+				r.selfTestReport.Fail(selftest.StageBid, err.Error())
+			}
+		}
+	}
+	// Only the first outcome counts, so a failed send or broadcast above stands
+	if r.selfTestReport != nil {
+		r.selfTestReport.Pass(selftest.StageBid, fmt.Sprintf("block %d, %g ETH", bid.BlockNumber, bid.AmountEth))
+	}
+}
+
+// probeOutage checks whether the bidder node answers again during an outage.
+func (r *runner) probeOutage() {
+	if !r.probing.CompareAndSwap(false, true) {
+		return
+	}
+	defer r.probing.Store(false)
+	probeCtx, probeCancel := context.WithTimeout(r.ctx, 5*time.Second)
+	defer probeCancel()
+	_, err := r.bidderClient.CheckReachability(probeCtx)
+	r.auditOutage(r.cfg.Outage.Record(err))
+}
+
+// flushOutageQueue sends the bids queued during an outage for blocks still
+// ahead once the node answers, and discards the others.
+func (r *runner) flushOutageQueue() {
+	send, expired := r.cfg.Outage.Recover(r.latestBlock.Load(), time.Now())
+	metrics.OutageBidsDiscarded.WithLabelValues(bb.OutageDiscardExpired).Add(float64(len(expired)))
+	slog.Info("Bidder node reachable again",
+		"queuedBidsSent", len(send),
+		"expiredBidsDiscarded", len(expired),
+	)
+	r.sender.Enqueue(send...)
+}
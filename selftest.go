@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FlagSelfTest             = "selftest"
+	FlagSelfTestMaxBidAmount = "selftest-max-bid-amount"
+	FlagAllowMainnet         = "allow-mainnet"
+	FlagMaxBidAmount         = "max-bid-amount"
+)
+
+// selftestCommand returns the selftest subcommand, which runs the bot in
+// single-shot mode: it builds one transaction, bids on the next eligible block,
+// waits for commitments and the target block, and prints a pass/fail report of
+// each stage.
+//
+// Parameters:
+// - run: The root action, run once the single-shot settings are applied.
+func selftestCommand(run cli.ActionFunc) *cli.Command {
+	return &cli.Command{
+		Name:  "selftest",
+		Usage: "Send one small end-to-end bid and report whether each stage of its lifecycle passed",
+		Flags: []cli.Flag{
+			&cli.Float64Flag{
+				Name:  FlagMaxBidAmount,
+				Usage: "Cap on the bid amount in ETH; the smallest configured amount is used below it",
+				Value: 0.0001,
+			},
+			&cli.BoolFlag{
+				Name:  FlagAllowMainnet,
+				Usage: "Acknowledge that the selftest sends a real bid when the chain is mainnet",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			maxBidAmount := c.Float64(FlagMaxBidAmount)
+			if maxBidAmount <= 0 {
+				return fmt.Errorf("--%s must be positive", FlagMaxBidAmount)
+			}
+
+			settings := map[string]string{
+				FlagSelfTest:             "true",
+				FlagSelfTestMaxBidAmount: strconv.FormatFloat(maxBidAmount, 'f', -1, 64),
+			}
+			for name, value := range settings {
+				if err := c.Set(name, value); err != nil {
+					return fmt.Errorf("failed to set --%s for selftest: %w", name, err)
+				}
+			}
+			return run(c)
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/params"
+	pb "github.com/primev/mev-commit/p2p/gen/go/bidderapi/v1"
+	"github.com/primev/preconf_blob_bidder/internal/demo"
+	"github.com/primev/preconf_blob_bidder/internal/fakebidder"
+	"github.com/primev/preconf_blob_bidder/internal/shutdown"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// runSelftest runs the selftest command against a simulated chain and a fake
+// bidder node configured by node, writing the report to out.
+func runSelftest(t *testing.T, node fakebidder.Config, out io.Writer) error {
+	t.Helper()
+	chain, err := demo.StartChain(500 * time.Millisecond)
+	require.NoError(t, err)
+	defer chain.Close()
+
+	node.OnBid = func(bid *pb.Bid) {
+		for _, rawTx := range bid.RawTransactions {
+			if raw, err := hex.DecodeString(rawTx); err == nil {
+				chain.SendRawTransaction(raw)
+			}
+		}
+	}
+	server, err := fakebidder.Start("127.0.0.1:0", node)
+	require.NoError(t, err)
+	defer server.Close()
+
+	closers := shutdown.NewRegistry()
+	defer closers.Close(closeTimeout)
+	app := newApp(closers)
+	app.Writer = out
+	return app.Run([]string{"biddercli",
+		"--" + FlagWsEndpoint, chain.URL,
+		"--" + FlagPrivateKey, chain.PrivateKeyHex(),
+		"--" + FlagServerAddress, server.Addr(),
+		"--" + FlagUsePayload,
+		"--" + FlagTipAsBaseFeePct, "10",
+		"--" + FlagTipFloorWei, strconv.FormatInt(params.GWei, 10),
+		"--" + FlagDrainConfirmations, "1",
+		"--" + FlagDrainTimeout, "30",
+		"selftest",
+	})
+}
+
+func TestSelftestPasses(t *testing.T) {
+	require.NoError(t, runSelftest(t, fakebidder.Config{CommitProbability: 1}, io.Discard))
+}
+
+func TestSelftestFailsWithoutCommitment(t *testing.T) {
+	require.EqualError(t, runSelftest(t, fakebidder.Config{CommitProbability: 0}, io.Discard), "selftest failed")
+}
+
+func TestSelftestFailsWhenTheBidIsRejected(t *testing.T) {
+	var out bytes.Buffer
+	err := runSelftest(t, fakebidder.Config{CommitProbability: 1, RejectCode: codes.InvalidArgument}, &out)
+	require.EqualError(t, err, "selftest failed")
+	require.Regexp(t, `FAIL\s+send bid`, out.String())
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bb "github.com/primev/preconf_blob_bidder/internal/mevcommit"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FlagBidderAddress = "bidder-address"
+	FlagTestTimeout   = "timeout"
+)
+
+// testBidderCommand returns the test-bidder subcommand, which checks that the
+// mev-commit bidder node answers gRPC requests without running the bot.
+func testBidderCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "test-bidder",
+		Usage: "Check that the mev-commit bidder node is reachable, then exit",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    FlagBidderAddress,
+				Usage:   "Address of the mev-commit bidder node gRPC API",
+				EnvVars: []string{"SERVER_ADDRESS"},
+				Value:   "localhost:13524",
+			},
+			&cli.BoolFlag{
+				Name:    FlagBidderTLS,
+				Usage:   "Connect to the bidder node over TLS and verify its certificate",
+				EnvVars: []string{"BIDDER_TLS"},
+			},
+			&cli.StringFlag{
+				Name:    FlagBidderTLSCAFile,
+				Usage:   "PEM CA bundle used to verify the bidder node certificate instead of the system roots",
+				EnvVars: []string{"BIDDER_TLS_CA_FILE"},
+			},
+			&cli.DurationFlag{
+				Name:  FlagTestTimeout,
+				Usage: "How long to wait for the bidder node to answer",
+				Value: 10 * time.Second,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			cfg := bb.BidderConfig{
+				ServerAddress: c.String(FlagBidderAddress),
+				TLS:           c.Bool(FlagBidderTLS),
+				TLSCAFile:     c.String(FlagBidderTLSCAFile),
+			}
+
+			bidderClient, err := bb.NewBidderClient(cfg)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("FAIL %s: %v", cfg.ServerAddress, err), 1)
+			}
+			defer bidderClient.Close()
+
+			ctx, cancel := context.WithTimeout(c.Context, c.Duration(FlagTestTimeout))
+			defer cancel()
+
+			report, err := bidderClient.CheckReachability(ctx)
+			if err != nil {
+				code, suggestion := bb.ReachabilitySuggestion(err)
+				return cli.Exit(fmt.Sprintf("FAIL %s: %s: %v\n%s", cfg.ServerAddress, code, err, suggestion), 1)
+			}
+
+			fmt.Printf("OK %s (tls=%t)\n", cfg.ServerAddress, cfg.TLS)
+			if report.Implemented {
+				fmt.Printf("  auto deposit enabled: %t\n", report.AutoDepositEnabled)
+				fmt.Printf("  deposit windows:      %d\n", report.Windows)
+			} else {
+				fmt.Println("  the node does not implement AutoDepositStatus, but answered the request")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/primev/preconf_blob_bidder/api"
+	"github.com/urfave/cli/v2"
+)
+
+const FlagRecordType = "type"
+
+// validateRecordsCommand returns the validate-records subcommand, which checks
+// each line of a JSONL record file against the schema of its declared version.
+func validateRecordsCommand() *cli.Command {
+	var names []string
+	for _, r := range api.Records {
+		names = append(names, r.Name)
+	}
+
+	return &cli.Command{
+		Name:      "validate-records",
+		Usage:     "Check a JSONL record file against the schema for each record's version",
+		ArgsUsage: "<file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  FlagRecordType,
+				Usage: "Record type of the file: " + strings.Join(names, ", "),
+				Value: "commitment",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("usage: validate-records [--type <type>] <file>", 2)
+			}
+			recordType := c.String(FlagRecordType)
+			if _, ok := api.LookupRecord(recordType); !ok {
+				return cli.Exit(fmt.Sprintf("unknown record type %q (must be one of %s)", recordType, strings.Join(names, ", ")), 2)
+			}
+
+			file, err := os.Open(c.Args().First())
+			if err != nil {
+				return cli.Exit(err.Error(), 1)
+			}
+			defer file.Close()
+
+			var valid, invalid int
+			versions := make(map[int]int)
+			scanner := bufio.NewScanner(file)
+			scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+			for line := 1; scanner.Scan(); line++ {
+				if len(strings.TrimSpace(scanner.Text())) == 0 {
+					continue
+				}
+				version, err := api.ValidateRecord(recordType, scanner.Bytes())
+				if err != nil {
+					invalid++
+					fmt.Printf("line %d: %v\n", line, err)
+					continue
+				}
+				valid++
+				versions[version]++
+			}
+			if err := scanner.Err(); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to read %s: %v", c.Args().First(), err), 1)
+			}
+
+			var counts []string
+			for v, n := range versions {
+				counts = append(counts, fmt.Sprintf("v%d=%d", v, n))
+			}
+			sort.Strings(counts)
+			fmt.Printf("%d valid, %d invalid %s records (%s)\n", valid, invalid, recordType, strings.Join(counts, " "))
+			if invalid > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import "fmt"
+
+// printWelcome prints the welcome message and the available flags.
+//
+// Parameters:
+// - demoMode: Whether to also print the demo mode banner.
+func printWelcome(demoMode bool) {
+	if demoMode {
+		fmt.Println("===============================================================================================")
+		fmt.Println("DEMO MODE: bidding against a fake bidder node. Commitments are synthetic and no ETH is spent.")
+		fmt.Println("===============================================================================================")
+	}
+
+	fmt.Println("-----------------------------------------------------------------------------------------------")
+	fmt.Println("Welcome to Preconf Bidder!")
+	fmt.Println("")
+	fmt.Println("This is a quickstart tool to make preconf bids on mev-commit chain.")
+	fmt.Println("")
+	fmt.Println("If you already know what you're doing, you can skip the prompts by providing flags upfront.")
+	fmt.Println("For example:")
+	fmt.Println("  ./biddercli --private-key <your_64_char_hex_key> --ws-endpoint wss://your-node.com/ws")
+	fmt.Println("")
+	fmt.Println("Available flags include:")
+	fmt.Println("  --private-key            Your private key for signing transactions (64 hex chars)")
+	fmt.Println("  --ws-endpoint            The WebSocket endpoint for your Ethereum node")
+	fmt.Println("  --ws-endpoints           Comma-separated WebSocket endpoints to fail over between, instead of --ws-endpoint")
+	fmt.Println("  --rpc-endpoint           The RPC endpoint if not using payload")
+	fmt.Println("  --bid-amount             The amount to bid (in ETH), default 0.001")
+	fmt.Println("  --priority-fee           The priority fee in wei, default 1")
+	fmt.Println("  --bid-amount-std-dev-percentage  Std dev percentage of bid amount, default 100.0")
+	fmt.Println("  --num-blob                       Number of blob transactions to send, default 0 makes the tx an eth transfer")
+	fmt.Println("  --default-timeout        Default client context timeout in seconds, default 15")
+	fmt.Println("  --run-duration-minutes   Duration to run the bidder in minutes (0 for infinite), overriding --max-runtime")
+	fmt.Println("  --max-runtime            How long the bidder runs before shutting down, e.g. 30m, 0 for infinite, default 336h")
+	fmt.Println("  --shutdown-bid-drain-timeout-sec  Seconds to wait for in-flight bids on shutdown, default 10")
+	fmt.Println("  --proposer-allowlist     Comma-separated validator pubkeys or indices to bid on (requires --beacon-endpoint)")
+	fmt.Println("  --beacon-endpoint        Beacon node API endpoint used for proposer lookahead")
+	fmt.Println("  --tx-abi-file            ABI file used to decode the calldata of sent transactions in logs")
+	fmt.Println("  --commitments-file       JSONL file that received commitments are appended to")
+	fmt.Println("  --tx-count-window        Number of blocks in the rolling transactions-per-block average, default 20")
+	fmt.Println("  --bid-profiles-file      JSON file of named bid profiles to select between per block")
+	fmt.Println("  --bid-profile-selector   How profiles are selected: round-robin, parity, or weighted, default round-robin")
+	fmt.Println("  --bid-block-range        Number of consecutive target blocks to bid on per header, default 1")
+	fmt.Println("  --fee-percentile-window  Number of recent blocks used to estimate the p90 priority fee, default 10")
+	fmt.Println("  --bid-cycle-retries      Times a failed bid cycle is retried on the next header, default 0")
+	fmt.Println("  --bidder-tls             Connect to the bidder node over TLS")
+	fmt.Println("  --bidder-tls-ca-file     CA bundle used to verify the bidder node certificate")
+	fmt.Println("  --bid-endpoint-timeout   With several bidder nodes in --server-address, time each is given to answer a bid, 0 for no limit, default 10s")
+	fmt.Println("  --drain-signal           Signal that starts a drain before exit, default SIGTERM (none to exit immediately)")
+	fmt.Println("  --drain-timeout-sec      Maximum seconds to drain before exiting, default 120")
+	fmt.Println("  --drain-confirmations    Confirmations before a drained bid is resolved, default 2")
+	fmt.Println("  --health-addr            Address to serve the /readyz readiness check on")
+	fmt.Println("  --heartbeat-interval     Interval between \"alive\" log lines (e.g. 30s), default 1m, 0 disables")
+	fmt.Println("  --bid-records-file       JSONL file of resolved bids (included or missed)")
+	fmt.Println("  --bid-log-file           File that every bid sent is appended to with its result")
+	fmt.Println("  --bid-log-format         Format of the bid log: jsonl or csv, default jsonl")
+	fmt.Println("  --bid-log-max-size-mb    Size in MB at which the bid log is rotated, 0 to never rotate, default 100")
+	fmt.Println("  --bid-log-max-backups    Rotated bid log files kept, default 10")
+	fmt.Println("  --escalation-missed-bids Consecutive missed bids before escalating the bid amount, default 3, 0 disables")
+	fmt.Println("  --escalation-step-pct    Percentage each escalation raises the bid amount by, default 25")
+	fmt.Println("  --escalation-max-multiplier  Cap on escalated bids as a multiple of the base amount, default 5")
+	fmt.Println("  --tip-as-base-fee-pct    Priority fee as a percentage of the base fee instead of --priority-fee")
+	fmt.Println("  --tip-floor-wei          Minimum priority fee in wei with --tip-as-base-fee-pct, default 1")
+	fmt.Println("  --gas-fee-cap-gwei       Fixed max fee per gas in gwei, 0 for the base fee plus the tip, default 0")
+	fmt.Println("  --gas-tip-gwei           Fixed priority fee in gwei for every transaction instead of --priority-fee, 0 to disable, default 0")
+	fmt.Println("  --blob-fee-cap-gwei      Fixed max fee per blob gas in gwei, 0 for 10% over the next blob base fee, default 0")
+	fmt.Println("  --blob-fee-as-gas-fee-multiple  Max fee per blob gas as this multiple of the max fee per gas, 0 to disable, default 0")
+	fmt.Println("  --base-fee-multiplier    Multiplier on the base fee in the max fee per gas, default 1")
+	fmt.Println("  --max-fee-gwei           Skip blocks whose max fee per gas would exceed this many gwei, 0 for no limit, default 0")
+	fmt.Println("  --tx-dedup               Rebid on a pending transaction instead of rebuilding an identical one, default true")
+	fmt.Println("  --bid-latency-slo        Time after a header past which payload bids are sent as hash-only bids (e.g. 500ms), 0 disables")
+	fmt.Println("  --kzg-trusted-setup      KZG trusted setup JSON file for blob commitments, default the embedded mainnet setup")
+	fmt.Println("  --tx-max-lifetime        Duration (e.g. 2m) or block count after which an unincluded tx is abandoned, 0 disables")
+	fmt.Println("  --bid-allow-reverts      Mark bid transactions as allowed to revert, default false")
+	fmt.Println("  --private-keys           Comma-separated keys of additional wallets, after --private-key in the wallet pool")
+	fmt.Println("  --wallet-modes           Modes per wallet in priority order, e.g. \"0=blob;1=transfer,blob\"")
+	fmt.Println("  --blob-ramp              Blob-only stress mode ramping the blobs per transaction, default false")
+	fmt.Println("  --blob-ramp-max          Blobs per transaction at the end of the ramp, default 6")
+	fmt.Println("  --blob-ramp-step-blocks  Blocks per ramp step, default 10")
+	fmt.Println("  --blob-ramp-schedule     Comma-separated blob counts of the ramp steps instead of 1 to --blob-ramp-max")
+	fmt.Println("  --exit-on-criteria       Success criteria checked on exit, e.g. \"min_commitments=1,min_inclusion_rate=0.5\"; unmet criteria exit 2")
+	fmt.Println("  --nonce-check-interval   Interval between checks of wallet nonces against the chain, 0 to disable, default 30s")
+	fmt.Println("  --funding-private-key    Key of a wallet outside the pool that tops up pool wallets running low, empty to disable")
+	fmt.Println("  --rebalance-min-balance  Balance in ETH below which a pool wallet is topped up")
+	fmt.Println("  --rebalance-top-up-amount ETH sent to a pool wallet by each top-up")
+	fmt.Println("  --rebalance-max-total    Most ETH sent by top-ups over the session, 0 for no limit")
+	fmt.Println("  --rebalance-interval     Interval between checks of the pool wallets' balances, default 1m")
+	fmt.Println("  --rebalance-cooldown     Least time between two top-ups of the same wallet, default 10m")
+	fmt.Println("  --nonce-pause-checks     Divergent nonce checks in a row before a wallet stops bidding, default 3")
+	fmt.Println("  --nonce-resync-failures  Failed sends in a row before a wallet's nonces resync from the chain, default 1")
+	fmt.Println("  --blob-chain-length      Blob transactions with sequential nonces sent from one wallet per block, default 1")
+	fmt.Println("  --decay-jitter           Most that decay windows of simultaneous bids for a block end early, to make them differ, default 0")
+	fmt.Println("  --bid-min-eth            Smallest amount any bid is raised to, in ETH, default 0 (unset)")
+	fmt.Println("  --bid-max-eth            Largest amount any bid is lowered to, in ETH, default 0 (unset)")
+	fmt.Println("  --bid-amount-strategy    uniform or fixed to draw bid amounts in wei between --bid-amount-min and --bid-amount-max instead of from profiles")
+	fmt.Println("  --bid-amount-min         Smallest bid amount in wei, the only one used by the fixed strategy")
+	fmt.Println("  --bid-amount-max         Largest bid amount in wei for the uniform strategy")
+	fmt.Println("  --bid-mode               random (profile amounts) or fixed (exactly --bid-amount-eth on every bid), default random")
+	fmt.Println("  --bid-amount-eth         Amount of every bid in ETH in fixed bid mode")
+	fmt.Println("  --transfer-schedule-csv  CSV of recipient,value (wei) rows, one used per block by transfers instead of a self transfer, looping")
+	fmt.Println("  --transfer-amount-wei    Wei sent by each ETH transfer without a schedule, default 1000000000")
+	fmt.Println("  --bidder-outage-policy   skip or queue: whether headers build transactions while the bidder node is unreachable, default skip")
+	fmt.Println("  --bidder-outage-failures Consecutive unreachable bid failures that start an outage, default 3")
+	fmt.Println("  --bidder-outage-queue-size Most hash-only bids queued during an outage with the queue policy, default 8")
+	fmt.Println("  --decay-start-offset-ms  Delay from building a bid to the start of its decay, in ms, default 0")
+	fmt.Println("  --decay-duration-ms      Decay duration of bids whose profile sets none, in ms, default 36000")
+	fmt.Println("  --adaptive-blob-count    Scale blobs per transaction between the adaptive bounds by the blob base fee, default false")
+	fmt.Println("  --adaptive-blob-min      Blobs per transaction at or above the high fee, default 1")
+	fmt.Println("  --adaptive-blob-max      Blobs per transaction at or below the low fee, default 6")
+	fmt.Println("  --adaptive-blob-low-fee-wei  Blob base fee at or below which the most blobs are sent, default 1000000000")
+	fmt.Println("  --adaptive-blob-high-fee-wei Blob base fee at or above which the fewest blobs are sent, default 30000000000")
+	fmt.Println("  --key-audit              Record every signature in an append-only log: off, on, or required, default off")
+	fmt.Println("  --key-audit-file         Key audit log file, default key_audit.jsonl")
+	fmt.Println("  --rpc-pool-size          Warm connections kept to the RPC endpoint, default 2")
+	fmt.Println("  --rpc-pool-health-interval  Interval between health checks of pooled RPC connections, default 30s")
+	fmt.Println("  --header-stale-timeout   Reconnect the header subscription when no header arrives for this long, 0 to wait forever, default 30s")
+	fmt.Println("  --chain-block-time       Block time that timing defaults derive from, or auto to detect it from headers, default auto")
+	fmt.Println("  --bid-retry-attempts     Attempts to send a bid while the bidder node returns a retryable gRPC status, default 3")
+	fmt.Println("  --bid-retry-backoff      Wait before retrying a bid, doubling after each failure, default 100ms")
+	fmt.Println("  --stale-bid-policy       Bids whose target block was reached while building are skipped (skip) or sent for the next target if still valid (retarget), default skip")
+	fmt.Println("  --bid-retryable-codes    Comma-separated gRPC statuses a bid is retried after, others abort it, default UNAVAILABLE,DEADLINE_EXCEEDED,RESOURCE_EXHAUSTED,ABORTED")
+	fmt.Println("  --num-blobs              Blob counts from 1 to 6 cycled on successive blocks, e.g. 1,3,6, instead of --num-blob")
+	fmt.Println("  --bid-record-strategy    Add the pricer, mode, observed fees and guards to each bid record, default false")
+	fmt.Println("  --bid-pricing-workers    Bids of a chain priced at once, still sent in nonce order, default 1")
+	fmt.Println("  --tx-type                Registered transaction generator used by profiles without blobs, default transfer")
+	fmt.Println("  --token-transfer         Bid with ERC-20 transfers of --token-address instead of ETH transfers, default false")
+	fmt.Println("  --token-address          ERC-20 contract that token transfers call")
+	fmt.Println("  --token-amount           Tokens sent per transfer in the token's base units, default 1")
+	fmt.Println("  --metrics-port           Port to serve Prometheus metrics on at /metrics, 0 to disable, default 9090")
+	fmt.Println("  --offset-decay-policy    When the decay window ends before the target block: error, extend, or anchor, default extend")
+	fmt.Println("  --tx-per-block           Transactions built per block by profiles without blobs, bid on together in one bid, default 1")
+	fmt.Println("  --bid-outcome-log-blocks Blocks between logs of won and lost bids, 0 to disable, default 10")
+	fmt.Println("  --backrun-to             Address of a backrun call bid on together with each transaction of profiles without blobs")
+	fmt.Println("  --backrun-data           Hex calldata of the backrun call")
+	fmt.Println("  --dry-run                Build and sign transactions and bids, and log them instead of sending them")
+	fmt.Println("  --metrics-addr           Address to serve Prometheus metrics on, e.g. :2112, instead of METRICS_PORT")
+	fmt.Println("  --blob-sidecar           Deliver blob transactions with their sidecar (full) or without it (omit, hash-only bids), default full")
+	fmt.Println("  --keystore-path          Geth keystore JSON file to load the private key from, instead of --private-key")
+	fmt.Println("  --keystore-password      Password of the keystore file")
+	fmt.Println("  --blob-seed              Seed for the blob data, so every run sends the same blobs and versioned hashes")
+	fmt.Println("  --max-concurrent-bundles Most bundle submissions in flight at once, 0 for no limit (default: 0)")
+	fmt.Println("  --bundle-overflow-policy Bundles over the limit wait (queue) or are dropped (drop) (default: queue)")
+	fmt.Println("  --broadcast-delay        Hold back the bundle of a hash-only bid this long after the bid, e.g. 2s (default: 0, sent before the bid)")
+	fmt.Println("  --blob-data-path         File or directory whose contents every blob transaction carries instead of random data")
+	fmt.Println("  --http-user-agent        User-Agent of bundle, RPC and beacon HTTP requests (default: <app name>/<version>)")
+	fmt.Println("  --mode                   build to bid on the bot's own transactions, or watch to bid on pending transactions of --watch-addresses (default: build)")
+	fmt.Println("  --watch-addresses        Comma-separated senders whose pending transactions are bid on in watch mode")
+	fmt.Println("  --send-interval-blocks   Create transactions only every Nth block, 0 or 1 for every block, default 0")
+	fmt.Println("  --send-interval-seconds  Create transactions at most once per this many seconds, ignored with --send-interval-blocks, default 0")
+	fmt.Println("  --inclusion-webhook-url  URL to POST a JSON record to when a bid's transaction is included, empty to disable")
+	fmt.Println("  --inclusion-webhook-timeout  Timeout of each inclusion webhook request, default 5s")
+	fmt.Println("  --inclusion-webhook-attempts Attempts per inclusion webhook call, default 3")
+	fmt.Println("  --silent-rejection-webhook-url  URL to POST a JSON record to when a bid gets no commitment and no error, empty to disable")
+	fmt.Println("  --min-deposit            Bidder deposit in ETH below which the target block's window is topped up, 0 to disable, default 0")
+	fmt.Println("  --top-up-amount          ETH deposited by each top-up, at least --min-deposit")
+	fmt.Println("  --deposit-blocks-per-window  Blocks per bidder registry deposit window, default 10")
+	fmt.Println("  --config                 YAML config file, overridden by flags and env vars")
+	fmt.Println("  --log-file               File that JSON logs are also written to, rotated by size")
+	fmt.Println("  --log-timestamp-format   Log timestamp format: rfc3339, rfc3339nano, unix, or unixmilli")
+	fmt.Println("  --log-dedup-window       Collapse repeats of a logged failure into one summary per window, 0 logs every one, default 1m")
+	fmt.Println("  --pretty-log             Pretty-print console logs, or false for one JSON object per line (default: true)")
+	fmt.Println("  --app-name               Application name for logging")
+	fmt.Println("  --version                Application version for logging")
+	fmt.Println("")
+	fmt.Println("You can also set environment variables like WS_ENDPOINT and PRIVATE_KEY.")
+	fmt.Println("For more details, check the documentation: https://docs.primev.xyz/get-started/bidders/best-practices")
+	fmt.Println("-----------------------------------------------------------------------------------------------")
+	fmt.Println()
+}